@@ -51,6 +51,23 @@ func NewErrCacheInconsistent(details string) *ErrCacheInconsistent {
 	}
 }
 
+// ErrCacheInvalid is returned when reading a table of the cache that is no
+// longer kept up to date, because the server canceled its monitor
+type ErrCacheInvalid struct {
+	table string
+}
+
+// Error implements the error interface
+func (e *ErrCacheInvalid) Error() string {
+	return fmt.Sprintf("cache of table %s is invalid, it must be monitored again", e.table)
+}
+
+func NewErrCacheInvalid(table string) *ErrCacheInvalid {
+	return &ErrCacheInvalid{
+		table: table,
+	}
+}
+
 // ErrIndexExists is returned when an item in the database cannot be inserted due to existing indexes
 type ErrIndexExists struct {
 	Table    string
@@ -149,13 +166,25 @@ type RowCache struct {
 	indexSpecs []indexSpec
 	indexes    columnToValue
 	mutex      sync.RWMutex
+	// columns are the columns the rows are cloned with when the table is
+	// monitored for some of its columns only, and nil otherwise
+	columns []string
+}
+
+// clone returns a deep copy of a model, of its monitored columns only if the
+// table is not monitored for all of them. Caller must hold the row cache lock.
+func (r *RowCache) clone(m model.Model) model.Model {
+	if r.columns != nil {
+		return model.CloneColumns(m, r.columns)
+	}
+	return model.Clone(m)
 }
 
 // rowByUUID returns one model from the cache by UUID. Caller must hold the row
 // cache lock.
 func (r *RowCache) rowByUUID(uuid string) model.Model {
 	if row, ok := r.cache[uuid]; ok {
-		return model.Clone(row)
+		return r.clone(row)
 	}
 	return nil
 }
@@ -302,7 +331,7 @@ func (r *RowCache) Create(uuid string, m model.Model, checkIndexes bool) error {
 		}
 	}
 
-	r.cache[uuid] = model.Clone(m)
+	r.cache[uuid] = r.clone(m)
 	return nil
 }
 
@@ -313,7 +342,7 @@ func (r *RowCache) Update(uuid string, m model.Model, checkIndexes bool) (model.
 	if _, ok := r.cache[uuid]; !ok {
 		return nil, NewErrCacheInconsistent(fmt.Sprintf("cannot update row %s as it does not exist in the cache", uuid))
 	}
-	oldRow := model.Clone(r.cache[uuid])
+	oldRow := r.clone(r.cache[uuid])
 	oldInfo, err := r.dbModel.NewModelInfo(oldRow)
 	if err != nil {
 		return nil, err
@@ -377,13 +406,15 @@ func (r *RowCache) Update(uuid string, m model.Model, checkIndexes bool) (model.
 			}
 		}
 		for k, v := range removeIndexes[index] {
-			if indexSpec.isSchemaIndex() || substractUUIDSet(r.indexes[index][k], v).empty() {
+			// only remove the index if it is pointing to this uuid, another
+			// row might have taken the old value earlier in the same update
+			if substractUUIDSet(r.indexes[index][k], v).empty() {
 				delete(r.indexes[index], k)
 			}
 		}
 	}
 
-	r.cache[uuid] = model.Clone(m)
+	r.cache[uuid] = r.clone(m)
 	return oldRow, nil
 }
 
@@ -472,7 +503,7 @@ func (r *RowCache) Rows() map[string]model.Model {
 	defer r.mutex.RUnlock()
 	result := make(map[string]model.Model)
 	for k, v := range r.cache {
-		result[k] = model.Clone(v)
+		result[k] = r.clone(v)
 	}
 	return result
 }
@@ -836,16 +867,30 @@ type TableCache struct {
 	ovsdb.NotificationHandler
 	mutex  sync.RWMutex
 	logger *logr.Logger
+	// invalid contains the tables that are no longer monitored
+	invalid map[string]bool
+	// partial contains the tables that are monitored for some kinds of
+	// changes only
+	partial map[string]bool
+	// columns contains the columns each table is monitored for
+	columns map[string]map[string]bool
+	// raw contains the rows the cache was populated with by table and
+	// UUID when it was created WithRawRows, and is nil otherwise
+	raw map[string]map[string]ovsdb.Row
 }
 
 // Data is the type for data that can be prepopulated in the cache
 type Data map[string]map[string]model.Model
 
 // NewTableCache creates a new TableCache
-func NewTableCache(dbModel model.DatabaseModel, data Data, logger *logr.Logger) (*TableCache, error) {
+func NewTableCache(dbModel model.DatabaseModel, data Data, logger *logr.Logger, opts ...Option) (*TableCache, error) {
 	if !dbModel.Valid() {
 		return nil, fmt.Errorf("tablecache without valid databasemodel cannot be populated")
 	}
+	options, err := newOptions(opts...)
+	if err != nil {
+		return nil, err
+	}
 	if logger == nil {
 		l := stdr.NewWithOptions(log.New(os.Stderr, "", log.LstdFlags), stdr.Options{LogCaller: stdr.All}).WithName("cache")
 		logger = &l
@@ -870,12 +915,20 @@ func NewTableCache(dbModel model.DatabaseModel, data Data, logger *logr.Logger)
 			}
 		}
 	}
+	var raw map[string]map[string]ovsdb.Row
+	if options.rawRows {
+		raw = make(map[string]map[string]ovsdb.Row)
+	}
 	return &TableCache{
 		cache:          cache,
 		eventProcessor: eventProcessor,
 		dbModel:        dbModel,
 		mutex:          sync.RWMutex{},
 		logger:         logger,
+		invalid:        make(map[string]bool),
+		partial:        make(map[string]bool),
+		columns:        make(map[string]map[string]bool),
+		raw:            raw,
 	}, nil
 }
 
@@ -899,6 +952,233 @@ func (t *TableCache) Table(name string) *RowCache {
 	return nil
 }
 
+// List returns a copy of every row of a table, sorted by UUID. The rows can be
+// modified without affecting the cache. An ErrCacheInvalid error is returned
+// if the table has been invalidated.
+func (t *TableCache) List(table string) ([]model.Model, error) {
+	rowCache := t.Table(table)
+	if rowCache == nil {
+		return nil, fmt.Errorf("table %s not found", table)
+	}
+	if err := t.CheckValid(table); err != nil {
+		return nil, err
+	}
+	rowCache.mutex.RLock()
+	defer rowCache.mutex.RUnlock()
+	uuids := make([]string, 0, len(rowCache.cache))
+	for uuid := range rowCache.cache {
+		uuids = append(uuids, uuid)
+	}
+	sort.Strings(uuids)
+	models := make([]model.Model, 0, len(uuids))
+	for _, uuid := range uuids {
+		models = append(models, rowCache.clone(rowCache.cache[uuid]))
+	}
+	return models, nil
+}
+
+// RawRow returns the ovsdb.Row of a row of a table as the cache was populated
+// with it, including the columns its model has no field for, or nil if the
+// row is not in the cache or the cache was not created WithRawRows. The values
+// of the row must not be modified.
+func (t *TableCache) RawRow(table, uuid string) ovsdb.Row {
+	t.mutex.RLock()
+	defer t.mutex.RUnlock()
+	raw, ok := t.raw[table][uuid]
+	if !ok {
+		return nil
+	}
+	row := make(ovsdb.Row, len(raw))
+	for column, value := range raw {
+		row[column] = value
+	}
+	return row
+}
+
+// setRawRow keeps the raw row of a row, or forgets it if it is nil, when the
+// cache was created WithRawRows. Caller must hold the mutex.
+func (t *TableCache) setRawRow(table, uuid string, row *ovsdb.Row) {
+	if t.raw == nil {
+		return
+	}
+	if row == nil {
+		delete(t.raw[table], uuid)
+		return
+	}
+	if t.raw[table] == nil {
+		t.raw[table] = make(map[string]ovsdb.Row)
+	}
+	raw := make(ovsdb.Row, len(*row))
+	for column, value := range *row {
+		raw[column] = value
+	}
+	t.raw[table][uuid] = raw
+}
+
+// dropRawRows forgets the raw rows of the given tables. Caller must hold the
+// mutex.
+func (t *TableCache) dropRawRows(tables ...string) {
+	if t.raw == nil {
+		return
+	}
+	for _, table := range tables {
+		delete(t.raw, table)
+	}
+}
+
+// Invalidate marks the given tables as no longer being kept up to date, so
+// that CheckValid fails for them until they are revalidated
+func (t *TableCache) Invalidate(tables ...string) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	for _, table := range tables {
+		t.invalid[table] = true
+	}
+}
+
+// Drop drops the rows of the given tables once they are no longer monitored
+// and invalidates them, so that CheckValid fails for them until they are
+// revalidated. If deleteEvents is true, a delete event is sent for each
+// dropped row.
+func (t *TableCache) Drop(deleteEvents bool, tables ...string) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	tableTypes := t.dbModel.Types()
+	for _, table := range tables {
+		if rows, ok := t.cache[table]; ok {
+			if deleteEvents {
+				rows.mutex.RLock()
+				for _, m := range rows.cache {
+					t.eventProcessor.AddEvent(deleteEvent, table, m, nil)
+				}
+				rows.mutex.RUnlock()
+			}
+			t.cache[table] = newRowCache(table, t.dbModel, tableTypes[table])
+		}
+		t.dropRawRows(table)
+		delete(t.columns, table)
+		delete(t.partial, table)
+		t.invalid[table] = true
+	}
+}
+
+// Revalidate drops the stale rows of the given tables that were invalidated
+// and marks them as valid again, so that they can be populated when they are
+// monitored again. A delete event is sent for each dropped row, as the rows
+// populated again are sent as add events.
+func (t *TableCache) Revalidate(tables ...string) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	tableTypes := t.dbModel.Types()
+	for _, table := range tables {
+		if !t.invalid[table] {
+			continue
+		}
+		if rows, ok := t.cache[table]; ok {
+			rows.mutex.RLock()
+			for _, m := range rows.cache {
+				t.eventProcessor.AddEvent(deleteEvent, table, m, nil)
+			}
+			rows.mutex.RUnlock()
+		}
+		t.cache[table] = newRowCache(table, t.dbModel, tableTypes[table])
+		t.dropRawRows(table)
+		delete(t.invalid, table)
+	}
+}
+
+// Partial marks the given tables as monitored for some kinds of changes only,
+// as selected by an ovsdb.MonitorSelect. The rows of these tables may not be
+// in the cache when they are modified or deleted, e.g. when the initial rows
+// are not selected, so such updates are ignored instead of failing with an
+// ErrCacheInconsistent error.
+func (t *TableCache) Partial(tables ...string) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	for _, table := range tables {
+		t.partial[table] = true
+	}
+}
+
+// Complete marks the given tables as monitored for every kind of change again,
+// e.g. when they are monitored again without an ovsdb.MonitorSelect, so that
+// the updates of rows that are not in the cache fail again, see Partial.
+func (t *TableCache) Complete(tables ...string) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	for _, table := range tables {
+		delete(t.partial, table)
+	}
+}
+
+// MonitoredColumns records that a table is monitored for the given columns,
+// or for all its columns if none is given, in addition to the columns of its
+// other monitors. While a table is not
+// monitored for all its columns, the cache only copies the monitored ones
+// when it clones its rows, see model.CloneColumns, since the other fields
+// are always empty.
+func (t *TableCache) MonitoredColumns(table string, columns ...string) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	monitored := t.columns[table]
+	if monitored == nil {
+		monitored = make(map[string]bool, len(columns))
+		t.columns[table] = monitored
+	}
+	if len(columns) == 0 {
+		if tableSchema := t.dbModel.Schema.Table(table); tableSchema != nil {
+			for column := range tableSchema.Columns {
+				monitored[column] = true
+			}
+		}
+	}
+	for _, column := range columns {
+		monitored[column] = true
+	}
+	if rowCache, ok := t.cache[table]; ok {
+		rowCache.mutex.Lock()
+		rowCache.columns = cloneColumns(t.dbModel, table, monitored)
+		rowCache.mutex.Unlock()
+	}
+}
+
+// cloneColumns returns the sorted columns the rows of a table monitored for
+// the given columns are cloned with, or nil if it is monitored for all its
+// columns
+func cloneColumns(dbModel model.DatabaseModel, table string, monitored map[string]bool) []string {
+	tableSchema := dbModel.Schema.Table(table)
+	if tableSchema == nil || len(monitored) == 0 {
+		return nil
+	}
+	all := true
+	for column := range tableSchema.Columns {
+		if !monitored[column] {
+			all = false
+			break
+		}
+	}
+	if all {
+		return nil
+	}
+	columns := make([]string, 0, len(monitored))
+	for column := range monitored {
+		columns = append(columns, column)
+	}
+	sort.Strings(columns)
+	return columns
+}
+
+// CheckValid returns an ErrCacheInvalid error if the given table has been
+// invalidated
+func (t *TableCache) CheckValid(name string) error {
+	t.mutex.RLock()
+	defer t.mutex.RUnlock()
+	if t.invalid[name] {
+		return NewErrCacheInvalid(name)
+	}
+	return nil
+}
+
 // Tables returns a list of table names that are in the cache
 func (t *TableCache) Tables() []string {
 	t.mutex.RLock()
@@ -983,18 +1263,32 @@ func (t *TableCache) Populate(tableUpdates ovsdb.TableUpdates) error {
 						}
 						t.eventProcessor.AddEvent(updateEvent, table, existing, newModel)
 					}
-					// no diff
+					// the columns without field may have changed anyway
+					t.setRawRow(table, uuid, row.New)
 					continue
 				}
+				// an update with both the old and the new row is a modify
+				if row.Old != nil {
+					if t.partial[table] {
+						dbgLogger.Info("ignoring modification of a row not in the cache")
+						continue
+					}
+					return NewErrCacheInconsistent(fmt.Sprintf("row with uuid %s does not exist", uuid))
+				}
 				if dbgLogger.Enabled() {
 					dbgLogger.Info("creating row", "model", fmt.Sprintf("%+v", newModel))
 				}
 				if err := tCache.Create(uuid, newModel, false); err != nil {
 					return err
 				}
+				t.setRawRow(table, uuid, row.New)
 				t.eventProcessor.AddEvent(addEvent, table, nil, newModel)
 				continue
 			} else {
+				if t.partial[table] && !tCache.HasRow(uuid) {
+					dbgLogger.Info("ignoring deletion of a row not in the cache")
+					continue
+				}
 				oldModel, err := t.CreateModel(table, row.Old, uuid)
 				if err != nil {
 					return err
@@ -1005,6 +1299,7 @@ func (t *TableCache) Populate(tableUpdates ovsdb.TableUpdates) error {
 				if err := tCache.Delete(uuid); err != nil {
 					return err
 				}
+				t.setRawRow(table, uuid, nil)
 				t.eventProcessor.AddEvent(deleteEvent, table, oldModel, nil)
 				continue
 			}
@@ -1038,6 +1333,7 @@ func (t *TableCache) Populate2(tableUpdates ovsdb.TableUpdates2) error {
 				if err := tCache.Create(uuid, m, false); err != nil {
 					return err
 				}
+				t.setRawRow(table, uuid, row.Initial)
 				t.eventProcessor.AddEvent(addEvent, table, nil, m)
 			case row.Insert != nil:
 				m, err := t.CreateModel(table, row.Insert, uuid)
@@ -1050,10 +1346,15 @@ func (t *TableCache) Populate2(tableUpdates ovsdb.TableUpdates2) error {
 				if err := tCache.Create(uuid, m, false); err != nil {
 					return err
 				}
+				t.setRawRow(table, uuid, row.Insert)
 				t.eventProcessor.AddEvent(addEvent, table, nil, m)
 			case row.Modify != nil:
 				modified := tCache.Row(uuid)
 				if modified == nil {
+					if t.partial[table] {
+						dbgLogger.Info("ignoring modification of a row not in the cache")
+						continue
+					}
 					return NewErrCacheInconsistent(fmt.Sprintf("row with uuid %s does not exist", uuid))
 				}
 				changed, err := t.ApplyModifications(table, modified, *row.Modify)
@@ -1070,6 +1371,10 @@ func (t *TableCache) Populate2(tableUpdates ovsdb.TableUpdates2) error {
 					}
 					t.eventProcessor.AddEvent(updateEvent, table, existing, modified)
 				}
+				if raw, ok := t.raw[table][uuid]; ok {
+					raw = applyRawModifications(t.dbModel.Schema.Table(table), raw, *row.Modify)
+					t.setRawRow(table, uuid, &raw)
+				}
 			case row.Delete != nil:
 				fallthrough
 			default:
@@ -1077,6 +1382,10 @@ func (t *TableCache) Populate2(tableUpdates ovsdb.TableUpdates2) error {
 				// no value on the wire), then process a delete
 				m := tCache.Row(uuid)
 				if m == nil {
+					if t.partial[table] {
+						dbgLogger.Info("ignoring deletion of a row not in the cache")
+						continue
+					}
 					return NewErrCacheInconsistent(fmt.Sprintf("row with uuid %s does not exist", uuid))
 				}
 				if dbgLogger.Enabled() {
@@ -1085,6 +1394,7 @@ func (t *TableCache) Populate2(tableUpdates ovsdb.TableUpdates2) error {
 				if err := tCache.Delete(uuid); err != nil {
 					return err
 				}
+				t.setRawRow(table, uuid, nil)
 				t.eventProcessor.AddEvent(deleteEvent, table, m, nil)
 			}
 		}
@@ -1092,6 +1402,72 @@ func (t *TableCache) Populate2(tableUpdates ovsdb.TableUpdates2) error {
 	return nil
 }
 
+// applyRawModifications returns a raw row with the modifications of an
+// update2 applied: the values of atomic columns are replaced, the elements of
+// the diff of a set column are added to the set or removed from it, and the
+// keys of the diff of a map column are added, updated, or removed when their
+// value is the current one
+func applyRawModifications(table *ovsdb.TableSchema, raw, modify ovsdb.Row) ovsdb.Row {
+	row := make(ovsdb.Row, len(raw))
+	for column, value := range raw {
+		row[column] = value
+	}
+	for column, diff := range modify {
+		var columnType ovsdb.ExtendedType
+		if schema := table.Column(column); schema != nil {
+			columnType = schema.Type
+		}
+		switch columnType {
+		case ovsdb.TypeSet:
+			elements := append([]interface{}{}, rawSetElements(row[column])...)
+			for _, element := range rawSetElements(diff) {
+				found := false
+				for i := range elements {
+					if elements[i] == element {
+						elements = append(elements[:i], elements[i+1:]...)
+						found = true
+						break
+					}
+				}
+				if !found {
+					elements = append(elements, element)
+				}
+			}
+			row[column] = ovsdb.OvsSet{GoSet: elements}
+		case ovsdb.TypeMap:
+			current, _ := row[column].(ovsdb.OvsMap)
+			m := make(map[interface{}]interface{}, len(current.GoMap))
+			for k, v := range current.GoMap {
+				m[k] = v
+			}
+			diffMap, _ := diff.(ovsdb.OvsMap)
+			for k, v := range diffMap.GoMap {
+				if existing, ok := m[k]; ok && existing == v {
+					delete(m, k)
+				} else {
+					m[k] = v
+				}
+			}
+			row[column] = ovsdb.OvsMap{GoMap: m}
+		default:
+			row[column] = diff
+		}
+	}
+	return row
+}
+
+// rawSetElements returns the elements of the raw value of a set column, which
+// is a single element when the set has exactly one
+func rawSetElements(value interface{}) []interface{} {
+	switch v := value.(type) {
+	case nil:
+		return nil
+	case ovsdb.OvsSet:
+		return v.GoSet
+	}
+	return []interface{}{value}
+}
+
 // Purge drops all data in the cache and reinitializes it using the
 // provided database model
 func (t *TableCache) Purge(dbModel model.DatabaseModel) {
@@ -1102,9 +1478,98 @@ func (t *TableCache) Purge(dbModel model.DatabaseModel) {
 	for name := range t.dbModel.Schema.Tables {
 		t.cache[name] = newRowCache(name, t.dbModel, tableTypes[name])
 	}
+	t.columns = make(map[string]map[string]bool)
+	t.partial = make(map[string]bool)
+	if t.raw != nil {
+		t.raw = make(map[string]map[string]ovsdb.Row)
+	}
 }
 
-// AddEventHandler registers the supplied EventHandler to receive cache events
+// NewShadow returns an empty cache for the given model that does not produce
+// events, to populate with the complete rows of the monitors of a database
+// before it replaces the rows of this cache with Replace, or to keep as a
+// snapshot of them
+func (t *TableCache) NewShadow(dbModel model.DatabaseModel) *TableCache {
+	tableTypes := dbModel.Types()
+	cache := make(map[string]*RowCache, len(dbModel.Schema.Tables))
+	for name := range dbModel.Schema.Tables {
+		cache[name] = newRowCache(name, dbModel, tableTypes[name])
+	}
+	var raw map[string]map[string]ovsdb.Row
+	if t.raw != nil {
+		raw = make(map[string]map[string]ovsdb.Row)
+	}
+	t.mutex.RLock()
+	partial := make(map[string]bool, len(t.partial))
+	for table := range t.partial {
+		partial[table] = true
+	}
+	columns := make(map[string]map[string]bool, len(t.columns))
+	for table, monitored := range t.columns {
+		columns[table] = make(map[string]bool, len(monitored))
+		for column := range monitored {
+			columns[table][column] = true
+		}
+		if rowCache, ok := cache[table]; ok {
+			rowCache.columns = cloneColumns(dbModel, table, monitored)
+		}
+	}
+	t.mutex.RUnlock()
+	return &TableCache{
+		cache:          cache,
+		eventProcessor: newEventProcessor(0, t.logger),
+		dbModel:        dbModel,
+		mutex:          sync.RWMutex{},
+		logger:         t.logger,
+		invalid:        make(map[string]bool),
+		partial:        partial,
+		columns:        columns,
+		raw:            raw,
+	}
+}
+
+// Replace replaces the rows and the model of the cache with the ones of a
+// shadow cache created by NewShadow, at once for readers. It adds the events
+// that turn the old rows into the new ones: rows that are only in the shadow
+// are added, rows that are not in it are deleted, and rows that differ are
+// updated. Rows that did not change produce no events.
+func (t *TableCache) Replace(shadow *TableCache) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	shadow.mutex.RLock()
+	defer shadow.mutex.RUnlock()
+	for table, newRows := range shadow.cache {
+		oldRows := t.cache[table]
+		if oldRows == nil {
+			for _, m := range newRows.cache {
+				t.eventProcessor.AddEvent(addEvent, table, nil, m)
+			}
+			continue
+		}
+		for uuid, oldModel := range oldRows.cache {
+			newModel, ok := newRows.cache[uuid]
+			if !ok {
+				t.eventProcessor.AddEvent(deleteEvent, table, oldModel, nil)
+			} else if !model.Equal(oldModel, newModel) {
+				t.eventProcessor.AddEvent(updateEvent, table, oldModel, newModel)
+			}
+		}
+		for uuid, newModel := range newRows.cache {
+			if _, ok := oldRows.cache[uuid]; !ok {
+				t.eventProcessor.AddEvent(addEvent, table, nil, newModel)
+			}
+		}
+	}
+	t.cache = shadow.cache
+	t.dbModel = shadow.dbModel
+	if t.raw != nil {
+		t.raw = shadow.raw
+	}
+}
+
+// AddEventHandler registers the supplied EventHandler to receive cache events.
+// Handlers are called in the order they were added, each with its own copy
+// of the models.
 func (t *TableCache) AddEventHandler(handler EventHandler) {
 	t.eventProcessor.AddEventHandler(handler)
 }
@@ -1158,6 +1623,29 @@ func newRowCache(name string, dbModel model.DatabaseModel, dataType reflect.Type
 		r.indexSpecs = append(r.indexSpecs, spec)
 		indexes[index] = spec
 	}
+	// the indexes the model was generated with might not be indexes of the
+	// schema of the server, so they are not unique like client indexes. Those
+	// with columns the schema does not have are ignored.
+	if dataType != nil {
+		if indexed, ok := reflect.New(dataType.Elem()).Interface().(model.IndexedModel); ok {
+		INDEXES:
+			for _, columns := range indexed.TableIndexes() {
+				for _, column := range columns {
+					if dbModel.Schema.Table(name).Column(column) == nil {
+						continue INDEXES
+					}
+				}
+				columnKeys := newColumnKeysFromColumns(columns...)
+				index := newIndexFromColumnKeys(columnKeys...)
+				if _, ok := indexes[index]; ok {
+					continue
+				}
+				spec := indexSpec{index: index, columns: columnKeys, indexType: clientIndexType}
+				r.indexSpecs = append(r.indexSpecs, spec)
+				indexes[index] = spec
+			}
+		}
+	}
 
 	r.indexes = r.newIndexes()
 	return r
@@ -1219,6 +1707,10 @@ func (e *eventProcessor) AddEvent(eventType string, table string, old model.Mode
 		old:       old,
 		new:       new,
 	}
+	if cap(e.events) == 0 {
+		// the events of shadow caches are discarded
+		return
+	}
 	select {
 	case e.events <- &event:
 		// noop
@@ -1239,14 +1731,21 @@ func (e *eventProcessor) Run(stopCh <-chan struct{}) {
 			return
 		case event := <-e.events:
 			e.handlersMutex.Lock()
-			for _, handler := range e.handlers {
+			for i, handler := range e.handlers {
+				old, new := event.old, event.new
+				// handlers are called in the order they were added; all but
+				// the last get their own copy of the models so that they
+				// can't modify what the next ones receive
+				if i < len(e.handlers)-1 {
+					old, new = cloneEventModel(old), cloneEventModel(new)
+				}
 				switch event.eventType {
 				case addEvent:
-					handler.OnAdd(event.table, event.new)
+					handler.OnAdd(event.table, new)
 				case updateEvent:
-					handler.OnUpdate(event.table, event.old, event.new)
+					handler.OnUpdate(event.table, old, new)
 				case deleteEvent:
-					handler.OnDelete(event.table, event.old)
+					handler.OnDelete(event.table, old)
 				}
 			}
 			e.handlersMutex.Unlock()
@@ -1254,6 +1753,13 @@ func (e *eventProcessor) Run(stopCh <-chan struct{}) {
 	}
 }
 
+func cloneEventModel(m model.Model) model.Model {
+	if m == nil {
+		return nil
+	}
+	return model.Clone(m)
+}
+
 // CreateModel creates a new Model instance based on the Row information
 func (t *TableCache) CreateModel(tableName string, row *ovsdb.Row, uuid string) (model.Model, error) {
 	if !t.dbModel.Valid() {
@@ -2,10 +2,12 @@ package cache
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"math/rand"
 	"reflect"
 	"testing"
+	"time"
 
 	"github.com/go-logr/logr"
 	"github.com/ovn-org/libovsdb/model"
@@ -349,6 +351,51 @@ func TestRowCacheCreateMultiIndex(t *testing.T) {
 	}
 }
 
+type testIndexedModel struct {
+	UUID string `ovsdb:"_uuid"`
+	Foo  string `ovsdb:"foo"`
+	Bar  string `ovsdb:"bar"`
+}
+
+func (m *testIndexedModel) TableIndexes() [][]string {
+	return [][]string{{"bar"}, {"foo"}, {"missing"}}
+}
+
+func TestRowCacheModelIndexes(t *testing.T) {
+	var schema ovsdb.DatabaseSchema
+	db, err := model.NewClientDBModel("Open_vSwitch", map[string]model.Model{"Open_vSwitch": &testIndexedModel{}})
+	require.NoError(t, err)
+	require.NoError(t, json.Unmarshal(getTestSchema(`["bar"]`), &schema))
+	dbModel, errs := model.NewDatabaseModel(schema, db)
+	require.Empty(t, errs)
+
+	tc, err := NewTableCache(dbModel, Data{
+		"Open_vSwitch": map[string]model.Model{
+			"a": &testIndexedModel{Foo: "a", Bar: "x"},
+			"b": &testIndexedModel{Foo: "b", Bar: "y"},
+		},
+	}, nil)
+	require.NoError(t, err)
+	rc := tc.Table("Open_vSwitch")
+	var indexTypes []indexType
+	for _, spec := range rc.indexSpecs {
+		indexTypes = append(indexTypes, spec.indexType)
+	}
+	// bar is an index of the schema, the model adds foo but not the index
+	// with a column the schema does not have
+	assert.Equal(t, []indexType{schemaIndexType, clientIndexType}, indexTypes)
+	_, err = rc.Index("missing")
+	assert.Error(t, err)
+
+	// like client indexes, the indexes of the model are not unique
+	require.NoError(t, rc.Create("c", &testIndexedModel{Foo: "a", Bar: "z"}, true))
+	assert.Error(t, rc.Create("d", &testIndexedModel{Foo: "d", Bar: "x"}, true))
+	index, err := rc.Index("foo")
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{"a", "c"}, index["a"])
+	assert.Equal(t, []string{"b"}, index["b"])
+}
+
 func TestRowCacheCreateMultiClientIndex(t *testing.T) {
 	type testModel struct {
 		UUID string            `ovsdb:"_uuid"`
@@ -592,6 +639,37 @@ func TestRowCacheUpdate(t *testing.T) {
 	}
 }
 
+func TestRowCacheUpdateSwapIndex(t *testing.T) {
+	var schema ovsdb.DatabaseSchema
+	db, err := model.NewClientDBModel("Open_vSwitch", map[string]model.Model{"Open_vSwitch": &testModel{}})
+	require.Nil(t, err)
+	err = json.Unmarshal(getTestSchema(`["foo"]`), &schema)
+	require.Nil(t, err)
+	testData := Data{
+		"Open_vSwitch": map[string]model.Model{
+			"a": &testModel{UUID: "a", Foo: "x"},
+			"b": &testModel{UUID: "b", Foo: "y"},
+		},
+	}
+	dbModel, errs := model.NewDatabaseModel(schema, db)
+	require.Empty(t, errs)
+	tc, err := NewTableCache(dbModel, testData, nil)
+	require.Nil(t, err)
+	rc := tc.Table("Open_vSwitch")
+
+	// both rows swap their index values in the same update
+	_, err = rc.Update("a", &testModel{UUID: "a", Foo: "y"}, false)
+	require.NoError(t, err)
+	_, err = rc.Update("b", &testModel{UUID: "b", Foo: "x"}, false)
+	require.NoError(t, err)
+
+	assert.Equal(t, newUUIDSet("a"), rc.indexes["foo"]["y"])
+	assert.Equal(t, newUUIDSet("b"), rc.indexes["foo"]["x"])
+	uuid, _, err := rc.RowByModel(&testModel{Foo: "y"})
+	require.NoError(t, err)
+	assert.Equal(t, "a", uuid)
+}
+
 func TestRowCacheUpdateClientIndex(t *testing.T) {
 	var schema ovsdb.DatabaseSchema
 	db, err := model.NewClientDBModel("Open_vSwitch", map[string]model.Model{"Open_vSwitch": &testModel{}})
@@ -1526,6 +1604,24 @@ func TestTableCachePopulate(t *testing.T) {
 
 	_, ok := tc.cache["Open_vSwitch"].cache["test"]
 	assert.False(t, ok)
+
+	t.Log("Update missing row")
+	updates["Open_vSwitch"]["test"] = &ovsdb.RowUpdate{
+		Old: &testRow,
+		New: &updatedRow,
+	}
+	err = tc.Populate(updates)
+	var errCacheInconsistent *ErrCacheInconsistent
+	require.ErrorAs(t, err, &errCacheInconsistent)
+	assert.Nil(t, tc.Table("Open_vSwitch").Row("test"))
+
+	t.Log("Delete missing row")
+	updates["Open_vSwitch"]["test"] = &ovsdb.RowUpdate{
+		Old: &updatedRow,
+		New: nil,
+	}
+	err = tc.Populate(updates)
+	require.ErrorAs(t, err, &errCacheInconsistent)
 }
 
 func TestTableCachePopulate2(t *testing.T) {
@@ -1600,6 +1696,39 @@ func TestTableCachePopulate2(t *testing.T) {
 	assert.False(t, ok)
 }
 
+func TestTableCacheUpdate2ModifyMerges(t *testing.T) {
+	db, err := model.NewClientDBModel("Open_vSwitch", map[string]model.Model{"Open_vSwitch": &testModel{}})
+	require.NoError(t, err)
+	var schema ovsdb.DatabaseSchema
+	err = json.Unmarshal(getTestSchema(""), &schema)
+	require.NoError(t, err)
+	dbModel, errs := model.NewDatabaseModel(schema, db)
+	require.Empty(t, errs)
+	tc, err := NewTableCache(dbModel, nil, nil)
+	require.NoError(t, err)
+
+	array, err := ovsdb.NewOvsSet([]string{"a", "b"})
+	require.NoError(t, err)
+	initial := ovsdb.Row{"foo": "foo", "bar": "bar", "baz": 1, "array": array}
+	err = tc.Update2(nil, ovsdb.TableUpdates2{"Open_vSwitch": {"test": &ovsdb.RowUpdate2{Initial: &initial}}})
+	require.NoError(t, err)
+
+	// a modify only carries the columns that changed, the others are kept
+	modify := ovsdb.Row{"bar": "quux"}
+	err = tc.Update2(nil, ovsdb.TableUpdates2{"Open_vSwitch": {"test": &ovsdb.RowUpdate2{Modify: &modify}}})
+	require.NoError(t, err)
+	assert.Equal(t, &testModel{UUID: "test", Foo: "foo", Bar: "quux", Baz: 1, Array: []string{"a", "b"}}, tc.Table("Open_vSwitch").Row("test"))
+
+	// the modify of a set is the difference with the old value: elements
+	// that are in both are removed, the others are added
+	diff, err := ovsdb.NewOvsSet([]string{"b", "c"})
+	require.NoError(t, err)
+	modify = ovsdb.Row{"array": diff}
+	err = tc.Update2(nil, ovsdb.TableUpdates2{"Open_vSwitch": {"test": &ovsdb.RowUpdate2{Modify: &modify}}})
+	require.NoError(t, err)
+	assert.Equal(t, &testModel{UUID: "test", Foo: "foo", Bar: "quux", Baz: 1, Array: []string{"a", "c"}}, tc.Table("Open_vSwitch").Row("test"))
+}
+
 // ovsdb-server can break index uniqueness inside a monitor update
 // the cache needs to be able to recover from this
 func TestTableCachePopulate2BrokenIndexes(t *testing.T) {
@@ -1695,6 +1824,74 @@ func TestEventProcessor_AddEvent(t *testing.T) {
 	assert.Equal(t, 0, len(ep.events))
 }
 
+func TestTableCacheEventHandlers(t *testing.T) {
+	db, err := model.NewClientDBModel("Open_vSwitch", map[string]model.Model{"Open_vSwitch": &testModel{}})
+	require.NoError(t, err)
+	var schema ovsdb.DatabaseSchema
+	err = json.Unmarshal(getTestSchema(`["foo"]`), &schema)
+	require.NoError(t, err)
+	dbModel, errs := model.NewDatabaseModel(schema, db)
+	require.Empty(t, errs)
+	tc, err := NewTableCache(dbModel, nil, nil)
+	require.NoError(t, err)
+
+	type call struct {
+		handler string
+		event   string
+		old     model.Model
+		new     model.Model
+	}
+	calls := make(chan call, 16)
+	recorder := func(name string) EventHandler {
+		return &EventHandlerFuncs{
+			AddFunc: func(_ string, m model.Model) {
+				calls <- call{name, addEvent, nil, model.Clone(m)}
+				// modifying the model must not affect other handlers
+				m.(*testModel).Foo = "modified"
+			},
+			UpdateFunc: func(_ string, old, new model.Model) {
+				calls <- call{name, updateEvent, model.Clone(old), model.Clone(new)}
+				new.(*testModel).Foo = "modified"
+			},
+			DeleteFunc: func(_ string, m model.Model) {
+				calls <- call{name, deleteEvent, model.Clone(m), nil}
+				m.(*testModel).Foo = "modified"
+			},
+		}
+	}
+	tc.AddEventHandler(recorder("first"))
+	tc.AddEventHandler(recorder("second"))
+
+	stopCh := make(chan struct{})
+	defer close(stopCh)
+	go tc.Run(stopCh)
+
+	expectCalls := func(event string, old, new model.Model) {
+		for _, handler := range []string{"first", "second"} {
+			select {
+			case c := <-calls:
+				assert.Equal(t, call{handler, event, old, new}, c)
+			case <-time.After(time.Second):
+				t.Fatalf("timed out waiting for %s event on %s handler", event, handler)
+			}
+		}
+	}
+
+	insert := ovsdb.Row{"foo": "bar"}
+	err = tc.Populate2(ovsdb.TableUpdates2{"Open_vSwitch": {"test": &ovsdb.RowUpdate2{Insert: &insert}}})
+	require.NoError(t, err)
+	expectCalls(addEvent, nil, &testModel{UUID: "test", Foo: "bar"})
+
+	modify := ovsdb.Row{"foo": "baz"}
+	err = tc.Populate2(ovsdb.TableUpdates2{"Open_vSwitch": {"test": &ovsdb.RowUpdate2{Modify: &modify}}})
+	require.NoError(t, err)
+	expectCalls(updateEvent, &testModel{UUID: "test", Foo: "bar"}, &testModel{UUID: "test", Foo: "baz"})
+
+	err = tc.Populate2(ovsdb.TableUpdates2{"Open_vSwitch": {"test": &ovsdb.RowUpdate2{Delete: &ovsdb.Row{}}}})
+	require.NoError(t, err)
+	expectCalls(deleteEvent, &testModel{UUID: "test", Foo: "baz"}, nil)
+}
+
 func TestIndex(t *testing.T) {
 	db, err := model.NewClientDBModel("Open_vSwitch", map[string]model.Model{"Open_vSwitch": &testModel{}})
 	assert.Nil(t, err)
@@ -1990,6 +2187,44 @@ func BenchmarkPopulate2UpdateArray(b *testing.B) {
 	}
 }
 
+func BenchmarkPopulate2MonitoredColumns(b *testing.B) {
+	const numRows int = 500
+
+	array := make([]string, 0, 50)
+	for i := 0; i < cap(array); i++ {
+		array = append(array, fmt.Sprintf("value%d", i))
+	}
+	for _, columns := range [][]string{nil, {"foo", "baz"}} {
+		b.Run(fmt.Sprintf("columns %v", columns), func(b *testing.B) {
+			_, tc := newTestColumnsCache(b)
+			tc.MonitoredColumns("Open_vSwitch", columns...)
+			rc := tc.Table("Open_vSwitch")
+			for i := 0; i < numRows; i++ {
+				uuid := fmt.Sprintf("%d", i)
+				model := &testColumnsModel{UUID: uuid, Foo: uuid, Bar: uuid, Array: array}
+				err := rc.Create(uuid, model, true)
+				require.NoError(b, err)
+			}
+
+			b.ReportAllocs()
+			b.ResetTimer()
+			for n := 0; n < b.N; n++ {
+				for i := 0; i < numRows; i++ {
+					updatedRow := ovsdb.Row(map[string]interface{}{"baz": n})
+					err := tc.Populate2(ovsdb.TableUpdates2{
+						"Open_vSwitch": {
+							fmt.Sprintf("%d", i): &ovsdb.RowUpdate2{
+								Modify: &updatedRow,
+							},
+						},
+					})
+					require.NoError(b, err)
+				}
+			}
+		})
+	}
+}
+
 func BenchmarkTableCacheApplyModificationsSet(b *testing.B) {
 	type testDBModel struct {
 		UUID string   `ovsdb:"_uuid"`
@@ -2317,6 +2552,509 @@ func TestTableCacheApplyModifications(t *testing.T) {
 	}
 }
 
+func TestTableCacheInvalidate(t *testing.T) {
+	db, err := model.NewClientDBModel("Open_vSwitch", map[string]model.Model{"Open_vSwitch": &testModel{}})
+	require.NoError(t, err)
+	var schema ovsdb.DatabaseSchema
+	err = json.Unmarshal(getTestSchema(""), &schema)
+	require.NoError(t, err)
+	dbModel, errs := model.NewDatabaseModel(schema, db)
+	require.Empty(t, errs)
+	tc, err := NewTableCache(dbModel, nil, nil)
+	require.NoError(t, err)
+
+	initial := ovsdb.Row{"foo": "foo"}
+	updates := ovsdb.TableUpdates2{"Open_vSwitch": {"test": &ovsdb.RowUpdate2{Initial: &initial}}}
+	require.NoError(t, tc.Update2(nil, updates))
+	require.NoError(t, tc.CheckValid("Open_vSwitch"))
+
+	tc.Invalidate("Open_vSwitch")
+	var errCacheInvalid *ErrCacheInvalid
+	err = tc.CheckValid("Open_vSwitch")
+	require.True(t, errors.As(err, &errCacheInvalid), "unexpected error %v", err)
+	assert.Equal(t, "cache of table Open_vSwitch is invalid, it must be monitored again", err.Error())
+	assert.Equal(t, 1, tc.Table("Open_vSwitch").Len())
+
+	// revalidating drops the stale rows so that they can be populated again,
+	// with a delete event for each of them
+	for len(tc.eventProcessor.events) > 0 {
+		<-tc.eventProcessor.events
+	}
+	tc.Revalidate("Open_vSwitch")
+	require.NoError(t, tc.CheckValid("Open_vSwitch"))
+	assert.Equal(t, 0, tc.Table("Open_vSwitch").Len())
+	require.Equal(t, 1, len(tc.eventProcessor.events))
+	ev := <-tc.eventProcessor.events
+	assert.Equal(t, deleteEvent, ev.eventType)
+	assert.Equal(t, &testModel{UUID: "test", Foo: "foo"}, ev.old)
+	require.NoError(t, tc.Update2(nil, updates))
+	assert.Equal(t, &testModel{UUID: "test", Foo: "foo"}, tc.Table("Open_vSwitch").Row("test"))
+
+	// valid tables are left untouched
+	for len(tc.eventProcessor.events) > 0 {
+		<-tc.eventProcessor.events
+	}
+	tc.Revalidate("Open_vSwitch")
+	assert.Equal(t, 1, tc.Table("Open_vSwitch").Len())
+	assert.Equal(t, 0, len(tc.eventProcessor.events))
+
+	// the dropped tables have no stale rows left to delete
+	tc.Drop(false, "Open_vSwitch")
+	tc.Revalidate("Open_vSwitch")
+	assert.Equal(t, 0, len(tc.eventProcessor.events))
+}
+
+func TestTableCacheDrop(t *testing.T) {
+	db, err := model.NewClientDBModel("Open_vSwitch", map[string]model.Model{"Open_vSwitch": &testModel{}})
+	require.NoError(t, err)
+	var schema ovsdb.DatabaseSchema
+	err = json.Unmarshal(getTestSchema(""), &schema)
+	require.NoError(t, err)
+	dbModel, errs := model.NewDatabaseModel(schema, db)
+	require.Empty(t, errs)
+
+	for _, deleteEvents := range []bool{false, true} {
+		tc, err := NewTableCache(dbModel, nil, nil)
+		require.NoError(t, err)
+		initial := ovsdb.Row{"foo": "foo"}
+		updates := ovsdb.TableUpdates2{"Open_vSwitch": {"test": &ovsdb.RowUpdate2{Initial: &initial}}}
+		require.NoError(t, tc.Populate2(updates))
+		<-tc.eventProcessor.events
+
+		tc.Drop(deleteEvents, "Open_vSwitch")
+		assert.Equal(t, 0, tc.Table("Open_vSwitch").Len())
+		var errCacheInvalid *ErrCacheInvalid
+		err = tc.CheckValid("Open_vSwitch")
+		assert.True(t, errors.As(err, &errCacheInvalid), "unexpected error %v", err)
+		if deleteEvents {
+			require.Equal(t, 1, len(tc.eventProcessor.events))
+			ev := <-tc.eventProcessor.events
+			assert.Equal(t, deleteEvent, ev.eventType)
+			assert.Equal(t, &testModel{UUID: "test", Foo: "foo"}, ev.old)
+		} else {
+			assert.Equal(t, 0, len(tc.eventProcessor.events))
+		}
+	}
+}
+
+func TestTableCacheReplace(t *testing.T) {
+	db, err := model.NewClientDBModel("Open_vSwitch", map[string]model.Model{"Open_vSwitch": &testModel{}})
+	require.NoError(t, err)
+	var schema ovsdb.DatabaseSchema
+	err = json.Unmarshal(getTestSchema(""), &schema)
+	require.NoError(t, err)
+	dbModel, errs := model.NewDatabaseModel(schema, db)
+	require.Empty(t, errs)
+	tc, err := NewTableCache(dbModel, nil, nil)
+	require.NoError(t, err)
+
+	rows := func(foos map[string]string) ovsdb.TableUpdates2 {
+		updates := ovsdb.TableUpdates2{"Open_vSwitch": {}}
+		for uuid, foo := range foos {
+			initial := ovsdb.Row{"foo": foo}
+			updates["Open_vSwitch"][uuid] = &ovsdb.RowUpdate2{Initial: &initial}
+		}
+		return updates
+	}
+	require.NoError(t, tc.Populate2(rows(map[string]string{"same": "same", "changed": "old", "deleted": "deleted"})))
+	for len(tc.eventProcessor.events) > 0 {
+		<-tc.eventProcessor.events
+	}
+
+	// a shadow with the same rows produces no events
+	shadow := tc.NewShadow(dbModel)
+	require.NoError(t, shadow.Populate2(rows(map[string]string{"same": "same", "changed": "old", "deleted": "deleted"})))
+	tc.Replace(shadow)
+	assert.Equal(t, 0, len(tc.eventProcessor.events))
+	assert.Equal(t, 3, tc.Table("Open_vSwitch").Len())
+
+	shadow = tc.NewShadow(dbModel)
+	require.NoError(t, shadow.Populate2(rows(map[string]string{"same": "same", "changed": "new", "added": "added"})))
+	assert.Equal(t, 0, len(shadow.eventProcessor.events))
+	tc.Replace(shadow)
+	require.Equal(t, 3, len(tc.eventProcessor.events))
+	events := map[string]*event{}
+	for i := 0; i < 3; i++ {
+		ev := <-tc.eventProcessor.events
+		events[ev.eventType] = ev
+	}
+	require.Contains(t, events, deleteEvent)
+	assert.Equal(t, &testModel{UUID: "deleted", Foo: "deleted"}, events[deleteEvent].old)
+	require.Contains(t, events, updateEvent)
+	assert.Equal(t, &testModel{UUID: "changed", Foo: "old"}, events[updateEvent].old)
+	assert.Equal(t, &testModel{UUID: "changed", Foo: "new"}, events[updateEvent].new)
+	require.Contains(t, events, addEvent)
+	assert.Equal(t, &testModel{UUID: "added", Foo: "added"}, events[addEvent].new)
+
+	assert.Equal(t, 3, tc.Table("Open_vSwitch").Len())
+	assert.Nil(t, tc.Table("Open_vSwitch").Row("deleted"))
+	assert.Equal(t, &testModel{UUID: "changed", Foo: "new"}, tc.Table("Open_vSwitch").Row("changed"))
+}
+
+func TestTableCacheRawRows(t *testing.T) {
+	type testDBModel struct {
+		UUID string `ovsdb:"_uuid"`
+		Name string `ovsdb:"name"`
+	}
+	db, err := model.NewClientDBModel("Open_vSwitch", map[string]model.Model{"Open_vSwitch": &testDBModel{}})
+	require.NoError(t, err)
+	var schema ovsdb.DatabaseSchema
+	err = json.Unmarshal([]byte(`
+	  {
+		"name": "Open_vSwitch",
+		"tables": {
+		  "Open_vSwitch": {
+			"columns": {
+			  "name": { "type": "string" },
+			  "set": { "type": { "key": { "type": "string" }, "min": 0, "max": "unlimited" } },
+			  "map": { "type": { "key": "string", "max": "unlimited", "min": 0, "value": "string" } }
+			}
+		  }
+		}
+	  }
+	`), &schema)
+	require.NoError(t, err)
+	dbModel, errs := model.NewDatabaseModel(schema, db)
+	require.Empty(t, errs)
+
+	initial := ovsdb.Row{
+		"name": "foo",
+		"set":  ovsdb.OvsSet{GoSet: []interface{}{"a", "b"}},
+		"map":  ovsdb.OvsMap{GoMap: map[interface{}]interface{}{"k1": "v1", "k2": "v2"}},
+	}
+	populate := func(tc *TableCache) {
+		require.NoError(t, tc.Populate2(ovsdb.TableUpdates2{"Open_vSwitch": {"row": &ovsdb.RowUpdate2{Initial: &initial}}}))
+	}
+
+	tc, err := NewTableCache(dbModel, nil, nil)
+	require.NoError(t, err)
+	populate(tc)
+	assert.Equal(t, &testDBModel{UUID: "row", Name: "foo"}, tc.Table("Open_vSwitch").Row("row"))
+	assert.Nil(t, tc.RawRow("Open_vSwitch", "row"))
+
+	tc, err = NewTableCache(dbModel, nil, nil, WithRawRows())
+	require.NoError(t, err)
+	populate(tc)
+	assert.Equal(t, initial, tc.RawRow("Open_vSwitch", "row"))
+	assert.Nil(t, tc.RawRow("Open_vSwitch", "other"))
+
+	// the diffs of the columns without field are applied too
+	modify := ovsdb.Row{
+		"set": ovsdb.OvsSet{GoSet: []interface{}{"a", "c"}},
+		"map": ovsdb.OvsMap{GoMap: map[interface{}]interface{}{"k1": "v1", "k2": "v3", "k3": "v3"}},
+	}
+	require.NoError(t, tc.Populate2(ovsdb.TableUpdates2{"Open_vSwitch": {"row": &ovsdb.RowUpdate2{Modify: &modify}}}))
+	raw := tc.RawRow("Open_vSwitch", "row")
+	assert.Equal(t, "foo", raw["name"])
+	assert.ElementsMatch(t, []interface{}{"b", "c"}, raw["set"].(ovsdb.OvsSet).GoSet)
+	assert.Equal(t, ovsdb.OvsMap{GoMap: map[interface{}]interface{}{"k2": "v3", "k3": "v3"}}, raw["map"])
+
+	require.NoError(t, tc.Populate2(ovsdb.TableUpdates2{"Open_vSwitch": {"row": &ovsdb.RowUpdate2{Delete: &ovsdb.Row{}}}}))
+	assert.Nil(t, tc.RawRow("Open_vSwitch", "row"))
+
+	// update notifications replace the raw row even if the model is unchanged
+	updated := ovsdb.Row{"name": "foo", "set": ovsdb.OvsSet{GoSet: []interface{}{}}}
+	require.NoError(t, tc.Populate(ovsdb.TableUpdates{"Open_vSwitch": {"row": &ovsdb.RowUpdate{New: &initial}}}))
+	assert.Equal(t, initial, tc.RawRow("Open_vSwitch", "row"))
+	require.NoError(t, tc.Populate(ovsdb.TableUpdates{"Open_vSwitch": {"row": &ovsdb.RowUpdate{Old: &initial, New: &updated}}}))
+	assert.Equal(t, updated, tc.RawRow("Open_vSwitch", "row"))
+
+	tc.Drop(false, "Open_vSwitch")
+	assert.Nil(t, tc.RawRow("Open_vSwitch", "row"))
+}
+
+func TestTableCacheList(t *testing.T) {
+	type testDBModel struct {
+		UUID string   `ovsdb:"_uuid"`
+		Name string   `ovsdb:"name"`
+		Set  []string `ovsdb:"set"`
+	}
+	db, err := model.NewClientDBModel("Open_vSwitch", map[string]model.Model{"Open_vSwitch": &testDBModel{}})
+	require.NoError(t, err)
+	var schema ovsdb.DatabaseSchema
+	err = json.Unmarshal([]byte(`
+	  {
+		"name": "Open_vSwitch",
+		"tables": {
+		  "Open_vSwitch": {
+			"columns": {
+			  "name": { "type": "string" },
+			  "set": { "type": { "key": { "type": "string" }, "min": 0, "max": "unlimited" } }
+			}
+		  }
+		}
+	  }
+	`), &schema)
+	require.NoError(t, err)
+	dbModel, errs := model.NewDatabaseModel(schema, db)
+	require.Empty(t, errs)
+	tc, err := NewTableCache(dbModel, nil, nil)
+	require.NoError(t, err)
+
+	models, err := tc.List("Open_vSwitch")
+	require.NoError(t, err)
+	assert.Empty(t, models)
+	_, err = tc.List("Bridge")
+	assert.Error(t, err)
+
+	for _, uuid := range []string{"c", "a", "b"} {
+		require.NoError(t, tc.Table("Open_vSwitch").Create(uuid, &testDBModel{UUID: uuid, Name: uuid, Set: []string{uuid}}, false))
+	}
+	models, err = tc.List("Open_vSwitch")
+	require.NoError(t, err)
+	require.Len(t, models, 3)
+	for i, uuid := range []string{"a", "b", "c"} {
+		assert.Equal(t, &testDBModel{UUID: uuid, Name: uuid, Set: []string{uuid}}, models[i])
+	}
+
+	// the rows are copies
+	first := models[0].(*testDBModel)
+	first.Name = "changed"
+	first.Set[0] = "changed"
+	assert.Equal(t, &testDBModel{UUID: "a", Name: "a", Set: []string{"a"}}, tc.Table("Open_vSwitch").Row("a"))
+
+	tc.Invalidate("Open_vSwitch")
+	_, err = tc.List("Open_vSwitch")
+	var invalid *ErrCacheInvalid
+	assert.True(t, errors.As(err, &invalid), "unexpected error %v", err)
+}
+
+func TestTableCachePartial(t *testing.T) {
+	type testDBModel struct {
+		UUID string `ovsdb:"_uuid"`
+		Name string `ovsdb:"name"`
+	}
+	db, err := model.NewClientDBModel("Open_vSwitch", map[string]model.Model{"Open_vSwitch": &testDBModel{}})
+	require.NoError(t, err)
+	var schema ovsdb.DatabaseSchema
+	err = json.Unmarshal([]byte(`
+	  {
+		"name": "Open_vSwitch",
+		"tables": {
+		  "Open_vSwitch": {
+			"columns": {
+			  "name": { "type": "string" }
+			}
+		  }
+		}
+	  }
+	`), &schema)
+	require.NoError(t, err)
+	dbModel, errs := model.NewDatabaseModel(schema, db)
+	require.Empty(t, errs)
+
+	row := ovsdb.Row{"name": "foo"}
+	updates := ovsdb.TableUpdates{"Open_vSwitch": {
+		"modified": &ovsdb.RowUpdate{Old: &row, New: &row},
+		"deleted":  &ovsdb.RowUpdate{Old: &row},
+	}}
+	updates2 := ovsdb.TableUpdates2{"Open_vSwitch": {
+		"modified": &ovsdb.RowUpdate2{Modify: &row},
+		"deleted":  &ovsdb.RowUpdate2{Delete: &ovsdb.Row{}},
+	}}
+
+	// the updates of rows not in the cache are inconsistent
+	tc, err := NewTableCache(dbModel, nil, nil)
+	require.NoError(t, err)
+	var inconsistent *ErrCacheInconsistent
+	err = tc.Populate(updates)
+	assert.True(t, errors.As(err, &inconsistent), "unexpected error %v", err)
+	err = tc.Populate2(updates2)
+	assert.True(t, errors.As(err, &inconsistent), "unexpected error %v", err)
+
+	// unless the table is monitored for some kinds of changes only
+	tc.Partial("Open_vSwitch")
+	require.NoError(t, tc.Populate(updates))
+	require.NoError(t, tc.Populate2(updates2))
+	assert.Equal(t, 0, tc.Table("Open_vSwitch").Len())
+	assert.True(t, tc.NewShadow(dbModel).partial["Open_vSwitch"])
+
+	// the rows in the cache are still updated
+	require.NoError(t, tc.Populate2(ovsdb.TableUpdates2{"Open_vSwitch": {"modified": &ovsdb.RowUpdate2{Insert: &row}}}))
+	require.NoError(t, tc.Populate2(ovsdb.TableUpdates2{"Open_vSwitch": {"modified": &ovsdb.RowUpdate2{Modify: &ovsdb.Row{"name": "bar"}}}}))
+	assert.Equal(t, &testDBModel{UUID: "modified", Name: "bar"}, tc.Table("Open_vSwitch").Row("modified"))
+	require.NoError(t, tc.Populate(ovsdb.TableUpdates{"Open_vSwitch": {"modified": &ovsdb.RowUpdate{Old: &row}}}))
+	assert.Nil(t, tc.Table("Open_vSwitch").Row("modified"))
+
+	// until the table is monitored for every kind of change again, dropped
+	// or purged
+	tc.Complete("Open_vSwitch")
+	err = tc.Populate2(updates2)
+	assert.True(t, errors.As(err, &inconsistent), "unexpected error %v", err)
+	tc.Partial("Open_vSwitch")
+	tc.Drop(false, "Open_vSwitch")
+	tc.Revalidate("Open_vSwitch")
+	err = tc.Populate2(updates2)
+	assert.True(t, errors.As(err, &inconsistent), "unexpected error %v", err)
+	tc.Partial("Open_vSwitch")
+	tc.Purge(dbModel)
+	err = tc.Populate2(updates2)
+	assert.True(t, errors.As(err, &inconsistent), "unexpected error %v", err)
+}
+
+// testColumnsModel is a testModel that copies the fields of some columns only,
+// like the generated models
+type testColumnsModel testModel
+
+func (a *testColumnsModel) CloneModel() model.Model {
+	b := *a
+	b.Array = append([]string(nil), a.Array...)
+	return &b
+}
+
+func (a *testColumnsModel) CloneModelInto(b model.Model) {
+	*b.(*testColumnsModel) = *a.CloneModel().(*testColumnsModel)
+}
+
+func (a *testColumnsModel) CloneModelColumns(columns []string) model.Model {
+	b := &testColumnsModel{UUID: a.UUID}
+	for _, column := range columns {
+		switch column {
+		case "foo":
+			b.Foo = a.Foo
+		case "bar":
+			b.Bar = a.Bar
+		case "baz":
+			b.Baz = a.Baz
+		case "array":
+			b.Array = append([]string(nil), a.Array...)
+		}
+	}
+	return b
+}
+
+func newTestColumnsCache(t require.TestingT) (model.DatabaseModel, *TableCache) {
+	var schema ovsdb.DatabaseSchema
+	db, err := model.NewClientDBModel("Open_vSwitch", map[string]model.Model{"Open_vSwitch": &testColumnsModel{}})
+	require.NoError(t, err)
+	err = json.Unmarshal(getTestSchema(""), &schema)
+	require.NoError(t, err)
+	dbModel, errs := model.NewDatabaseModel(schema, db)
+	require.Empty(t, errs)
+	tc, err := NewTableCache(dbModel, nil, nil)
+	require.NoError(t, err)
+	return dbModel, tc
+}
+
+func TestTableCacheMonitoredColumns(t *testing.T) {
+	dbModel, tc := newTestColumnsCache(t)
+	row := &testColumnsModel{UUID: "foo", Foo: "foo", Bar: "bar", Baz: 1, Array: []string{"a"}}
+	rc := tc.Table("Open_vSwitch")
+
+	// the rows of tables monitored for all their columns are cloned as a whole
+	tc.MonitoredColumns("Open_vSwitch")
+	require.NoError(t, rc.Create("foo", row, true))
+	assert.Equal(t, row, rc.Row("foo"))
+
+	// only the monitored columns are copied otherwise
+	_, tc = newTestColumnsCache(t)
+	rc = tc.Table("Open_vSwitch")
+	tc.MonitoredColumns("Open_vSwitch", "foo")
+	tc.MonitoredColumns("Open_vSwitch", "array")
+	require.NoError(t, rc.Create("foo", row, true))
+	assert.Equal(t, &testColumnsModel{UUID: "foo", Foo: "foo", Array: []string{"a"}}, rc.Row("foo"))
+	_, err := rc.Update("foo", &testColumnsModel{UUID: "foo", Foo: "bar", Bar: "bar", Array: []string{"b"}}, true)
+	require.NoError(t, err)
+	assert.Equal(t, &testColumnsModel{UUID: "foo", Foo: "bar", Array: []string{"b"}}, rc.Row("foo"))
+	shadow := tc.NewShadow(dbModel)
+	assert.Equal(t, []string{"array", "foo"}, shadow.Table("Open_vSwitch").columns)
+
+	// until another monitor monitors all the columns
+	tc.MonitoredColumns("Open_vSwitch", "bar", "baz")
+	_, err = rc.Update("foo", row, true)
+	require.NoError(t, err)
+	assert.Equal(t, row, rc.Row("foo"))
+
+	// or the table is no longer monitored
+	tc.MonitoredColumns("Open_vSwitch", "foo")
+	tc.Drop(false, "Open_vSwitch")
+	tc.Revalidate("Open_vSwitch")
+	assert.Nil(t, tc.Table("Open_vSwitch").columns)
+	assert.Empty(t, tc.columns)
+}
+
+func TestTableCachePopulate2Diffs(t *testing.T) {
+	type testDBModel struct {
+		UUID string            `ovsdb:"_uuid"`
+		Set  []string          `ovsdb:"set"`
+		Map  map[string]string `ovsdb:"map"`
+	}
+	db, err := model.NewClientDBModel("Open_vSwitch", map[string]model.Model{"Open_vSwitch": &testDBModel{}})
+	require.NoError(t, err)
+	var schema ovsdb.DatabaseSchema
+	err = json.Unmarshal([]byte(`
+	  {
+		"name": "Open_vSwitch",
+		"tables": {
+		  "Open_vSwitch": {
+			"columns": {
+			  "set": { "type": { "key": { "type": "string" }, "min": 0, "max": "unlimited" } },
+			  "map": { "type": { "key": "string", "max": "unlimited", "min": 0, "value": "string" } }
+			}
+		  }
+		}
+	  }
+	`), &schema)
+	require.NoError(t, err)
+	dbModel, errs := model.NewDatabaseModel(schema, db)
+	require.Empty(t, errs)
+	tc, err := NewTableCache(dbModel, nil, nil)
+	require.NoError(t, err)
+
+	set := func(elems ...string) ovsdb.OvsSet {
+		s, err := ovsdb.NewOvsSet(elems)
+		require.NoError(t, err)
+		return s
+	}
+	ovsMap := func(m map[string]string) ovsdb.OvsMap {
+		om, err := ovsdb.NewOvsMap(m)
+		require.NoError(t, err)
+		return om
+	}
+	initial := ovsdb.Row{"set": set("a"), "map": ovsMap(map[string]string{"foo": "bar"})}
+	err = tc.Populate2(ovsdb.TableUpdates2{"Open_vSwitch": {"test": &ovsdb.RowUpdate2{Initial: &initial}}})
+	require.NoError(t, err)
+
+	tests := []struct {
+		name     string
+		modify   ovsdb.Row
+		expected *testDBModel
+	}{
+		{
+			"set gains elements",
+			ovsdb.Row{"set": set("b", "c")},
+			&testDBModel{UUID: "test", Set: []string{"a", "b", "c"}, Map: map[string]string{"foo": "bar"}},
+		},
+		{
+			"set loses elements",
+			ovsdb.Row{"set": set("a", "c")},
+			&testDBModel{UUID: "test", Set: []string{"b"}, Map: map[string]string{"foo": "bar"}},
+		},
+		{
+			"map key added",
+			ovsdb.Row{"map": ovsMap(map[string]string{"baz": "quux"})},
+			&testDBModel{UUID: "test", Set: []string{"b"}, Map: map[string]string{"foo": "bar", "baz": "quux"}},
+		},
+		{
+			// a key with its current value toggles it out of the map
+			"map key removed",
+			ovsdb.Row{"map": ovsMap(map[string]string{"baz": "quux"})},
+			&testDBModel{UUID: "test", Set: []string{"b"}, Map: map[string]string{"foo": "bar"}},
+		},
+		{
+			"map value replaced and set emptied",
+			ovsdb.Row{"map": ovsMap(map[string]string{"foo": "baz"}), "set": set("b")},
+			&testDBModel{UUID: "test", Set: []string{}, Map: map[string]string{"foo": "baz"}},
+		},
+	}
+	for _, tt := range tests {
+		modify := tt.modify
+		err = tc.Populate2(ovsdb.TableUpdates2{"Open_vSwitch": {"test": &ovsdb.RowUpdate2{Modify: &modify}}})
+		require.NoError(t, err, tt.name)
+		assert.Equal(t, tt.expected, tc.Table("Open_vSwitch").Row("test"), tt.name)
+	}
+}
+
 func TestTableCacheRowsByModels(t *testing.T) {
 	type testModel struct {
 		UUID string            `ovsdb:"_uuid"`
@@ -5,6 +5,10 @@ The cache can be accessed using a simple API:
 
     cache.Table("Open_vSwitch").Row("<ovs-uuid>")
 
+A copy of every row of a table, e.g. for a reconciliation loop, is returned by:
+
+    cache.List("Open_vSwitch")
+
 It implements the ovsdb.NotificationHandler interface
 such that it can be populated automatically by
 update notifications
@@ -0,0 +1,29 @@
+package cache
+
+type options struct {
+	rawRows bool
+}
+
+// Option configures a TableCache
+type Option func(o *options) error
+
+func newOptions(opts ...Option) (*options, error) {
+	o := &options{}
+	for _, opt := range opts {
+		if err := opt(o); err != nil {
+			return nil, err
+		}
+	}
+	return o, nil
+}
+
+// WithRawRows tells the cache to keep the ovsdb.Row of every row it is
+// populated with, next to its model, so that the columns the model has no
+// field for are available from RawRow. It roughly doubles the memory used by
+// the cache.
+func WithRawRows() Option {
+	return func(o *options) error {
+		o.rawRows = true
+		return nil
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"reflect"
+	"sort"
 
 	"github.com/go-logr/logr"
 	"github.com/ovn-org/libovsdb/cache"
@@ -53,8 +54,24 @@ type API interface {
 	// Create returns the operation needed to add the model(s) to the Database
 	// Only fields with non-default values will be added to the transaction
 	// If the field associated with column "_uuid" has some content, it will be
-	// treated as named-uuid
+	// treated as named-uuid. The UUIDs assigned to named-uuids can be found
+	// from the transaction results with ovsdb.NamedUUIDs
 	Create(...model.Model) ([]ovsdb.Operation, error)
+
+	// CreateOrUpdate returns the operations needed to insert the model in the
+	// Database if no row has its values in the given index fields (pointers to
+	// fields in the model), or to update the row that has them otherwise. The
+	// cache decides which, and a wait operation with no timeout guards the
+	// decision, so that the transaction fails rather than create a duplicate
+	// or update the wrong row if the Database changed in the meantime.
+	// It fails if more than one row in the cache matches the index.
+	CreateOrUpdate(model.Model, ...interface{}) ([]ovsdb.Operation, error)
+
+	// UpdateChanged returns the operation needed to update the row of the old
+	// model, selected like Where does, with the values of the columns whose
+	// field differs in the new model. Sets are compared regardless of the order
+	// of their elements. It returns ErrNoChanges if no field differs.
+	UpdateChanged(old, new model.Model) (ovsdb.Operation, error)
 }
 
 // ConditionalAPI is an interface used to perform operations that require / use Conditions
@@ -75,11 +92,14 @@ type ConditionalAPI interface {
 	// the fields to be updated
 	Update(model.Model, ...interface{}) ([]ovsdb.Operation, error)
 
-	// Delete returns the Operations needed to delete the models selected via the condition
+	// Delete returns the Operations needed to delete the models selected via the condition.
+	// With WithGarbageCollectionCheck, it fails with an ErrReferencedGarbageCollectedRow if
+	// one of them is garbage collected by the server and still has a strong reference in the cache.
 	Delete() ([]ovsdb.Operation, error)
 
 	// Wait returns the operations needed to perform the wait specified
 	// by the until condition, timeout, row and columns based on provided parameters.
+	// The timeout is in milliseconds; if nil, the wait has no timeout.
 	Wait(ovsdb.WaitCondition, *int, model.Model, ...interface{}) ([]ovsdb.Operation, error)
 }
 
@@ -93,15 +113,41 @@ func (e *ErrWrongType) Error() string {
 	return fmt.Sprintf("Wrong parameter type (%s): %s", e.inputType, e.reason)
 }
 
+// ErrReferencedGarbageCollectedRow is returned by Delete, when the client is
+// configured with WithGarbageCollectionCheck, if a row of a table that the
+// server garbage collects is still referenced by a strong reference.
+// The server would fail to delete it unless the transaction removes the
+// reference, which is enough for the row to be deleted.
+type ErrReferencedGarbageCollectedRow struct {
+	Table string
+	UUID  string
+	// ReferencingTable and ReferencingColumn are the table and column of
+	// the reference
+	ReferencingTable  string
+	ReferencingColumn string
+}
+
+func (e *ErrReferencedGarbageCollectedRow) Error() string {
+	return fmt.Sprintf("row %s of table %s is garbage collected and still referenced by column %s of table %s: "+
+		"remove the reference instead of deleting the row", e.UUID, e.Table, e.ReferencingColumn, e.ReferencingTable)
+}
+
 // ErrNotFound is used to inform the object or table was not found in the cache
 var ErrNotFound = errors.New("object not found")
 
+// ErrNoChanges is returned by UpdateChanged when the new model has the same
+// values as the old one, so that there is nothing to update
+var ErrNoChanges = errors.New("no changes to update")
+
 // api struct implements both API and ConditionalAPI
 // Where() can be used to create a ConditionalAPI api
 type api struct {
 	cache  *cache.TableCache
 	cond   Conditional
 	logger *logr.Logger
+	// gcCheck makes Delete check that the deleted rows of garbage collected
+	// tables are not strongly referenced
+	gcCheck bool
 }
 
 // List populates a slice of Models given as parameter based on the configured Condition
@@ -146,6 +192,9 @@ func (a api) List(ctx context.Context, result interface{}) error {
 	if tableCache == nil {
 		return ErrNotFound
 	}
+	if err := a.cache.CheckValid(table); err != nil {
+		return err
+	}
 
 	// If given a null slice, fill it in the cache table completely, if not, just up to
 	// its capability
@@ -178,24 +227,24 @@ func (a api) List(ctx context.Context, result interface{}) error {
 // Where returns a conditionalAPI based on model indexes. All provided models
 // must be the same type.
 func (a api) Where(models ...model.Model) ConditionalAPI {
-	return newConditionalAPI(a.cache, a.conditionFromModels(models), a.logger)
+	return a.withCondition(a.conditionFromModels(models))
 }
 
 // WhereAny returns a conditionalAPI based on a Condition list that matches any
 // of the conditions individually
 func (a api) WhereAny(m model.Model, cond ...model.Condition) ConditionalAPI {
-	return newConditionalAPI(a.cache, a.conditionFromExplicitConditions(false, m, cond...), a.logger)
+	return a.withCondition(a.conditionFromExplicitConditions(false, m, cond...))
 }
 
 // WhereAll returns a conditionalAPI based on a Condition list that matches all
 // of the conditions together
 func (a api) WhereAll(m model.Model, cond ...model.Condition) ConditionalAPI {
-	return newConditionalAPI(a.cache, a.conditionFromExplicitConditions(true, m, cond...), a.logger)
+	return a.withCondition(a.conditionFromExplicitConditions(true, m, cond...))
 }
 
 // WhereCache returns a conditionalAPI based a Predicate
 func (a api) WhereCache(predicate interface{}) ConditionalAPI {
-	return newConditionalAPI(a.cache, a.conditionFromFunc(predicate), a.logger)
+	return a.withCondition(a.conditionFromFunc(predicate))
 }
 
 // Conditional interface implementation
@@ -264,6 +313,9 @@ func (a api) Get(ctx context.Context, m model.Model) error {
 	if tableCache == nil {
 		return ErrNotFound
 	}
+	if err := a.cache.CheckValid(table); err != nil {
+		return err
+	}
 
 	_, found, err := tableCache.RowByModel(m)
 	if err != nil {
@@ -301,11 +353,18 @@ func (a api) Create(models ...model.Model) ([]ovsdb.Operation, error) {
 		} else {
 			return nil, err
 		}
+		if namedUUID != "" {
+			if err := (ovsdb.UUID{GoUUID: namedUUID}).Validate(); err != nil {
+				return nil, err
+			}
+		}
 
-		row, err := a.cache.Mapper().NewRow(info)
+		row, err := a.cache.Mapper().NewInsertRow(info)
 		if err != nil {
 			return nil, err
 		}
+		// the server assigns the _uuid of new rows
+		delete(row, "_uuid")
 
 		operations = append(operations, ovsdb.Operation{
 			Op:       ovsdb.OperationInsert,
@@ -317,6 +376,81 @@ func (a api) Create(models ...model.Model) ([]ovsdb.Operation, error) {
 	return operations, nil
 }
 
+// CreateOrUpdate returns a wait operation that checks the rows matching the
+// index fields of the model are still the ones in the cache, followed by either
+// the insert of the model or the update of the only matching row
+func (a api) CreateOrUpdate(m model.Model, indexFields ...interface{}) ([]ovsdb.Operation, error) {
+	if len(indexFields) == 0 {
+		return nil, fmt.Errorf("at least one index field is required")
+	}
+	table, err := a.getTableFromModel(m)
+	if err != nil {
+		return nil, err
+	}
+	tableCache := a.cache.Table(table)
+	if tableCache == nil {
+		return nil, ErrNotFound
+	}
+	if err := a.cache.CheckValid(table); err != nil {
+		return nil, err
+	}
+	info, err := a.cache.DatabaseModel().NewModelInfo(m)
+	if err != nil {
+		return nil, err
+	}
+
+	columns := make([]string, 0, len(indexFields))
+	conditions := make([]model.Condition, 0, len(indexFields))
+	for _, f := range indexFields {
+		column, err := info.ColumnByPtr(f)
+		if err != nil {
+			return nil, err
+		}
+		columns = append(columns, column)
+		conditions = append(conditions, model.Condition{
+			Field:    f,
+			Function: ovsdb.ConditionEqual,
+			Value:    reflect.ValueOf(f).Elem().Interface(),
+		})
+	}
+	where, err := a.cache.Mapper().NewEqualityCondition(info, indexFields...)
+	if err != nil {
+		return nil, err
+	}
+	rows, err := tableCache.RowsByCondition(where)
+	if err != nil {
+		return nil, err
+	}
+
+	timeout := 0
+	wait := ovsdb.Operation{
+		Op:      ovsdb.OperationWait,
+		Table:   table,
+		Timeout: &timeout,
+		Where:   where,
+		Until:   string(ovsdb.WaitConditionEqual),
+		Rows:    []ovsdb.Row{},
+	}
+	var operations []ovsdb.Operation
+	switch len(rows) {
+	case 0:
+		wait.Columns = columns
+		operations, err = a.Create(m)
+	case 1:
+		for uuid := range rows {
+			wait.Columns = []string{"_uuid"}
+			wait.Rows = []ovsdb.Row{{"_uuid": ovsdb.UUID{GoUUID: uuid}}}
+		}
+		operations, err = a.WhereAll(m, conditions...).Update(m)
+	default:
+		return nil, fmt.Errorf("%d rows of table %s match the index %v of the model, expected at most one", len(rows), table, columns)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return append([]ovsdb.Operation{wait}, operations...), nil
+}
+
 // Mutate returns the operations needed to transform the one Model into another one
 func (a api) Mutate(model model.Model, mutationObjs ...model.Mutation) ([]ovsdb.Operation, error) {
 	var mutations []ovsdb.Mutation
@@ -350,6 +484,9 @@ func (a api) Mutate(model model.Model, mutationObjs ...model.Mutation) ([]ovsdb.
 		if err != nil {
 			return nil, err
 		}
+		if column := table.Column(col); column != nil && !column.Mutable() {
+			return nil, fmt.Errorf("unable to mutate field %s of table %s as it is not mutable", col, tableName)
+		}
 
 		mutation, err := a.cache.Mapper().NewMutation(info, col, mobj.Mutator, mobj.Value)
 		if err != nil {
@@ -392,7 +529,8 @@ func (a api) Update(model model.Model, fields ...interface{}) ([]ovsdb.Operation
 			if err != nil {
 				return nil, err
 			}
-			if !tableSchema.Columns[colName].Mutable() {
+			// _uuid is not part of the table columns and cannot be updated
+			if column, ok := tableSchema.Columns[colName]; !ok || !column.Mutable() {
 				return nil, fmt.Errorf("unable to update field %s of table %s as it is not mutable", colName, table)
 			}
 		}
@@ -433,6 +571,80 @@ func (a api) Update(model model.Model, fields ...interface{}) ([]ovsdb.Operation
 	return operations, nil
 }
 
+// UpdateChanged returns the update of the columns whose field differs between
+// the old and new models
+func (a api) UpdateChanged(old, new model.Model) (ovsdb.Operation, error) {
+	if reflect.TypeOf(old) != reflect.TypeOf(new) {
+		return ovsdb.Operation{}, fmt.Errorf("cannot compare models of types %T and %T", old, new)
+	}
+	oldInfo, err := a.cache.DatabaseModel().NewModelInfo(old)
+	if err != nil {
+		return ovsdb.Operation{}, err
+	}
+	newInfo, err := a.cache.DatabaseModel().NewModelInfo(new)
+	if err != nil {
+		return ovsdb.Operation{}, err
+	}
+	columns := make([]string, 0, len(newInfo.Metadata.Fields))
+	for column := range newInfo.Metadata.Fields {
+		if column != "_uuid" {
+			columns = append(columns, column)
+		}
+	}
+	sort.Strings(columns)
+	var fields []interface{}
+	for _, column := range columns {
+		oldValue, err := oldInfo.FieldByColumn(column)
+		if err != nil {
+			return ovsdb.Operation{}, err
+		}
+		newValue, err := newInfo.FieldByColumn(column)
+		if err != nil {
+			return ovsdb.Operation{}, err
+		}
+		if equalColumnValues(oldValue, newValue) {
+			continue
+		}
+		field := reflect.ValueOf(new).Elem().FieldByName(newInfo.Metadata.Fields[column])
+		fields = append(fields, field.Addr().Interface())
+	}
+	if len(fields) == 0 {
+		return ovsdb.Operation{}, ErrNoChanges
+	}
+	ops, err := a.Where(old).Update(new, fields...)
+	if err != nil {
+		return ovsdb.Operation{}, err
+	}
+	if len(ops) != 1 {
+		return ovsdb.Operation{}, fmt.Errorf("expected a single update operation, got %d", len(ops))
+	}
+	return ops[0], nil
+}
+
+// equalColumnValues returns whether the native values of a column are equal.
+// Slices hold the elements of sets, which are equal regardless of their order.
+func equalColumnValues(a, b interface{}) bool {
+	va, vb := reflect.ValueOf(a), reflect.ValueOf(b)
+	if va.Kind() != reflect.Slice || vb.Kind() != reflect.Slice {
+		return reflect.DeepEqual(a, b)
+	}
+	if va.Len() != vb.Len() {
+		return false
+	}
+	counts := make(map[interface{}]int, va.Len())
+	for i := 0; i < va.Len(); i++ {
+		counts[va.Index(i).Interface()]++
+	}
+	for i := 0; i < vb.Len(); i++ {
+		elem := vb.Index(i).Interface()
+		if counts[elem] == 0 {
+			return false
+		}
+		counts[elem]--
+	}
+	return true
+}
+
 // Delete returns the Operation needed to delete the selected models from the database
 func (a api) Delete() ([]ovsdb.Operation, error) {
 	var operations []ovsdb.Operation
@@ -440,6 +652,11 @@ func (a api) Delete() ([]ovsdb.Operation, error) {
 	if err != nil {
 		return nil, err
 	}
+	if a.gcCheck {
+		if err := a.checkGarbageCollected(); err != nil {
+			return nil, err
+		}
+	}
 
 	for _, condition := range conditions {
 		operations = append(operations,
@@ -454,6 +671,95 @@ func (a api) Delete() ([]ovsdb.Operation, error) {
 	return operations, nil
 }
 
+// checkGarbageCollected returns an ErrReferencedGarbageCollectedRow if one of
+// the rows of the cache selected by the condition belongs to a garbage
+// collected table and is referenced by a strong reference of a row of the cache
+func (a api) checkGarbageCollected() error {
+	if a.cache == nil {
+		return nil
+	}
+	table := a.cond.Table()
+	dbModel := a.cache.DatabaseModel()
+	if !dbModel.Schema.IsGarbageCollected(table) {
+		return nil
+	}
+	references := dbModel.Schema.StrongReferences(table)
+	if len(references) == 0 {
+		return nil
+	}
+	selected, err := a.cond.Matches()
+	if err != nil || len(selected) == 0 {
+		// the rows that are not in the cache can't be checked
+		return nil
+	}
+	tables := make([]string, 0, len(references))
+	for referencing := range references {
+		tables = append(tables, referencing)
+	}
+	sort.Strings(tables)
+	for _, referencing := range tables {
+		rowCache := a.cache.Table(referencing)
+		if rowCache == nil {
+			continue
+		}
+		columns := references[referencing]
+		sort.Strings(columns)
+		for _, row := range rowCache.RowsShallow() {
+			info, err := dbModel.NewModelInfo(row)
+			if err != nil {
+				return err
+			}
+			for _, column := range columns {
+				value, err := info.FieldByColumn(column)
+				if err != nil {
+					// the model has no field for the column
+					continue
+				}
+				for _, uuid := range referencedUUIDs(reflect.ValueOf(value)) {
+					if _, ok := selected[uuid]; ok {
+						return &ErrReferencedGarbageCollectedRow{
+							Table:             table,
+							UUID:              uuid,
+							ReferencingTable:  referencing,
+							ReferencingColumn: column,
+						}
+					}
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// referencedUUIDs returns the strings held by the value of a field, which are
+// the UUIDs of the rows it references if its column is a reference
+func referencedUUIDs(v reflect.Value) []string {
+	switch v.Kind() {
+	case reflect.String:
+		return []string{v.String()}
+	case reflect.Ptr:
+		if v.IsNil() {
+			return nil
+		}
+		return referencedUUIDs(v.Elem())
+	case reflect.Slice, reflect.Array:
+		var uuids []string
+		for i := 0; i < v.Len(); i++ {
+			uuids = append(uuids, referencedUUIDs(v.Index(i))...)
+		}
+		return uuids
+	case reflect.Map:
+		var uuids []string
+		iter := v.MapRange()
+		for iter.Next() {
+			uuids = append(uuids, referencedUUIDs(iter.Key())...)
+			uuids = append(uuids, referencedUUIDs(iter.Value())...)
+		}
+		return uuids
+	}
+	return nil
+}
+
 func (a api) Wait(untilConFun ovsdb.WaitCondition, timeout *int, model model.Model, fields ...interface{}) ([]ovsdb.Operation, error) {
 	var operations []ovsdb.Operation
 
@@ -475,6 +781,13 @@ func (a api) Wait(untilConFun ovsdb.WaitCondition, timeout *int, model model.Mod
 			)
 	*/
 
+	if untilConFun != ovsdb.WaitConditionEqual && untilConFun != ovsdb.WaitConditionNotEqual {
+		return nil, fmt.Errorf("invalid wait until condition %q", untilConFun)
+	}
+	if timeout != nil && *timeout < 0 {
+		return nil, fmt.Errorf("invalid wait timeout %d, it must not be negative", *timeout)
+	}
+
 	conditions, err := a.cond.Generate()
 	if err != nil {
 		return nil, err
@@ -575,6 +888,13 @@ func newAPI(cache *cache.TableCache, logger *logr.Logger) API {
 	}
 }
 
+// withCondition returns a ConditionalAPI with the same configuration as a
+// for the given condition
+func (a api) withCondition(cond Conditional) ConditionalAPI {
+	a.cond = cond
+	return a
+}
+
 // newConditionalAPI returns a new ConditionalAPI to interact with the database
 func newConditionalAPI(cache *cache.TableCache, cond Conditional, logger *logr.Logger) ConditionalAPI {
 	return api{
@@ -2,6 +2,8 @@ package client
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"math/rand"
 	"strings"
@@ -841,6 +843,13 @@ func TestAPICreate(t *testing.T) {
 			}},
 			err: false,
 		},
+		{
+			name: "With invalid named UUID",
+			input: []model.Model{&testLogicalSwitch{
+				UUID: "foo bar",
+			}},
+			err: true,
+		},
 	}
 	for _, tt := range test {
 		t.Run(fmt.Sprintf("ApiCreate: %s", tt.name), func(t *testing.T) {
@@ -856,6 +865,88 @@ func TestAPICreate(t *testing.T) {
 	}
 }
 
+func TestAPICreateOmitsUUID(t *testing.T) {
+	tcache := apiTestCache(t, cache.Data{
+		"Logical_Switch":      map[string]model.Model{},
+		"Logical_Switch_Port": map[string]model.Model{},
+	})
+	api := newAPI(tcache, &discardLogger)
+	// the server assigns the _uuid, so it is never part of the inserted row
+	for _, uuid := range []string{aUUID0, "foo"} {
+		ops, err := api.Create(&testLogicalSwitch{UUID: uuid, Name: "foo"})
+		assert.NoError(t, err)
+		assert.Len(t, ops, 1)
+		assert.Equal(t, ovsdb.Row{"name": "foo"}, ops[0].Row)
+	}
+}
+
+func TestAPICreateOrUpdate(t *testing.T) {
+	tcache := apiTestCache(t, cache.Data{
+		"Logical_Switch_Port": map[string]model.Model{
+			aUUID0: &testLogicalSwitchPort{UUID: aUUID0, Name: "lsp0", Type: "someType"},
+			aUUID1: &testLogicalSwitchPort{UUID: aUUID1, Name: "lsp1", Type: "someType"},
+		},
+	})
+	api := newAPI(tcache, &discardLogger)
+	timeout0 := 0
+
+	t.Run("insert when no row matches the index", func(t *testing.T) {
+		lsp := &testLogicalSwitchPort{Name: "lsp2", Type: "someOtherType"}
+		ops, err := api.CreateOrUpdate(lsp, &lsp.Name)
+		require.NoError(t, err)
+		assert.Equal(t, []ovsdb.Operation{
+			{
+				Op:      ovsdb.OperationWait,
+				Table:   "Logical_Switch_Port",
+				Timeout: &timeout0,
+				Where:   []ovsdb.Condition{{Column: "name", Function: ovsdb.ConditionEqual, Value: "lsp2"}},
+				Until:   string(ovsdb.WaitConditionEqual),
+				Columns: []string{"name"},
+				Rows:    []ovsdb.Row{},
+			},
+			{
+				Op:    ovsdb.OperationInsert,
+				Table: "Logical_Switch_Port",
+				Row:   ovsdb.Row{"name": "lsp2", "type": "someOtherType"},
+			},
+		}, ops)
+	})
+
+	t.Run("update the row that matches the index", func(t *testing.T) {
+		lsp := &testLogicalSwitchPort{Name: "lsp1", Type: "someOtherType"}
+		ops, err := api.CreateOrUpdate(lsp, &lsp.Name)
+		require.NoError(t, err)
+		assert.Equal(t, []ovsdb.Operation{
+			{
+				Op:      ovsdb.OperationWait,
+				Table:   "Logical_Switch_Port",
+				Timeout: &timeout0,
+				Where:   []ovsdb.Condition{{Column: "name", Function: ovsdb.ConditionEqual, Value: "lsp1"}},
+				Until:   string(ovsdb.WaitConditionEqual),
+				Columns: []string{"_uuid"},
+				Rows:    []ovsdb.Row{{"_uuid": ovsdb.UUID{GoUUID: aUUID1}}},
+			},
+			{
+				Op:    ovsdb.OperationUpdate,
+				Table: "Logical_Switch_Port",
+				Row:   ovsdb.Row{"name": "lsp1", "type": "someOtherType"},
+				Where: []ovsdb.Condition{{Column: "_uuid", Function: ovsdb.ConditionEqual, Value: ovsdb.UUID{GoUUID: aUUID1}}},
+			},
+		}, ops)
+	})
+
+	t.Run("fail when several rows match the index", func(t *testing.T) {
+		lsp := &testLogicalSwitchPort{Name: "lsp2", Type: "someType"}
+		_, err := api.CreateOrUpdate(lsp, &lsp.Type)
+		assert.ErrorContains(t, err, "2 rows of table Logical_Switch_Port match the index [type] of the model")
+	})
+
+	t.Run("fail without index fields", func(t *testing.T) {
+		_, err := api.CreateOrUpdate(&testLogicalSwitchPort{Name: "lsp2"})
+		assert.Error(t, err)
+	})
+}
+
 func TestAPIMutate(t *testing.T) {
 	lspCache := map[string]model.Model{
 		aUUID0: &testLogicalSwitchPort{
@@ -1455,6 +1546,28 @@ func TestAPIUpdate(t *testing.T) {
 			},
 			err: true,
 		},
+		{
+			name: "fails if a field does not belong to the model",
+			condition: func(a API) ConditionalAPI {
+				return a.Where(&testLogicalSwitchPort{UUID: aUUID0})
+			},
+			prepare: func(t *testLogicalSwitchPort) {
+				t.Type = "somethingElse"
+			},
+			fields: []interface{}{&testObj},
+			err:    true,
+		},
+		{
+			name: "fails if the field is the UUID",
+			condition: func(a API) ConditionalAPI {
+				return a.Where(&testLogicalSwitchPort{UUID: aUUID0})
+			},
+			prepare: func(t *testLogicalSwitchPort) {
+				t.UUID = aUUID1
+			},
+			fields: []interface{}{&testObj.UUID},
+			err:    true,
+		},
 	}
 	for _, tt := range test {
 		t.Run(fmt.Sprintf("ApiUpdate: %s", tt.name), func(t *testing.T) {
@@ -1476,6 +1589,93 @@ func TestAPIUpdate(t *testing.T) {
 	}
 }
 
+// testEmptyLogicalSwitch tells that its external_ids are empty when nil
+type testEmptyLogicalSwitch struct {
+	UUID        string            `ovsdb:"_uuid"`
+	Name        string            `ovsdb:"name"`
+	ExternalIDs map[string]string `ovsdb:"external_ids"`
+}
+
+func (ls *testEmptyLogicalSwitch) IsEmpty(column string) bool {
+	if column == "external_ids" {
+		return ls.ExternalIDs == nil
+	}
+	return false
+}
+
+func TestAPIEmptyDetector(t *testing.T) {
+	var schema ovsdb.DatabaseSchema
+	require.NoError(t, json.Unmarshal(apiTestSchema, &schema))
+	db, err := model.NewClientDBModel("OVN_Northbound", map[string]model.Model{"Logical_Switch": &testEmptyLogicalSwitch{}})
+	require.NoError(t, err)
+	dbModel, errs := model.NewDatabaseModel(schema, db)
+	require.Empty(t, errs)
+	tcache, err := cache.NewTableCache(dbModel, cache.Data{
+		"Logical_Switch": {
+			aUUID0: &testEmptyLogicalSwitch{UUID: aUUID0, Name: "ls0"},
+		},
+	}, nil)
+	require.NoError(t, err)
+	api := newAPI(tcache, &discardLogger)
+
+	// the zero values of the columns that are not empty are inserted
+	ops, err := api.Create(&testEmptyLogicalSwitch{ExternalIDs: map[string]string{}})
+	require.NoError(t, err)
+	require.Len(t, ops, 1)
+	assert.Equal(t, ovsdb.Row{
+		"name":         "",
+		"external_ids": ovsdb.OvsMap{GoMap: map[interface{}]interface{}{}},
+	}, ops[0].Row)
+
+	// updates without fields still leave out the zero values
+	ops, err = api.Where(&testEmptyLogicalSwitch{UUID: aUUID0}).Update(&testEmptyLogicalSwitch{
+		UUID:        aUUID0,
+		ExternalIDs: map[string]string{"k": "v"},
+	})
+	require.NoError(t, err)
+	require.Len(t, ops, 1)
+	assert.Equal(t, ovsdb.Row{
+		"external_ids": ovsdb.OvsMap{GoMap: map[interface{}]interface{}{"k": "v"}},
+	}, ops[0].Row)
+}
+
+func TestAPIImmutableColumn(t *testing.T) {
+	var schema ovsdb.DatabaseSchema
+	require.NoError(t, json.Unmarshal(apiTestSchema, &schema))
+	var immutable ovsdb.ColumnSchema
+	require.NoError(t, json.Unmarshal([]byte(`{"type": "string", "mutable": false}`), &immutable))
+	schema.Tables["Logical_Switch_Port"].Columns["type"] = &immutable
+
+	lsp := &testLogicalSwitchPort{UUID: aUUID0, Name: "lsp0", Type: "someType", ExternalIds: map[string]string{}}
+	tcache := apiTestCacheWithSchema(t, schema, cache.Data{
+		"Logical_Switch":      map[string]model.Model{},
+		"Logical_Switch_Port": map[string]model.Model{aUUID0: lsp},
+	})
+	api := newAPI(tcache, &discardLogger)
+
+	t.Run("update explicit immutable field", func(t *testing.T) {
+		obj := &testLogicalSwitchPort{UUID: aUUID0, Type: "otherType"}
+		_, err := api.Where(obj).Update(obj, &obj.Type)
+		require.EqualError(t, err, "unable to update field type of table Logical_Switch_Port as it is not mutable")
+	})
+	t.Run("update ignores immutable fields", func(t *testing.T) {
+		obj := &testLogicalSwitchPort{UUID: aUUID0, Name: "lsp0", Type: "otherType"}
+		ops, err := api.Where(obj).Update(obj)
+		require.NoError(t, err)
+		require.Len(t, ops, 1)
+		assert.NotContains(t, ops[0].Row, "type")
+	})
+	t.Run("mutate immutable field", func(t *testing.T) {
+		obj := &testLogicalSwitchPort{UUID: aUUID0}
+		_, err := api.Where(obj).Mutate(obj, model.Mutation{
+			Field:   &obj.Type,
+			Mutator: ovsdb.MutateOperationInsert,
+			Value:   "otherType",
+		})
+		require.EqualError(t, err, "unable to mutate field type of table Logical_Switch_Port as it is not mutable")
+	})
+}
+
 func TestAPIDelete(t *testing.T) {
 	lspCache := map[string]model.Model{
 		aUUID0: &testLogicalSwitchPort{
@@ -1697,6 +1897,60 @@ func TestAPIDelete(t *testing.T) {
 	}
 }
 
+func TestAPIDeleteGarbageCollected(t *testing.T) {
+	testData := func() cache.Data {
+		return cache.Data{
+			"Logical_Switch": {
+				aUUID3: &testLogicalSwitch{UUID: aUUID3, Name: "ls", Ports: []string{aUUID0}},
+			},
+			"Logical_Switch_Port": {
+				aUUID0: &testLogicalSwitchPort{UUID: aUUID0, Name: "lsp0"},
+				aUUID1: &testLogicalSwitchPort{UUID: aUUID1, Name: "lsp1"},
+			},
+		}
+	}
+	// by default, the rows are deleted without checking the references, as
+	// the transaction may remove them too
+	ops, err := newAPI(apiTestCache(t, testData()), &discardLogger).Where(&testLogicalSwitchPort{Name: "lsp0"}).Delete()
+	require.NoError(t, err)
+	assert.Len(t, ops, 1)
+
+	api := api{cache: apiTestCache(t, testData()), logger: &discardLogger, gcCheck: true}
+
+	// Logical_Switch_Port rows are garbage collected, and the ports of
+	// Logical_Switch are strong references
+	_, err = api.Where(&testLogicalSwitchPort{Name: "lsp0"}).Delete()
+	var gcErr *ErrReferencedGarbageCollectedRow
+	require.True(t, errors.As(err, &gcErr), "unexpected error %v", err)
+	assert.Equal(t, &ErrReferencedGarbageCollectedRow{
+		Table:             "Logical_Switch_Port",
+		UUID:              aUUID0,
+		ReferencingTable:  "Logical_Switch",
+		ReferencingColumn: "ports",
+	}, gcErr)
+	_, err = api.WhereCache(func(*testLogicalSwitchPort) bool { return true }).Delete()
+	assert.True(t, errors.As(err, &gcErr), "unexpected error %v", err)
+
+	ops, err = api.Where(&testLogicalSwitchPort{UUID: aUUID1}).Delete()
+	require.NoError(t, err)
+	assert.Len(t, ops, 1)
+	ops, err = api.Where(&testLogicalSwitch{UUID: aUUID3}).Delete()
+	require.NoError(t, err)
+	assert.Len(t, ops, 1)
+
+	// weak references don't keep rows from being deleted
+	weakSchema := strings.Replace(string(apiTestSchema), `"refTable": "Logical_Switch_Port",
+                                           "refType": "strong"`, `"refTable": "Logical_Switch_Port",
+                                           "refType": "weak"`, 1)
+	require.NotEqual(t, string(apiTestSchema), weakSchema)
+	var schema ovsdb.DatabaseSchema
+	require.NoError(t, json.Unmarshal([]byte(weakSchema), &schema))
+	api.cache = apiTestCacheWithSchema(t, schema, testData())
+	ops, err = api.Where(&testLogicalSwitchPort{Name: "lsp0"}).Delete()
+	require.NoError(t, err)
+	assert.Len(t, ops, 1)
+}
+
 func BenchmarkAPIList(b *testing.B) {
 	const numRows = 10000
 
@@ -1842,6 +2096,7 @@ func BenchmarkAPIListMultiple(b *testing.B) {
 func TestAPIWait(t *testing.T) {
 	tcache := apiTestCache(t, cache.Data{})
 	timeout0 := 0
+	timeoutNegative := -1
 
 	test := []struct {
 		name      string
@@ -1963,6 +2218,31 @@ func TestAPIWait(t *testing.T) {
 			},
 			err: false,
 		},
+		{
+			name: "invalid until condition",
+			condition: func(a API) ConditionalAPI {
+				return a.Where(&testLogicalSwitchPort{Name: "lsp0"})
+			},
+			until: "<",
+			prepare: func() (model.Model, []interface{}) {
+				testLSP := testLogicalSwitchPort{Name: "lsp0"}
+				return &testLSP, nil
+			},
+			err: true,
+		},
+		{
+			name: "negative timeout",
+			condition: func(a API) ConditionalAPI {
+				return a.Where(&testLogicalSwitchPort{Name: "lsp0"})
+			},
+			until:   "==",
+			timeout: &timeoutNegative,
+			prepare: func() (model.Model, []interface{}) {
+				testLSP := testLogicalSwitchPort{Name: "lsp0"}
+				return &testLSP, nil
+			},
+			err: true,
+		},
 		{
 			name: "non-indexed condition error",
 			condition: func(a API) ConditionalAPI {
@@ -2016,3 +2296,74 @@ func TestAPIWait(t *testing.T) {
 		})
 	}
 }
+
+func TestAPIUpdateChanged(t *testing.T) {
+	old := &testLogicalSwitchPort{
+		UUID:        aUUID0,
+		Name:        "lsp0",
+		Type:        "someType",
+		Addresses:   []string{"a", "b"},
+		ExternalIds: map[string]string{"foo": "bar"},
+		Tag:         &one,
+	}
+	tcache := apiTestCache(t, cache.Data{
+		"Logical_Switch_Port": map[string]model.Model{aUUID0: old},
+	})
+	api := newAPI(tcache, &discardLogger)
+	uuidCondition := []ovsdb.Condition{{Column: "_uuid", Function: ovsdb.ConditionEqual, Value: ovsdb.UUID{GoUUID: aUUID0}}}
+	clone := func() *testLogicalSwitchPort {
+		return model.Clone(old).(*testLogicalSwitchPort)
+	}
+
+	tests := []struct {
+		name    string
+		prepare func(*testLogicalSwitchPort)
+		row     ovsdb.Row
+		err     error
+	}{
+		{
+			name:    "one field changed",
+			prepare: func(lsp *testLogicalSwitchPort) { lsp.Type = "someOtherType" },
+			row:     ovsdb.Row{"type": "someOtherType"},
+		},
+		{
+			name:    "set gained an element",
+			prepare: func(lsp *testLogicalSwitchPort) { lsp.Addresses = append(lsp.Addresses, "c") },
+			row:     ovsdb.Row{"addresses": testOvsSet(t, []string{"a", "b", "c"})},
+		},
+		{
+			name: "set reordered and map changed",
+			prepare: func(lsp *testLogicalSwitchPort) {
+				lsp.Addresses = []string{"b", "a"}
+				lsp.ExternalIds = map[string]string{"foo": "baz"}
+			},
+			row: ovsdb.Row{"external_ids": testOvsMap(t, map[string]string{"foo": "baz"})},
+		},
+		{
+			name:    "nothing changed",
+			prepare: func(*testLogicalSwitchPort) {},
+			err:     ErrNoChanges,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			updated := clone()
+			tt.prepare(updated)
+			op, err := api.UpdateChanged(old, updated)
+			if tt.err != nil {
+				assert.True(t, errors.Is(err, tt.err), "unexpected error %v", err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, ovsdb.Operation{
+				Op:    ovsdb.OperationUpdate,
+				Table: "Logical_Switch_Port",
+				Row:   tt.row,
+				Where: uuidCondition,
+			}, op)
+		})
+	}
+
+	_, err := api.UpdateChanged(old, &testLogicalSwitch{UUID: aUUID0})
+	assert.Error(t, err)
+}
@@ -157,6 +157,10 @@ func apiTestCache(t testing.TB, data map[string]map[string]model.Model) *cache.T
 	var schema ovsdb.DatabaseSchema
 	err := json.Unmarshal(apiTestSchema, &schema)
 	assert.Nil(t, err)
+	return apiTestCacheWithSchema(t, schema, data)
+}
+
+func apiTestCacheWithSchema(t testing.TB, schema ovsdb.DatabaseSchema, data map[string]map[string]model.Model) *cache.TableCache {
 	db, err := model.NewClientDBModel("OVN_Northbound", map[string]model.Model{"Logical_Switch": &testLogicalSwitch{}, "Logical_Switch_Port": &testLogicalSwitchPort{}})
 	assert.Nil(t, err)
 	dbModel, errs := model.NewDatabaseModel(schema, db)
@@ -6,11 +6,13 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
 	"log"
 	"net"
 	"net/url"
 	"os"
 	"reflect"
+	"sort"
 	"strings"
 	"sync"
 	"time"
@@ -20,6 +22,7 @@ import (
 	"github.com/cenkalti/rpc2/jsonrpc"
 	"github.com/go-logr/logr"
 	"github.com/go-logr/stdr"
+	"github.com/google/uuid"
 	"github.com/ovn-org/libovsdb/cache"
 	"github.com/ovn-org/libovsdb/mapper"
 	"github.com/ovn-org/libovsdb/model"
@@ -36,15 +39,102 @@ const (
 
 const serverDB = "_Server"
 
+// ConnectionState is the state of the connection of a client to the server
+type ConnectionState int
+
+const (
+	// Disconnected means that the client is not connected and does not try
+	// to connect to the server
+	Disconnected ConnectionState = iota
+	// Connecting means that the client is connecting, or reconnecting after
+	// the connection to the server was lost
+	Connecting
+	// Connected means that the client is connected and its monitors are
+	// running
+	Connected
+)
+
+func (s ConnectionState) String() string {
+	switch s {
+	case Disconnected:
+		return "disconnected"
+	case Connecting:
+		return "connecting"
+	case Connected:
+		return "connected"
+	}
+	return fmt.Sprintf("ConnectionState(%d)", int(s))
+}
+
 // ErrNotConnected is an error returned when the client is not connected
 var ErrNotConnected = errors.New("not connected")
 
+// ErrDisconnected is an error returned by the rpc calls that are rejected, or
+// aborted, while the client is disconnecting with DisconnectContext
+var ErrDisconnected = errors.New("client disconnected")
+
 // ErrAlreadyConnected is an error returned when the client is already connected
 var ErrAlreadyConnected = errors.New("already connected")
 
+// ErrConnUsed is an error returned when a client created with NewClientFromConn
+// connects again once its connection was used
+var ErrConnUsed = errors.New("connection already used")
+
 // ErrUnsupportedRPC is an error returned when an unsupported RPC method is called
 var ErrUnsupportedRPC = errors.New("unsupported rpc")
 
+// ErrDatabaseValidation is an error returned when the schema of a database
+// offered by the server does not match the client database model. The
+// individual validation errors can be inspected with errors.As, e.g. to look for
+// a model.ErrTableNotFound
+type ErrDatabaseValidation struct {
+	database string
+	errors   []error
+}
+
+// Error implements the error interface
+func (e *ErrDatabaseValidation) Error() string {
+	var combined []string
+	for _, err := range e.errors {
+		combined = append(combined, err.Error())
+	}
+	return fmt.Sprintf("database %s validation error (%d): %s",
+		e.database, len(e.errors), strings.Join(combined, ". "))
+}
+
+// Errors returns the individual validation errors
+func (e *ErrDatabaseValidation) Errors() []error {
+	return e.errors
+}
+
+// As finds the first validation error that matches target
+func (e *ErrDatabaseValidation) As(target interface{}) bool {
+	for _, err := range e.errors {
+		if errors.As(err, target) {
+			return true
+		}
+	}
+	return false
+}
+
+// ErrTLSHandshake is an error returned when the TLS handshake with an ssl
+// endpoint fails, as opposed to a failure later on while negotiating the
+// databases and their schemas
+type ErrTLSHandshake struct {
+	endpoint string
+	err      error
+}
+
+// Error implements the error interface
+func (e *ErrTLSHandshake) Error() string {
+	return fmt.Sprintf("tls handshake with %s failed: %v", e.endpoint, e.err)
+}
+
+// Unwrap returns the underlying handshake error
+func (e *ErrTLSHandshake) Unwrap() error {
+	return e.err
+}
+
 // Client represents an OVSDB Client Connection
 // It provides all the necessary functionality to Connect to a server,
 // perform transactions, and build your own replica of the database with
@@ -53,18 +143,36 @@ var ErrUnsupportedRPC = errors.New("unsupported rpc")
 type Client interface {
 	Connect(context.Context) error
 	Disconnect()
+	DisconnectContext(context.Context) error
 	Close()
 	Schema() ovsdb.DatabaseSchema
 	Cache() *cache.TableCache
 	SetOption(Option) error
 	Connected() bool
+	State() ConnectionState
 	DisconnectNotify() chan struct{}
+	ReconnectNotify() chan struct{}
+	OnMonitorCanceled(func(dbName string))
+	Lock(ctx context.Context, id string) (<-chan struct{}, error)
+	Steal(ctx context.Context, id string) error
+	Unlock(ctx context.Context, id string) error
+	HasLock(id string) bool
+	OnLockStolen(func(id string))
 	Echo(context.Context) error
+	Ping(context.Context) (time.Duration, error)
+	ListDatabases(context.Context) ([]string, error)
+	GetSchema(ctx context.Context, dbName string) (*ovsdb.DatabaseSchema, error)
 	Transact(context.Context, ...ovsdb.Operation) ([]ovsdb.OperationResult, error)
+	TransactWithRetry(ctx context.Context, maxAttempts int, operation ...ovsdb.Operation) ([]ovsdb.OperationResult, error)
+	DryRun(...ovsdb.Operation) (Diff, error)
+	Select(ctx context.Context, table string, conditions []ovsdb.Condition, columns []string) ([]model.Model, error)
 	Monitor(context.Context, *Monitor) (MonitorCookie, error)
 	MonitorAll(context.Context) (MonitorCookie, error)
+	MonitorOnce(context.Context, *Monitor) (*cache.TableCache, error)
 	MonitorCancel(ctx context.Context, cookie MonitorCookie) error
+	MonitorCondChange(ctx context.Context, cookie MonitorCookie, table string, conditions []ovsdb.Condition) error
 	NewMonitor(...MonitorOption) *Monitor
+	NewTransaction() *Transaction
 	CurrentEndpoint() string
 	API
 }
@@ -73,6 +181,7 @@ type bufferedUpdate struct {
 	updates   *ovsdb.TableUpdates
 	updates2  *ovsdb.TableUpdates2
 	lastTxnID string
+	monitorID string
 }
 
 type epInfo struct {
@@ -87,6 +196,14 @@ type ovsdbClient struct {
 	connected bool
 	rpcClient *rpc2.Client
 	rpcMutex  sync.RWMutex
+	// inflight contains the method of every rpc call waiting for its reply
+	inflight      map[uint64]string
+	inflightSeq   uint64
+	inflightMutex sync.Mutex
+	// draining is set while DisconnectContext waits for the inflight calls,
+	// and drained is closed once there are none left
+	draining bool
+	drained  chan struct{}
 	// endpoints contains all possible endpoints; the first element is
 	// the active endpoint if connected=true
 	endpoints []*epInfo
@@ -99,10 +216,37 @@ type ovsdbClient struct {
 	errorCh       chan error
 	stopCh        chan struct{}
 	disconnect    chan struct{}
+	reconnected   chan struct{}
 	shutdown      bool
 	shutdownMutex sync.Mutex
 
 	handlerShutdown *sync.WaitGroup
+	// disconnectHandled is closed once the disconnection of the current
+	// connection was handled and its handlers stopped
+	disconnectHandled chan struct{}
+	// conn is the current connection, which records why it was lost
+	conn *trackedConn
+
+	// monitorCanceled contains the functions registered with OnMonitorCanceled
+	monitorCanceled      []func(dbName string)
+	monitorCanceledMutex sync.Mutex
+
+	// locks contains the locks requested with Lock or Steal by id, and
+	// lockStolen the functions registered with OnLockStolen
+	locks      map[string]*lock
+	lockStolen []func(id string)
+	locksMutex sync.Mutex
+
+	// state is the state of the connection, and stateCallbacks the
+	// connection callbacks waiting to be called by the goroutine that runs
+	// them, if stateNotifying. disconnectReason and disconnectErr tell why
+	// the last connection was lost.
+	state            ConnectionState
+	stateCallbacks   []func()
+	stateNotifying   bool
+	disconnectReason DisconnectReason
+	disconnectErr    error
+	stateMutex       sync.Mutex
 
 	logger *logr.Logger
 }
@@ -128,6 +272,10 @@ type database struct {
 	// tracks any outstanding updates while waiting for a monitor response
 	deferUpdates    bool
 	deferredUpdates []*bufferedUpdate
+
+	// resync is the shadow cache the monitors populate on reconnect, which
+	// replaces cache once all of them replied
+	resync *cache.TableCache
 }
 
 // NewOVSDBClient creates a new OVSDB Client with the provided
@@ -138,6 +286,17 @@ func NewOVSDBClient(clientDBModel model.ClientDBModel, opts ...Option) (Client,
 	return newOVSDBClient(clientDBModel, opts...)
 }
 
+// NewClientFromConn creates a new OVSDB Client with the provided database model
+// that talks to the server over conn instead of dialing an endpoint, e.g. to
+// tunnel the connection over ssh or websockets, or to test the client with one
+// end of a net.Pipe. Connect starts the rpc session over conn, which the client
+// closes when it disconnects. The connection cannot be reopened, so Connect
+// fails with ErrConnUsed afterwards and WithReconnect is rejected. The
+// WithEndpoint and WithDialContext options are ignored.
+func NewClientFromConn(conn io.ReadWriteCloser, clientDBModel model.ClientDBModel, opts ...Option) (Client, error) {
+	return newOVSDBClient(clientDBModel, append(opts, withConn(conn))...)
+}
+
 // newOVSDBClient creates a new ovsdbClient
 func newOVSDBClient(clientDBModel model.ClientDBModel, opts ...Option) (*ovsdbClient, error) {
 	ovs := &ovsdbClient{
@@ -150,9 +309,12 @@ func newOVSDBClient(clientDBModel model.ClientDBModel, opts ...Option) (*ovsdbCl
 				deferredUpdates: make([]*bufferedUpdate, 0),
 			},
 		},
+		inflight:        make(map[uint64]string),
+		locks:           make(map[string]*lock),
 		errorCh:         make(chan error),
 		handlerShutdown: &sync.WaitGroup{},
 		disconnect:      make(chan struct{}),
+		reconnected:     make(chan struct{}),
 	}
 	var err error
 	ovs.options, err = newOptions(opts...)
@@ -178,6 +340,9 @@ func newOVSDBClient(clientDBModel model.ClientDBModel, opts ...Option) (*ovsdbCl
 		)
 		ovs.logger = &l
 	}
+	if ovs.options.metrics == nil {
+		ovs.options.metrics = noopMetrics{}
+	}
 	ovs.metrics.init(clientDBModel.Name(), ovs.options.metricNamespace, ovs.options.metricSubsystem)
 	ovs.registerMetrics()
 
@@ -196,6 +361,135 @@ func newOVSDBClient(clientDBModel model.ClientDBModel, opts ...Option) (*ovsdbCl
 	return ovs, nil
 }
 
+// ListDatabases connects to the first reachable endpoint configured by the
+// options and returns the names of the databases the server offers. Unlike a
+// Client, it does not need a ClientDBModel, so it can be used to find out what
+// a server offers before building one.
+func ListDatabases(ctx context.Context, opts ...Option) ([]string, error) {
+	var dbs []string
+	err := withBareConnection(ctx, func(o *ovsdbClient) error {
+		var err error
+		dbs, err = o.ListDatabases(ctx)
+		return err
+	}, opts...)
+	return dbs, err
+}
+
+// GetSchema connects to the first reachable endpoint configured by the options
+// and returns the schema of the given database. Like ListDatabases, it does not
+// need a ClientDBModel.
+func GetSchema(ctx context.Context, dbName string, opts ...Option) (*ovsdb.DatabaseSchema, error) {
+	var schema *ovsdb.DatabaseSchema
+	err := withBareConnection(ctx, func(o *ovsdbClient) error {
+		var err error
+		schema, err = o.GetSchema(ctx, dbName)
+		return err
+	}, opts...)
+	return schema, err
+}
+
+// newRawModel returns a model of a table built from its schema: a pointer to a
+// struct with a field of the native type of each column, see MonitorTable
+func newRawModel(table *ovsdb.TableSchema) model.Model {
+	columns := make([]string, 0, len(table.Columns))
+	for column := range table.Columns {
+		columns = append(columns, column)
+	}
+	sort.Strings(columns)
+	fields := make([]reflect.StructField, 0, len(columns)+1)
+	fields = append(fields, reflect.StructField{Name: "UUID", Type: reflect.TypeOf(""), Tag: `ovsdb:"_uuid"`})
+	for i, column := range columns {
+		fields = append(fields, reflect.StructField{
+			Name: fmt.Sprintf("Column%d", i),
+			Type: ovsdb.NativeType(table.Columns[column]),
+			Tag:  reflect.StructTag(fmt.Sprintf("ovsdb:%q", column)),
+		})
+	}
+	return reflect.New(reflect.StructOf(fields)).Interface()
+}
+
+// MonitorTable connects to the first reachable endpoint configured by the
+// options and returns a snapshot of the rows of a table keyed by UUID (see
+// MonitorOnce), with every column of the table. Like ListDatabases, it does
+// not need a ClientDBModel: it gets the schema of the database first, and
+// builds the model of the table from it. The rows are returned as ovsdb.Row,
+// as the server sent them (see cache.WithRawRows). The connections are closed
+// once the rows are returned.
+func MonitorTable(ctx context.Context, dbName, table string, opts ...Option) (map[string]ovsdb.Row, error) {
+	schema, err := GetSchema(ctx, dbName, opts...)
+	if err != nil {
+		return nil, err
+	}
+	tableSchema := schema.Table(table)
+	if tableSchema == nil {
+		return nil, fmt.Errorf("table %s not found in database %s", table, dbName)
+	}
+	m := newRawModel(tableSchema)
+	dbModel, err := model.NewClientDBModel(dbName, map[string]model.Model{table: m})
+	if err != nil {
+		return nil, err
+	}
+	opts = append(opts[:len(opts):len(opts)], WithCacheOptions(cache.WithRawRows()))
+	o, err := newOVSDBClient(dbModel, opts...)
+	if err != nil {
+		return nil, err
+	}
+	if err := o.Connect(ctx); err != nil {
+		return nil, err
+	}
+	defer o.Close()
+	snapshot, err := o.MonitorOnce(ctx, o.NewMonitor(WithTable(m)))
+	if err != nil {
+		return nil, err
+	}
+	uuids := snapshot.Table(table).RowsShallow()
+	rows := make(map[string]ovsdb.Row, len(uuids))
+	for uuid := range uuids {
+		rows[uuid] = snapshot.RawRow(table, uuid)
+	}
+	return rows, nil
+}
+
+// withBareConnection opens a connection to the first reachable endpoint,
+// without any database, calls fn and closes the connection
+func withBareConnection(ctx context.Context, fn func(*ovsdbClient) error, opts ...Option) error {
+	options, err := newOptions(opts...)
+	if err != nil {
+		return err
+	}
+	logger := logr.Discard()
+	if options.logger != nil {
+		logger = *options.logger
+	}
+	if options.metrics == nil {
+		options.metrics = noopMetrics{}
+	}
+	o := &ovsdbClient{
+		options:   options,
+		databases: map[string]*database{},
+		inflight:  make(map[uint64]string),
+		logger:    &logger,
+	}
+	var connectErrors []string
+	for _, endpoint := range options.endpoints {
+		u, err := url.Parse(endpoint)
+		if err != nil {
+			return err
+		}
+		c, err := o.dial(ctx, u)
+		if err != nil {
+			connectErrors = append(connectErrors, fmt.Sprintf("failed to connect to %s: %v", endpoint, err))
+			continue
+		}
+		// the connection is not shared, so there is no need to hold the
+		// rpcMutex while creating the rpc client
+		o.createRPC2Client(c)
+		defer o.rpcClient.Close()
+		return fn(o)
+	}
+	return fmt.Errorf("unable to connect to any endpoints: %s", strings.Join(connectErrors, ". "))
+}
+
 // Connect opens a connection to an OVSDB Server using the
 // endpoint provided when the Client was created.
 // The connection can be configured using one or more Option(s), like WithTLSConfig
@@ -237,13 +531,21 @@ func (o *ovsdbClient) resetRPCClient() {
 	}
 }
 
-func (o *ovsdbClient) connect(ctx context.Context, reconnect bool) error {
+func (o *ovsdbClient) connect(ctx context.Context, reconnect bool) (err error) {
 	o.rpcMutex.Lock()
 	defer o.rpcMutex.Unlock()
 	if o.rpcClient != nil {
 		return ErrAlreadyConnected
 	}
 
+	o.setState(Connecting)
+	defer func() {
+		// failed reconnections are retried, so the client is still connecting
+		if err != nil && !reconnect {
+			o.setState(Disconnected)
+		}
+	}()
+
 	connected := false
 	connectErrors := []error{}
 	for i, endpoint := range o.endpoints {
@@ -290,19 +592,36 @@ func (o *ovsdbClient) connect(ctx context.Context, reconnect bool) error {
 				continue
 			}
 
-			// Restart all monitors; each monitor will handle purging
-			// the cache if necessary
+			// Restart all monitors. Their complete rows go into a shadow
+			// cache that replaces the cache once all of them replied, so
+			// that readers never see a partially populated cache and rows
+			// that did not change produce no events.
+			db.resync = db.cache.NewShadow(db.model)
 			for id, request := range db.monitors {
 				err := o.monitor(ctx, MonitorCookie{DatabaseName: dbName, ID: id}, true, request)
 				if err != nil {
+					db.resync = nil
 					o.resetRPCClient()
 					return err
 				}
 			}
+			if err := o.finishResync(dbName, db); err != nil {
+				o.resetRPCClient()
+				return err
+			}
+		}
+		if err := o.relock(ctx); err != nil {
+			o.resetRPCClient()
+			return err
 		}
 	}
 
-	go o.handleDisconnectNotification()
+	o.disconnectHandled = make(chan struct{})
+	go o.handleDisconnectNotification(o.disconnectHandled)
+	if o.options.inactivityProbe > 0 {
+		o.handlerShutdown.Add(1)
+		go o.handleInactivityProbe(o.stopCh)
+	}
 	for _, db := range o.databases {
 		o.handlerShutdown.Add(1)
 		eventStopChan := make(chan struct{})
@@ -316,6 +635,7 @@ func (o *ovsdbClient) connect(ctx context.Context, reconnect bool) error {
 	}
 
 	o.connected = true
+	o.setState(Connected)
 	return nil
 }
 
@@ -323,25 +643,15 @@ func (o *ovsdbClient) connect(ctx context.Context, reconnect bool) error {
 // server ID (if clustered) on success, or an error.
 func (o *ovsdbClient) tryEndpoint(ctx context.Context, u *url.URL) (string, error) {
 	o.logger.V(3).Info("trying to connect", "endpoint", fmt.Sprintf("%v", u))
-	var dialer net.Dialer
-	var err error
 	var c net.Conn
-
-	switch u.Scheme {
-	case UNIX:
-		c, err = dialer.DialContext(ctx, u.Scheme, u.Path)
-	case TCP:
-		c, err = dialer.DialContext(ctx, u.Scheme, u.Opaque)
-	case SSL:
-		dialer := tls.Dialer{
-			Config: o.options.tlsConfig,
-		}
-		c, err = dialer.DialContext(ctx, "tcp", u.Opaque)
-	default:
-		err = fmt.Errorf("unknown network protocol %s", u.Scheme)
+	var err error
+	if o.options.pool != nil {
+		c, err = o.options.pool.dial(ctx, u, o.dial)
+	} else {
+		c, err = o.dial(ctx, u)
 	}
 	if err != nil {
-		return "", fmt.Errorf("failed to open connection: %w", err)
+		return "", err
 	}
 
 	o.createRPC2Client(c)
@@ -371,28 +681,32 @@ func (o *ovsdbClient) tryEndpoint(ctx context.Context, u *url.URL) (string, erro
 		if err != nil {
 			return "", err
 		}
+		if dbName == o.primaryDBName && o.options.minSchemaVersion != nil {
+			if err := checkSchemaVersion(schema, *o.options.minSchemaVersion); err != nil {
+				return "", err
+			}
+		}
 
 		db.modelMutex.Lock()
 		var errors []error
 		db.model, errors = model.NewDatabaseModel(schema, db.model.Client())
 		db.modelMutex.Unlock()
 		if len(errors) > 0 {
-			var combined []string
-			for _, err := range errors {
-				combined = append(combined, err.Error())
-			}
-			return "", fmt.Errorf("database %s validation error (%d): %s",
-				dbName, len(errors), strings.Join(combined, ". "))
+			return "", &ErrDatabaseValidation{database: dbName, errors: errors}
 		}
 
 		db.cacheMutex.Lock()
 		if db.cache == nil {
-			db.cache, err = cache.NewTableCache(db.model, nil, o.logger)
+			db.cache, err = cache.NewTableCache(db.model, nil, o.logger, o.options.cacheOptions...)
 			if err != nil {
 				db.cacheMutex.Unlock()
 				return "", err
 			}
-			db.api = newAPI(db.cache, o.logger)
+			db.api = api{
+				cache:   db.cache,
+				logger:  o.logger,
+				gcCheck: o.options.gcCheck,
+			}
 		}
 		db.cacheMutex.Unlock()
 	}
@@ -412,12 +726,83 @@ func (o *ovsdbClient) tryEndpoint(ctx context.Context, u *url.URL) (string, erro
 	return sid, nil
 }
 
+// dial opens a connection to a single database endpoint
+func (o *ovsdbClient) dial(ctx context.Context, u *url.URL) (net.Conn, error) {
+	var dialer net.Dialer
+	var err error
+	var c net.Conn
+
+	dial := dialer.DialContext
+	if o.options.dialContext != nil {
+		dial = o.options.dialContext
+	}
+	switch u.Scheme {
+	case UNIX:
+		c, err = dial(ctx, u.Scheme, u.Path)
+	case TCP:
+		c, err = dial(ctx, u.Scheme, u.Opaque)
+	case SSL:
+		c, err = dial(ctx, "tcp", u.Opaque)
+	default:
+		err = fmt.Errorf("unknown network protocol %s", u.Scheme)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to open connection: %w", err)
+	}
+
+	if u.Scheme == SSL {
+		// perform the handshake before starting the rpc session so that
+		// certificate errors are reported as such
+		return o.tlsHandshake(ctx, c, u.Opaque)
+	}
+	return c, nil
+}
+
+// checkSchemaVersion checks that the version of the schema is not older than
+// the minimum version
+func checkSchemaVersion(schema ovsdb.DatabaseSchema, minVersion ovsdb.SchemaVersion) error {
+	version, err := schema.SchemaVersion()
+	if err != nil {
+		return fmt.Errorf("database %s: %w", schema.Name, err)
+	}
+	if version.Compare(minVersion) < 0 {
+		return fmt.Errorf("database %s schema version %s is older than the minimum supported version %s",
+			schema.Name, version, minVersion)
+	}
+	return nil
+}
+
+// tlsHandshake wraps the connection to an ssl endpoint in a TLS client and
+// performs the handshake, verifying the server certificate
+func (o *ovsdbClient) tlsHandshake(ctx context.Context, c net.Conn, address string) (net.Conn, error) {
+	var config *tls.Config
+	if o.options.tlsConfig != nil {
+		config = o.options.tlsConfig.Clone()
+	} else {
+		config = &tls.Config{}
+	}
+	if config.ServerName == "" {
+		host, _, err := net.SplitHostPort(address)
+		if err != nil {
+			host = address
+		}
+		config.ServerName = host
+	}
+	tlsConn := tls.Client(c, config)
+	if err := tlsConn.HandshakeContext(ctx); err != nil {
+		c.Close()
+		return nil, &ErrTLSHandshake{endpoint: address, err: err}
+	}
+	return tlsConn, nil
+}
+
 // createRPC2Client creates an rpcClient using the provided connection
 // It is also responsible for setting up go routines for client-side event handling
 // Should only be called when the mutex is held
 func (o *ovsdbClient) createRPC2Client(conn net.Conn) {
 	o.stopCh = make(chan struct{})
-	o.rpcClient = rpc2.NewClientWithCodec(jsonrpc.NewJSONCodec(conn))
+	o.conn = newTrackedConn(conn)
+	o.rpcClient = rpc2.NewClientWithCodec(jsonrpc.NewJSONCodec(o.conn))
 	o.rpcClient.SetBlocking(true)
 	o.rpcClient.Handle("echo", func(_ *rpc2.Client, args []interface{}, reply *[]interface{}) error {
 		return o.echo(args, reply)
@@ -431,6 +816,15 @@ func (o *ovsdbClient) createRPC2Client(conn net.Conn) {
 	o.rpcClient.Handle("update3", func(_ *rpc2.Client, args []json.RawMessage, reply *[]interface{}) error {
 		return o.update3(args, reply)
 	})
+	o.rpcClient.Handle("monitor_canceled", func(_ *rpc2.Client, args []json.RawMessage, reply *[]interface{}) error {
+		return o.monitorCanceledNotification(args, reply)
+	})
+	o.rpcClient.Handle("locked", func(_ *rpc2.Client, args []interface{}, reply *[]interface{}) error {
+		return o.lockNotification("locked", args)
+	})
+	o.rpcClient.Handle("stolen", func(_ *rpc2.Client, args []interface{}, reply *[]interface{}) error {
+		return o.lockNotification("stolen", args)
+	})
 	go o.rpcClient.Run()
 }
 
@@ -547,12 +941,90 @@ func (o *ovsdbClient) CurrentEndpoint() string {
 	return o.endpoints[0].address
 }
 
+// State returns the state of the connection to the server
+func (o *ovsdbClient) State() ConnectionState {
+	o.stateMutex.Lock()
+	defer o.stateMutex.Unlock()
+	return o.state
+}
+
+// setState changes the state of the connection and queues the connection
+// callbacks of the change, if any: onConnect when the client becomes
+// connected and onDisconnect and the disconnect callback when it no longer is
+func (o *ovsdbClient) setState(state ConnectionState) {
+	o.stateMutex.Lock()
+	defer o.stateMutex.Unlock()
+	if o.state == state {
+		return
+	}
+	var callbacks []func()
+	if state == Connected {
+		if o.options.onConnect != nil {
+			callbacks = append(callbacks, o.options.onConnect)
+		}
+	} else if o.state == Connected {
+		if o.options.onDisconnect != nil {
+			callbacks = append(callbacks, o.options.onDisconnect)
+		}
+		if fn := o.options.disconnectCallback; fn != nil {
+			reason, err := o.disconnectReason, o.disconnectErr
+			callbacks = append(callbacks, func() { fn(reason, err) })
+		}
+	}
+	o.state = state
+	if len(callbacks) == 0 {
+		return
+	}
+	o.stateCallbacks = append(o.stateCallbacks, callbacks...)
+	if !o.stateNotifying {
+		o.stateNotifying = true
+		go o.runStateCallbacks()
+	}
+}
+
+// runStateCallbacks calls the queued connection callbacks in order until
+// there are none left
+func (o *ovsdbClient) runStateCallbacks() {
+	for {
+		o.stateMutex.Lock()
+		if len(o.stateCallbacks) == 0 {
+			o.stateNotifying = false
+			o.stateMutex.Unlock()
+			return
+		}
+		callback := o.stateCallbacks[0]
+		o.stateCallbacks = o.stateCallbacks[1:]
+		o.stateMutex.Unlock()
+		callback()
+	}
+}
+
 // DisconnectNotify returns a channel which will notify the caller when the
 // server has disconnected
 func (o *ovsdbClient) DisconnectNotify() chan struct{} {
 	return o.disconnect
 }
 
+// ReconnectNotify returns a channel which will notify the caller when the
+// client has reconnected to the server and restarted all its monitors
+func (o *ovsdbClient) ReconnectNotify() chan struct{} {
+	return o.reconnected
+}
+
+// OnMonitorCanceled registers a function that is called when the server
+// cancels a monitor of the given database, or when it is canceled with
+// MonitorCancel unless the client was created WithMonitorCancelDeletes. The
+// tables that are no longer monitored are invalidated in the cache, and
+// reading them fails with cache.ErrCacheInvalid until they are monitored
+// again. The functions are called in their own goroutine, so they can monitor
+// the tables again. The event handlers then get a delete event for each stale
+// row, before the add events of the rows of the new monitor.
+func (o *ovsdbClient) OnMonitorCanceled(fn func(dbName string)) {
+	o.monitorCanceledMutex.Lock()
+	defer o.monitorCanceledMutex.Unlock()
+	o.monitorCanceled = append(o.monitorCanceled, fn)
+}
+
 // RFC 7047 : Section 4.1.6 : Echo
 func (o *ovsdbClient) echo(args []interface{}, reply *[]interface{}) error {
 	*reply = args
@@ -589,7 +1061,7 @@ func (o *ovsdbClient) update(params []json.RawMessage, reply *[]interface{}) err
 
 	db.cacheMutex.Lock()
 	if db.deferUpdates {
-		db.deferredUpdates = append(db.deferredUpdates, &bufferedUpdate{&updates, nil, ""})
+		db.deferredUpdates = append(db.deferredUpdates, &bufferedUpdate{&updates, nil, "", cookie.ID})
 		db.cacheMutex.Unlock()
 		return nil
 	}
@@ -598,6 +1070,11 @@ func (o *ovsdbClient) update(params []json.RawMessage, reply *[]interface{}) err
 	// Update the local DB cache with the tableUpdates
 	db.cacheMutex.RLock()
 	err = db.cache.Update(cookie.ID, updates)
+	if err == nil {
+		for table := range updates {
+			o.recordUpdate(cookie.DatabaseName, db, table)
+		}
+	}
 	db.cacheMutex.RUnlock()
 
 	if err != nil {
@@ -630,7 +1107,7 @@ func (o *ovsdbClient) update2(params []json.RawMessage, reply *[]interface{}) er
 
 	db.cacheMutex.Lock()
 	if db.deferUpdates {
-		db.deferredUpdates = append(db.deferredUpdates, &bufferedUpdate{nil, &updates, ""})
+		db.deferredUpdates = append(db.deferredUpdates, &bufferedUpdate{nil, &updates, "", cookie.ID})
 		db.cacheMutex.Unlock()
 		return nil
 	}
@@ -639,6 +1116,11 @@ func (o *ovsdbClient) update2(params []json.RawMessage, reply *[]interface{}) er
 	// Update the local DB cache with the tableUpdates
 	db.cacheMutex.RLock()
 	err = db.cache.Update2(cookie, updates)
+	if err == nil {
+		for table := range updates {
+			o.recordUpdate(cookie.DatabaseName, db, table)
+		}
+	}
 	db.cacheMutex.RUnlock()
 
 	if err != nil {
@@ -677,7 +1159,7 @@ func (o *ovsdbClient) update3(params []json.RawMessage, reply *[]interface{}) er
 
 	db.cacheMutex.Lock()
 	if db.deferUpdates {
-		db.deferredUpdates = append(db.deferredUpdates, &bufferedUpdate{nil, &updates, lastTransactionID})
+		db.deferredUpdates = append(db.deferredUpdates, &bufferedUpdate{nil, &updates, lastTransactionID, cookie.ID})
 		db.cacheMutex.Unlock()
 		return nil
 	}
@@ -686,25 +1168,178 @@ func (o *ovsdbClient) update3(params []json.RawMessage, reply *[]interface{}) er
 	// Update the local DB cache with the tableUpdates
 	db.cacheMutex.RLock()
 	err = db.cache.Update2(cookie, updates)
+	if err == nil {
+		for table := range updates {
+			o.recordUpdate(cookie.DatabaseName, db, table)
+		}
+	}
 	db.cacheMutex.RUnlock()
 
-	if err == nil {
-		db.monitorsMutex.Lock()
-		mon := db.monitors[cookie.ID]
+	if err != nil {
+		o.errorCh <- err
+		return err
+	}
+
+	db.monitorsMutex.Lock()
+	if mon, ok := db.monitors[cookie.ID]; ok {
 		mon.LastTransactionID = lastTransactionID
+	}
+	db.monitorsMutex.Unlock()
+
+	return nil
+}
+
+// monitor_canceled handling from ovsdb-server.7
+func (o *ovsdbClient) monitorCanceledNotification(params []json.RawMessage, reply *[]interface{}) error {
+	cookie := MonitorCookie{}
+	*reply = []interface{}{}
+	if len(params) != 1 {
+		return fmt.Errorf("monitor_canceled requires exactly 1 arg")
+	}
+	err := json.Unmarshal(params[0], &cookie)
+	if err != nil {
+		return err
+	}
+	db := o.databases[cookie.DatabaseName]
+	if db == nil {
+		return fmt.Errorf("monitor_canceled: invalid database name: %s unknown", cookie.DatabaseName)
+	}
+
+	db.monitorsMutex.Lock()
+	monitor, ok := db.monitors[cookie.ID]
+	if !ok {
 		db.monitorsMutex.Unlock()
+		return nil
+	}
+	delete(db.monitors, cookie.ID)
+	o.metrics.numMonitors.Dec()
+	tables := unmonitoredTables(db, monitor)
+	db.monitorsMutex.Unlock()
+
+	db.cacheMutex.RLock()
+	if db.cache != nil {
+		db.cache.Invalidate(tables...)
 	}
+	db.cacheMutex.RUnlock()
+	o.logger.V(3).Info("monitor canceled by the server", "database", cookie.DatabaseName, "tables", tables)
+	o.notifyMonitorCanceled(cookie.DatabaseName)
+	return nil
+}
+
+// notifyMonitorCanceled calls the functions registered with OnMonitorCanceled
+// in their own goroutine
+func (o *ovsdbClient) notifyMonitorCanceled(dbName string) {
+	o.monitorCanceledMutex.Lock()
+	handlers := make([]func(string), len(o.monitorCanceled))
+	copy(handlers, o.monitorCanceled)
+	o.monitorCanceledMutex.Unlock()
+	go func() {
+		for _, fn := range handlers {
+			fn(dbName)
+		}
+	}()
+}
+
+// call issues an rpc call and waits for its reply, or for the context to be
+// done, in which case the error wraps the context error with the method of the
+// aborted call. rpc2 offers no way to cancel a call: it keeps the aborted call
+// pending until the reply arrives, which it drops, or until the connection
+// closes.
+// While the client is disconnecting with DisconnectContext, new calls fail
+// and calls aborted by the disconnection return ErrDisconnected.
+// Should only be called when rpcMutex is held
+func (o *ovsdbClient) call(ctx context.Context, method string, args interface{}, reply interface{}) error {
+	o.inflightMutex.Lock()
+	if o.draining {
+		o.inflightMutex.Unlock()
+		return fmt.Errorf("%w: %s not sent", ErrDisconnected, method)
+	}
+	seq := o.inflightSeq
+	o.inflightSeq++
+	o.inflight[seq] = method
+	o.inflightMutex.Unlock()
+	defer func() {
+		o.inflightMutex.Lock()
+		delete(o.inflight, seq)
+		if o.drained != nil && len(o.inflight) == 0 {
+			close(o.drained)
+			o.drained = nil
+		}
+		o.inflightMutex.Unlock()
+	}()
 
+	o.options.metrics.RPCSent(method)
+	start := time.Now()
+	err := o.rpcClient.CallWithContext(ctx, method, args, reply)
+	o.options.metrics.RPCLatency(method, time.Since(start))
+	if err != nil && err == ctx.Err() {
+		return fmt.Errorf("%w: while awaiting %s reply", err, method)
+	}
+	if _, ok := err.(rpc2.ServerError); err != nil && !ok {
+		// the connection failed, or was closed by DisconnectContext
+		o.inflightMutex.Lock()
+		draining := o.draining
+		o.inflightMutex.Unlock()
+		if draining {
+			return fmt.Errorf("%w: while awaiting %s reply", ErrDisconnected, method)
+		}
+	}
 	return err
 }
 
+// waitInflight returns a channel closed once no rpc call is awaiting its
+// reply. Must be called with inflightMutex held while draining.
+func (o *ovsdbClient) waitInflight() <-chan struct{} {
+	if len(o.inflight) == 0 {
+		// drained is only kept while calls are in flight, as the last of
+		// them closes it
+		done := make(chan struct{})
+		close(done)
+		return done
+	}
+	if o.drained == nil {
+		o.drained = make(chan struct{})
+	}
+	return o.drained
+}
+
+// ListDatabases returns the names of the databases the server offers
+// RFC 7047 : list_dbs
+func (o *ovsdbClient) ListDatabases(ctx context.Context) ([]string, error) {
+	o.rpcMutex.RLock()
+	defer o.rpcMutex.RUnlock()
+	if o.rpcClient == nil {
+		return nil, ErrNotConnected
+	}
+	return o.listDbs(ctx)
+}
+
+// GetSchema returns the schema of any of the databases the server offers,
+// which does not need to be the database of the client model
+// RFC 7047 : get_schema
+func (o *ovsdbClient) GetSchema(ctx context.Context, dbName string) (*ovsdb.DatabaseSchema, error) {
+	o.rpcMutex.RLock()
+	defer o.rpcMutex.RUnlock()
+	if o.rpcClient == nil {
+		return nil, ErrNotConnected
+	}
+	schema, err := o.getSchema(ctx, dbName)
+	if err != nil {
+		return nil, err
+	}
+	if schema.Name != dbName {
+		return nil, fmt.Errorf("get_schema for database %s returned the schema of database %q", dbName, schema.Name)
+	}
+	return &schema, nil
+}
+
 // getSchema returns the schema in use for the provided database name
 // RFC 7047 : get_schema
 // Should only be called when mutex is held
 func (o *ovsdbClient) getSchema(ctx context.Context, dbName string) (ovsdb.DatabaseSchema, error) {
 	args := ovsdb.NewGetSchemaArgs(dbName)
 	var reply ovsdb.DatabaseSchema
-	err := o.rpcClient.CallWithContext(ctx, "get_schema", args, &reply)
+	err := o.call(ctx, "get_schema", args, &reply)
 	if err != nil {
 		if err == rpc2.ErrShutdown {
 			return ovsdb.DatabaseSchema{}, ErrNotConnected
@@ -719,19 +1354,25 @@ func (o *ovsdbClient) getSchema(ctx context.Context, dbName string) (ovsdb.Datab
 // Should only be called when mutex is held
 func (o *ovsdbClient) listDbs(ctx context.Context) ([]string, error) {
 	var dbs []string
-	err := o.rpcClient.CallWithContext(ctx, "list_dbs", nil, &dbs)
+	err := o.call(ctx, "list_dbs", nil, &dbs)
 	if err != nil {
 		if err == rpc2.ErrShutdown {
 			return nil, ErrNotConnected
 		}
-		return nil, fmt.Errorf("listdbs failure - %v", err)
+		return nil, fmt.Errorf("listdbs failure - %w", err)
 	}
 	return dbs, err
 }
 
-// Transact performs the provided Operations on the database
+// Transact performs the provided Operations on the database, once the rate
+// limit of the client allows it (see WithRateLimit)
 // RFC 7047 : transact
 func (o *ovsdbClient) Transact(ctx context.Context, operation ...ovsdb.Operation) ([]ovsdb.OperationResult, error) {
+	if o.options.rateLimiter != nil {
+		if err := o.options.rateLimiter.wait(ctx); err != nil {
+			return nil, err
+		}
+	}
 	o.rpcMutex.RLock()
 	if o.rpcClient == nil || !o.connected {
 		o.rpcMutex.RUnlock()
@@ -761,6 +1402,70 @@ func (o *ovsdbClient) Transact(ctx context.Context, operation ...ovsdb.Operation
 	return o.transact(ctx, o.primaryDBName, operation...)
 }
 
+// Comment returns a comment operation, which does not change the database but
+// makes the server log the given text along with the transaction. Prepend it to
+// the operations of a transaction to correlate them with the server logs:
+//
+//	ops = append([]ovsdb.Operation{client.Comment("reconcile switch foo")}, ops...)
+//	reply, err := ovs.Transact(ctx, ops...)
+//
+// Transactions with an empty comment fail validation.
+func Comment(text string) ovsdb.Operation {
+	return ovsdb.Operation{
+		Op:      ovsdb.OperationComment,
+		Comment: &text,
+	}
+}
+
+// Select reads the rows of a table that match all the conditions, or every row
+// if there are none, and returns them as models of the table. It reads from the
+// server rather than the cache, so the table does not need to be monitored. If
+// columns are given, only those are read and the other fields of the models are
+// left unset.
+// RFC 7047 : select
+func (o *ovsdbClient) Select(ctx context.Context, table string, conditions []ovsdb.Condition, columns []string) ([]model.Model, error) {
+	db := o.primaryDB()
+	db.modelMutex.RLock()
+	_, err := db.model.NewModel(table)
+	db.modelMutex.RUnlock()
+	if err != nil {
+		return nil, err
+	}
+
+	op := ovsdb.Operation{
+		Op:      ovsdb.OperationSelect,
+		Table:   table,
+		Where:   conditions,
+		Columns: columns,
+	}
+	results, err := o.Transact(ctx, op)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := ovsdb.CheckOperationResults(results, []ovsdb.Operation{op}); err != nil {
+		return nil, err
+	}
+
+	db.modelMutex.RLock()
+	defer db.modelMutex.RUnlock()
+	models := make([]model.Model, 0, len(results[0].Rows))
+	for i := range results[0].Rows {
+		m, err := db.model.NewModel(table)
+		if err != nil {
+			return nil, err
+		}
+		info, err := db.model.NewModelInfo(m)
+		if err != nil {
+			return nil, err
+		}
+		if err := db.model.Mapper.GetRowData(&results[0].Rows[i], info); err != nil {
+			return nil, err
+		}
+		models = append(models, m)
+	}
+	return models, nil
+}
+
 func (o *ovsdbClient) transact(ctx context.Context, dbName string, operation ...ovsdb.Operation) ([]ovsdb.OperationResult, error) {
 	var reply []ovsdb.OperationResult
 	db := o.databases[dbName]
@@ -773,6 +1478,11 @@ func (o *ovsdbClient) transact(ctx context.Context, dbName string, operation ...
 	if ok := schema.ValidateOperations(operation...); !ok {
 		return nil, fmt.Errorf("validation failed for the operation")
 	}
+	if o.options.validation {
+		if err := validateRows(&schema, operation); err != nil {
+			return nil, err
+		}
+	}
 
 	args := ovsdb.NewTransactArgs(dbName, operation...)
 	if o.rpcClient == nil {
@@ -782,7 +1492,7 @@ func (o *ovsdbClient) transact(ctx context.Context, dbName string, operation ...
 	if dbgLogger.Enabled() {
 		dbgLogger.Info("transacting operations", "operations", fmt.Sprintf("%+v", operation))
 	}
-	err := o.rpcClient.CallWithContext(ctx, "transact", args, &reply)
+	err := o.call(ctx, "transact", args, &reply)
 	if err != nil {
 		if err == rpc2.ErrShutdown {
 			return nil, ErrNotConnected
@@ -792,16 +1502,49 @@ func (o *ovsdbClient) transact(ctx context.Context, dbName string, operation ...
 	return reply, nil
 }
 
-// MonitorAll is a convenience method to monitor every table/column
+// validateRows checks the rows of the insert and update operations against
+// the schema
+func validateRows(schema *ovsdb.DatabaseSchema, operations []ovsdb.Operation) error {
+	for i, op := range operations {
+		var err error
+		switch op.Op {
+		case ovsdb.OperationInsert:
+			err = ovsdb.ValidateRow(schema, op.Table, op.Row)
+		case ovsdb.OperationUpdate:
+			err = ovsdb.ValidateRowColumns(schema, op.Table, op.Row)
+		}
+		if err != nil {
+			return fmt.Errorf("operation %d: %w", i, err)
+		}
+	}
+	return nil
+}
+
+// MonitorAll is a convenience method to monitor every column of every table
+// registered in the DBModel of the client with a single monitor rpc. The
+// tables of the schema that the model does not register are not monitored.
 func (o *ovsdbClient) MonitorAll(ctx context.Context) (MonitorCookie, error) {
+	db := o.primaryDB()
+	db.modelMutex.RLock()
+	names := make([]string, 0, len(db.model.Types()))
+	for name := range db.model.Types() {
+		names = append(names, name)
+	}
+	db.modelMutex.RUnlock()
+	sort.Strings(names)
 	m := newMonitor()
-	for name := range o.primaryDB().model.Types() {
+	for _, name := range names {
 		m.Tables = append(m.Tables, TableMonitor{Table: name})
 	}
 	return o.Monitor(ctx, m)
 }
 
-// MonitorCancel will request cancel a previously issued monitor request
+// MonitorCancel will request cancel a previously issued monitor request.
+// The tables of the monitor that no other monitor watches are dropped from
+// the cache, and reading them fails with cache.ErrCacheInvalid until they are
+// monitored again. The cache event handlers get a delete event for each
+// dropped row if the client was created WithMonitorCancelDeletes; otherwise
+// the functions registered with OnMonitorCanceled are called.
 // RFC 7047 : monitor_cancel
 func (o *ovsdbClient) MonitorCancel(ctx context.Context, cookie MonitorCookie) error {
 	var reply ovsdb.OperationResult
@@ -811,7 +1554,11 @@ func (o *ovsdbClient) MonitorCancel(ctx context.Context, cookie MonitorCookie) e
 	if o.rpcClient == nil {
 		return ErrNotConnected
 	}
-	err := o.rpcClient.CallWithContext(ctx, "monitor_cancel", args, &reply)
+	db, ok := o.databases[cookie.DatabaseName]
+	if !ok {
+		return fmt.Errorf("invalid database name: %s unknown", cookie.DatabaseName)
+	}
+	err := o.call(ctx, "monitor_cancel", args, &reply)
 	if err != nil {
 		if err == rpc2.ErrShutdown {
 			return ErrNotConnected
@@ -821,13 +1568,131 @@ func (o *ovsdbClient) MonitorCancel(ctx context.Context, cookie MonitorCookie) e
 	if reply.Error != "" {
 		return fmt.Errorf("error while executing transaction: %s", reply.Error)
 	}
-	o.primaryDB().monitorsMutex.Lock()
-	defer o.primaryDB().monitorsMutex.Unlock()
-	delete(o.primaryDB().monitors, cookie.ID)
+	db.monitorsMutex.Lock()
+	monitor, ok := db.monitors[cookie.ID]
+	if !ok {
+		db.monitorsMutex.Unlock()
+		return nil
+	}
+	delete(db.monitors, cookie.ID)
 	o.metrics.numMonitors.Dec()
+	tables := unmonitoredTables(db, monitor)
+	db.monitorsMutex.Unlock()
+
+	db.cacheMutex.RLock()
+	if db.cache != nil {
+		db.cache.Drop(o.options.monitorCancelDeletes, tables...)
+	}
+	db.cacheMutex.RUnlock()
+	if !o.options.monitorCancelDeletes {
+		o.notifyMonitorCanceled(cookie.DatabaseName)
+	}
+	return nil
+}
+
+// unmonitoredTables returns the tables of a monitor that was removed from the
+// monitors of a database that no other monitor watches; the tables that are
+// also part of another monitor are still up to date. Caller must hold the
+// database's monitorsMutex.
+func unmonitoredTables(db *database, monitor *Monitor) []string {
+	var tables []string
+	for _, table := range monitor.Tables {
+		monitored := false
+		for _, other := range db.monitors {
+			for _, otherTable := range other.Tables {
+				if otherTable.Table == table.Table {
+					monitored = true
+				}
+			}
+		}
+		if !monitored {
+			tables = append(tables, table.Table)
+		}
+	}
+	return tables
+}
+
+// MonitorCondChange replaces the conditions of a table in an existing
+// conditional monitor. The server replies with the rows that stopped or
+// started matching as a regular update notification, which prunes and
+// populates the cache accordingly. The new conditions are kept so that they
+// are used if the monitor is restarted on reconnection.
+// ovsdb-server(7) : monitor_cond_change
+func (o *ovsdbClient) MonitorCondChange(ctx context.Context, cookie MonitorCookie, table string, conditions []ovsdb.Condition) error {
+	o.rpcMutex.RLock()
+	defer o.rpcMutex.RUnlock()
+	if o.rpcClient == nil {
+		return ErrNotConnected
+	}
+	db, ok := o.databases[cookie.DatabaseName]
+	if !ok {
+		return fmt.Errorf("invalid database name: %s unknown", cookie.DatabaseName)
+	}
+	// monitorsMutex is not held during the rpc, since the update the server
+	// sends before its reply needs it
+	db.monitorsMutex.Lock()
+	tableIndex, err := condChangeTableIndex(db, cookie, table)
+	db.monitorsMutex.Unlock()
+	if err != nil {
+		return err
+	}
+
+	db.modelMutex.RLock()
+	tableSchema := db.model.Schema.Table(table)
+	db.modelMutex.RUnlock()
+	if tableSchema == nil {
+		return fmt.Errorf("table %s not found in schema", table)
+	}
+	for _, condition := range conditions {
+		if tableSchema.Column(condition.Column) == nil {
+			return mapper.NewErrColumnNotFound(condition.Column, table)
+		}
+	}
+
+	if conditions == nil {
+		conditions = []ovsdb.Condition{}
+	}
+	requests := map[string]ovsdb.MonitorCondChangeRequest{
+		table: {Where: conditions},
+	}
+	args := ovsdb.NewMonitorCondChangeArgs(cookie, cookie, requests)
+	var reply interface{}
+	err = o.call(ctx, ovsdb.ConditionalMonitorChangeRPC, args, &reply)
+	if err != nil {
+		if err == rpc2.ErrShutdown {
+			return ErrNotConnected
+		}
+		return err
+	}
+
+	db.monitorsMutex.Lock()
+	defer db.monitorsMutex.Unlock()
+	// the monitor may have been canceled meanwhile
+	if monitor, ok := db.monitors[cookie.ID]; ok && tableIndex < len(monitor.Tables) &&
+		monitor.Tables[tableIndex].Table == table {
+		monitor.Tables[tableIndex].Conditions = conditions
+	}
 	return nil
 }
 
+// condChangeTableIndex returns the index of a table in the tables of a monitor
+// whose conditions can be changed. Must be called with monitorsMutex held.
+func condChangeTableIndex(db *database, cookie MonitorCookie, table string) (int, error) {
+	monitor, ok := db.monitors[cookie.ID]
+	if !ok {
+		return -1, fmt.Errorf("monitor %s not found", cookie.ID)
+	}
+	if monitor.Method == ovsdb.MonitorRPC {
+		return -1, fmt.Errorf("monitor %s does not support conditions", cookie.ID)
+	}
+	for i := range monitor.Tables {
+		if monitor.Tables[i].Table == table {
+			return i, nil
+		}
+	}
+	return -1, fmt.Errorf("table %s is not monitored by monitor %s", table, cookie.ID)
+}
+
 // Monitor will provide updates for a given table/column
 // and populate the cache with them. Subsequent updates will be processed
 // by the Update Notifications
@@ -840,9 +1705,140 @@ func (o *ovsdbClient) Monitor(ctx context.Context, monitor *Monitor) (MonitorCoo
 	return cookie, o.monitor(ctx, cookie, false, monitor)
 }
 
+// MonitorOnce returns a snapshot of the tables of a monitor: it issues the
+// monitor, populates a new cache with its initial rows and cancels it right
+// away. The returned cache is not the cache of the client and is never
+// updated, nor does it produce events.
+func (o *ovsdbClient) MonitorOnce(ctx context.Context, monitor *Monitor) (*cache.TableCache, error) {
+	o.rpcMutex.RLock()
+	defer o.rpcMutex.RUnlock()
+	if o.rpcClient == nil {
+		return nil, ErrNotConnected
+	}
+	if err := monitorErrors(monitor); err != nil {
+		return nil, err
+	}
+	cookie := newMonitorCookie(o.primaryDBName)
+	db := o.databases[o.primaryDBName]
+	requests, err := monitorRequests(db, monitor)
+	if err != nil {
+		return nil, err
+	}
+	// only the initial rows are requested, the server sends no update
+	for table, request := range requests {
+		request.Select = ovsdb.NewMonitorSelect(true, false, false, false)
+		requests[table] = request
+	}
+
+	db.modelMutex.RLock()
+	dbModel := db.model
+	db.modelMutex.RUnlock()
+	db.cacheMutex.RLock()
+	snapshot := db.cache.NewShadow(dbModel)
+	db.cacheMutex.RUnlock()
+
+	// the snapshot does not need the last transaction id of monitor_cond_since
+	method := monitor.Method
+	if method == ovsdb.ConditionalMonitorSinceRPC {
+		method = ovsdb.ConditionalMonitorRPC
+	}
+	if method == ovsdb.ConditionalMonitorRPC {
+		var reply ovsdb.TableUpdates2
+		err = o.call(ctx, method, ovsdb.NewMonitorArgs(o.primaryDBName, cookie, requests), &reply)
+		if err == nil {
+			err = snapshot.Populate2(reply)
+		} else if err.Error() == "unknown method" {
+			o.logger.V(3).Error(err, "method monitor_cond not supported, falling back to monitor")
+			method = ovsdb.MonitorRPC
+		}
+	}
+	if method == ovsdb.MonitorRPC {
+		var reply ovsdb.TableUpdates
+		err = o.call(ctx, method, ovsdb.NewMonitorArgs(o.primaryDBName, cookie, requests), &reply)
+		if err == nil {
+			err = snapshot.Populate(reply)
+		}
+	}
+	if err != nil {
+		if err == rpc2.ErrShutdown {
+			return nil, ErrNotConnected
+		}
+		return nil, err
+	}
+
+	var reply ovsdb.OperationResult
+	if err := o.call(ctx, "monitor_cancel", ovsdb.NewMonitorCancelArgs(cookie), &reply); err != nil {
+		if err == rpc2.ErrShutdown {
+			return nil, ErrNotConnected
+		}
+		return nil, fmt.Errorf("failed to cancel the monitor of the snapshot: %w", err)
+	}
+	return snapshot, nil
+}
+
+// monitorErrors returns the errors of the options of a monitor, or an error
+// if it has no table
+func monitorErrors(monitor *Monitor) error {
+	if len(monitor.Errors) != 0 {
+		var errString []string
+		for _, err := range monitor.Errors {
+			errString = append(errString, err.Error())
+		}
+		return fmt.Errorf(strings.Join(errString, ". "))
+	}
+	if len(monitor.Tables) == 0 {
+		return fmt.Errorf("at least one table should be monitored")
+	}
+	return nil
+}
+
+// monitorRequests returns the requests of the tables of a monitor
+func monitorRequests(db *database, monitor *Monitor) (map[string]ovsdb.MonitorRequest, error) {
+	db.modelMutex.RLock()
+	defer db.modelMutex.RUnlock()
+	typeMap := db.model.Types()
+	requests := make(map[string]ovsdb.MonitorRequest)
+	for _, table := range monitor.Tables {
+		_, ok := typeMap[table.Table]
+		if !ok {
+			return nil, fmt.Errorf("type for table %s does not exist in model", table.Table)
+		}
+		model, err := db.model.NewModel(table.Table)
+		if err != nil {
+			return nil, err
+		}
+		info, err := db.model.NewModelInfo(model)
+		if err != nil {
+			return nil, err
+		}
+		request, err := newMonitorRequest(info, table.Fields, table.Conditions)
+		if err != nil {
+			return nil, err
+		}
+		if table.Select != nil {
+			request.Select = table.Select
+		}
+		requests[table.Table] = *request
+	}
+	return requests, nil
+}
+
 // If fields is provided, the request will be constrained to the provided columns
 // If no fields are provided, all columns will be used
+// Fields and conditions are validated against the table schema so that an
+// invalid request is rejected before being sent to the server
 func newMonitorRequest(data *mapper.Info, fields []string, conditions []ovsdb.Condition) (*ovsdb.MonitorRequest, error) {
+	tableSchema := data.Metadata.TableSchema
+	for _, field := range fields {
+		if tableSchema.Column(field) == nil {
+			return nil, mapper.NewErrColumnNotFound(field, data.Metadata.TableName)
+		}
+	}
+	for _, condition := range conditions {
+		if tableSchema.Column(condition.Column) == nil {
+			return nil, mapper.NewErrColumnNotFound(condition.Column, data.Metadata.TableName)
+		}
+	}
 	var columns []string
 	if len(fields) > 0 {
 		columns = append(columns, fields...)
@@ -865,41 +1861,15 @@ func (o *ovsdbClient) monitor(ctx context.Context, cookie MonitorCookie, reconne
 	if o.rpcClient == nil {
 		return ErrNotConnected
 	}
-	if len(monitor.Errors) != 0 {
-		var errString []string
-		for _, err := range monitor.Errors {
-			errString = append(errString, err.Error())
-		}
-		return fmt.Errorf(strings.Join(errString, ". "))
-	}
-	if len(monitor.Tables) == 0 {
-		return fmt.Errorf("at least one table should be monitored")
+	if err := monitorErrors(monitor); err != nil {
+		return err
 	}
 	dbName := cookie.DatabaseName
 	db := o.databases[dbName]
-	db.modelMutex.RLock()
-	typeMap := db.model.Types()
-	requests := make(map[string]ovsdb.MonitorRequest)
-	for _, o := range monitor.Tables {
-		_, ok := typeMap[o.Table]
-		if !ok {
-			return fmt.Errorf("type for table %s does not exist in model", o.Table)
-		}
-		model, err := db.model.NewModel(o.Table)
-		if err != nil {
-			return err
-		}
-		info, err := db.model.NewModelInfo(model)
-		if err != nil {
-			return err
-		}
-		request, err := newMonitorRequest(info, o.Fields, o.Conditions)
-		if err != nil {
-			return err
-		}
-		requests[o.Table] = *request
+	requests, err := monitorRequests(db, monitor)
+	if err != nil {
+		return err
 	}
-	db.modelMutex.RUnlock()
 
 	var args []interface{}
 	if monitor.Method == ovsdb.ConditionalMonitorSinceRPC {
@@ -915,25 +1885,26 @@ func (o *ovsdbClient) monitor(ctx context.Context, cookie MonitorCookie, reconne
 	} else {
 		args = ovsdb.NewMonitorArgs(dbName, cookie, requests)
 	}
-	var err error
 	var tableUpdates interface{}
 
 	var lastTransactionFound bool
 	switch monitor.Method {
 	case ovsdb.MonitorRPC:
 		var reply ovsdb.TableUpdates
-		err = o.rpcClient.CallWithContext(ctx, monitor.Method, args, &reply)
+		err = o.call(ctx, monitor.Method, args, &reply)
 		tableUpdates = reply
 	case ovsdb.ConditionalMonitorRPC:
 		var reply ovsdb.TableUpdates2
-		err = o.rpcClient.CallWithContext(ctx, monitor.Method, args, &reply)
+		err = o.call(ctx, monitor.Method, args, &reply)
 		tableUpdates = reply
 	case ovsdb.ConditionalMonitorSinceRPC:
 		var reply ovsdb.MonitorCondSinceReply
-		err = o.rpcClient.CallWithContext(ctx, monitor.Method, args, &reply)
-		if err == nil && reply.Found {
+		err = o.call(ctx, monitor.Method, args, &reply)
+		if err == nil {
+			// the server always provides its latest transaction ID, even if
+			// the requested one was not found and the reply has all rows
 			monitor.LastTransactionID = reply.LastTransactionID
-			lastTransactionFound = true
+			lastTransactionFound = reply.Found
 		}
 		tableUpdates = reply.Updates
 	default:
@@ -967,65 +1938,149 @@ func (o *ovsdbClient) monitor(ctx context.Context, cookie MonitorCookie, reconne
 	db.cacheMutex.Lock()
 	defer db.cacheMutex.Unlock()
 
-	// On reconnect, purge the cache _unless_ the only monitor is a
-	// MonitorCondSince one, whose LastTransactionID was known to the
-	// server. In this case the reply contains only updates to the existing
-	// cache data, while otherwise it includes complete DB data so we must
-	// purge to get rid of old rows.
-	if reconnecting && (len(db.monitors) > 1 || !lastTransactionFound) {
-		db.cache.Purge(db.model)
+	// tables whose monitor was canceled are populated from scratch
+	tables := make([]string, 0, len(monitor.Tables))
+	var partial, complete []string
+	for _, table := range monitor.Tables {
+		tables = append(tables, table.Table)
+		if selectsAll(table.Select) {
+			complete = append(complete, table.Table)
+		} else {
+			partial = append(partial, table.Table)
+		}
+	}
+	db.cache.Revalidate(tables...)
+	db.cache.Complete(complete...)
+	db.cache.Partial(partial...)
+	for table, request := range requests {
+		db.cache.MonitoredColumns(table, request.Columns...)
+	}
+
+	// On reconnect, the reply includes complete DB data that goes into the
+	// shadow cache, _unless_ the only monitor is a MonitorCondSince one
+	// whose LastTransactionID was known to the server. In this case the
+	// reply contains only updates to the existing cache data.
+	target := db.cache
+	resyncing := reconnecting && db.resync != nil && (len(db.monitors) > 1 || !lastTransactionFound)
+	if resyncing {
+		target = db.resync
 	}
 
 	if monitor.Method == ovsdb.MonitorRPC {
 		u := tableUpdates.(ovsdb.TableUpdates)
-		err = db.cache.Populate(u)
+		if err = target.Populate(u); err != nil {
+			return err
+		}
+		if !resyncing {
+			for table := range u {
+				o.recordUpdate(dbName, db, table)
+			}
+		}
 	} else {
 		u := tableUpdates.(ovsdb.TableUpdates2)
-		err = db.cache.Populate2(u)
+		if err = target.Populate2(u); err != nil {
+			return err
+		}
+		if !resyncing {
+			for table := range u {
+				o.recordUpdate(dbName, db, table)
+			}
+		}
 	}
 
-	if err != nil {
-		return err
+	// the deferred updates apply to the shadow cache once it replaced
+	// the cache, see finishResync
+	if resyncing {
+		return nil
 	}
+	return o.populateDeferredUpdates(dbName, db)
+}
 
-	// populate any deferred updates
+// populateDeferredUpdates applies the updates received while the cache was
+// being populated to it. The cacheMutex and the monitorsMutex of the database
+// must be held.
+func (o *ovsdbClient) populateDeferredUpdates(dbName string, db *database) error {
 	db.deferUpdates = false
 	for _, update := range db.deferredUpdates {
 		if update.updates != nil {
-			if err = db.cache.Populate(*update.updates); err != nil {
+			if err := db.cache.Populate(*update.updates); err != nil {
 				return err
 			}
+			for table := range *update.updates {
+				o.recordUpdate(dbName, db, table)
+			}
 		}
 
 		if update.updates2 != nil {
-			if err = db.cache.Populate2(*update.updates2); err != nil {
+			if err := db.cache.Populate2(*update.updates2); err != nil {
 				return err
 			}
+			for table := range *update.updates2 {
+				o.recordUpdate(dbName, db, table)
+			}
 		}
-		if len(update.lastTxnID) > 0 {
-			db.monitors[cookie.ID].LastTransactionID = update.lastTxnID
+		if mon, ok := db.monitors[update.monitorID]; ok && len(update.lastTxnID) > 0 {
+			mon.LastTransactionID = update.lastTxnID
 		}
 	}
 	// clear deferred updates for next time
 	db.deferredUpdates = make([]*bufferedUpdate, 0)
+	return nil
+}
 
-	return err
+// finishResync replaces the cache of the database with the shadow cache its
+// monitors populated on reconnect, if they did, and then applies the updates
+// received in the meantime. The monitorsMutex of the database must be held.
+func (o *ovsdbClient) finishResync(dbName string, db *database) error {
+	db.cacheMutex.Lock()
+	defer db.cacheMutex.Unlock()
+	shadow := db.resync
+	db.resync = nil
+	if !db.deferUpdates {
+		// a MonitorCondSince monitor updated the cache itself
+		return nil
+	}
+	db.cache.Replace(shadow)
+	for _, monitor := range db.monitors {
+		for _, table := range monitor.Tables {
+			o.recordUpdate(dbName, db, table.Table)
+		}
+	}
+	return o.populateDeferredUpdates(dbName, db)
 }
 
 // Echo tests the liveness of the OVSDB connetion
 func (o *ovsdbClient) Echo(ctx context.Context) error {
+	return o.sendEcho(ctx, ovsdb.NewEchoArgs())
+}
+
+// Ping sends an echo request with a unique payload to the server and returns
+// the time it took to receive the matching reply
+func (o *ovsdbClient) Ping(ctx context.Context) (time.Duration, error) {
 	args := ovsdb.NewEchoArgs()
+	args = append(args, uuid.NewString())
+	start := time.Now()
+	if err := o.sendEcho(ctx, args); err != nil {
+		return 0, err
+	}
+	return time.Since(start), nil
+}
+
+// sendEcho sends an echo request and checks that the server replied with
+// the same arguments
+func (o *ovsdbClient) sendEcho(ctx context.Context, args []interface{}) error {
 	var reply []interface{}
 	o.rpcMutex.RLock()
 	defer o.rpcMutex.RUnlock()
 	if o.rpcClient == nil {
 		return ErrNotConnected
 	}
-	err := o.rpcClient.CallWithContext(ctx, "echo", args, &reply)
+	err := o.call(ctx, "echo", args, &reply)
 	if err != nil {
 		if err == rpc2.ErrShutdown {
 			return ErrNotConnected
 		}
+		return err
 	}
 	if !reflect.DeepEqual(args, reply) {
 		return fmt.Errorf("incorrect server response: %v, %v", args, reply)
@@ -1138,7 +2193,42 @@ func (o *ovsdbClient) handleClientErrors(stopCh <-chan struct{}) {
 	}
 }
 
-func (o *ovsdbClient) handleDisconnectNotification() {
+// handleInactivityProbe sends an echo to the server every inactivity probe
+// interval, and disconnects if the server does not reply in time
+func (o *ovsdbClient) handleInactivityProbe(stopCh <-chan struct{}) {
+	defer o.handlerShutdown.Done()
+	ticker := time.NewTicker(o.options.inactivityProbe)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-stopCh:
+			return
+		case <-ticker.C:
+			ctx, cancel := context.WithTimeout(context.Background(), o.options.inactivityProbe)
+			err := o.Echo(ctx)
+			cancel()
+			if err == nil {
+				continue
+			}
+			select {
+			case <-stopCh:
+				// already disconnected
+				return
+			default:
+			}
+			o.logger.V(3).Error(err, "inactivity probe failed, disconnecting", "endpoint", o.CurrentEndpoint())
+			o.rpcMutex.RLock()
+			if o.conn != nil {
+				o.conn.fail(fmt.Errorf("inactivity probe failed: %w", err))
+			}
+			o.rpcMutex.RUnlock()
+			o.Disconnect()
+			return
+		}
+	}
+}
+
+func (o *ovsdbClient) handleDisconnectNotification(handled chan struct{}) {
 	<-o.rpcClient.DisconnectNotify()
 	// close the stopCh, which will stop the cache event processor
 	close(o.stopCh)
@@ -1146,9 +2236,16 @@ func (o *ovsdbClient) handleDisconnectNotification() {
 	// wait for client related handlers to shutdown
 	o.handlerShutdown.Wait()
 	o.rpcMutex.Lock()
+	reason, err := o.conn.disconnectReason()
+	o.stateMutex.Lock()
+	o.disconnectReason, o.disconnectErr = reason, err
+	o.stateMutex.Unlock()
+	o.releaseLocks(o.options.reconnect && !o.shutdown)
 	if o.options.reconnect && !o.shutdown {
 		o.rpcClient = nil
+		o.setState(Connecting)
 		o.rpcMutex.Unlock()
+		close(handled)
 		suppressionCounter := 1
 		connect := func() error {
 			// need to ensure deferredUpdates is cleared on every reconnect attempt
@@ -1179,13 +2276,22 @@ func (o *ovsdbClient) handleDisconnectNotification() {
 			// caller to handle
 			panic(err)
 		}
+		o.options.metrics.Reconnected()
+		select {
+		case o.reconnected <- struct{}{}:
+			// sent reconnect notification to client
+		default:
+			// client is not listening to the channel
+		}
 		// this goroutine finishes, and is replaced with a new one (from Connect)
 		return
 	}
 
 	// clear connection state
 	o.rpcClient = nil
+	o.setState(Disconnected)
 	o.rpcMutex.Unlock()
+	defer close(handled)
 
 	for _, db := range o.databases {
 		db.cacheMutex.Lock()
@@ -1237,6 +2343,56 @@ func (o *ovsdbClient) Disconnect() {
 	o._disconnect()
 }
 
+// DisconnectContext gracefully closes the connection to the OVSDB server. It
+// stops sending new rpc calls, which fail with ErrDisconnected, and waits for
+// the replies of the calls already sent before closing the connection. If the
+// context is done first, the connection is closed anyway: the pending calls
+// return ErrDisconnected, and DisconnectContext the context error. Like with
+// Disconnect, the client reconnects afterwards if it was created with
+// WithReconnect. It returns once the disconnection was handled and the
+// monitors stopped.
+func (o *ovsdbClient) DisconnectContext(ctx context.Context) error {
+	o.rpcMutex.RLock()
+	rpcClient := o.rpcClient
+	handled := o.disconnectHandled
+	o.rpcMutex.RUnlock()
+
+	o.inflightMutex.Lock()
+	if o.draining {
+		o.inflightMutex.Unlock()
+		return ErrDisconnected
+	}
+	o.draining = true
+	drained := o.waitInflight()
+	o.inflightMutex.Unlock()
+	defer func() {
+		o.inflightMutex.Lock()
+		o.draining = false
+		o.inflightMutex.Unlock()
+	}()
+
+	var err error
+	select {
+	case <-drained:
+	case <-ctx.Done():
+		err = fmt.Errorf("%w: while awaiting the pending rpc replies", ctx.Err())
+		// closing the connection aborts the pending calls, which release
+		// rpcMutex once they returned
+		if rpcClient != nil {
+			rpcClient.Close()
+		}
+		o.inflightMutex.Lock()
+		drained = o.waitInflight()
+		o.inflightMutex.Unlock()
+		<-drained
+	}
+	o.Disconnect()
+	if rpcClient != nil {
+		<-handled
+	}
+	return err
+}
+
 // Close will close the connection to the OVSDB server
 // It will remove all stored state ready for the next connection
 // Even If the client was created with WithReconnect it will not reconnect afterwards
@@ -1320,6 +2476,24 @@ func (o *ovsdbClient) Create(models ...model.Model) ([]ovsdb.Operation, error) {
 	return o.primaryDB().api.Create(models...)
 }
 
+// CreateOrUpdate implements the API interface's CreateOrUpdate function. It
+// does not wait for the cache to be consistent, the wait operation it returns
+// fails the transaction if the cache was not up to date.
+func (o *ovsdbClient) CreateOrUpdate(m model.Model, indexFields ...interface{}) ([]ovsdb.Operation, error) {
+	primaryDB := o.primaryDB()
+	primaryDB.cacheMutex.RLock()
+	defer primaryDB.cacheMutex.RUnlock()
+	return primaryDB.api.CreateOrUpdate(m, indexFields...)
+}
+
+// UpdateChanged implements the API interface's UpdateChanged function
+func (o *ovsdbClient) UpdateChanged(old, new model.Model) (ovsdb.Operation, error) {
+	primaryDB := o.primaryDB()
+	primaryDB.cacheMutex.RLock()
+	defer primaryDB.cacheMutex.RUnlock()
+	return primaryDB.api.UpdateChanged(old, new)
+}
+
 //List implements the API interface's List function
 func (o *ovsdbClient) List(ctx context.Context, result interface{}) error {
 	primaryDB := o.primaryDB()
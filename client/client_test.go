@@ -2,18 +2,32 @@ package client
 
 import (
 	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	crand "crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
+	"io/ioutil"
+	"math/big"
 	"math/rand"
+	"net"
 	"os"
+	"path/filepath"
 	"reflect"
 	"strings"
+	"sync"
 	"sync/atomic"
 	"testing"
 	"time"
 
 	"github.com/cenkalti/backoff/v4"
 	"github.com/cenkalti/rpc2"
+	"github.com/cenkalti/rpc2/jsonrpc"
 	"github.com/google/uuid"
 	"github.com/ovn-org/libovsdb/cache"
 	db "github.com/ovn-org/libovsdb/database"
@@ -24,6 +38,7 @@ import (
 	"github.com/ovn-org/libovsdb/server"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"go.uber.org/goleak"
 )
 
 var (
@@ -903,6 +918,116 @@ func newOVSDBServer(t *testing.T, dbModel model.ClientDBModel, schema ovsdb.Data
 	return server, tmpfile
 }
 
+// tempSocket returns the path of a unix socket in a directory removed when
+// the test ends
+func tempSocket(t *testing.T) string {
+	dir, err := ioutil.TempDir("", "ovsdb")
+	require.NoError(t, err)
+	t.Cleanup(func() { os.RemoveAll(dir) })
+	return filepath.Join(dir, "ovsdb.sock")
+}
+
+// stubServer is a minimal OVSDB server for the tests that control the replies
+// of the server. It replies to list_dbs and get_schema with the test schema,
+// unless the test handles them, and to the other methods with the handlers of
+// the test, which must be registered with Handle before calling Serve.
+type stubServer struct {
+	*rpc2.Server
+	t       *testing.T
+	schema  ovsdb.DatabaseSchema
+	handled map[string]bool
+	// done is closed when the test ends, releasing the handlers that block
+	// to never reply
+	done  chan struct{}
+	mutex sync.Mutex
+	conns []net.Conn
+}
+
+func newStubServer(t *testing.T) *stubServer {
+	s := &stubServer{
+		Server:  rpc2.NewServer(),
+		t:       t,
+		handled: make(map[string]bool),
+		done:    make(chan struct{}),
+	}
+	require.NoError(t, json.Unmarshal([]byte(schema), &s.schema))
+	return s
+}
+
+// Handle registers the handler of a method, see rpc2.Server.Handle
+func (s *stubServer) Handle(method string, handler interface{}) {
+	s.handled[method] = true
+	s.Server.Handle(method, handler)
+}
+
+// Serve starts accepting connections and returns the path of the unix socket
+// the server listens on
+func (s *stubServer) Serve() string {
+	if !s.handled["list_dbs"] {
+		s.Handle("list_dbs", func(_ *rpc2.Client, _ []interface{}, reply *[]string) error {
+			*reply = []string{s.schema.Name}
+			return nil
+		})
+	}
+	if !s.handled["get_schema"] {
+		s.Handle("get_schema", func(_ *rpc2.Client, _ []interface{}, reply *ovsdb.DatabaseSchema) error {
+			*reply = s.schema
+			return nil
+		})
+	}
+
+	sock := tempSocket(s.t)
+	lis, err := net.Listen("unix", sock)
+	require.NoError(s.t, err)
+	s.t.Cleanup(func() {
+		close(s.done)
+		lis.Close()
+		s.Disconnect()
+	})
+	go func() {
+		for {
+			conn, err := lis.Accept()
+			if err != nil {
+				return
+			}
+			s.mutex.Lock()
+			s.conns = append(s.conns, conn)
+			s.mutex.Unlock()
+			go s.ServeCodec(&serializedCodec{Codec: jsonrpc.NewJSONCodec(conn)})
+		}
+	}()
+	return sock
+}
+
+// Disconnect closes the connections of all the clients
+func (s *stubServer) Disconnect() {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	for _, conn := range s.conns {
+		conn.Close()
+	}
+	s.conns = nil
+}
+
+// serializedCodec serializes the messages sent by a stubServer, as rpc2 sends
+// the replies of concurrent handlers without locking the codec
+type serializedCodec struct {
+	rpc2.Codec
+	mutex sync.Mutex
+}
+
+func (c *serializedCodec) WriteRequest(r *rpc2.Request, x interface{}) error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	return c.Codec.WriteRequest(r, x)
+}
+
+func (c *serializedCodec) WriteResponse(r *rpc2.Response, x interface{}) error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	return c.Codec.WriteResponse(r, x)
+}
+
 func newClientServerPair(t *testing.T, connectCounter *int32, isLeader bool) (Client, *serverdb.Database, string) {
 	var defSchema ovsdb.DatabaseSchema
 	err := json.Unmarshal([]byte(schema), &defSchema)
@@ -1016,6 +1141,478 @@ func TestClientReconnectLeaderOnly(t *testing.T) {
 	}, 2*time.Second, 10*time.Millisecond)
 }
 
+func TestClientConnectLeaderOnlyCluster(t *testing.T) {
+	rand.Seed(time.Now().UnixNano())
+
+	var connected1, connected2, connected3 int32
+	_, _, endpoint1 := newClientServerPair(t, &connected1, false)
+	cli2, row2, endpoint2 := newClientServerPair(t, &connected2, true)
+	cli3, row3, endpoint3 := newClientServerPair(t, &connected3, false)
+
+	// the first endpoint is a follower, so the client should skip it
+	ovs, err := newOVSDBClient(defDB,
+		WithLeaderOnly(true),
+		WithReconnect(5*time.Second, &backoff.ZeroBackOff{}),
+		WithEndpoint(endpoint1),
+		WithEndpoint(endpoint2),
+		WithEndpoint(endpoint3))
+	require.NoError(t, err)
+	err = ovs.Connect(context.Background())
+	require.NoError(t, err)
+	t.Cleanup(ovs.Close)
+	assert.Equal(t, endpoint2, ovs.CurrentEndpoint())
+
+	// on failover the client should move to the new leader
+	setLeader(t, cli3, row3, true)
+	setLeader(t, cli2, row2, false)
+	require.Eventually(t, func() bool {
+		return ovs.CurrentEndpoint() == endpoint3
+	}, 2*time.Second, 10*time.Millisecond)
+	require.True(t, ovs.Connected())
+}
+
+func TestClientReconnectMonitors(t *testing.T) {
+	var defSchema ovsdb.DatabaseSchema
+	err := json.Unmarshal([]byte(schema), &defSchema)
+	require.NoError(t, err)
+	_, sock := newOVSDBServer(t, defDB, defSchema)
+
+	ovs, err := newOVSDBClient(defDB,
+		WithEndpoint(fmt.Sprintf("unix:%s", sock)),
+		WithReconnect(5*time.Second, &backoff.ZeroBackOff{}))
+	require.NoError(t, err)
+	err = ovs.Connect(context.Background())
+	require.NoError(t, err)
+	t.Cleanup(ovs.Close)
+
+	_, err = ovs.MonitorAll(context.Background())
+	require.NoError(t, err)
+
+	insert := func(name string) {
+		ops := []ovsdb.Operation{{Op: ovsdb.OperationInsert, Table: "Bridge", Row: ovsdb.Row{"name": name}}}
+		reply, err := ovs.Transact(context.Background(), ops...)
+		require.NoError(t, err)
+		_, err = ovsdb.CheckOperationResults(reply, ops)
+		require.NoError(t, err)
+	}
+	insert("br0")
+	require.Eventually(t, func() bool {
+		return ovs.Cache().Table("Bridge").Len() == 1
+	}, 2*time.Second, 10*time.Millisecond)
+
+	reconnected := ovs.ReconnectNotify()
+	ovs.Disconnect()
+	select {
+	case <-reconnected:
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for the client to reconnect")
+	}
+	require.True(t, ovs.Connected())
+
+	// the monitor was re-registered, so new rows still reach the cache
+	primaryDB := ovs.primaryDB()
+	primaryDB.monitorsMutex.Lock()
+	assert.Len(t, primaryDB.monitors, 1)
+	primaryDB.monitorsMutex.Unlock()
+	insert("br1")
+	require.Eventually(t, func() bool {
+		return ovs.Cache().Table("Bridge").Len() == 2
+	}, 2*time.Second, 10*time.Millisecond)
+}
+
+func TestClientReconnectNoSpuriousEvents(t *testing.T) {
+	var defSchema ovsdb.DatabaseSchema
+	err := json.Unmarshal([]byte(schema), &defSchema)
+	require.NoError(t, err)
+	_, sock := newOVSDBServer(t, defDB, defSchema)
+
+	ovs, err := newOVSDBClient(defDB,
+		WithEndpoint(fmt.Sprintf("unix:%s", sock)),
+		WithReconnect(5*time.Second, &backoff.ZeroBackOff{}))
+	require.NoError(t, err)
+	err = ovs.Connect(context.Background())
+	require.NoError(t, err)
+	t.Cleanup(ovs.Close)
+
+	// the tables are monitored separately, so that re-monitoring one of them
+	// must not drop the rows of the other
+	_, err = ovs.Monitor(context.Background(), ovs.NewMonitor(WithTable(&OpenvSwitch{})))
+	require.NoError(t, err)
+	_, err = ovs.Monitor(context.Background(), ovs.NewMonitor(WithTable(&Bridge{})))
+	require.NoError(t, err)
+
+	ops, err := ovs.Create(&OpenvSwitch{UUID: "ovs", NextCfg: 1}, &Bridge{UUID: "br0", Name: "br0"})
+	require.NoError(t, err)
+	reply, err := ovs.Transact(context.Background(), ops...)
+	require.NoError(t, err)
+	_, err = ovsdb.CheckOperationResults(reply, ops)
+	require.NoError(t, err)
+	require.Eventually(t, func() bool {
+		return ovs.Cache().Table("Open_vSwitch").Len() == 1 && ovs.Cache().Table("Bridge").Len() == 1
+	}, 2*time.Second, 10*time.Millisecond)
+
+	var eventsMutex sync.Mutex
+	var events []string
+	record := func(event, table string) {
+		eventsMutex.Lock()
+		defer eventsMutex.Unlock()
+		events = append(events, event+" "+table)
+	}
+	ovs.Cache().AddEventHandler(&cache.EventHandlerFuncs{
+		AddFunc: func(table string, _ model.Model) {
+			record("add", table)
+		},
+		UpdateFunc: func(table string, _, _ model.Model) {
+			record("update", table)
+		},
+		DeleteFunc: func(table string, _ model.Model) {
+			record("delete", table)
+		},
+	})
+
+	reconnected := ovs.ReconnectNotify()
+	ovs.Disconnect()
+	select {
+	case <-reconnected:
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for the client to reconnect")
+	}
+	require.True(t, ovs.Connected())
+	assert.Equal(t, 1, ovs.Cache().Table("Open_vSwitch").Len())
+	assert.Equal(t, 1, ovs.Cache().Table("Bridge").Len())
+
+	// events are processed in order, so once the event of a new row is seen
+	// any event of the resync would have been seen too
+	ops, err = ovs.Create(&OpenvSwitch{UUID: "ovs1", NextCfg: 2})
+	require.NoError(t, err)
+	reply, err = ovs.Transact(context.Background(), ops...)
+	require.NoError(t, err)
+	_, err = ovsdb.CheckOperationResults(reply, ops)
+	require.NoError(t, err)
+	require.Eventually(t, func() bool {
+		eventsMutex.Lock()
+		defer eventsMutex.Unlock()
+		return len(events) > 0
+	}, 2*time.Second, 10*time.Millisecond)
+	eventsMutex.Lock()
+	defer eventsMutex.Unlock()
+	assert.Equal(t, []string{"add Open_vSwitch"}, events)
+	assert.Equal(t, 1, ovs.Cache().Table("Bridge").Len())
+}
+
+func TestConnectionCallbacks(t *testing.T) {
+	var defSchema ovsdb.DatabaseSchema
+	err := json.Unmarshal([]byte(schema), &defSchema)
+	require.NoError(t, err)
+	_, sock := newOVSDBServer(t, defDB, defSchema)
+
+	events := make(chan string, 10)
+	var ovs *ovsdbClient
+	ovs, err = newOVSDBClient(defDB,
+		WithEndpoint(fmt.Sprintf("unix:%s", sock)),
+		WithReconnect(5*time.Second, &backoff.ZeroBackOff{}),
+		WithConnectionCallback(func() {
+			// the callbacks can use the client
+			events <- fmt.Sprintf("connect %s %v", ovs.State(), ovs.Echo(context.Background()))
+		}, func() {
+			events <- "disconnect"
+		}))
+	require.NoError(t, err)
+	assert.Equal(t, Disconnected, ovs.State())
+
+	next := func() string {
+		select {
+		case event := <-events:
+			return event
+		case <-time.After(5 * time.Second):
+			t.Fatal("timed out waiting for a connection callback")
+		}
+		return ""
+	}
+
+	err = ovs.Connect(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, Connected, ovs.State())
+	assert.Equal(t, "connect connected <nil>", next())
+
+	ovs.Disconnect()
+	assert.Equal(t, "disconnect", next())
+	assert.Equal(t, "connect connected <nil>", next())
+	assert.Equal(t, Connected, ovs.State())
+
+	ovs.Close()
+	assert.Equal(t, "disconnect", next())
+	assert.Equal(t, Disconnected, ovs.State())
+	select {
+	case event := <-events:
+		t.Fatalf("unexpected connection callback: %s", event)
+	case <-time.After(100 * time.Millisecond):
+	}
+}
+
+func TestConnectionStateConnectFailure(t *testing.T) {
+	called := false
+	ovs, err := newOVSDBClient(defDB,
+		WithEndpoint("unix:/tmp/libovsdb-does-not-exist.sock"),
+		WithConnectionCallback(func() { called = true }, func() { called = true }))
+	require.NoError(t, err)
+	err = ovs.Connect(context.Background())
+	require.Error(t, err)
+	assert.Equal(t, Disconnected, ovs.State())
+	assert.False(t, called)
+}
+
+func TestCreateNamedUUIDs(t *testing.T) {
+	var defSchema ovsdb.DatabaseSchema
+	err := json.Unmarshal([]byte(schema), &defSchema)
+	require.NoError(t, err)
+	_, sock := newOVSDBServer(t, defDB, defSchema)
+
+	ovs, err := newOVSDBClient(defDB, WithEndpoint(fmt.Sprintf("unix:%s", sock)))
+	require.NoError(t, err)
+	err = ovs.Connect(context.Background())
+	require.NoError(t, err)
+	t.Cleanup(ovs.Close)
+	_, err = ovs.MonitorAll(context.Background())
+	require.NoError(t, err)
+
+	// the Open_vSwitch row references the bridge created in the same
+	// transaction by its named-uuid
+	br := &Bridge{UUID: "br0", Name: "br0"}
+	ovsRow := &OpenvSwitch{UUID: "ovs", Bridges: []string{br.UUID}}
+	ops, err := ovs.Create(br, ovsRow)
+	require.NoError(t, err)
+	require.Equal(t, "br0", ops[0].UUIDName)
+	require.Equal(t, "ovs", ops[1].UUIDName)
+
+	reply, err := ovs.Transact(context.Background(), ops...)
+	require.NoError(t, err)
+	_, err = ovsdb.CheckOperationResults(reply, ops)
+	require.NoError(t, err)
+
+	uuids, err := ovsdb.NamedUUIDs(ops, reply)
+	require.NoError(t, err)
+	require.Len(t, uuids, 2)
+	require.Eventually(t, func() bool {
+		m := ovs.Cache().Table("Open_vSwitch").Row(uuids["ovs"])
+		if m == nil {
+			return false
+		}
+		return reflect.DeepEqual([]string{uuids["br0"]}, m.(*OpenvSwitch).Bridges)
+	}, 2*time.Second, 10*time.Millisecond)
+}
+
+func TestConnectSchemaMissingTable(t *testing.T) {
+	var defSchema ovsdb.DatabaseSchema
+	err := json.Unmarshal([]byte(schema), &defSchema)
+	require.NoError(t, err)
+	delete(defSchema.Tables, "Bridge")
+
+	// the server does not know about the Bridge table
+	serverModel, err := model.NewClientDBModel("Open_vSwitch", map[string]model.Model{
+		"Open_vSwitch": &OpenvSwitch{},
+	})
+	require.NoError(t, err)
+	_, sock := newOVSDBServer(t, serverModel, defSchema)
+
+	ovs, err := newOVSDBClient(defDB, WithEndpoint(fmt.Sprintf("unix:%s", sock)))
+	require.NoError(t, err)
+	err = ovs.Connect(context.Background())
+	require.Error(t, err)
+	t.Cleanup(ovs.Close)
+
+	var errValidation *ErrDatabaseValidation
+	require.ErrorAs(t, err, &errValidation)
+	assert.Len(t, errValidation.Errors(), 1)
+	var errTableNotFound *model.ErrTableNotFound
+	require.ErrorAs(t, err, &errTableNotFound)
+	assert.Equal(t, "Bridge", errTableNotFound.Table())
+}
+
+// condSinceServer is a minimal OVSDB server that replies to
+// monitor_cond_since requests with a predefined sequence of replies
+type condSinceServer struct {
+	*stubServer
+	mutex   sync.Mutex
+	replies []ovsdb.MonitorCondSinceReply
+	txnIDs  []string
+}
+
+func newCondSinceServer(t *testing.T, replies ...ovsdb.MonitorCondSinceReply) (*condSinceServer, string) {
+	s := &condSinceServer{stubServer: newStubServer(t), replies: replies}
+	s.Handle("monitor_cond_since", func(_ *rpc2.Client, args []json.RawMessage, reply *ovsdb.MonitorCondSinceReply) error {
+		var txnID string
+		if err := json.Unmarshal(args[3], &txnID); err != nil {
+			return err
+		}
+		s.mutex.Lock()
+		defer s.mutex.Unlock()
+		s.txnIDs = append(s.txnIDs, txnID)
+		if len(s.replies) == 0 {
+			return fmt.Errorf("unexpected monitor_cond_since request")
+		}
+		*reply = s.replies[0]
+		s.replies = s.replies[1:]
+		return nil
+	})
+	return s, s.Serve()
+}
+
+func (s *condSinceServer) requestedTxnIDs() []string {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return append([]string{}, s.txnIDs...)
+}
+
+func TestMonitorCondSinceReconnect(t *testing.T) {
+	bridgeUpdate := func(uuid, name string, initial bool) ovsdb.TableUpdates2 {
+		row := ovsdb.Row{"_uuid": ovsdb.UUID{GoUUID: uuid}, "name": name}
+		update := ovsdb.RowUpdate2{Insert: &row}
+		if initial {
+			update = ovsdb.RowUpdate2{Initial: &row}
+		}
+		return ovsdb.TableUpdates2{"Bridge": {uuid: &update}}
+	}
+
+	tests := []struct {
+		name  string
+		reply ovsdb.MonitorCondSinceReply
+		rows  []string
+	}{
+		{
+			name: "transaction found",
+			reply: ovsdb.MonitorCondSinceReply{
+				Found:             true,
+				LastTransactionID: "txn2",
+				Updates:           bridgeUpdate(aUUID1, "bar", false),
+			},
+			rows: []string{aUUID0, aUUID1},
+		},
+		{
+			name: "transaction not found",
+			reply: ovsdb.MonitorCondSinceReply{
+				Found:             false,
+				LastTransactionID: "txn2",
+				Updates:           bridgeUpdate(aUUID1, "bar", true),
+			},
+			rows: []string{aUUID1},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// the first monitor never finds a transaction, but the
+			// server still provides its latest one
+			first := ovsdb.MonitorCondSinceReply{
+				Found:             false,
+				LastTransactionID: "txn1",
+				Updates:           bridgeUpdate(aUUID0, "foo", true),
+			}
+			s, sock := newCondSinceServer(t, first, tt.reply)
+
+			ovs, err := newOVSDBClient(defDB,
+				WithEndpoint(fmt.Sprintf("unix:%s", sock)),
+				WithReconnect(5*time.Second, &backoff.ZeroBackOff{}))
+			require.NoError(t, err)
+			err = ovs.Connect(context.Background())
+			require.NoError(t, err)
+			t.Cleanup(ovs.Close)
+
+			_, err = ovs.Monitor(context.Background(), ovs.NewMonitor(WithTable(&Bridge{})))
+			require.NoError(t, err)
+			assert.Equal(t, []string{emptyUUID}, s.requestedTxnIDs())
+			assert.Equal(t, 1, ovs.Cache().Table("Bridge").Len())
+
+			// on reconnect the client resumes from the last transaction
+			s.Disconnect()
+			require.Eventually(t, func() bool {
+				return len(s.requestedTxnIDs()) == 2
+			}, 2*time.Second, 10*time.Millisecond)
+			assert.Equal(t, "txn1", s.requestedTxnIDs()[1])
+
+			require.Eventually(t, func() bool {
+				rows := ovs.Cache().Table("Bridge").Rows()
+				if len(rows) != len(tt.rows) {
+					return false
+				}
+				for _, uuid := range tt.rows {
+					if _, ok := rows[uuid]; !ok {
+						return false
+					}
+				}
+				return true
+			}, 2*time.Second, 10*time.Millisecond)
+		})
+	}
+}
+
+// newEchoServer starts a minimal OVSDB server. If it is not responsive, its
+// echo handler never replies, simulating a dead server.
+func newEchoServer(t *testing.T, responsive bool, onConnect func(*rpc2.Client)) string {
+	srv := newStubServer(t)
+	srv.Handle("echo", func(_ *rpc2.Client, args []interface{}, reply *[]interface{}) error {
+		if !responsive {
+			<-srv.done
+		}
+		*reply = args
+		return nil
+	})
+	if onConnect != nil {
+		srv.OnConnect(onConnect)
+	}
+	return srv.Serve()
+}
+
+func TestInactivityProbe(t *testing.T) {
+	sock := newEchoServer(t, false, nil)
+
+	ovs, err := newOVSDBClient(defDB,
+		WithEndpoint(fmt.Sprintf("unix:%s", sock)),
+		WithInactivityProbe(100*time.Millisecond))
+	require.NoError(t, err)
+	err = ovs.Connect(context.Background())
+	require.NoError(t, err)
+	t.Cleanup(ovs.Close)
+
+	// the server never replies to the echo, so the client disconnects
+	select {
+	case <-ovs.DisconnectNotify():
+	case <-time.After(2 * time.Second):
+		t.Fatal("timed out waiting for the client to disconnect")
+	}
+	assert.False(t, ovs.Connected())
+}
+
+func TestInactivityProbeResponsiveServer(t *testing.T) {
+	echoed := make(chan []interface{}, 1)
+	sock := newEchoServer(t, true, func(c *rpc2.Client) {
+		// the server probes the client too
+		go func() {
+			var reply []interface{}
+			if err := c.Call("echo", []interface{}{"probe"}, &reply); err == nil {
+				echoed <- reply
+			}
+		}()
+	})
+
+	ovs, err := newOVSDBClient(defDB,
+		WithEndpoint(fmt.Sprintf("unix:%s", sock)),
+		WithInactivityProbe(50*time.Millisecond))
+	require.NoError(t, err)
+	err = ovs.Connect(context.Background())
+	require.NoError(t, err)
+	t.Cleanup(ovs.Close)
+
+	select {
+	case reply := <-echoed:
+		assert.Equal(t, []interface{}{"probe"}, reply)
+	case <-time.After(2 * time.Second):
+		t.Fatal("timed out waiting for the client to reply to the echo")
+	}
+	require.Never(t, func() bool {
+		return !ovs.Connected()
+	}, 500*time.Millisecond, 10*time.Millisecond)
+}
+
 func TestNewMonitorRequest(t *testing.T) {
 	var testSchema = []byte(`{
   "cksum": "223619766 22548",
@@ -1080,4 +1677,1323 @@ func TestNewMonitorRequest(t *testing.T) {
 	mr2, err := newMonitorRequest(info, []string{"int1", "name"}, nil)
 	require.NoError(t, err)
 	assert.ElementsMatch(t, mr2.Columns, []string{"int1", "name"})
+
+	conditions := []ovsdb.Condition{ovsdb.NewCondition("name", ovsdb.ConditionEqual, "foo")}
+	mr3, err := newMonitorRequest(info, nil, conditions)
+	require.NoError(t, err)
+	assert.Equal(t, conditions, mr3.Where)
+	// no conditions result in a request for every row
+	assert.Nil(t, mr.Where)
+
+	var errColumnNotFound *mapper.ErrColumnNotFound
+	_, err = newMonitorRequest(info, []string{"missing"}, nil)
+	assert.ErrorAs(t, err, &errColumnNotFound)
+	conditions = []ovsdb.Condition{ovsdb.NewCondition("missing", ovsdb.ConditionEqual, "foo")}
+	_, err = newMonitorRequest(info, nil, conditions)
+	assert.ErrorAs(t, err, &errColumnNotFound)
+}
+
+// newTLSProxy starts a TLS listener on the loopback interface with a freshly
+// generated self-signed certificate that forwards every connection to the
+// given unix socket. It returns the listener address and a pool containing
+// the certificate.
+func newTLSProxy(t *testing.T, sock string) (string, *x509.CertPool) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), crand.Reader)
+	require.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "ovsdb-test"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+		IPAddresses:           []net.IP{net.ParseIP("127.0.0.1")},
+	}
+	der, err := x509.CreateCertificate(crand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+	pool := x509.NewCertPool()
+	pool.AddCert(cert)
+
+	l, err := tls.Listen("tcp", "127.0.0.1:0", &tls.Config{
+		Certificates: []tls.Certificate{{Certificate: [][]byte{der}, PrivateKey: key}},
+	})
+	require.NoError(t, err)
+	t.Cleanup(func() { l.Close() })
+	go func() {
+		for {
+			conn, err := l.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer conn.Close()
+				backend, err := net.Dial("unix", sock)
+				if err != nil {
+					return
+				}
+				defer backend.Close()
+				go func() {
+					_, _ = io.Copy(backend, conn)
+					backend.Close()
+				}()
+				_, _ = io.Copy(conn, backend)
+			}()
+		}
+	}()
+	return l.Addr().String(), pool
+}
+
+func TestClientConnectSSL(t *testing.T) {
+	var defSchema ovsdb.DatabaseSchema
+	err := json.Unmarshal([]byte(schema), &defSchema)
+	require.NoError(t, err)
+	_, sock := newOVSDBServer(t, defDB, defSchema)
+	addr, pool := newTLSProxy(t, sock)
+
+	t.Run("trusted certificate", func(t *testing.T) {
+		ovs, err := newOVSDBClient(defDB,
+			WithEndpoint(fmt.Sprintf("ssl:%s", addr)),
+			WithTLSConfig(&tls.Config{RootCAs: pool}))
+		require.NoError(t, err)
+		err = ovs.Connect(context.Background())
+		require.NoError(t, err)
+		t.Cleanup(ovs.Close)
+		assert.True(t, ovs.Connected())
+		_, err = ovs.MonitorAll(context.Background())
+		require.NoError(t, err)
+	})
+
+	t.Run("untrusted certificate", func(t *testing.T) {
+		ovs, err := newOVSDBClient(defDB,
+			WithEndpoint(fmt.Sprintf("ssl:%s", addr)),
+			WithTLSConfig(&tls.Config{RootCAs: x509.NewCertPool()}))
+		require.NoError(t, err)
+		err = ovs.Connect(context.Background())
+		require.Error(t, err)
+		var handshakeErr *ErrTLSHandshake
+		assert.ErrorAs(t, err, &handshakeErr)
+		assert.False(t, ovs.Connected())
+	})
+}
+
+// newCondChangeServer starts a minimal OVSDB server that replies to
+// monitor_cond_since with the initial rows and to monitor_cond_change by
+// sending the updates returned by onChange as an update3 notification before
+// its reply, the way ovsdb-server reports rows that stopped or started
+// matching
+func newCondChangeServer(t *testing.T, initial ovsdb.TableUpdates2, onChange func(map[string]ovsdb.MonitorCondChangeRequest) ovsdb.TableUpdates2) string {
+	srv := newStubServer(t)
+	srv.Handle("monitor_cond_since", func(_ *rpc2.Client, _ []json.RawMessage, reply *ovsdb.MonitorCondSinceReply) error {
+		*reply = ovsdb.MonitorCondSinceReply{Found: false, LastTransactionID: "txn1", Updates: initial}
+		return nil
+	})
+	srv.Handle("monitor_cond_change", func(client *rpc2.Client, args []json.RawMessage, reply *map[string]interface{}) error {
+		var cookie MonitorCookie
+		if err := json.Unmarshal(args[0], &cookie); err != nil {
+			return err
+		}
+		var requests map[string]ovsdb.MonitorCondChangeRequest
+		if err := json.Unmarshal(args[2], &requests); err != nil {
+			return err
+		}
+		updates := onChange(requests)
+		if err := client.Notify("update3", []interface{}{cookie, "txn2", updates}); err != nil {
+			return err
+		}
+		// like ovsdb-server, reply with an empty object
+		*reply = map[string]interface{}{}
+		return nil
+	})
+
+	return srv.Serve()
+}
+
+func TestMonitorCondChange(t *testing.T) {
+	br0 := ovsdb.Row{"_uuid": ovsdb.UUID{GoUUID: aUUID0}, "name": "br0"}
+	br1 := ovsdb.Row{"_uuid": ovsdb.UUID{GoUUID: aUUID1}, "name": "br1"}
+	initial := ovsdb.TableUpdates2{"Bridge": {
+		aUUID0: &ovsdb.RowUpdate2{Initial: &br0},
+		aUUID1: &ovsdb.RowUpdate2{Initial: &br1},
+	}}
+	var requested map[string]ovsdb.MonitorCondChangeRequest
+	var mutex sync.Mutex
+	sock := newCondChangeServer(t, initial, func(requests map[string]ovsdb.MonitorCondChangeRequest) ovsdb.TableUpdates2 {
+		mutex.Lock()
+		requested = requests
+		mutex.Unlock()
+		// br1 no longer matches the tightened condition
+		return ovsdb.TableUpdates2{"Bridge": {aUUID1: &ovsdb.RowUpdate2{Delete: &ovsdb.Row{}}}}
+	})
+
+	ovs, err := newOVSDBClient(defDB, WithEndpoint(fmt.Sprintf("unix:%s", sock)))
+	require.NoError(t, err)
+	err = ovs.Connect(context.Background())
+	require.NoError(t, err)
+	t.Cleanup(ovs.Close)
+
+	cookie, err := ovs.Monitor(context.Background(), ovs.NewMonitor(WithTable(&Bridge{})))
+	require.NoError(t, err)
+	require.Equal(t, 2, ovs.Cache().Table("Bridge").Len())
+
+	conditions := []ovsdb.Condition{ovsdb.NewCondition("name", ovsdb.ConditionEqual, "br0")}
+
+	t.Run("unknown monitor", func(t *testing.T) {
+		err := ovs.MonitorCondChange(context.Background(), MonitorCookie{DatabaseName: cookie.DatabaseName, ID: "foo"}, "Bridge", conditions)
+		assert.Error(t, err)
+	})
+
+	t.Run("table not monitored", func(t *testing.T) {
+		err := ovs.MonitorCondChange(context.Background(), cookie, "Open_vSwitch", conditions)
+		assert.Error(t, err)
+	})
+
+	t.Run("unknown column", func(t *testing.T) {
+		err := ovs.MonitorCondChange(context.Background(), cookie, "Bridge",
+			[]ovsdb.Condition{ovsdb.NewCondition("foo", ovsdb.ConditionEqual, "br0")})
+		var colErr *mapper.ErrColumnNotFound
+		assert.ErrorAs(t, err, &colErr)
+	})
+
+	t.Run("tighten conditions", func(t *testing.T) {
+		err := ovs.MonitorCondChange(context.Background(), cookie, "Bridge", conditions)
+		require.NoError(t, err)
+
+		mutex.Lock()
+		assert.Equal(t, map[string]ovsdb.MonitorCondChangeRequest{"Bridge": {Where: conditions}}, requested)
+		mutex.Unlock()
+
+		require.Eventually(t, func() bool {
+			return ovs.Cache().Table("Bridge").Len() == 1
+		}, 2*time.Second, 10*time.Millisecond)
+		assert.NotNil(t, ovs.Cache().Table("Bridge").Row(aUUID0))
+
+		// the new conditions are used if the monitor is restarted
+		db := ovs.primaryDB()
+		db.monitorsMutex.Lock()
+		assert.Equal(t, conditions, db.monitors[cookie.ID].Tables[0].Conditions)
+		db.monitorsMutex.Unlock()
+	})
+}
+
+func TestClientMinSchemaVersion(t *testing.T) {
+	var defSchema ovsdb.DatabaseSchema
+	err := json.Unmarshal([]byte(schema), &defSchema)
+	require.NoError(t, err)
+	_, sock := newOVSDBServer(t, defDB, defSchema)
+
+	tests := []struct {
+		name       string
+		minVersion string
+		wantErr    bool
+	}{
+		{
+			"older minimum version",
+			"8.1.9",
+			false,
+		},
+		{
+			"same version",
+			defSchema.Version,
+			false,
+		},
+		{
+			"newer minimum version",
+			"8.3.0",
+			true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ovs, err := newOVSDBClient(defDB,
+				WithEndpoint(fmt.Sprintf("unix:%s", sock)),
+				WithMinSchemaVersion(tt.minVersion))
+			require.NoError(t, err)
+			err = ovs.Connect(context.Background())
+			t.Cleanup(ovs.Close)
+			if tt.wantErr {
+				assert.ErrorContains(t, err, "is older than the minimum supported version "+tt.minVersion)
+				assert.False(t, ovs.Connected())
+			} else {
+				require.NoError(t, err)
+				assert.True(t, ovs.Connected())
+			}
+		})
+	}
+}
+
+func TestCallContextCancelled(t *testing.T) {
+	// once connected, the server reports the method of each request it gets
+	// and stops replying until the test ends
+	srv := newStubServer(t)
+	var block int32
+	received := make(chan string, 1)
+	wait := func(method string) {
+		if atomic.LoadInt32(&block) == 1 {
+			received <- method
+			<-srv.done
+		}
+	}
+	srv.Handle("list_dbs", func(_ *rpc2.Client, _ []interface{}, reply *[]string) error {
+		wait("list_dbs")
+		*reply = []string{srv.schema.Name}
+		return nil
+	})
+	srv.Handle("get_schema", func(_ *rpc2.Client, _ []interface{}, reply *ovsdb.DatabaseSchema) error {
+		wait("get_schema")
+		*reply = srv.schema
+		return nil
+	})
+	srv.Handle("echo", func(_ *rpc2.Client, args []interface{}, reply *[]interface{}) error {
+		wait("echo")
+		*reply = args
+		return nil
+	})
+	srv.Handle("transact", func(_ *rpc2.Client, _ []json.RawMessage, reply *[]ovsdb.OperationResult) error {
+		wait("transact")
+		*reply = []ovsdb.OperationResult{{}}
+		return nil
+	})
+	srv.Handle("monitor_cond_since", func(_ *rpc2.Client, _ []json.RawMessage, reply *ovsdb.MonitorCondSinceReply) error {
+		wait("monitor_cond_since")
+		*reply = ovsdb.MonitorCondSinceReply{LastTransactionID: "txn1", Updates: ovsdb.TableUpdates2{}}
+		return nil
+	})
+	sock := srv.Serve()
+
+	ovs, err := newOVSDBClient(defDB, WithEndpoint(fmt.Sprintf("unix:%s", sock)))
+	require.NoError(t, err)
+	err = ovs.Connect(context.Background())
+	require.NoError(t, err)
+	t.Cleanup(ovs.Close)
+	atomic.StoreInt32(&block, 1)
+
+	tests := []struct {
+		method string
+		call   func(ctx context.Context) error
+	}{
+		{
+			"transact",
+			func(ctx context.Context) error {
+				_, err := ovs.Transact(ctx, ovsdb.Operation{Op: ovsdb.OperationSelect, Table: "Bridge"})
+				return err
+			},
+		},
+		{
+			"monitor_cond_since",
+			func(ctx context.Context) error {
+				_, err := ovs.Monitor(ctx, ovs.NewMonitor(WithTable(&Bridge{})))
+				return err
+			},
+		},
+		{
+			"echo",
+			func(ctx context.Context) error {
+				return ovs.Echo(ctx)
+			},
+		},
+		{
+			"get_schema",
+			func(ctx context.Context) error {
+				ovs.rpcMutex.RLock()
+				defer ovs.rpcMutex.RUnlock()
+				_, err := ovs.getSchema(ctx, srv.schema.Name)
+				return err
+			},
+		},
+		{
+			"list_dbs",
+			func(ctx context.Context) error {
+				ovs.rpcMutex.RLock()
+				defer ovs.rpcMutex.RUnlock()
+				_, err := ovs.listDbs(ctx)
+				return err
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.method, func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			go func() {
+				// cancel once the server got the request
+				select {
+				case method := <-received:
+					assert.Equal(t, tt.method, method)
+					cancel()
+				case <-time.After(2 * time.Second):
+				}
+			}()
+			err := tt.call(ctx)
+			require.Error(t, err)
+			assert.ErrorIs(t, err, context.Canceled)
+			assert.Contains(t, err.Error(), tt.method)
+		})
+	}
+	assert.True(t, ovs.Connected())
+}
+
+func newDiscoveryServer(t *testing.T) string {
+	srv := newStubServer(t)
+	s := srv.schema
+	schemas := map[string]ovsdb.DatabaseSchema{
+		s.Name:   s,
+		serverDB: serverdb.Schema(),
+	}
+
+	srv.Handle("list_dbs", func(_ *rpc2.Client, _ []interface{}, reply *[]string) error {
+		*reply = []string{s.Name, serverDB}
+		return nil
+	})
+	srv.Handle("get_schema", func(_ *rpc2.Client, args []interface{}, reply *ovsdb.DatabaseSchema) error {
+		schema, ok := schemas[args[0].(string)]
+		if !ok {
+			return fmt.Errorf("unknown database")
+		}
+		*reply = schema
+		return nil
+	})
+	srv.Handle("monitor_cond", func(_ *rpc2.Client, args []json.RawMessage, reply *ovsdb.TableUpdates2) error {
+		var requests map[string]ovsdb.MonitorRequest
+		if err := json.Unmarshal(args[2], &requests); err != nil {
+			return err
+		}
+		if len(requests["Bridge"].Columns) != len(s.Tables["Bridge"].Columns) {
+			return fmt.Errorf("not every column is monitored")
+		}
+		*reply = ovsdb.TableUpdates2{"Bridge": {
+			aUUID0: &ovsdb.RowUpdate2{Initial: &ovsdb.Row{"name": "br0", "ports": ovsdb.OvsSet{GoSet: []interface{}{ovsdb.UUID{GoUUID: aUUID2}, ovsdb.UUID{GoUUID: aUUID3}}}}},
+			aUUID1: &ovsdb.RowUpdate2{Initial: &ovsdb.Row{"name": "br1", "external_ids": ovsdb.OvsMap{GoMap: map[interface{}]interface{}{"k": "v"}}}},
+		}}
+		return nil
+	})
+	srv.Handle("monitor_cancel", func(_ *rpc2.Client, _ []interface{}, reply *ovsdb.OperationResult) error {
+		return nil
+	})
+
+	sock := srv.Serve()
+	return sock
+}
+
+func TestDiscovery(t *testing.T) {
+	sock := newDiscoveryServer(t)
+	endpoint := WithEndpoint(fmt.Sprintf("unix:%s", sock))
+	ctx := context.Background()
+
+	t.Run("without a database model", func(t *testing.T) {
+		dbs, err := ListDatabases(ctx, WithEndpoint("unix:/tmp/does-not-exist.sock"), endpoint)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"Open_vSwitch", serverDB}, dbs)
+
+		for _, dbName := range dbs {
+			schema, err := GetSchema(ctx, dbName, endpoint)
+			require.NoError(t, err)
+			assert.Equal(t, dbName, schema.Name)
+			assert.NotEmpty(t, schema.Tables)
+		}
+		serverSchema, err := GetSchema(ctx, serverDB, endpoint)
+		require.NoError(t, err)
+		assert.Contains(t, serverSchema.Tables, "Database")
+
+		_, err = GetSchema(ctx, "Unknown", endpoint)
+		assert.Error(t, err)
+
+		_, err = ListDatabases(ctx, WithEndpoint("unix:/tmp/does-not-exist.sock"))
+		assert.Error(t, err)
+	})
+
+	t.Run("with a client", func(t *testing.T) {
+		ovs, err := newOVSDBClient(defDB, endpoint)
+		require.NoError(t, err)
+		_, err = ovs.ListDatabases(ctx)
+		assert.Equal(t, ErrNotConnected, err)
+		_, err = ovs.GetSchema(ctx, serverDB)
+		assert.Equal(t, ErrNotConnected, err)
+
+		err = ovs.Connect(ctx)
+		require.NoError(t, err)
+		t.Cleanup(ovs.Close)
+		dbs, err := ovs.ListDatabases(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"Open_vSwitch", serverDB}, dbs)
+		// any database can be queried, not only the one of the client
+		schema, err := ovs.GetSchema(ctx, serverDB)
+		require.NoError(t, err)
+		assert.Equal(t, serverDB, schema.Name)
+		assert.Contains(t, schema.Tables, "Database")
+	})
+}
+
+func TestMonitorTable(t *testing.T) {
+	sock := newDiscoveryServer(t)
+	endpoint := WithEndpoint(fmt.Sprintf("unix:%s", sock))
+	ctx := context.Background()
+
+	rows, err := MonitorTable(ctx, "Open_vSwitch", "Bridge", endpoint)
+	require.NoError(t, err)
+	// the rows keep the columns as the server sent them
+	assert.Equal(t, map[string]ovsdb.Row{
+		aUUID0: {"name": "br0", "ports": ovsdb.OvsSet{GoSet: []interface{}{ovsdb.UUID{GoUUID: aUUID2}, ovsdb.UUID{GoUUID: aUUID3}}}},
+		aUUID1: {"name": "br1", "external_ids": ovsdb.OvsMap{GoMap: map[interface{}]interface{}{"k": "v"}}},
+	}, rows)
+
+	_, err = MonitorTable(ctx, "Open_vSwitch", "Unknown", endpoint)
+	assert.Error(t, err)
+	_, err = MonitorTable(ctx, "Unknown", "Bridge", endpoint)
+	assert.Error(t, err)
+}
+
+func TestSelect(t *testing.T) {
+	var mutex sync.Mutex
+	var received []json.RawMessage
+	srv := newStubServer(t)
+	srv.Handle("transact", func(_ *rpc2.Client, args []json.RawMessage, reply *[]ovsdb.OperationResult) error {
+		mutex.Lock()
+		received = args
+		mutex.Unlock()
+		*reply = []ovsdb.OperationResult{{Rows: []ovsdb.Row{
+			{"_uuid": ovsdb.UUID{GoUUID: aUUID0}, "name": "br0"},
+			{"_uuid": ovsdb.UUID{GoUUID: aUUID1}, "name": "br1"},
+		}}}
+		return nil
+	})
+	sock := srv.Serve()
+
+	ovs, err := newOVSDBClient(defDB, WithEndpoint(fmt.Sprintf("unix:%s", sock)))
+	require.NoError(t, err)
+	_, err = ovs.Select(context.Background(), "Bridge", nil, nil)
+	assert.Equal(t, ErrNotConnected, err)
+	err = ovs.Connect(context.Background())
+	require.NoError(t, err)
+	t.Cleanup(ovs.Close)
+
+	tests := []struct {
+		name       string
+		conditions []ovsdb.Condition
+		columns    []string
+		wireOp     string
+	}{
+		{
+			"all rows and columns",
+			nil,
+			nil,
+			`{"op":"select","table":"Bridge","where":[]}`,
+		},
+		{
+			"some rows and columns",
+			[]ovsdb.Condition{ovsdb.NewCondition("name", ovsdb.ConditionNotEqual, "br2")},
+			[]string{"_uuid", "name"},
+			`{"op":"select","table":"Bridge","columns":["_uuid","name"],"where":[["name","!=","br2"]]}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			models, err := ovs.Select(context.Background(), "Bridge", tt.conditions, tt.columns)
+			require.NoError(t, err)
+			assert.Equal(t, []model.Model{
+				&Bridge{UUID: aUUID0, Name: "br0"},
+				&Bridge{UUID: aUUID1, Name: "br1"},
+			}, models)
+
+			mutex.Lock()
+			defer mutex.Unlock()
+			require.Len(t, received, 2)
+			assert.JSONEq(t, `"Open_vSwitch"`, string(received[0]))
+			assert.JSONEq(t, tt.wireOp, string(received[1]))
+		})
+	}
+
+	_, err = ovs.Select(context.Background(), "Unknown", nil, nil)
+	assert.Error(t, err)
+	_, err = ovs.Select(context.Background(), "Bridge", nil, []string{"unknown"})
+	assert.Error(t, err)
+}
+
+func TestTransactAborted(t *testing.T) {
+	srv := newStubServer(t)
+	srv.Handle("transact", func(_ *rpc2.Client, _ []json.RawMessage, reply *[]ovsdb.OperationResult) error {
+		// the wait operation fails, so the server aborts the transaction
+		// without executing the remaining operations
+		*reply = []ovsdb.OperationResult{{Error: "timed out", Details: `"wait" timed out`}}
+		return nil
+	})
+	sock := srv.Serve()
+
+	ovs, err := newOVSDBClient(defDB, WithEndpoint(fmt.Sprintf("unix:%s", sock)))
+	require.NoError(t, err)
+	err = ovs.Connect(context.Background())
+	require.NoError(t, err)
+	t.Cleanup(ovs.Close)
+
+	timeout := 0
+	br := &Bridge{UUID: aUUID0, Name: "br0"}
+	ops, err := ovs.Where(br).Wait(ovsdb.WaitConditionEqual, &timeout, br, &br.Name)
+	require.NoError(t, err)
+	br.ExternalIDs = map[string]string{"foo": "bar"}
+	updateOps, err := ovs.Where(br).Update(br, &br.ExternalIDs)
+	require.NoError(t, err)
+	ops = append(ops, updateOps...)
+
+	reply, err := ovs.Transact(context.Background(), ops...)
+	require.NoError(t, err)
+	_, err = ovsdb.CheckOperationResults(reply, ops)
+	require.ErrorIs(t, err, ovsdb.ErrAborted)
+	var abortErr *ovsdb.AbortError
+	require.ErrorAs(t, err, &abortErr)
+	assert.Equal(t, 0, abortErr.Index)
+	assert.Equal(t, ovsdb.OperationWait, abortErr.Reason.Operation().Op)
+	var timedOut *ovsdb.TimedOut
+	assert.ErrorAs(t, err, &timedOut)
+}
+
+type recordingMetrics struct {
+	mutex      sync.Mutex
+	rpcs       map[string]int
+	latencies  map[string]int
+	updates    map[string]int
+	cacheSizes map[string]int
+}
+
+func newRecordingMetrics() *recordingMetrics {
+	return &recordingMetrics{
+		rpcs:       make(map[string]int),
+		latencies:  make(map[string]int),
+		updates:    make(map[string]int),
+		cacheSizes: make(map[string]int),
+	}
+}
+
+func (m *recordingMetrics) RPCSent(method string) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.rpcs[method]++
+}
+
+func (m *recordingMetrics) RPCLatency(method string, _ time.Duration) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.latencies[method]++
+}
+
+func (m *recordingMetrics) Reconnected() {}
+
+func (m *recordingMetrics) UpdateApplied(database, table string) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.updates[database+"/"+table]++
+}
+
+func (m *recordingMetrics) CacheSize(database, table string, rows int) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.cacheSizes[database+"/"+table] = rows
+}
+
+func TestMetrics(t *testing.T) {
+	srv := newStubServer(t)
+	srv.Handle("transact", func(_ *rpc2.Client, _ []json.RawMessage, reply *[]ovsdb.OperationResult) error {
+		*reply = []ovsdb.OperationResult{{}}
+		return nil
+	})
+	srv.Handle("monitor_cond_since", func(_ *rpc2.Client, _ []json.RawMessage, reply *ovsdb.MonitorCondSinceReply) error {
+		*reply = ovsdb.MonitorCondSinceReply{LastTransactionID: "txn1", Updates: ovsdb.TableUpdates2{
+			"Bridge": {aUUID0: &ovsdb.RowUpdate2{Initial: &ovsdb.Row{"name": "br0"}}},
+		}}
+		return nil
+	})
+	sock := srv.Serve()
+
+	metrics := newRecordingMetrics()
+	ovs, err := newOVSDBClient(defDB, WithEndpoint(fmt.Sprintf("unix:%s", sock)), WithMetrics(metrics))
+	require.NoError(t, err)
+	err = ovs.Connect(context.Background())
+	require.NoError(t, err)
+	t.Cleanup(ovs.Close)
+
+	comment := "test"
+	for i := 0; i < 2; i++ {
+		_, err = ovs.Transact(context.Background(), ovsdb.Operation{Op: ovsdb.OperationComment, Comment: &comment})
+		require.NoError(t, err)
+	}
+	metrics.mutex.Lock()
+	assert.Equal(t, 2, metrics.rpcs["transact"])
+	assert.Equal(t, 2, metrics.latencies["transact"])
+	assert.Equal(t, 1, metrics.rpcs["list_dbs"])
+	assert.Equal(t, 1, metrics.rpcs["get_schema"])
+	metrics.mutex.Unlock()
+
+	_, err = ovs.Monitor(context.Background(), ovs.NewMonitor(WithTable(&Bridge{})))
+	require.NoError(t, err)
+	metrics.mutex.Lock()
+	assert.Equal(t, 1, metrics.rpcs["monitor_cond_since"])
+	assert.Equal(t, map[string]int{"Open_vSwitch/Bridge": 1}, metrics.updates)
+	assert.Equal(t, map[string]int{"Open_vSwitch/Bridge": 1}, metrics.cacheSizes)
+	metrics.mutex.Unlock()
+
+	update := []byte(`{
+		"Bridge": {
+			"` + aUUID1 + `": {"insert": {"name": "br1"}}
+		}
+	}`)
+	var reply []interface{}
+	err = ovs.update3([]json.RawMessage{[]byte(`{"databaseName":"Open_vSwitch","id":"v1"}`), []byte(`"txn2"`), update}, &reply)
+	require.NoError(t, err)
+	metrics.mutex.Lock()
+	assert.Equal(t, map[string]int{"Open_vSwitch/Bridge": 2}, metrics.updates)
+	assert.Equal(t, map[string]int{"Open_vSwitch/Bridge": 2}, metrics.cacheSizes)
+	metrics.mutex.Unlock()
+}
+
+func TestMonitorTableColumns(t *testing.T) {
+	var mutex sync.Mutex
+	var requests map[string]ovsdb.MonitorRequest
+	srv := newStubServer(t)
+	srv.Handle("monitor_cond_since", func(_ *rpc2.Client, args []json.RawMessage, reply *ovsdb.MonitorCondSinceReply) error {
+		mutex.Lock()
+		defer mutex.Unlock()
+		if err := json.Unmarshal(args[2], &requests); err != nil {
+			return err
+		}
+		*reply = ovsdb.MonitorCondSinceReply{LastTransactionID: "txn1", Updates: ovsdb.TableUpdates2{
+			"Bridge": {aUUID0: &ovsdb.RowUpdate2{Initial: &ovsdb.Row{"name": "br0", "datapath_type": "system"}}},
+		}}
+		return nil
+	})
+	sock := srv.Serve()
+
+	ovs, err := newOVSDBClient(defDB, WithEndpoint(fmt.Sprintf("unix:%s", sock)))
+	require.NoError(t, err)
+	err = ovs.Connect(context.Background())
+	require.NoError(t, err)
+	t.Cleanup(ovs.Close)
+
+	_, err = ovs.Monitor(context.Background(), ovs.NewMonitor(WithTableColumns(map[string][]string{
+		"Bridge": {"name", "datapath_type"},
+	})))
+	require.NoError(t, err)
+	mutex.Lock()
+	require.Contains(t, requests, "Bridge")
+	assert.ElementsMatch(t, []string{"name", "datapath_type"}, requests["Bridge"].Columns)
+	mutex.Unlock()
+
+	expected := &Bridge{UUID: aUUID0, Name: "br0", DatapathType: "system"}
+	assert.Equal(t, expected, ovs.Cache().Table("Bridge").Row(aUUID0))
+
+	// a modify only carries the monitored columns that changed
+	update := []byte(`{"Bridge": {"` + aUUID0 + `": {"modify": {"datapath_type": "netdev"}}}}`)
+	var reply []interface{}
+	err = ovs.update3([]json.RawMessage{[]byte(`{"databaseName":"Open_vSwitch","id":"v1"}`), []byte(`"txn2"`), update}, &reply)
+	require.NoError(t, err)
+	expected.DatapathType = "netdev"
+	assert.Equal(t, expected, ovs.Cache().Table("Bridge").Row(aUUID0))
+}
+
+func TestPing(t *testing.T) {
+	newPingServer := func(echo func(srv *stubServer, args []interface{}, reply *[]interface{})) *ovsdbClient {
+		srv := newStubServer(t)
+		srv.Handle("echo", func(_ *rpc2.Client, args []interface{}, reply *[]interface{}) error {
+			echo(srv, args, reply)
+			return nil
+		})
+		sock := srv.Serve()
+		ovs, err := newOVSDBClient(defDB, WithEndpoint(fmt.Sprintf("unix:%s", sock)))
+		require.NoError(t, err)
+		err = ovs.Connect(context.Background())
+		require.NoError(t, err)
+		t.Cleanup(ovs.Close)
+		return ovs
+	}
+
+	t.Run("immediate reply", func(t *testing.T) {
+		var mutex sync.Mutex
+		var payloads []interface{}
+		ovs := newPingServer(func(_ *stubServer, args []interface{}, reply *[]interface{}) {
+			mutex.Lock()
+			payloads = append(payloads, args[len(args)-1])
+			mutex.Unlock()
+			*reply = args
+		})
+		for i := 0; i < 2; i++ {
+			latency, err := ovs.Ping(context.Background())
+			require.NoError(t, err)
+			assert.Greater(t, latency, time.Duration(0))
+		}
+		mutex.Lock()
+		defer mutex.Unlock()
+		require.Len(t, payloads, 2)
+		assert.NotEqual(t, payloads[0], payloads[1], "every ping has a unique payload")
+	})
+
+	t.Run("mismatched reply", func(t *testing.T) {
+		ovs := newPingServer(func(_ *stubServer, args []interface{}, reply *[]interface{}) {
+			*reply = ovsdb.NewEchoArgs()
+		})
+		_, err := ovs.Ping(context.Background())
+		assert.ErrorContains(t, err, "incorrect server response")
+	})
+
+	t.Run("no reply", func(t *testing.T) {
+		ovs := newPingServer(func(srv *stubServer, args []interface{}, reply *[]interface{}) {
+			// do not reply before the test ends
+			<-srv.done
+		})
+		ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+		defer cancel()
+		latency, err := ovs.Ping(ctx)
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+		assert.Zero(t, latency)
+	})
+}
+
+func TestConcurrentCallsRouting(t *testing.T) {
+	sock := newEchoServer(t, true, nil)
+	ovs, err := newOVSDBClient(defDB, WithEndpoint(fmt.Sprintf("unix:%s", sock)))
+	require.NoError(t, err)
+	err = ovs.Connect(context.Background())
+	require.NoError(t, err)
+	t.Cleanup(ovs.Close)
+
+	// every ping carries a unique payload and fails if it gets the reply
+	// of another call
+	const callers, calls = 50, 20
+	var wg sync.WaitGroup
+	errs := make(chan error, callers*calls)
+	for i := 0; i < callers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < calls; j++ {
+				if _, err := ovs.Ping(context.Background()); err != nil {
+					errs <- err
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		assert.NoError(t, err)
+	}
+}
+
+func TestUnknownReplyID(t *testing.T) {
+	var s ovsdb.DatabaseSchema
+	err := json.Unmarshal([]byte(schema), &s)
+	require.NoError(t, err)
+
+	sock := tempSocket(t)
+	lis, err := net.Listen("unix", sock)
+	require.NoError(t, err)
+	t.Cleanup(func() { lis.Close() })
+	go func() {
+		conn, err := lis.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		dec := json.NewDecoder(conn)
+		enc := json.NewEncoder(conn)
+		for {
+			var req struct {
+				Method string            `json:"method"`
+				Params []json.RawMessage `json:"params"`
+				ID     json.RawMessage   `json:"id"`
+			}
+			if err := dec.Decode(&req); err != nil {
+				return
+			}
+			var result interface{}
+			switch req.Method {
+			case "list_dbs":
+				result = []string{s.Name}
+			case "get_schema":
+				result = s
+			case "echo":
+				// reply first to a request that was never sent
+				err := enc.Encode(map[string]interface{}{"id": 1 << 40, "result": []interface{}{"stray"}, "error": nil})
+				if err != nil {
+					return
+				}
+				result = req.Params
+			}
+			err := enc.Encode(map[string]interface{}{"id": req.ID, "result": result, "error": nil})
+			if err != nil {
+				return
+			}
+		}
+	}()
+
+	ovs, err := newOVSDBClient(defDB, WithEndpoint(fmt.Sprintf("unix:%s", sock)))
+	require.NoError(t, err)
+	err = ovs.Connect(context.Background())
+	require.NoError(t, err)
+	t.Cleanup(ovs.Close)
+
+	// the stray reply is discarded and the echo gets its own reply
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	err = ovs.Echo(ctx)
+	require.NoError(t, err)
+	err = ovs.Echo(ctx)
+	require.NoError(t, err)
+	assert.True(t, ovs.Connected())
+}
+
+func TestConcurrentTransactsAndMonitors(t *testing.T) {
+	var defSchema ovsdb.DatabaseSchema
+	err := json.Unmarshal([]byte(schema), &defSchema)
+	require.NoError(t, err)
+	_, sock := newOVSDBServer(t, defDB, defSchema)
+
+	ovs, err := newOVSDBClient(defDB, WithEndpoint(fmt.Sprintf("unix:%s", sock)))
+	require.NoError(t, err)
+	err = ovs.Connect(context.Background())
+	require.NoError(t, err)
+	t.Cleanup(ovs.Close)
+	_, err = ovs.Monitor(context.Background(), ovs.NewMonitor(WithTable(&OpenvSwitch{})))
+	require.NoError(t, err)
+
+	// requests of concurrent callers share the connection, each transaction
+	// must get the uuid of the row it inserted. The Bridge table stays empty
+	// so that the overlapping monitors do not insert the same rows.
+	const callers = 100
+	var wg sync.WaitGroup
+	var mutex sync.Mutex
+	inserted := make(map[string]int, callers/2)
+	errs := make(chan error, callers)
+	for i := 0; i < callers/2; i++ {
+		i := i
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			_, err := ovs.Monitor(context.Background(), ovs.NewMonitor(WithTable(&Bridge{})))
+			if err != nil {
+				errs <- err
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			ops := []ovsdb.Operation{{Op: ovsdb.OperationInsert, Table: "Open_vSwitch", Row: ovsdb.Row{"next_cfg": i}}}
+			reply, err := ovs.Transact(context.Background(), ops...)
+			if err == nil {
+				_, err = ovsdb.CheckOperationResults(reply, ops)
+			}
+			if err != nil {
+				errs <- err
+				return
+			}
+			mutex.Lock()
+			inserted[reply[0].UUID.GoUUID] = i
+			mutex.Unlock()
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		require.NoError(t, err)
+	}
+	require.Len(t, inserted, callers/2)
+
+	primaryDB := ovs.primaryDB()
+	primaryDB.monitorsMutex.Lock()
+	assert.Len(t, primaryDB.monitors, callers/2+1)
+	primaryDB.monitorsMutex.Unlock()
+	require.Eventually(t, func() bool {
+		return ovs.Cache().Table("Open_vSwitch").Len() == callers/2
+	}, 2*time.Second, 10*time.Millisecond)
+	for uuid, i := range inserted {
+		m := ovs.Cache().Table("Open_vSwitch").Row(uuid)
+		require.NotNil(t, m, "row %s not found", uuid)
+		assert.Equal(t, i, m.(*OpenvSwitch).NextCfg)
+	}
+}
+
+func TestMonitorCanceled(t *testing.T) {
+	var mutex sync.Mutex
+	var serverClient *rpc2.Client
+	srv := newStubServer(t)
+	srv.Handle("monitor_cond_since", func(client *rpc2.Client, args []json.RawMessage, reply *ovsdb.MonitorCondSinceReply) error {
+		mutex.Lock()
+		defer mutex.Unlock()
+		serverClient = client
+		var requests map[string]ovsdb.MonitorRequest
+		if err := json.Unmarshal(args[2], &requests); err != nil {
+			return err
+		}
+		updates := ovsdb.TableUpdates2{}
+		if _, ok := requests["Bridge"]; ok {
+			updates["Bridge"] = ovsdb.TableUpdate2{aUUID0: &ovsdb.RowUpdate2{Initial: &ovsdb.Row{"name": "br0"}}}
+		}
+		*reply = ovsdb.MonitorCondSinceReply{LastTransactionID: "txn1", Updates: updates}
+		return nil
+	})
+	sock := srv.Serve()
+
+	ovs, err := newOVSDBClient(defDB, WithEndpoint(fmt.Sprintf("unix:%s", sock)))
+	require.NoError(t, err)
+	err = ovs.Connect(context.Background())
+	require.NoError(t, err)
+	t.Cleanup(ovs.Close)
+	canceled := make(chan string, 1)
+	ovs.OnMonitorCanceled(func(dbName string) {
+		canceled <- dbName
+	})
+
+	bridgeCookie, err := ovs.Monitor(context.Background(), ovs.NewMonitor(WithTable(&Bridge{})))
+	require.NoError(t, err)
+	_, err = ovs.Monitor(context.Background(), ovs.NewMonitor(WithTable(&OpenvSwitch{})))
+	require.NoError(t, err)
+	var bridges []Bridge
+	require.NoError(t, ovs.List(context.Background(), &bridges))
+	require.Len(t, bridges, 1)
+
+	mutex.Lock()
+	err = serverClient.Notify("monitor_canceled", []interface{}{bridgeCookie})
+	mutex.Unlock()
+	require.NoError(t, err)
+	select {
+	case dbName := <-canceled:
+		assert.Equal(t, defDB.Name(), dbName)
+	case <-time.After(2 * time.Second):
+		t.Fatal("timed out waiting for the monitor to be canceled")
+	}
+
+	// the cache of the table is stale until it is monitored again
+	var errCacheInvalid *cache.ErrCacheInvalid
+	err = ovs.List(context.Background(), &bridges)
+	assert.True(t, errors.As(err, &errCacheInvalid), "unexpected error %v", err)
+	err = ovs.Get(context.Background(), &Bridge{UUID: aUUID0})
+	assert.True(t, errors.As(err, &errCacheInvalid), "unexpected error %v", err)
+	err = ovs.WhereCache(func(*Bridge) bool { return true }).List(context.Background(), &bridges)
+	assert.True(t, errors.As(err, &errCacheInvalid), "unexpected error %v", err)
+	var ovsRows []OpenvSwitch
+	assert.NoError(t, ovs.List(context.Background(), &ovsRows))
+	primaryDB := ovs.primaryDB()
+	primaryDB.monitorsMutex.Lock()
+	assert.NotContains(t, primaryDB.monitors, bridgeCookie.ID)
+	primaryDB.monitorsMutex.Unlock()
+
+	_, err = ovs.Monitor(context.Background(), ovs.NewMonitor(WithTable(&Bridge{})))
+	require.NoError(t, err)
+	bridges = nil
+	require.NoError(t, ovs.List(context.Background(), &bridges))
+	assert.Len(t, bridges, 1)
+}
+
+func TestMonitorCancel(t *testing.T) {
+	var defSchema ovsdb.DatabaseSchema
+	err := json.Unmarshal([]byte(schema), &defSchema)
+	require.NoError(t, err)
+
+	for _, deletes := range []bool{false, true} {
+		t.Run(fmt.Sprintf("delete events %v", deletes), func(t *testing.T) {
+			_, sock := newOVSDBServer(t, defDB, defSchema)
+			opts := []Option{WithEndpoint(fmt.Sprintf("unix:%s", sock))}
+			if deletes {
+				opts = append(opts, WithMonitorCancelDeletes())
+			}
+			ovs, err := newOVSDBClient(defDB, opts...)
+			require.NoError(t, err)
+			err = ovs.Connect(context.Background())
+			require.NoError(t, err)
+			t.Cleanup(ovs.Close)
+			canceled := make(chan string, 1)
+			ovs.OnMonitorCanceled(func(dbName string) {
+				canceled <- dbName
+			})
+
+			ovsCookie, err := ovs.Monitor(context.Background(), ovs.NewMonitor(WithTable(&OpenvSwitch{})))
+			require.NoError(t, err)
+			_, err = ovs.Monitor(context.Background(), ovs.NewMonitor(WithTable(&Bridge{})))
+			require.NoError(t, err)
+			ops, err := ovs.Create(&OpenvSwitch{UUID: "ovs", NextCfg: 1})
+			require.NoError(t, err)
+			_, err = ovs.Transact(context.Background(), ops...)
+			require.NoError(t, err)
+			require.Eventually(t, func() bool {
+				return ovs.Cache().Table("Open_vSwitch").Len() == 1
+			}, 2*time.Second, 10*time.Millisecond)
+
+			deleted := make(chan string, 1)
+			ovs.Cache().AddEventHandler(&cache.EventHandlerFuncs{
+				DeleteFunc: func(table string, _ model.Model) {
+					deleted <- table
+				},
+			})
+
+			err = ovs.MonitorCancel(context.Background(), ovsCookie)
+			require.NoError(t, err)
+			if deletes {
+				select {
+				case table := <-deleted:
+					assert.Equal(t, "Open_vSwitch", table)
+				case <-time.After(2 * time.Second):
+					t.Fatal("timed out waiting for the delete event")
+				}
+			} else {
+				select {
+				case dbName := <-canceled:
+					assert.Equal(t, defDB.Name(), dbName)
+				case <-time.After(2 * time.Second):
+					t.Fatal("timed out waiting for the monitor to be canceled")
+				}
+			}
+			select {
+			case table := <-deleted:
+				t.Fatalf("unexpected delete event for table %s", table)
+			case <-canceled:
+				t.Fatal("unexpected monitor canceled notification")
+			case <-time.After(100 * time.Millisecond):
+			}
+
+			// the rows of the table are dropped until it is monitored again
+			assert.Equal(t, 0, ovs.Cache().Table("Open_vSwitch").Len())
+			var errCacheInvalid *cache.ErrCacheInvalid
+			var ovsRows []OpenvSwitch
+			err = ovs.List(context.Background(), &ovsRows)
+			assert.True(t, errors.As(err, &errCacheInvalid), "unexpected error %v", err)
+			var bridges []Bridge
+			assert.NoError(t, ovs.List(context.Background(), &bridges))
+			primaryDB := ovs.primaryDB()
+			primaryDB.monitorsMutex.Lock()
+			assert.NotContains(t, primaryDB.monitors, ovsCookie.ID)
+			primaryDB.monitorsMutex.Unlock()
+
+			// the server no longer knows the monitor
+			err = ovs.MonitorCancel(context.Background(), ovsCookie)
+			assert.Error(t, err)
+
+			_, err = ovs.Monitor(context.Background(), ovs.NewMonitor(WithTable(&OpenvSwitch{})))
+			require.NoError(t, err)
+			require.NoError(t, ovs.List(context.Background(), &ovsRows))
+			assert.Len(t, ovsRows, 1)
+		})
+	}
+}
+
+func TestCreateOrUpdate(t *testing.T) {
+	var defSchema ovsdb.DatabaseSchema
+	err := json.Unmarshal([]byte(schema), &defSchema)
+	require.NoError(t, err)
+	_, sock := newOVSDBServer(t, defDB, defSchema)
+
+	ovs, err := newOVSDBClient(defDB, WithEndpoint(fmt.Sprintf("unix:%s", sock)))
+	require.NoError(t, err)
+	err = ovs.Connect(context.Background())
+	require.NoError(t, err)
+	t.Cleanup(ovs.Close)
+	_, err = ovs.Monitor(context.Background(), ovs.NewMonitor(WithTable(&OpenvSwitch{})))
+	require.NoError(t, err)
+
+	createOrUpdate := func(nextCfg, curCfg int) error {
+		row := &OpenvSwitch{NextCfg: nextCfg, CurCfg: curCfg}
+		ops, err := ovs.CreateOrUpdate(row, &row.NextCfg)
+		if err != nil {
+			return err
+		}
+		reply, err := ovs.Transact(context.Background(), ops...)
+		if err != nil {
+			return err
+		}
+		_, err = ovsdb.CheckOperationResults(reply, ops)
+		return err
+	}
+	curCfg := func(nextCfg int) []int {
+		var rows []OpenvSwitch
+		err := ovs.WhereCache(func(r *OpenvSwitch) bool { return r.NextCfg == nextCfg }).List(context.Background(), &rows)
+		require.NoError(t, err)
+		var result []int
+		for _, r := range rows {
+			result = append(result, r.CurCfg)
+		}
+		return result
+	}
+
+	// no row has next_cfg 1, so it is inserted
+	require.NoError(t, createOrUpdate(1, 1))
+	require.Eventually(t, func() bool { return assert.ObjectsAreEqual([]int{1}, curCfg(1)) }, time.Second, 10*time.Millisecond)
+
+	// then the row with next_cfg 1 is updated
+	require.NoError(t, createOrUpdate(1, 2))
+	require.Eventually(t, func() bool { return assert.ObjectsAreEqual([]int{2}, curCfg(1)) }, time.Second, 10*time.Millisecond)
+	assert.Equal(t, 1, ovs.Cache().Table("Open_vSwitch").Len())
+
+	// a row inserted after the operations were built fails the transaction
+	// instead of being duplicated
+	row := &OpenvSwitch{NextCfg: 2, CurCfg: 1}
+	ops, err := ovs.CreateOrUpdate(row, &row.NextCfg)
+	require.NoError(t, err)
+	require.NoError(t, createOrUpdate(2, 2))
+	reply, err := ovs.Transact(context.Background(), ops...)
+	require.NoError(t, err)
+	_, err = ovsdb.CheckOperationResults(reply, ops)
+	assert.Error(t, err)
+	require.Eventually(t, func() bool { return assert.ObjectsAreEqual([]int{2}, curCfg(2)) }, time.Second, 10*time.Millisecond)
+}
+
+func TestComment(t *testing.T) {
+	b, err := json.Marshal(Comment("reconcile bridge br0"))
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"op":"comment","comment":"reconcile bridge br0"}`, string(b))
+
+	var defSchema ovsdb.DatabaseSchema
+	err = json.Unmarshal([]byte(schema), &defSchema)
+	require.NoError(t, err)
+	_, sock := newOVSDBServer(t, defDB, defSchema)
+	ovs, err := newOVSDBClient(defDB, WithEndpoint(fmt.Sprintf("unix:%s", sock)))
+	require.NoError(t, err)
+	err = ovs.Connect(context.Background())
+	require.NoError(t, err)
+	t.Cleanup(ovs.Close)
+
+	ops := []ovsdb.Operation{
+		Comment("add a row"),
+		{Op: ovsdb.OperationInsert, Table: "Open_vSwitch", Row: ovsdb.Row{"next_cfg": 1}},
+	}
+	reply, err := ovs.Transact(context.Background(), ops...)
+	require.NoError(t, err)
+	_, err = ovsdb.CheckOperationResults(reply, ops)
+	require.NoError(t, err)
+
+	_, err = ovs.Transact(context.Background(), Comment(""), ops[1])
+	assert.Error(t, err)
+}
+
+func TestDisconnectContext(t *testing.T) {
+	// transact replies once the test releases it
+	transacting := make(chan struct{}, 1)
+	release := make(chan struct{})
+	srv := newStubServer(t)
+	srv.Handle("transact", func(_ *rpc2.Client, _ []json.RawMessage, reply *[]ovsdb.OperationResult) error {
+		transacting <- struct{}{}
+		<-release
+		*reply = []ovsdb.OperationResult{{}}
+		return nil
+	})
+	sock := srv.Serve()
+	defer goleak.VerifyNone(t, goleak.IgnoreCurrent())
+
+	ovs, err := newOVSDBClient(defDB, WithEndpoint(fmt.Sprintf("unix:%s", sock)))
+	require.NoError(t, err)
+	err = ovs.Connect(context.Background())
+	require.NoError(t, err)
+	t.Cleanup(ovs.Close)
+
+	transact := func() <-chan error {
+		errs := make(chan error, 1)
+		comment := "slow"
+		go func() {
+			_, err := ovs.Transact(context.Background(), ovsdb.Operation{Op: ovsdb.OperationComment, Comment: &comment})
+			errs <- err
+		}()
+		select {
+		case <-transacting:
+		case err := <-errs:
+			t.Fatalf("transact failed: %v", err)
+		}
+		return errs
+	}
+	draining := func() bool {
+		ovs.inflightMutex.Lock()
+		defer ovs.inflightMutex.Unlock()
+		return ovs.draining
+	}
+
+	// the in-flight transact completes before the connection is closed, new
+	// calls are rejected meanwhile
+	errs := transact()
+	disconnected := make(chan error, 1)
+	go func() {
+		disconnected <- ovs.DisconnectContext(context.Background())
+	}()
+	require.Eventually(t, draining, 2*time.Second, 10*time.Millisecond)
+	err = ovs.Echo(context.Background())
+	assert.True(t, errors.Is(err, ErrDisconnected), "unexpected error %v", err)
+	release <- struct{}{}
+	assert.NoError(t, <-errs)
+	assert.NoError(t, <-disconnected)
+	assert.Equal(t, Disconnected, ovs.State())
+
+	// the client connects again after disconnecting with no call in flight
+	err = ovs.Connect(context.Background())
+	require.NoError(t, err)
+	assert.NoError(t, ovs.DisconnectContext(context.Background()))
+	err = ovs.Connect(context.Background())
+	require.NoError(t, err)
+	_, err = ovs.ListDatabases(context.Background())
+	assert.NoError(t, err)
+	assert.NoError(t, ovs.DisconnectContext(context.Background()))
+
+	// the in-flight transact is aborted once the context is done
+	err = ovs.Connect(context.Background())
+	require.NoError(t, err)
+	errs = transact()
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	err = ovs.DisconnectContext(ctx)
+	assert.True(t, errors.Is(err, context.DeadlineExceeded), "unexpected error %v", err)
+	select {
+	case err = <-errs:
+		assert.True(t, errors.Is(err, ErrDisconnected), "unexpected error %v", err)
+	case <-time.After(2 * time.Second):
+		t.Fatal("timed out waiting for the transact to be aborted")
+	}
+	close(release)
+	ovs.Close()
+}
+
+func TestMonitorOnce(t *testing.T) {
+	var defSchema ovsdb.DatabaseSchema
+	err := json.Unmarshal([]byte(schema), &defSchema)
+	require.NoError(t, err)
+	_, sock := newOVSDBServer(t, defDB, defSchema)
+
+	metrics := newRecordingMetrics()
+	ovs, err := newOVSDBClient(defDB, WithEndpoint(fmt.Sprintf("unix:%s", sock)), WithMetrics(metrics))
+	require.NoError(t, err)
+	err = ovs.Connect(context.Background())
+	require.NoError(t, err)
+	t.Cleanup(ovs.Close)
+
+	ops, err := ovs.Create(&OpenvSwitch{UUID: "ovs", NextCfg: 1})
+	require.NoError(t, err)
+	results, err := ovs.Transact(context.Background(), ops...)
+	require.NoError(t, err)
+	ovsUUID := results[0].UUID.GoUUID
+
+	snapshot, err := ovs.MonitorOnce(context.Background(), ovs.NewMonitor(WithTable(&OpenvSwitch{})))
+	require.NoError(t, err)
+	require.Equal(t, 1, snapshot.Table("Open_vSwitch").Len())
+	assert.Equal(t, 1, snapshot.Table("Open_vSwitch").Row(ovsUUID).(*OpenvSwitch).NextCfg)
+
+	// the monitor was canceled once the initial rows were received
+	metrics.mutex.Lock()
+	assert.Equal(t, 1, metrics.rpcs["monitor_cond"])
+	assert.Equal(t, 1, metrics.rpcs["monitor_cancel"])
+	metrics.mutex.Unlock()
+	primaryDB := ovs.primaryDB()
+	primaryDB.monitorsMutex.Lock()
+	assert.Empty(t, primaryDB.monitors)
+	primaryDB.monitorsMutex.Unlock()
+	assert.Equal(t, 0, ovs.Cache().Table("Open_vSwitch").Len())
+
+	// the snapshot is not updated
+	_, err = ovs.Monitor(context.Background(), ovs.NewMonitor(WithTable(&OpenvSwitch{})))
+	require.NoError(t, err)
+	o := &OpenvSwitch{UUID: ovsUUID, NextCfg: 2}
+	ops, err = ovs.Where(o).Update(o, &o.NextCfg)
+	require.NoError(t, err)
+	_, err = ovs.Transact(context.Background(), ops...)
+	require.NoError(t, err)
+	require.Eventually(t, func() bool {
+		m := ovs.Cache().Table("Open_vSwitch").Row(ovsUUID)
+		return m != nil && m.(*OpenvSwitch).NextCfg == 2
+	}, 2*time.Second, 10*time.Millisecond)
+	assert.Equal(t, 1, snapshot.Table("Open_vSwitch").Row(ovsUUID).(*OpenvSwitch).NextCfg)
+
+	_, err = ovs.MonitorOnce(context.Background(), ovs.NewMonitor())
+	assert.EqualError(t, err, "at least one table should be monitored")
 }
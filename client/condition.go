@@ -77,6 +77,9 @@ func (c *equalityConditional) Matches() (map[string]model.Model, error) {
 	if tableCache == nil {
 		return nil, ErrNotFound
 	}
+	if err := c.cache.CheckValid(c.tableName); err != nil {
+		return nil, err
+	}
 	return tableCache.RowsByModels(c.models)
 }
 
@@ -128,6 +131,9 @@ func (c *explicitConditional) Matches() (map[string]model.Model, error) {
 	if tableCache == nil {
 		return nil, ErrNotFound
 	}
+	if err := c.cache.CheckValid(c.tableName); err != nil {
+		return nil, err
+	}
 	found := map[string]model.Model{}
 	for _, allConditions := range c.anyConditions {
 		models, err := tableCache.RowsByCondition(allConditions)
@@ -188,6 +194,9 @@ func (c *predicateConditional) Matches() (map[string]model.Model, error) {
 	if tableCache == nil {
 		return nil, ErrNotFound
 	}
+	if err := c.cache.CheckValid(c.tableName); err != nil {
+		return nil, err
+	}
 	found := map[string]model.Model{}
 	// run the predicate on a shallow copy of the models for speed and only
 	// clone the matches
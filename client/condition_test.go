@@ -273,6 +273,7 @@ func TestExplicitConditionalWithNoCache(t *testing.T) {
 	tcache := apiTestCache(t, testData)
 
 	testObj := &testLogicalSwitchPort{}
+	otherObj := &testLogicalSwitchPort{}
 
 	test := []struct {
 		name   string
@@ -405,18 +406,40 @@ func TestExplicitConditionalWithNoCache(t *testing.T) {
 				}}},
 			all: true,
 		},
+		{
+			name: "field not in model",
+			args: []model.Condition{
+				{
+					Field:    &otherObj.Name,
+					Function: ovsdb.ConditionEqual,
+					Value:    "lsp0",
+				},
+			},
+			err: true,
+		},
+		{
+			name: "wrong function for type",
+			args: []model.Condition{
+				{
+					Field:    &testObj.Name,
+					Function: ovsdb.ConditionGreaterThan,
+					Value:    "lsp0",
+				},
+			},
+			err: true,
+		},
 	}
 	for _, tt := range test {
 		t.Run(fmt.Sprintf("Explicit Conditional with no cache: %s", tt.name), func(t *testing.T) {
 			cond, err := newExplicitConditional("Logical_Switch_Port", tcache, tt.all, testObj, tt.args...)
-			assert.Nil(t, err)
-			generated, err := cond.Generate()
 			if tt.err {
 				assert.NotNil(t, err)
-			} else {
-				assert.Nil(t, err)
-				assert.ElementsMatch(t, tt.result, generated)
+				return
 			}
+			assert.Nil(t, err)
+			generated, err := cond.Generate()
+			assert.Nil(t, err)
+			assert.ElementsMatch(t, tt.result, generated)
 		})
 	}
 }
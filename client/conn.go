@@ -0,0 +1,61 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"sync"
+	"time"
+)
+
+// connEndpoint is the endpoint of the clients created with NewClientFromConn,
+// which only shows up in their logs
+const connEndpoint = "unix:conn"
+
+// connDialer hands out a connection provided by the user once, in place of
+// dialing the endpoint
+type connDialer struct {
+	mutex sync.Mutex
+	conn  net.Conn
+}
+
+func newConnDialer(conn io.ReadWriteCloser) *connDialer {
+	c, ok := conn.(net.Conn)
+	if !ok {
+		c = &rwcConn{ReadWriteCloser: conn}
+	}
+	return &connDialer{conn: c}
+}
+
+func (d *connDialer) dial(context.Context, string, string) (net.Conn, error) {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	if d.conn == nil {
+		return nil, ErrConnUsed
+	}
+	conn := d.conn
+	d.conn = nil
+	return conn, nil
+}
+
+// errNoDeadline is returned when setting the deadline of an rwcConn
+var errNoDeadline = errors.New("deadlines are not supported by the connection")
+
+// rwcConn is a net.Conn over an io.ReadWriteCloser that is not one, which has
+// no address and no deadlines
+type rwcConn struct {
+	io.ReadWriteCloser
+}
+
+// rwcAddr is the address of both ends of an rwcConn
+type rwcAddr struct{}
+
+func (rwcAddr) Network() string { return "conn" }
+func (rwcAddr) String() string  { return "conn" }
+
+func (c *rwcConn) LocalAddr() net.Addr                { return rwcAddr{} }
+func (c *rwcConn) RemoteAddr() net.Addr               { return rwcAddr{} }
+func (c *rwcConn) SetDeadline(t time.Time) error      { return errNoDeadline }
+func (c *rwcConn) SetReadDeadline(t time.Time) error  { return errNoDeadline }
+func (c *rwcConn) SetWriteDeadline(t time.Time) error { return errNoDeadline }
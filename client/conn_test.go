@@ -0,0 +1,68 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/cenkalti/backoff/v4"
+	"github.com/ovn-org/libovsdb/ovsdb"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// readWriteCloser hides the net.Conn methods of a connection
+type readWriteCloser struct {
+	io.ReadWriteCloser
+}
+
+func TestNewClientFromConn(t *testing.T) {
+	_, err := NewClientFromConn(nil, defDB)
+	assert.Error(t, err)
+
+	// the connection cannot be reopened to reconnect
+	client, server := net.Pipe()
+	defer client.Close()
+	defer server.Close()
+	_, err = NewClientFromConn(client, defDB, WithReconnect(time.Second, &backoff.ZeroBackOff{}))
+	assert.Error(t, err)
+
+	var defSchema ovsdb.DatabaseSchema
+	err = json.Unmarshal([]byte(schema), &defSchema)
+	require.NoError(t, err)
+	srv, _ := newOVSDBServer(t, defDB, defSchema)
+
+	// the writer is given a net.Conn, the reader a plain io.ReadWriteCloser
+	writer, err := NewClientFromConn(srv.Pipe(), defDB)
+	require.NoError(t, err)
+	reader, err := NewClientFromConn(&readWriteCloser{srv.Pipe()}, defDB)
+	require.NoError(t, err)
+	for _, ovs := range []Client{writer, reader} {
+		err = ovs.Connect(context.Background())
+		require.NoError(t, err)
+		t.Cleanup(ovs.Close)
+	}
+
+	_, err = reader.MonitorAll(context.Background())
+	require.NoError(t, err)
+	ops := []ovsdb.Operation{{Op: ovsdb.OperationInsert, Table: "Bridge", Row: ovsdb.Row{"name": "br0"}}}
+	reply, err := writer.Transact(context.Background(), ops...)
+	require.NoError(t, err)
+	_, err = ovsdb.CheckOperationResults(reply, ops)
+	require.NoError(t, err)
+	require.Eventually(t, func() bool {
+		return reader.Cache().Table("Bridge").Len() == 1
+	}, 2*time.Second, 10*time.Millisecond)
+
+	// the connection cannot be used again once the client disconnected
+	writer.Disconnect()
+	require.Eventually(t, func() bool {
+		return !writer.Connected()
+	}, 2*time.Second, 10*time.Millisecond)
+	err = writer.Connect(context.Background())
+	assert.True(t, errors.Is(err, ErrConnUsed), "unexpected error %v", err)
+}
@@ -0,0 +1,94 @@
+package client
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"sync"
+)
+
+// DisconnectReason is why the connection to the server was lost
+type DisconnectReason int
+
+const (
+	// DisconnectRequested means that the client closed the connection, with
+	// Disconnect or Close
+	DisconnectRequested DisconnectReason = iota
+	// DisconnectServerClosed means that the server closed the connection
+	// cleanly, as ovsdb-server does when it shuts down
+	DisconnectServerClosed
+	// DisconnectConnectionError means that the connection failed abruptly,
+	// e.g. it was reset or the inactivity probe failed
+	DisconnectConnectionError
+)
+
+func (r DisconnectReason) String() string {
+	switch r {
+	case DisconnectRequested:
+		return "requested"
+	case DisconnectServerClosed:
+		return "server closed"
+	case DisconnectConnectionError:
+		return "connection error"
+	}
+	return fmt.Sprintf("DisconnectReason(%d)", int(r))
+}
+
+// trackedConn is a connection to the server that records why it stopped
+// working: the client closed it, or reading from it failed
+type trackedConn struct {
+	net.Conn
+	mutex  sync.Mutex
+	done   bool
+	reason DisconnectReason
+	err    error
+}
+
+func newTrackedConn(conn net.Conn) *trackedConn {
+	return &trackedConn{Conn: conn}
+}
+
+// Read reads from the connection, and records the first error unless the
+// connection was closed by the client. The connection was closed cleanly by
+// the server if the error is io.EOF.
+func (c *trackedConn) Read(b []byte) (int, error) {
+	n, err := c.Conn.Read(b)
+	if errors.Is(err, io.EOF) {
+		c.record(DisconnectServerClosed, nil)
+	} else if err != nil {
+		c.record(DisconnectConnectionError, err)
+	}
+	return n, err
+}
+
+// Close closes the connection on behalf of the client
+func (c *trackedConn) Close() error {
+	c.record(DisconnectRequested, nil)
+	return c.Conn.Close()
+}
+
+// fail records that the connection failed with an error detected by the
+// client, before the client closes it
+func (c *trackedConn) fail(err error) {
+	c.record(DisconnectConnectionError, err)
+}
+
+func (c *trackedConn) record(reason DisconnectReason, err error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if c.done {
+		return
+	}
+	c.done = true
+	c.reason = reason
+	c.err = err
+}
+
+// disconnectReason returns why the connection stopped working, along with
+// the error it failed with for DisconnectConnectionError
+func (c *trackedConn) disconnectReason() (DisconnectReason, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	return c.reason, c.err
+}
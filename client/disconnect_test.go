@@ -0,0 +1,98 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/ovn-org/libovsdb/ovsdb"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// resetConn turns the end of a connection into a connection reset
+type resetConn struct {
+	net.Conn
+}
+
+func (c *resetConn) Read(b []byte) (int, error) {
+	n, err := c.Conn.Read(b)
+	if err == io.EOF {
+		err = &net.OpError{Op: "read", Net: "tcp", Err: syscall.ECONNRESET}
+	}
+	return n, err
+}
+
+func TestDisconnectReason(t *testing.T) {
+	var defSchema ovsdb.DatabaseSchema
+	err := json.Unmarshal([]byte(schema), &defSchema)
+	require.NoError(t, err)
+	srv, _ := newOVSDBServer(t, defDB, defSchema)
+
+	type disconnect struct {
+		reason DisconnectReason
+		err    error
+	}
+	connect := func(t *testing.T, reset bool) (*ovsdbClient, net.Conn, chan disconnect) {
+		disconnects := make(chan disconnect, 1)
+		serverConns := make(chan net.Conn, 1)
+		ovs, err := newOVSDBClient(defDB, WithEndpoint("unix:/ovsdb.sock"),
+			WithDialContext(func(context.Context, string, string) (net.Conn, error) {
+				serverConn, clientConn := net.Pipe()
+				go srv.ServeConn(serverConn)
+				serverConns <- serverConn
+				if reset {
+					return &resetConn{Conn: clientConn}, nil
+				}
+				return clientConn, nil
+			}),
+			WithDisconnectCallback(func(reason DisconnectReason, err error) {
+				disconnects <- disconnect{reason, err}
+			}))
+		require.NoError(t, err)
+		err = ovs.Connect(context.Background())
+		require.NoError(t, err)
+		t.Cleanup(ovs.Close)
+		return ovs, <-serverConns, disconnects
+	}
+	wait := func(t *testing.T, disconnects chan disconnect) disconnect {
+		select {
+		case d := <-disconnects:
+			return d
+		case <-time.After(2 * time.Second):
+			t.Fatal("the disconnect callback was not called")
+		}
+		return disconnect{}
+	}
+
+	t.Run("server closed", func(t *testing.T) {
+		_, serverConn, disconnects := connect(t, false)
+		require.NoError(t, serverConn.Close())
+		d := wait(t, disconnects)
+		assert.Equal(t, DisconnectServerClosed, d.reason)
+		assert.NoError(t, d.err)
+	})
+
+	t.Run("connection reset", func(t *testing.T) {
+		_, serverConn, disconnects := connect(t, true)
+		require.NoError(t, serverConn.Close())
+		d := wait(t, disconnects)
+		assert.Equal(t, DisconnectConnectionError, d.reason)
+		assert.True(t, errors.Is(d.err, syscall.ECONNRESET), "unexpected error %v", d.err)
+	})
+
+	t.Run("requested", func(t *testing.T) {
+		ovs, _, disconnects := connect(t, false)
+		ovs.Disconnect()
+		d := wait(t, disconnects)
+		assert.Equal(t, DisconnectRequested, d.reason)
+		assert.NoError(t, d.err)
+	})
+
+	assert.Equal(t, "server closed", DisconnectServerClosed.String())
+}
@@ -45,40 +45,41 @@ return Model instances (or a list thereof) directly.
 
 Conditions
 
-Some API functions (Create() and Get()), can be run directly. Others, require us to use
+Some API functions (Create(), CreateOrUpdate() and Get()), can be run directly. Others, require us to use
 a ConditionalAPI. The ConditionalAPI injects RFC7047 Conditions into ovsdb Operations as well as
 uses the Conditions to search the internal cache.
 
-The ConditionalAPI is created using the Where(), WhereCache() and WhereAll() functions.
+The ConditionalAPI is created using the Where(), WhereAny(), WhereAll() and WhereCache() functions.
 
-Where() accepts a Model (pointer to a struct with ovs tags) and a number of Condition instances.
+Where() accepts a number of Models (pointers to structs with ovs tags) of the same table and generates
+conditions from the fields that correspond to indexes, for instance to update the Logical Switch named "foo"
+(provided "name" is a schema or client index of the table):
+
+	ls = &MyLogicalSwitch {Name: "foo"}
+	ovs.Where(ls)
+
+If the index can be matched against a Model in the cache, the condition is based on its "_uuid". Therefore
+the following two statements are equivalent when that Logical Switch is in the cache:
+
+	ovs.Where(&MyLogicalSwitch {Name: "foo"})
+
+	ovs.Where(&MyLogicalSwitch {UUID: "myUUID"})
+
+WhereAny() accepts a Model and a number of Condition instances.
 Conditions must refer to fields of the provided Model (via pointer to fields). Example:
 
 	ls = &MyLogicalSwitch {}
-	ovs.Where(ls, client.Condition {
+	ovs.WhereAny(ls, client.Condition {
 		Field: &ls.Ports,
 		Function: ovsdb.ConditionIncludes,
 		Value: []string{"portUUID"},
 	    })
 
-If no client.Condition is provided, the client will use any of fields that correspond to indexes to
-generate an appropriate condition. Therefore the following two statements are equivalent:
-
-	ls = &MyLogicalSwitch {UUID:"myUUID"}
-
-	ovs.Where(ls)
-
-	ovs.Where(ls, client.Condition {
-		Field: &ls.UUID,
-		Function: ovsdb.ConditionEqual,
-		Value: "myUUID"},
-	    })
-
-Where() accepts multiple Condition instances (through variadic arguments).
+WhereAny() accepts multiple Condition instances (through variadic arguments).
 If provided, the client will generate multiple operations each matching one condition.
 For example, the following operation will delete all the Logical Switches named "foo" OR "bar":
 
-	ops, err := ovs.Where(ls,
+	ops, err := ovs.WhereAny(ls,
 		client.Condition {
 			Field: &ls.Name
 			Function: ovsdb.ConditionEqual,
@@ -91,7 +92,7 @@ For example, the following operation will delete all the Logical Switches named
 
 To create a Condition that matches all of the conditions simultaneously (i.e: AND semantics), use WhereAll().
 
-Where() or WhereAll() evaluate the provided index values or explicit conditions against the cache and generate
+Where(), WhereAny() or WhereAll() evaluate the provided index values or explicit conditions against the cache and generate
 conditions based on the UUIDs of matching models. If no matches are found in the cache, the generated conditions
 will be based on the index or condition fields themselves.
 
@@ -109,7 +110,7 @@ It is used to search the cache so commonly used with List() function. For exampl
 Server side operations can be executed using WhereCache() conditions but it's not recommended. For each matching
 cache element, an operation will be created matching on the "_uuid" column. The number of operations can be
 quite large depending on the cache size and the provided function. Most likely there is a way to express the
-same condition using Where() or WhereAll() which will be more efficient.
+same condition using Where(), WhereAny() or WhereAll() which will be more efficient.
 
 Get
 
@@ -137,12 +138,31 @@ Create returns a list of operations to create the models provided. E.g:
 
 	ops, err := ovs.Create(&LogicalSwitch{Name:"foo")}, &LogicalSwitch{Name:"bar"})
 
+CreateOrUpdate
+
+CreateOrUpdate returns a list of operations to create the model, or to update the row that has the same
+values in the given index fields if the cache has one. A wait operation makes the transaction fail if the
+rows matching the index changed since, instead of creating a duplicate. E.g:
+
+	ls := &LogicalSwitch{Name: "foo", ExternalIDs: map[string]string{"foo": "bar"}}
+	ops, err := ovs.CreateOrUpdate(ls, &ls.Name)
+
 Update
 Update returns a list of operations to update the matching rows to match the values of the provided model. E.g:
 
 	ls := &LogicalSwitch{ExternalIDs: map[string]string {"foo": "bar"}}
 	ops, err := ovs.Where(...).Update(&ls, &ls.ExternalIDs}
 
+UpdateChanged
+
+UpdateChanged returns the operation to update the row of a model with only the columns whose value changed in
+another version of the model, or ErrNoChanges if none did. E.g:
+
+	ls := cached.(*LogicalSwitch)
+	updated := ls.DeepCopy()
+	updated.ExternalIDs = map[string]string{"foo": "baz"}
+	op, err := ovs.UpdateChanged(ls, updated)
+
 Mutate
 
 Mutate returns a list of operations needed to mutate the matching rows as described by the list of Mutation objects. E.g:
@@ -160,5 +180,44 @@ Delete returns a list of operations needed to delete the matching rows. E.g:
 
 	ops, err := ovs.Where(...).Delete()
 
+Transaction
+
+A Transaction accumulates the operations returned by several of the functions above and commits them in a single
+transact rpc, in order. Their operations can reference the rows inserted by each other with their named-uuids. E.g:
+
+	tx := ovs.NewTransaction()
+	create := tx.Add(ovs.Create(&ls))
+	tx.Add(ovs.Where(...).Delete())
+	results, err := tx.Commit(ctx)
+	uuid := create.Results()[0].UUID.GoUUID
+
+Locks
+
+Clients can coordinate with the locks of the server, e.g. so that a single one of several controllers is active. Lock
+returns a channel that is closed once the client owns the lock, which another client can take with Steal:
+
+	acquired, err := ovs.Lock(ctx, "controller")
+	<-acquired
+	ovs.OnLockStolen(func(id string) {
+		// stop being active until HasLock(id) is true again
+	})
+
+As the lock may be stolen at any time, a transaction can assert that the client still owns it when the server executes
+it, and fails with a "not owner" error otherwise:
+
+	tx := ovs.NewTransaction()
+	tx.Add(ovs.Create(...))
+	tx.Assert("controller")
+	results, err := tx.Commit(ctx)
+
+Client pools
+
+The clients created by a ClientPool share a single connection to each of their endpoints, while having their own
+database model, cache and monitors:
+
+	pool, err := client.NewClientPool(client.WithEndpoint("tcp:172.18.0.4:6641"))
+	nbClient, err := pool.Client(nbModel)
+	lbClient, err := pool.Client(lbModel)
+
 */
 package client
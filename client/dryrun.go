@@ -0,0 +1,179 @@
+package client
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+
+	"github.com/google/uuid"
+	"github.com/ovn-org/libovsdb/cache"
+	inmemory "github.com/ovn-org/libovsdb/database"
+	"github.com/ovn-org/libovsdb/model"
+	"github.com/ovn-org/libovsdb/ovsdb"
+)
+
+// Diff describes the rows a transaction would create, update or delete, keyed
+// by table name and then by row UUID
+type Diff struct {
+	Created map[string]map[string]model.Model
+	Updated map[string]map[string]RowDiff
+	Deleted map[string]map[string]model.Model
+}
+
+// RowDiff is a row before and after an update, along with the columns whose
+// value changed
+type RowDiff struct {
+	Old     model.Model
+	New     model.Model
+	Columns []string
+}
+
+// DryRun executes the provided operations against the cache of the client,
+// without sending anything to the server nor modifying the cache, and returns
+// the rows they would create, update or delete. The operations are checked
+// like the server does, so the error returned by ovsdb.CheckOperationResults
+// is returned if one of them would fail. The outcome is only as accurate as the
+// cache: the rows of the tables that are not monitored are unknown to the
+// client, and rows inserted without a named-uuid get a random UUID.
+func (o *ovsdbClient) DryRun(operation ...ovsdb.Operation) (Diff, error) {
+	db := o.primaryDB()
+	db.modelMutex.RLock()
+	dbModel := db.model
+	db.modelMutex.RUnlock()
+	if !dbModel.Valid() {
+		return Diff{}, fmt.Errorf("cannot dry run on database %s: schema unknown", o.primaryDBName)
+	}
+	if ok := dbModel.Schema.ValidateOperations(operation...); !ok {
+		return Diff{}, fmt.Errorf("validation failed for the operation")
+	}
+	tableCache := o.Cache()
+	if tableCache == nil {
+		return Diff{}, ErrNotConnected
+	}
+
+	cached := &cacheDatabase{name: o.primaryDBName, cache: tableCache}
+	tx := inmemory.NewTransaction(dbModel, o.primaryDBName, cached, o.logger)
+	results, _ := tx.Transact(operation)
+	opResults := make([]ovsdb.OperationResult, 0, len(results))
+	for _, r := range results {
+		if r != nil {
+			opResults = append(opResults, *r)
+		}
+	}
+	if _, err := ovsdb.CheckOperationResults(opResults, operation); err != nil {
+		return Diff{}, err
+	}
+
+	diff := Diff{
+		Created: make(map[string]map[string]model.Model),
+		Updated: make(map[string]map[string]RowDiff),
+		Deleted: make(map[string]map[string]model.Model),
+	}
+	for _, table := range tx.Cache.Tables() {
+		for rowUUID, m := range tx.Cache.Table(table).Rows() {
+			old := tableCache.Table(table).Row(rowUUID)
+			if old == nil {
+				addDiff(diff.Created, table, rowUUID, m)
+				continue
+			}
+			columns, err := changedColumns(dbModel, old, m)
+			if err != nil {
+				return Diff{}, err
+			}
+			if len(columns) > 0 {
+				if diff.Updated[table] == nil {
+					diff.Updated[table] = make(map[string]RowDiff)
+				}
+				diff.Updated[table][rowUUID] = RowDiff{Old: old, New: m, Columns: columns}
+			}
+		}
+	}
+	for rowUUID := range tx.DeletedRows {
+		for _, table := range tableCache.Tables() {
+			if old := tableCache.Table(table).Row(rowUUID); old != nil {
+				addDiff(diff.Deleted, table, rowUUID, old)
+			}
+		}
+	}
+	return diff, nil
+}
+
+func addDiff(rows map[string]map[string]model.Model, table, rowUUID string, m model.Model) {
+	if rows[table] == nil {
+		rows[table] = make(map[string]model.Model)
+	}
+	rows[table][rowUUID] = m
+}
+
+// changedColumns returns the sorted columns of the schema whose value differs
+// between two models of the same table
+func changedColumns(dbModel model.DatabaseModel, old, new model.Model) ([]string, error) {
+	oldInfo, err := dbModel.NewModelInfo(old)
+	if err != nil {
+		return nil, err
+	}
+	newInfo, err := dbModel.NewModelInfo(new)
+	if err != nil {
+		return nil, err
+	}
+	names := make([]string, 0, len(oldInfo.Metadata.TableSchema.Columns))
+	for name := range oldInfo.Metadata.TableSchema.Columns {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var columns []string
+	for _, column := range names {
+		oldValue, err := oldInfo.FieldByColumn(column)
+		if err != nil {
+			// the model has no field for the column
+			continue
+		}
+		newValue, err := newInfo.FieldByColumn(column)
+		if err != nil {
+			return nil, err
+		}
+		if !reflect.DeepEqual(oldValue, newValue) {
+			columns = append(columns, column)
+		}
+	}
+	return columns, nil
+}
+
+// cacheDatabase is a read-only database.Database backed by the cache of the
+// client, which the transactions of DryRun execute against
+type cacheDatabase struct {
+	name  string
+	cache *cache.TableCache
+}
+
+func (c *cacheDatabase) CreateDatabase(string, ovsdb.DatabaseSchema) error {
+	return fmt.Errorf("cannot create a database in the cache")
+}
+
+func (c *cacheDatabase) Exists(name string) bool {
+	return name == c.name
+}
+
+func (c *cacheDatabase) Commit(string, uuid.UUID, ovsdb.TableUpdates2) error {
+	return fmt.Errorf("cannot commit to the cache")
+}
+
+func (c *cacheDatabase) CheckIndexes(_ string, table string, m model.Model) error {
+	return c.cache.Table(table).IndexExists(m)
+}
+
+func (c *cacheDatabase) List(_, table string, conditions ...ovsdb.Condition) (map[string]model.Model, error) {
+	rowCache := c.cache.Table(table)
+	if rowCache == nil {
+		return nil, fmt.Errorf("table does not exist")
+	}
+	return rowCache.RowsByCondition(conditions)
+}
+
+func (c *cacheDatabase) Get(_, table string, rowUUID string) (model.Model, error) {
+	rowCache := c.cache.Table(table)
+	if rowCache == nil {
+		return nil, fmt.Errorf("table does not exist")
+	}
+	return rowCache.Row(rowUUID), nil
+}
@@ -0,0 +1,95 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/ovn-org/libovsdb/model"
+	"github.com/ovn-org/libovsdb/ovsdb"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDryRun(t *testing.T) {
+	var defSchema ovsdb.DatabaseSchema
+	err := json.Unmarshal([]byte(schema), &defSchema)
+	require.NoError(t, err)
+	_, sock := newOVSDBServer(t, defDB, defSchema)
+
+	metrics := newRecordingMetrics()
+	ovs, err := newOVSDBClient(defDB, WithEndpoint(fmt.Sprintf("unix:%s", sock)), WithMetrics(metrics))
+	require.NoError(t, err)
+	err = ovs.Connect(context.Background())
+	require.NoError(t, err)
+	t.Cleanup(ovs.Close)
+	_, err = ovs.MonitorAll(context.Background())
+	require.NoError(t, err)
+
+	ops, err := ovs.Create(&Bridge{Name: "br0", ExternalIDs: map[string]string{"foo": "bar"}})
+	require.NoError(t, err)
+	results, err := ovs.Transact(context.Background(), ops...)
+	require.NoError(t, err)
+	_, err = ovsdb.CheckOperationResults(results, ops)
+	require.NoError(t, err)
+	br0UUID := results[0].UUID.GoUUID
+	require.Eventually(t, func() bool {
+		return ovs.Cache().Table("Bridge").Row(br0UUID) != nil
+	}, 2*time.Second, 10*time.Millisecond)
+
+	metrics.mutex.Lock()
+	transacts := metrics.rpcs["transact"]
+	metrics.mutex.Unlock()
+
+	// insert
+	ops, err = ovs.Create(&Bridge{UUID: "br1", Name: "br1"})
+	require.NoError(t, err)
+	diff, err := ovs.DryRun(ops...)
+	require.NoError(t, err)
+	assert.Empty(t, diff.Updated)
+	assert.Empty(t, diff.Deleted)
+	require.Len(t, diff.Created["Bridge"], 1)
+	for _, m := range diff.Created["Bridge"] {
+		assert.Equal(t, "br1", m.(*Bridge).Name)
+	}
+	assert.Len(t, ovs.Cache().Table("Bridge").Rows(), 1)
+
+	// update
+	br0 := &Bridge{UUID: br0UUID, ExternalIDs: map[string]string{"foo": "baz"}}
+	ops, err = ovs.Where(br0).Update(br0, &br0.ExternalIDs)
+	require.NoError(t, err)
+	diff, err = ovs.DryRun(ops...)
+	require.NoError(t, err)
+	assert.Empty(t, diff.Created)
+	assert.Empty(t, diff.Deleted)
+	require.Contains(t, diff.Updated["Bridge"], br0UUID)
+	rowDiff := diff.Updated["Bridge"][br0UUID]
+	assert.Equal(t, []string{"external_ids"}, rowDiff.Columns)
+	assert.Equal(t, map[string]string{"foo": "bar"}, rowDiff.Old.(*Bridge).ExternalIDs)
+	assert.Equal(t, map[string]string{"foo": "baz"}, rowDiff.New.(*Bridge).ExternalIDs)
+	assert.Equal(t, "br0", rowDiff.New.(*Bridge).Name)
+	cached := ovs.Cache().Table("Bridge").Row(br0UUID)
+	assert.Equal(t, map[string]string{"foo": "bar"}, cached.(*Bridge).ExternalIDs)
+
+	// delete
+	ops, err = ovs.Where(&Bridge{UUID: br0UUID}).Delete()
+	require.NoError(t, err)
+	diff, err = ovs.DryRun(ops...)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]map[string]model.Model{"Bridge": {br0UUID: cached}}, diff.Deleted)
+	assert.NotNil(t, ovs.Cache().Table("Bridge").Row(br0UUID))
+
+	// failures are reported like by the server
+	ops, err = ovs.Create(&Bridge{Name: "br0"})
+	require.NoError(t, err)
+	_, err = ovs.DryRun(ops...)
+	var constraint *ovsdb.ConstraintViolation
+	assert.True(t, errors.As(err, &constraint), "unexpected error %v", err)
+
+	metrics.mutex.Lock()
+	assert.Equal(t, transacts, metrics.rpcs["transact"])
+	metrics.mutex.Unlock()
+}
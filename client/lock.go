@@ -0,0 +1,225 @@
+package client
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ovn-org/libovsdb/ovsdb"
+)
+
+// lock is the state of a lock requested with Lock or Steal
+type lock struct {
+	// acquired is closed once the client owned the lock for the first time
+	acquired chan struct{}
+	owned    bool
+	// notifications counts the locked and stolen notifications about the
+	// lock, so that the reply of a request does not override a more recent
+	// notification
+	notifications int
+}
+
+func newLock() *lock {
+	return &lock{acquired: make(chan struct{})}
+}
+
+func (l *lock) setOwned(owned bool) {
+	l.owned = owned
+	select {
+	case <-l.acquired:
+	default:
+		// a lock can only be stolen once it was acquired
+		close(l.acquired)
+	}
+}
+
+// Lock requests the lock with the given id, and returns a channel that is
+// closed once the client owns it: as soon as the server replied if no other
+// client owns or waits for the lock, or once the clients before it released
+// it otherwise. The client waits for the lock until Unlock is called, even
+// after it was stolen, and requests it again when it reconnects.
+// RFC 7047 : lock
+func (o *ovsdbClient) Lock(ctx context.Context, id string) (<-chan struct{}, error) {
+	o.rpcMutex.RLock()
+	defer o.rpcMutex.RUnlock()
+	if o.rpcClient == nil {
+		return nil, ErrNotConnected
+	}
+	o.locksMutex.Lock()
+	if _, ok := o.locks[id]; ok {
+		o.locksMutex.Unlock()
+		return nil, fmt.Errorf("lock %s already requested", id)
+	}
+	l := newLock()
+	o.locks[id] = l
+	o.locksMutex.Unlock()
+	if err := o.requestLock(ctx, "lock", id, l); err != nil {
+		o.forgetLock(id, l)
+		return nil, err
+	}
+	return l.acquired, nil
+}
+
+// Steal takes the lock with the given id from the client that owns it, which
+// gets a stolen notification, and returns once the client owns the lock.
+// RFC 7047 : steal
+func (o *ovsdbClient) Steal(ctx context.Context, id string) error {
+	o.rpcMutex.RLock()
+	defer o.rpcMutex.RUnlock()
+	if o.rpcClient == nil {
+		return ErrNotConnected
+	}
+	o.locksMutex.Lock()
+	l, requested := o.locks[id]
+	if !requested {
+		l = newLock()
+		o.locks[id] = l
+	}
+	o.locksMutex.Unlock()
+	if err := o.requestLock(ctx, "steal", id, l); err != nil {
+		if !requested {
+			o.forgetLock(id, l)
+		}
+		return err
+	}
+	return nil
+}
+
+// Unlock releases the lock with the given id, or stops waiting for it.
+// RFC 7047 : unlock
+func (o *ovsdbClient) Unlock(ctx context.Context, id string) error {
+	o.rpcMutex.RLock()
+	defer o.rpcMutex.RUnlock()
+	if o.rpcClient == nil {
+		return ErrNotConnected
+	}
+	var reply struct{}
+	if err := o.call(ctx, "unlock", ovsdb.NewLockArgs(id), &reply); err != nil {
+		return err
+	}
+	o.locksMutex.Lock()
+	delete(o.locks, id)
+	o.locksMutex.Unlock()
+	return nil
+}
+
+// Assert returns an assert operation, which makes the transaction it is part of
+// fail with a "not owner" error, see ovsdb.NotOwner, unless the client owns the
+// lock with the given id when the server executes it. Prepend it to the
+// operations of a transaction that only the owner of the lock may commit, e.g.
+// with Transaction.Assert. Assert operations with an empty lock id fail
+// validation.
+// RFC 7047 : assert
+func Assert(id string) ovsdb.Operation {
+	return ovsdb.Operation{
+		Op:   ovsdb.OperationAssert,
+		Lock: &id,
+	}
+}
+
+// HasLock returns whether the client currently owns the lock with the given
+// id. It no longer does once the lock was stolen or the client disconnected.
+func (o *ovsdbClient) HasLock(id string) bool {
+	o.locksMutex.Lock()
+	defer o.locksMutex.Unlock()
+	l, ok := o.locks[id]
+	return ok && l.owned
+}
+
+// OnLockStolen registers a function that is called with the id of a lock
+// when another client steals it. The client waits for the lock again, and
+// gets it back once the other client releases it. The functions are called in
+// their own goroutine.
+func (o *ovsdbClient) OnLockStolen(fn func(id string)) {
+	o.locksMutex.Lock()
+	defer o.locksMutex.Unlock()
+	o.lockStolen = append(o.lockStolen, fn)
+}
+
+// requestLock sends a lock or steal request for a lock, which is owned
+// afterwards if the server granted it, unless a notification about the lock
+// arrived in the meantime. Should only be called when rpcMutex is held.
+func (o *ovsdbClient) requestLock(ctx context.Context, method, id string, l *lock) error {
+	o.locksMutex.Lock()
+	notifications := l.notifications
+	o.locksMutex.Unlock()
+	var reply ovsdb.LockResult
+	if err := o.call(ctx, method, ovsdb.NewLockArgs(id), &reply); err != nil {
+		return err
+	}
+	o.locksMutex.Lock()
+	defer o.locksMutex.Unlock()
+	if reply.Locked && l.notifications == notifications {
+		l.setOwned(true)
+	}
+	return nil
+}
+
+// forgetLock stops tracking a lock whose request failed
+func (o *ovsdbClient) forgetLock(id string, l *lock) {
+	o.locksMutex.Lock()
+	defer o.locksMutex.Unlock()
+	if o.locks[id] == l {
+		delete(o.locks, id)
+	}
+}
+
+// relock requests the locks again after a reconnection. Should only be called
+// when rpcMutex is held.
+func (o *ovsdbClient) relock(ctx context.Context) error {
+	o.locksMutex.Lock()
+	locks := make(map[string]*lock, len(o.locks))
+	for id, l := range o.locks {
+		locks[id] = l
+	}
+	o.locksMutex.Unlock()
+	for id, l := range locks {
+		if err := o.requestLock(ctx, "lock", id, l); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// releaseLocks marks the locks as no longer owned after a disconnection, and
+// forgets them unless the client reconnects
+func (o *ovsdbClient) releaseLocks(reconnect bool) {
+	o.locksMutex.Lock()
+	defer o.locksMutex.Unlock()
+	for _, l := range o.locks {
+		l.owned = false
+	}
+	if !reconnect {
+		o.locks = make(map[string]*lock)
+	}
+}
+
+// RFC 7047 : Section 4.1.9 : Locked, and Section 4.1.10 : Stolen
+func (o *ovsdbClient) lockNotification(method string, args []interface{}) error {
+	if len(args) != 1 {
+		return fmt.Errorf("%s requires exactly 1 arg", method)
+	}
+	id, ok := args[0].(string)
+	if !ok {
+		return fmt.Errorf("%s: invalid lock id %v", method, args[0])
+	}
+	o.locksMutex.Lock()
+	l, ok := o.locks[id]
+	if !ok {
+		o.locksMutex.Unlock()
+		return nil
+	}
+	l.notifications++
+	l.setOwned(method == "locked")
+	handlers := make([]func(string), len(o.lockStolen))
+	copy(handlers, o.lockStolen)
+	o.locksMutex.Unlock()
+	if method == "stolen" {
+		o.logger.V(3).Info("lock stolen", "lock", id)
+		go func() {
+			for _, fn := range handlers {
+				fn(id)
+			}
+		}()
+	}
+	return nil
+}
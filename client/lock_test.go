@@ -0,0 +1,135 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/ovn-org/libovsdb/ovsdb"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLock(t *testing.T) {
+	var defSchema ovsdb.DatabaseSchema
+	err := json.Unmarshal([]byte(schema), &defSchema)
+	require.NoError(t, err)
+	_, sock := newOVSDBServer(t, defDB, defSchema)
+
+	connect := func() *ovsdbClient {
+		ovs, err := newOVSDBClient(defDB, WithEndpoint(fmt.Sprintf("unix:%s", sock)))
+		require.NoError(t, err)
+		err = ovs.Connect(context.Background())
+		require.NoError(t, err)
+		t.Cleanup(ovs.Close)
+		return ovs
+	}
+	active, standby := connect(), connect()
+	stolen := make(chan string, 1)
+	active.OnLockStolen(func(id string) {
+		stolen <- id
+	})
+
+	acquired, err := active.Lock(context.Background(), "leader")
+	require.NoError(t, err)
+	select {
+	case <-acquired:
+	default:
+		t.Fatal("a free lock is acquired immediately")
+	}
+	assert.True(t, active.HasLock("leader"))
+	_, err = active.Lock(context.Background(), "leader")
+	assert.Error(t, err)
+
+	// the standby waits for the lock
+	waiting, err := standby.Lock(context.Background(), "leader")
+	require.NoError(t, err)
+	select {
+	case <-waiting:
+		t.Fatal("the lock is owned by another client")
+	case <-time.After(100 * time.Millisecond):
+	}
+	assert.False(t, standby.HasLock("leader"))
+
+	// until it steals it
+	require.NoError(t, standby.Steal(context.Background(), "leader"))
+	<-waiting
+	assert.True(t, standby.HasLock("leader"))
+	select {
+	case id := <-stolen:
+		assert.Equal(t, "leader", id)
+	case <-time.After(2 * time.Second):
+		t.Fatal("the stolen notification was not received")
+	}
+	assert.False(t, active.HasLock("leader"))
+
+	// the previous owner gets the lock back once it is released
+	require.NoError(t, standby.Unlock(context.Background(), "leader"))
+	assert.False(t, standby.HasLock("leader"))
+	require.Eventually(t, func() bool {
+		return active.HasLock("leader")
+	}, 2*time.Second, 10*time.Millisecond)
+
+	// and the waiters get it when it disconnects
+	waiting, err = standby.Lock(context.Background(), "leader")
+	require.NoError(t, err)
+	active.Close()
+	select {
+	case <-waiting:
+	case <-time.After(2 * time.Second):
+		t.Fatal("the lock of a disconnected client was not released")
+	}
+	assert.True(t, standby.HasLock("leader"))
+	assert.False(t, active.HasLock("leader"))
+}
+
+func TestAssert(t *testing.T) {
+	b, err := json.Marshal(Assert("leader"))
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"op":"assert","lock":"leader"}`, string(b))
+
+	var defSchema ovsdb.DatabaseSchema
+	err = json.Unmarshal([]byte(schema), &defSchema)
+	require.NoError(t, err)
+	_, sock := newOVSDBServer(t, defDB, defSchema)
+	ovs, err := newOVSDBClient(defDB, WithEndpoint(fmt.Sprintf("unix:%s", sock)))
+	require.NoError(t, err)
+	err = ovs.Connect(context.Background())
+	require.NoError(t, err)
+	t.Cleanup(ovs.Close)
+
+	insert := ovsdb.Operation{Op: ovsdb.OperationInsert, Table: "Open_vSwitch", Row: ovsdb.Row{"next_cfg": 1}}
+
+	// the transaction fails unless the client owns the lock
+	tx := ovs.NewTransaction()
+	pending := tx.Add([]ovsdb.Operation{insert}, nil)
+	tx.Assert("leader")
+	assert.Equal(t, []ovsdb.Operation{Assert("leader"), insert}, tx.Operations())
+	assert.Equal(t, []ovsdb.Operation{insert}, pending.Operations())
+	_, err = tx.Commit(context.Background())
+	var notOwner *ovsdb.NotOwner
+	assert.True(t, errors.As(err, &notOwner), "unexpected error %v", err)
+
+	acquired, err := ovs.Lock(context.Background(), "leader")
+	require.NoError(t, err)
+	<-acquired
+	tx = ovs.NewTransaction()
+	pending = tx.Add([]ovsdb.Operation{insert}, nil)
+	asserted := tx.Assert("leader")
+	_, err = tx.Commit(context.Background())
+	require.NoError(t, err)
+	assert.Len(t, asserted.Results(), 1)
+	require.Len(t, pending.Results(), 1)
+	assert.NotEmpty(t, pending.Results()[0].UUID.GoUUID)
+
+	// the lock id has to be given
+	tx = ovs.NewTransaction()
+	tx.Assert("")
+	_, err = tx.Commit(context.Background())
+	assert.Error(t, err)
+	_, err = ovs.Transact(context.Background(), Assert(""), insert)
+	assert.Error(t, err)
+}
@@ -2,11 +2,44 @@ package client
 
 import (
 	"sync"
+	"time"
 
 	"github.com/prometheus/client_golang/prometheus"
 )
 
-const libovsdbName = "libovsdb"
+// DefaultMetricsNamespace is the namespace of the Prometheus metrics of the
+// client, and of the client/metrics package, when none is given
+const DefaultMetricsNamespace = "libovsdb"
+
+// Metrics receives events from the client so that they can be exported to
+// any monitoring system. Its methods are called synchronously by the client,
+// so they must be safe for concurrent use and return quickly.
+// See the client/metrics package for a Prometheus implementation.
+type Metrics interface {
+	// RPCSent is called when an rpc call of the given method is sent
+	RPCSent(method string)
+	// RPCLatency is called with the time it took to receive the reply to an
+	// rpc call, or for the call to fail
+	RPCLatency(method string, latency time.Duration)
+	// Reconnected is called when the client reconnected after losing the
+	// connection to the server
+	Reconnected()
+	// UpdateApplied is called when a monitor update of a table was applied
+	// to the cache
+	UpdateApplied(database, table string)
+	// CacheSize is called with the number of rows cached for a table, after
+	// an update of the table was applied to the cache
+	CacheSize(database, table string, rows int)
+}
+
+// noopMetrics is the Metrics used unless WithMetrics is given
+type noopMetrics struct{}
+
+func (noopMetrics) RPCSent(string)                   {}
+func (noopMetrics) RPCLatency(string, time.Duration) {}
+func (noopMetrics) Reconnected()                     {}
+func (noopMetrics) UpdateApplied(string, string)     {}
+func (noopMetrics) CacheSize(string, string, int)    {}
 
 type metrics struct {
 	numUpdates      *prometheus.CounterVec
@@ -21,7 +54,7 @@ func (m *metrics) init(modelName string, namespace, subsystem string) {
 	constLabels := prometheus.Labels{"primary_model": modelName}
 
 	if namespace == "" {
-		namespace = libovsdbName
+		namespace = DefaultMetricsNamespace
 		subsystem = ""
 	}
 
@@ -86,3 +119,17 @@ func (o *ovsdbClient) registerMetrics() {
 	o.metrics.register(o.options.registry)
 	o.options.shouldRegisterMetrics = false
 }
+
+// recordUpdate reports a monitor update of the table, which has been applied
+// to the cache of the database, to the Metrics of the client. The cacheMutex
+// of the database must be held.
+func (o *ovsdbClient) recordUpdate(dbName string, db *database, table string) {
+	// counting the rows locks the table, skip it when no one gets the count
+	if _, ok := o.options.metrics.(noopMetrics); ok {
+		return
+	}
+	o.options.metrics.UpdateApplied(dbName, table)
+	if rows := db.cache.Table(table); rows != nil {
+		o.options.metrics.CacheSize(dbName, table, rows.Len())
+	}
+}
@@ -0,0 +1,104 @@
+// Package metrics provides implementations of client.Metrics
+package metrics
+
+import (
+	"time"
+
+	"github.com/ovn-org/libovsdb/client"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Prometheus is a client.Metrics that exports the client events as
+// Prometheus metrics
+type Prometheus struct {
+	rpcs       *prometheus.CounterVec
+	rpcLatency *prometheus.HistogramVec
+	reconnects prometheus.Counter
+	updates    *prometheus.CounterVec
+	cachedRows *prometheus.GaugeVec
+}
+
+var _ client.Metrics = &Prometheus{}
+
+// NewPrometheus creates the Prometheus metrics and registers them with r.
+// If namespace is empty, the metrics are named libovsdb_<name>.
+func NewPrometheus(r prometheus.Registerer, namespace, subsystem string) *Prometheus {
+	if namespace == "" {
+		namespace = client.DefaultMetricsNamespace
+		subsystem = ""
+	}
+	p := &Prometheus{
+		rpcs: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Namespace: namespace,
+				Subsystem: subsystem,
+				Name:      "rpcs_total",
+				Help:      "Count of libovsdb rpc calls sent, partitioned by method",
+			},
+			[]string{"method"},
+		),
+		rpcLatency: prometheus.NewHistogramVec(
+			prometheus.HistogramOpts{
+				Namespace: namespace,
+				Subsystem: subsystem,
+				Name:      "rpc_duration_seconds",
+				Help:      "Latency of libovsdb rpc calls, partitioned by method",
+				Buckets:   prometheus.DefBuckets,
+			},
+			[]string{"method"},
+		),
+		reconnects: prometheus.NewCounter(
+			prometheus.CounterOpts{
+				Namespace: namespace,
+				Subsystem: subsystem,
+				Name:      "reconnects_total",
+				Help:      "Count of libovsdb reconnections",
+			},
+		),
+		updates: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Namespace: namespace,
+				Subsystem: subsystem,
+				Name:      "applied_table_updates_total",
+				Help:      "Count of libovsdb monitor table updates applied to the cache",
+			},
+			[]string{"database", "table"},
+		),
+		cachedRows: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Namespace: namespace,
+				Subsystem: subsystem,
+				Name:      "cache_rows",
+				Help:      "Number of rows in the libovsdb cache, partitioned by table",
+			},
+			[]string{"database", "table"},
+		),
+	}
+	r.MustRegister(p.rpcs, p.rpcLatency, p.reconnects, p.updates, p.cachedRows)
+	return p
+}
+
+// RPCSent implements client.Metrics
+func (p *Prometheus) RPCSent(method string) {
+	p.rpcs.WithLabelValues(method).Inc()
+}
+
+// RPCLatency implements client.Metrics
+func (p *Prometheus) RPCLatency(method string, latency time.Duration) {
+	p.rpcLatency.WithLabelValues(method).Observe(latency.Seconds())
+}
+
+// Reconnected implements client.Metrics
+func (p *Prometheus) Reconnected() {
+	p.reconnects.Inc()
+}
+
+// UpdateApplied implements client.Metrics
+func (p *Prometheus) UpdateApplied(database, table string) {
+	p.updates.WithLabelValues(database, table).Inc()
+}
+
+// CacheSize implements client.Metrics
+func (p *Prometheus) CacheSize(database, table string, rows int) {
+	p.cachedRows.WithLabelValues(database, table).Set(float64(rows))
+}
@@ -0,0 +1,43 @@
+package metrics
+
+import (
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPrometheus(t *testing.T) {
+	r := prometheus.NewPedanticRegistry()
+	p := NewPrometheus(r, "", "")
+
+	p.RPCSent("transact")
+	p.RPCSent("transact")
+	p.RPCLatency("transact", time.Millisecond)
+	p.Reconnected()
+	p.UpdateApplied("Open_vSwitch", "Bridge")
+	p.CacheSize("Open_vSwitch", "Bridge", 3)
+	p.CacheSize("Open_vSwitch", "Bridge", 2)
+
+	assert.Equal(t, 2.0, testutil.ToFloat64(p.rpcs.WithLabelValues("transact")))
+	assert.Equal(t, 1, testutil.CollectAndCount(p.rpcLatency))
+	assert.Equal(t, 1.0, testutil.ToFloat64(p.reconnects))
+	assert.Equal(t, 1.0, testutil.ToFloat64(p.updates.WithLabelValues("Open_vSwitch", "Bridge")))
+	assert.Equal(t, 2.0, testutil.ToFloat64(p.cachedRows.WithLabelValues("Open_vSwitch", "Bridge")))
+
+	families, err := r.Gather()
+	assert.NoError(t, err)
+	var names []string
+	for _, family := range families {
+		names = append(names, family.GetName())
+	}
+	assert.ElementsMatch(t, []string{
+		"libovsdb_rpcs_total",
+		"libovsdb_rpc_duration_seconds",
+		"libovsdb_reconnects_total",
+		"libovsdb_applied_table_updates_total",
+		"libovsdb_cache_rows",
+	}, names)
+}
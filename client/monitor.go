@@ -3,6 +3,7 @@ package client
 import (
 	"fmt"
 	"reflect"
+	"sort"
 
 	"github.com/google/uuid"
 	"github.com/ovn-org/libovsdb/model"
@@ -66,6 +67,14 @@ type TableMonitor struct {
 	// Fields are the fields in the model to monitor
 	// If none are supplied, all fields will be used
 	Fields []string
+	// Select are the kinds of changes of the table to be notified of
+	// If none is supplied, every kind of change is selected
+	Select *ovsdb.MonitorSelect
+}
+
+// selectsAll returns whether a select includes every kind of change
+func selectsAll(sel *ovsdb.MonitorSelect) bool {
+	return sel == nil || (sel.Initial() && sel.Insert() && sel.Delete() && sel.Modify())
 }
 
 func newTableMonitor(o *ovsdbClient, m model.Model, conditions []model.Condition, fields []interface{}) (*TableMonitor, error) {
@@ -134,3 +143,59 @@ func WithConditionalTable(m model.Model, conditions []model.Condition, fields ..
 		return nil
 	}
 }
+
+// WithTableSelect selects the kinds of changes of the table of the model that
+// the monitor is notified of, e.g. ovsdb.NewMonitorSelect(true, false, true,
+// false) for the initial rows and their deletions only. The table is monitored
+// for all its columns if no previous option added it. As the cache only learns
+// about rows from the initial rows and the insertions, the modifications and
+// deletions of other rows are ignored.
+func WithTableSelect(m model.Model, sel *ovsdb.MonitorSelect) MonitorOption {
+	return func(o *ovsdbClient, monitor *Monitor) error {
+		tableName := o.primaryDB().model.FindTable(reflect.TypeOf(m))
+		if tableName == "" {
+			return fmt.Errorf("object of type %s is not part of the ClientDBModel", reflect.TypeOf(m))
+		}
+		for i := range monitor.Tables {
+			if monitor.Tables[i].Table == tableName {
+				monitor.Tables[i].Select = sel
+				return nil
+			}
+		}
+		monitor.Tables = append(monitor.Tables, TableMonitor{Table: tableName, Select: sel})
+		return nil
+	}
+}
+
+// WithTableColumns monitors every table in columns, each only for the given
+// columns, or for every column if none are given. The fields of the models in
+// the cache that map to columns which are not monitored keep their zero value.
+func WithTableColumns(columns map[string][]string) MonitorOption {
+	return func(o *ovsdbClient, monitor *Monitor) error {
+		dbModel := o.primaryDB().model
+		tables := make([]string, 0, len(columns))
+		for table := range columns {
+			tables = append(tables, table)
+		}
+		sort.Strings(tables)
+		for _, table := range tables {
+			if _, ok := dbModel.Types()[table]; !ok {
+				return fmt.Errorf("table %s is not part of the ClientDBModel", table)
+			}
+			// the schema is only known once connected, monitor validates
+			// the columns again in any case
+			if tableSchema := dbModel.Schema.Table(table); tableSchema != nil {
+				for _, column := range columns[table] {
+					if tableSchema.Column(column) == nil {
+						return fmt.Errorf("column %s not found in table %s", column, table)
+					}
+				}
+			}
+			monitor.Tables = append(monitor.Tables, TableMonitor{
+				Table:  table,
+				Fields: columns[table],
+			})
+		}
+		return nil
+	}
+}
@@ -1,12 +1,18 @@
 package client
 
 import (
+	"context"
 	"encoding/json"
+	"fmt"
+	"sync"
 	"testing"
+	"time"
 
+	"github.com/cenkalti/rpc2"
 	"github.com/ovn-org/libovsdb/model"
 	"github.com/ovn-org/libovsdb/ovsdb"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestWithTable(t *testing.T) {
@@ -81,3 +87,145 @@ func TestWithTableAndFieldsAndConditions(t *testing.T) {
 		},
 	}, m.Tables[0].Conditions)
 }
+
+func TestWithTableColumns(t *testing.T) {
+	client, err := newOVSDBClient(defDB)
+	assert.NoError(t, err)
+	populateClientModel(t, client)
+
+	m := newMonitor()
+	opt := WithTableColumns(map[string][]string{
+		"Open_vSwitch": {"bridges", "cur_cfg"},
+		"Bridge":       nil,
+	})
+	err = opt(client, m)
+	assert.NoError(t, err)
+	assert.Equal(t, []TableMonitor{
+		{Table: "Bridge"},
+		{Table: "Open_vSwitch", Fields: []string{"bridges", "cur_cfg"}},
+	}, m.Tables)
+
+	err = WithTableColumns(map[string][]string{"Bridge": {"foo"}})(client, newMonitor())
+	assert.EqualError(t, err, "column foo not found in table Bridge")
+	err = WithTableColumns(map[string][]string{"Port": {"name"}})(client, newMonitor())
+	assert.EqualError(t, err, "table Port is not part of the ClientDBModel")
+}
+
+func TestMonitorAllModelTables(t *testing.T) {
+	var mutex sync.Mutex
+	var requests map[string]ovsdb.MonitorRequest
+	srv := newStubServer(t)
+	// the schema has a table the model does not register
+	var extra ovsdb.TableSchema
+	err := json.Unmarshal([]byte(`{"columns": {"name": {"type": "string"}}}`), &extra)
+	require.NoError(t, err)
+	srv.schema.Tables["Extra"] = extra
+	srv.Handle("monitor_cond_since", func(_ *rpc2.Client, args []json.RawMessage, reply *ovsdb.MonitorCondSinceReply) error {
+		mutex.Lock()
+		defer mutex.Unlock()
+		if err := json.Unmarshal(args[2], &requests); err != nil {
+			return err
+		}
+		*reply = ovsdb.MonitorCondSinceReply{LastTransactionID: "txn1", Updates: ovsdb.TableUpdates2{}}
+		return nil
+	})
+	sock := srv.Serve()
+
+	ovs, err := newOVSDBClient(defDB, WithEndpoint(fmt.Sprintf("unix:%s", sock)))
+	require.NoError(t, err)
+	err = ovs.Connect(context.Background())
+	require.NoError(t, err)
+	t.Cleanup(ovs.Close)
+
+	_, err = ovs.MonitorAll(context.Background())
+	require.NoError(t, err)
+	mutex.Lock()
+	defer mutex.Unlock()
+	tables := make([]string, 0, len(requests))
+	for table := range requests {
+		tables = append(tables, table)
+	}
+	assert.ElementsMatch(t, []string{"Bridge", "Open_vSwitch"}, tables)
+}
+
+func TestWithTableSelect(t *testing.T) {
+	client, err := newOVSDBClient(defDB)
+	assert.NoError(t, err)
+	populateClientModel(t, client)
+
+	deletions := ovsdb.NewMonitorSelect(true, false, true, false)
+	m := newMonitor()
+	ovs := OpenvSwitch{}
+	require.NoError(t, WithTable(&ovs, &ovs.CurCfg)(client, m))
+	require.NoError(t, WithTableSelect(&ovs, deletions)(client, m))
+	require.NoError(t, WithTableSelect(&Bridge{}, deletions)(client, m))
+	assert.Equal(t, []TableMonitor{
+		{Table: "Open_vSwitch", Fields: []string{"cur_cfg"}, Select: deletions},
+		{Table: "Bridge", Select: deletions},
+	}, m.Tables)
+	assert.Error(t, WithTableSelect(&struct{ model.Model }{}, deletions)(client, m))
+
+	requests, err := monitorRequests(client.primaryDB(), m)
+	require.NoError(t, err)
+	b, err := json.Marshal(requests["Open_vSwitch"])
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"columns": ["cur_cfg"], "select": {"initial": true, "insert": false, "delete": true, "modify": false}}`, string(b))
+}
+
+func TestMonitorSelect(t *testing.T) {
+	var defSchema ovsdb.DatabaseSchema
+	err := json.Unmarshal([]byte(schema), &defSchema)
+	require.NoError(t, err)
+	_, sock := newOVSDBServer(t, defDB, defSchema)
+
+	ovs, err := newOVSDBClient(defDB, WithEndpoint(fmt.Sprintf("unix:%s", sock)))
+	require.NoError(t, err)
+	err = ovs.Connect(context.Background())
+	require.NoError(t, err)
+	t.Cleanup(ovs.Close)
+
+	transact := func(ops ...ovsdb.Operation) []ovsdb.OperationResult {
+		results, err := ovs.Transact(context.Background(), ops...)
+		require.NoError(t, err)
+		_, err = ovsdb.CheckOperationResults(results, ops)
+		require.NoError(t, err)
+		return results
+	}
+	create := func(name string) string {
+		ops, err := ovs.Create(&Bridge{Name: name})
+		require.NoError(t, err)
+		return transact(ops...)[0].UUID.GoUUID
+	}
+	where := func(uuid string) []ovsdb.Condition {
+		return []ovsdb.Condition{ovsdb.NewCondition("_uuid", ovsdb.ConditionEqual, ovsdb.UUID{GoUUID: uuid})}
+	}
+	br0 := create("br0")
+	br1 := create("br1")
+
+	// only the initial rows and the deletions
+	_, err = ovs.Monitor(context.Background(), ovs.NewMonitor(
+		WithTableSelect(&Bridge{}, ovsdb.NewMonitorSelect(true, false, true, false)),
+	))
+	require.NoError(t, err)
+	rows := ovs.Cache().Table("Bridge")
+	require.NotNil(t, rows.Row(br0))
+	require.NotNil(t, rows.Row(br1))
+
+	// the insertion and the modification are not notified, and the deletion
+	// of the inserted row is ignored
+	br2 := create("br2")
+	transact(ovsdb.Operation{
+		Op:    ovsdb.OperationUpdate,
+		Table: "Bridge",
+		Where: where(br0),
+		Row:   ovsdb.Row{"datapath_type": "netdev"},
+	})
+	transact(ovsdb.Operation{Op: ovsdb.OperationDelete, Table: "Bridge", Where: where(br2)})
+	transact(ovsdb.Operation{Op: ovsdb.OperationDelete, Table: "Bridge", Where: where(br1)})
+	require.Eventually(t, func() bool {
+		return rows.Row(br1) == nil
+	}, 2*time.Second, 10*time.Millisecond)
+	assert.Equal(t, "", rows.Row(br0).(*Bridge).DatapathType)
+	assert.Nil(t, rows.Row(br2))
+	assert.True(t, ovs.Connected())
+}
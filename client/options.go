@@ -1,12 +1,18 @@
 package client
 
 import (
+	"context"
 	"crypto/tls"
+	"fmt"
+	"io"
+	"net"
 	"net/url"
 	"time"
 
 	"github.com/cenkalti/backoff/v4"
 	"github.com/go-logr/logr"
+	"github.com/ovn-org/libovsdb/cache"
+	"github.com/ovn-org/libovsdb/ovsdb"
 	"github.com/prometheus/client_golang/prometheus"
 )
 
@@ -23,11 +29,27 @@ type options struct {
 	leaderOnly            bool
 	timeout               time.Duration
 	backoff               backoff.BackOff
+	inactivityProbe       time.Duration
+	minSchemaVersion      *ovsdb.SchemaVersion
 	logger                *logr.Logger
+	metrics               Metrics
 	registry              prometheus.Registerer
 	shouldRegisterMetrics bool   // in case metrics are changed after-the-fact
 	metricNamespace       string // prometheus metric namespace
 	metricSubsystem       string // prometheus metric subsystem
+	monitorCancelDeletes  bool
+	validation            bool
+	gcCheck               bool
+	cacheOptions          []cache.Option
+	dialContext           func(ctx context.Context, network, address string) (net.Conn, error)
+	onConnect             func()
+	onDisconnect          func()
+	disconnectCallback    func(reason DisconnectReason, err error)
+	rateLimiter           *rateLimiter
+	// pool is the ClientPool that created the client, whose connections
+	// it shares
+	pool     *ClientPool
+	poolSize int
 }
 
 type Option func(o *options) error
@@ -87,6 +109,35 @@ func WithEndpoint(endpoint string) Option {
 	}
 }
 
+// WithDialContext sets the function the client opens the connections to its
+// endpoints with instead of a net.Dialer. It is given the network and address
+// of the endpoint, e.g. "unix" and the path of the socket, and makes it
+// possible to connect to an in-memory server, like the one end of a net.Pipe
+// returned by server.OvsdbServer.Pipe. The TLS handshake of ssl endpoints
+// is still performed by the client.
+func WithDialContext(dial func(ctx context.Context, network, address string) (net.Conn, error)) Option {
+	return func(o *options) error {
+		o.dialContext = dial
+		return nil
+	}
+}
+
+// withConn makes the client use conn as its only connection, see
+// NewClientFromConn
+func withConn(conn io.ReadWriteCloser) Option {
+	return func(o *options) error {
+		if conn == nil {
+			return fmt.Errorf("the connection is nil")
+		}
+		if o.reconnect {
+			return fmt.Errorf("a provided connection cannot be reopened to reconnect")
+		}
+		o.endpoints = []string{connEndpoint}
+		o.dialContext = newConnDialer(conn).dial
+		return nil
+	}
+}
+
 // WithLeaderOnly tells the client to treat endpoints that are clustered
 // and not the leader as down.
 func WithLeaderOnly(leaderOnly bool) Option {
@@ -111,6 +162,67 @@ func WithReconnect(timeout time.Duration, backoff backoff.BackOff) Option {
 	}
 }
 
+// WithInactivityProbe tells the client to send an echo request to the
+// server every interval, and to disconnect if the server has not replied
+// before the next one is due. Combined with WithReconnect, this detects
+// connections that silently died and reconnects them.
+func WithInactivityProbe(interval time.Duration) Option {
+	return func(o *options) error {
+		if interval <= 0 {
+			return fmt.Errorf("inactivity probe interval must be positive, got %s", interval)
+		}
+		o.inactivityProbe = interval
+		return nil
+	}
+}
+
+// WithRateLimit limits the rate of the transactions of the client to rps
+// transactions per second, with bursts of up to burst transactions. Transact
+// blocks until the limit allows the transaction, or until its context is done.
+func WithRateLimit(rps int, burst int) Option {
+	return func(o *options) error {
+		if rps <= 0 {
+			return fmt.Errorf("rate limit must be positive, got %d", rps)
+		}
+		if burst <= 0 {
+			return fmt.Errorf("rate limit burst must be positive, got %d", burst)
+		}
+		o.rateLimiter = newRateLimiter(rps, burst)
+		return nil
+	}
+}
+
+// WithPoolSize tells a ClientPool to give each of its clients a connection of
+// its own rather than to share a single connection to each endpoint between
+// them, e.g. for clients that wait for the same lock, which the server cannot
+// tell apart on a shared connection. The pool opens at most size connections
+// to each endpoint at once: Connect waits for a client of the pool to
+// disconnect from the endpoint, or for its context to be done. The option only
+// applies to NewClientPool.
+func WithPoolSize(size int) Option {
+	return func(o *options) error {
+		if size <= 0 {
+			return fmt.Errorf("pool size must be positive, got %d", size)
+		}
+		o.poolSize = size
+		return nil
+	}
+}
+
+// WithMinSchemaVersion tells the client to refuse to connect to servers
+// whose schema for the client database is older than the given version,
+// specified as <major>.<minor>.<patch>
+func WithMinSchemaVersion(version string) Option {
+	return func(o *options) error {
+		v, err := ovsdb.ParseSchemaVersion(version)
+		if err != nil {
+			return err
+		}
+		o.minSchemaVersion = &v
+		return nil
+	}
+}
+
 // WithLogger allows setting a specific log sink. Otherwise, the default
 // go log package is used.
 func WithLogger(l *logr.Logger) Option {
@@ -145,3 +257,83 @@ func WithMetricsRegistryNamespaceSubsystem(r prometheus.Registerer, namespace, s
 		return nil
 	}
 }
+
+// WithMetrics allows the user to specify a Metrics implementation that is
+// notified of rpc calls, reconnections and cache updates. Otherwise, these
+// events are not recorded.
+func WithMetrics(m Metrics) Option {
+	return func(o *options) error {
+		o.metrics = m
+		return nil
+	}
+}
+
+// WithMonitorCancelDeletes tells the client to send a delete event to the
+// cache event handlers for each row that is dropped from the cache when a
+// monitor is canceled with MonitorCancel. Otherwise, the rows are dropped
+// silently and the functions registered with OnMonitorCanceled are called
+// once instead.
+func WithMonitorCancelDeletes() Option {
+	return func(o *options) error {
+		o.monitorCancelDeletes = true
+		return nil
+	}
+}
+
+// WithDisconnectCallback sets a function that is called when the connection to
+// the server is lost, like the onDisconnect function of WithConnectionCallback
+// and right after it, with the reason why. The error is the one the connection
+// failed with for DisconnectConnectionError, and nil otherwise, so that a
+// clean shutdown of the server can be told apart from a network failure.
+func WithDisconnectCallback(fn func(reason DisconnectReason, err error)) Option {
+	return func(o *options) error {
+		o.disconnectCallback = fn
+		return nil
+	}
+}
+
+// WithClientValidation tells the client to validate the rows of the insert
+// and update operations against the schema with ovsdb.ValidateRow and
+// ovsdb.ValidateRowColumns before sending them to the server, so that invalid
+// rows fail with an ovsdb.ErrInvalidRow naming the offending column
+func WithClientValidation() Option {
+	return func(o *options) error {
+		o.validation = true
+		return nil
+	}
+}
+
+// WithGarbageCollectionCheck tells the client to fail Delete with an
+// ErrReferencedGarbageCollectedRow when one of the rows to delete belongs to a
+// table garbage collected by the server and is still strongly referenced by a
+// row of the cache. Transactions deleting such a row while removing its last
+// reference are then rejected too, and each Delete scans the referencing
+// tables of the cache
+func WithGarbageCollectionCheck() Option {
+	return func(o *options) error {
+		o.gcCheck = true
+		return nil
+	}
+}
+
+// WithCacheOptions configures the cache of each database of the client with
+// the given options, e.g. cache.WithRawRows()
+func WithCacheOptions(opts ...cache.Option) Option {
+	return func(o *options) error {
+		o.cacheOptions = append(o.cacheOptions, opts...)
+		return nil
+	}
+}
+
+// WithConnectionCallback sets functions that are called when the client
+// connects or reconnects to the server, once the monitors are restarted, and
+// when the connection to the server is lost. Either of them can be nil. They
+// are called in order, one at a time, from a goroutine that holds no lock of
+// the client, so they can call its methods.
+func WithConnectionCallback(onConnect, onDisconnect func()) Option {
+	return func(o *options) error {
+		o.onConnect = onConnect
+		o.onDisconnect = onDisconnect
+		return nil
+	}
+}
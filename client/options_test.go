@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"github.com/cenkalti/backoff/v4"
+	"github.com/ovn-org/libovsdb/ovsdb"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -116,3 +117,23 @@ func TestWithReconnect(t *testing.T) {
 	assert.Equal(t, true, opts.reconnect)
 	assert.Equal(t, &backoff.ZeroBackOff{}, opts.backoff)
 }
+
+func TestWithInactivityProbe(t *testing.T) {
+	opts := &options{}
+	err := WithInactivityProbe(5 * time.Second)(opts)
+	require.NoError(t, err)
+	assert.Equal(t, 5*time.Second, opts.inactivityProbe)
+
+	err = WithInactivityProbe(0)(opts)
+	assert.Error(t, err)
+}
+
+func TestWithMinSchemaVersion(t *testing.T) {
+	opts := &options{}
+	err := WithMinSchemaVersion("5.1.0")(opts)
+	require.NoError(t, err)
+	assert.Equal(t, &ovsdb.SchemaVersion{Major: 5, Minor: 1}, opts.minSchemaVersion)
+
+	err = WithMinSchemaVersion("5.1")(opts)
+	assert.Error(t, err)
+}
@@ -0,0 +1,543 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"sync"
+
+	"github.com/ovn-org/libovsdb/model"
+)
+
+// ClientPool creates clients that share a single connection to each of their
+// endpoints, e.g. for the controllers of a process that each monitor their own
+// tables of the same server. Every client has its own database model, cache,
+// monitors and locks, and connects, reconnects and disconnects on its own. The
+// pool multiplexes their rpcs over the shared connection and routes the
+// replies, the monitor updates and the lock notifications of the server to the
+// client they belong to. A shared connection is opened by the first client
+// that connects to its endpoint, and closed once all of them disconnected;
+// the monitors and locks of a client that disconnects are canceled and
+// released on the shared connection. Since the server sees a single
+// connection, the clients of a pool cannot wait for the same lock. A pool
+// created WithPoolSize gives each client a connection of its own instead, and
+// bounds the number of connections to each endpoint.
+type ClientPool struct {
+	options []Option
+	size    int
+	mutex   sync.Mutex
+	conns   map[string]*sharedConn
+	// dialing holds a channel for each endpoint a shared connection is
+	// being opened to, which is closed once it is done
+	dialing map[string]chan struct{}
+	// slots holds a semaphore of the connections to each endpoint of a
+	// pool created WithPoolSize
+	slots map[string]chan struct{}
+}
+
+// NewClientPool returns a ClientPool whose clients are created with the given
+// options, like WithEndpoint or WithTLSConfig
+func NewClientPool(opts ...Option) (*ClientPool, error) {
+	options, err := newOptions(opts...)
+	if err != nil {
+		return nil, err
+	}
+	return &ClientPool{
+		options: opts,
+		size:    options.poolSize,
+		conns:   make(map[string]*sharedConn),
+		dialing: make(map[string]chan struct{}),
+		slots:   make(map[string]chan struct{}),
+	}, nil
+}
+
+// Client returns a new client of the pool for the given database model. The
+// options are applied after the ones of the pool.
+func (p *ClientPool) Client(clientDBModel model.ClientDBModel, opts ...Option) (Client, error) {
+	all := make([]Option, 0, len(p.options)+len(opts)+1)
+	all = append(all, p.options...)
+	all = append(all, opts...)
+	all = append(all, func(o *options) error {
+		o.pool = p
+		return nil
+	})
+	return newOVSDBClient(clientDBModel, all...)
+}
+
+// dial returns the end of a connection of a client multiplexed over the
+// shared connection to an endpoint, which is opened with dial if there is
+// none yet. A single client opens it, the others wait for it to be done.
+func (p *ClientPool) dial(ctx context.Context, u *url.URL, dial func(context.Context, *url.URL) (net.Conn, error)) (net.Conn, error) {
+	if p.size > 0 {
+		return p.dialOwn(ctx, u, dial)
+	}
+	endpoint := u.String()
+	for {
+		p.mutex.Lock()
+		if shared, ok := p.conns[endpoint]; ok {
+			if c, err := shared.add(); err == nil {
+				p.mutex.Unlock()
+				return c, nil
+			}
+		}
+		dialing, ok := p.dialing[endpoint]
+		if !ok {
+			break
+		}
+		p.mutex.Unlock()
+		select {
+		case <-dialing:
+			// try the shared connection, or to open it if it failed
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+	dialing := make(chan struct{})
+	p.dialing[endpoint] = dialing
+	p.mutex.Unlock()
+
+	conn, err := dial(ctx, u)
+
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	delete(p.dialing, endpoint)
+	close(dialing)
+	if err != nil {
+		return nil, err
+	}
+	shared := newSharedConn(p, endpoint, conn)
+	p.conns[endpoint] = shared
+	go shared.read()
+	return shared.add()
+}
+
+// dialOwn opens a connection of a client of a pool created WithPoolSize, once
+// the number of connections to the endpoint allows it
+func (p *ClientPool) dialOwn(ctx context.Context, u *url.URL, dial func(context.Context, *url.URL) (net.Conn, error)) (net.Conn, error) {
+	endpoint := u.String()
+	p.mutex.Lock()
+	slots, ok := p.slots[endpoint]
+	if !ok {
+		slots = make(chan struct{}, p.size)
+		p.slots[endpoint] = slots
+	}
+	p.mutex.Unlock()
+	select {
+	case slots <- struct{}{}:
+	case <-ctx.Done():
+		return nil, fmt.Errorf("%d connections to %s already open: %w", p.size, endpoint, ctx.Err())
+	}
+	conn, err := dial(ctx, u)
+	if err != nil {
+		<-slots
+		return nil, err
+	}
+	return &ownConn{Conn: conn, slots: slots}, nil
+}
+
+// ownConn is a connection of a client of a pool created WithPoolSize, which
+// frees its slot once closed
+type ownConn struct {
+	net.Conn
+	slots chan struct{}
+	once  sync.Once
+}
+
+func (c *ownConn) Close() error {
+	err := c.Conn.Close()
+	c.once.Do(func() {
+		<-c.slots
+	})
+	return err
+}
+
+// forget removes a shared connection that was closed from the pool
+func (p *ClientPool) forget(shared *sharedConn) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	if p.conns[shared.endpoint] == shared {
+		delete(p.conns, shared.endpoint)
+	}
+}
+
+// errSharedConnClosed is returned when adding a client to a shared connection
+// that was closed
+var errSharedConnClosed = errors.New("shared connection closed")
+
+// message is a JSON-RPC message, kept raw so that the pool only rewrites the
+// members it needs to
+type message map[string]json.RawMessage
+
+func (m message) method() string {
+	var method string
+	_ = json.Unmarshal(m["method"], &method)
+	return method
+}
+
+func (m message) params() []json.RawMessage {
+	var params []json.RawMessage
+	_ = json.Unmarshal(m["params"], &params)
+	return params
+}
+
+// hasID returns whether the message is a request that expects a reply, or a
+// reply
+func (m message) hasID() bool {
+	id, ok := m["id"]
+	return ok && string(id) != "null"
+}
+
+// pendingCall is a call of a client that awaits its reply, and the id the
+// client gave it
+type pendingCall struct {
+	client *pooledConn
+	id     json.RawMessage
+}
+
+// sharedConn is a connection to an endpoint shared by the clients of a pool.
+// The calls of the clients are sent with ids of the shared connection, which
+// identify the client their replies are routed to. The monitor updates are
+// routed by monitor cookie, and the lock notifications by lock id.
+type sharedConn struct {
+	pool     *ClientPool
+	endpoint string
+	conn     net.Conn
+	// writeMutex serializes the messages written to conn
+	writeMutex sync.Mutex
+
+	mutex    sync.Mutex
+	closed   bool
+	clients  map[*pooledConn]bool
+	seq      uint64
+	calls    map[uint64]pendingCall
+	monitors map[string]*pooledConn
+	locks    map[string]*pooledConn
+}
+
+func newSharedConn(pool *ClientPool, endpoint string, conn net.Conn) *sharedConn {
+	return &sharedConn{
+		pool:     pool,
+		endpoint: endpoint,
+		conn:     conn,
+		clients:  make(map[*pooledConn]bool),
+		calls:    make(map[uint64]pendingCall),
+		monitors: make(map[string]*pooledConn),
+		locks:    make(map[string]*pooledConn),
+	}
+}
+
+// add returns the end of the connection of a new client of the shared
+// connection
+func (s *sharedConn) add() (net.Conn, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if s.closed {
+		return nil, errSharedConnClosed
+	}
+	clientEnd, poolEnd := net.Pipe()
+	c := &pooledConn{
+		shared: s,
+		conn:   poolEnd,
+		ready:  make(chan struct{}, 1),
+		done:   make(chan struct{}),
+	}
+	s.clients[c] = true
+	go c.read()
+	go c.write()
+	return clientEnd, nil
+}
+
+// remove stops routing messages to a client that disconnected, and cancels
+// its monitors and releases its locks. The shared connection is closed once
+// it has no client left.
+func (s *sharedConn) remove(c *pooledConn) {
+	s.mutex.Lock()
+	if !s.clients[c] {
+		s.mutex.Unlock()
+		return
+	}
+	delete(s.clients, c)
+	if s.closed {
+		s.mutex.Unlock()
+		return
+	}
+	for seq, call := range s.calls {
+		if call.client == c {
+			delete(s.calls, seq)
+		}
+	}
+	var cleanup []message
+	for cookie, client := range s.monitors {
+		if client == c {
+			delete(s.monitors, cookie)
+			cleanup = append(cleanup, s.request("monitor_cancel", json.RawMessage(cookie)))
+		}
+	}
+	for id, client := range s.locks {
+		if client == c {
+			delete(s.locks, id)
+			lockID, _ := json.Marshal(id)
+			cleanup = append(cleanup, s.request("unlock", lockID))
+		}
+	}
+	last := len(s.clients) == 0
+	if last {
+		s.closed = true
+	}
+	s.mutex.Unlock()
+
+	if last {
+		s.pool.forget(s)
+		s.conn.Close()
+		return
+	}
+	for _, msg := range cleanup {
+		s.writeMessage(msg)
+	}
+}
+
+// request returns a request of the pool itself, whose reply is dropped.
+// Caller must hold the mutex.
+func (s *sharedConn) request(method string, params ...json.RawMessage) message {
+	rawMethod, _ := json.Marshal(method)
+	rawParams, _ := json.Marshal(params)
+	seq := s.seq
+	s.seq++
+	rawID, _ := json.Marshal(seq)
+	s.calls[seq] = pendingCall{}
+	return message{"method": rawMethod, "params": rawParams, "id": rawID}
+}
+
+// send sends a message of a client to the server
+func (s *sharedConn) send(c *pooledConn, msg message) {
+	method := msg.method()
+	if method == "" {
+		// the reply to a request of the server, whose id is unique
+		s.writeMessage(msg)
+		return
+	}
+	params := msg.params()
+	s.mutex.Lock()
+	switch method {
+	case "monitor", "monitor_cond", "monitor_cond_since":
+		if len(params) > 1 {
+			s.monitors[canonicalJSON(params[1])] = c
+		}
+	case "monitor_cancel":
+		if len(params) > 0 {
+			delete(s.monitors, canonicalJSON(params[0]))
+		}
+	case "lock", "steal":
+		if id, ok := lockParam(params); ok {
+			s.locks[id] = c
+		}
+	case "unlock":
+		if id, ok := lockParam(params); ok {
+			delete(s.locks, id)
+		}
+	}
+	if msg.hasID() {
+		seq := s.seq
+		s.seq++
+		s.calls[seq] = pendingCall{client: c, id: msg["id"]}
+		msg["id"], _ = json.Marshal(seq)
+	}
+	s.mutex.Unlock()
+	s.writeMessage(msg)
+}
+
+func (s *sharedConn) writeMessage(msg message) {
+	b, err := json.Marshal(msg)
+	if err != nil {
+		return
+	}
+	s.writeMutex.Lock()
+	defer s.writeMutex.Unlock()
+	// a failed write closes the connection, which read notices
+	_, _ = s.conn.Write(b)
+}
+
+// read routes the messages of the server to the clients until the connection
+// fails, and then closes the connections of the clients
+func (s *sharedConn) read() {
+	decoder := json.NewDecoder(s.conn)
+	for {
+		var msg message
+		if err := decoder.Decode(&msg); err != nil {
+			break
+		}
+		s.route(msg)
+	}
+	s.mutex.Lock()
+	s.closed = true
+	clients := make([]*pooledConn, 0, len(s.clients))
+	for c := range s.clients {
+		clients = append(clients, c)
+	}
+	s.mutex.Unlock()
+	s.pool.forget(s)
+	s.conn.Close()
+	for _, c := range clients {
+		c.conn.Close()
+	}
+}
+
+// route delivers a message of the server to the client it belongs to
+func (s *sharedConn) route(msg message) {
+	method := msg.method()
+	if method == "" {
+		var seq uint64
+		if err := json.Unmarshal(msg["id"], &seq); err != nil {
+			return
+		}
+		s.mutex.Lock()
+		call, ok := s.calls[seq]
+		delete(s.calls, seq)
+		s.mutex.Unlock()
+		if !ok || call.client == nil {
+			// the reply to a request of the pool, or of a client that
+			// disconnected
+			return
+		}
+		msg["id"] = call.id
+		call.client.deliver(msg)
+		return
+	}
+
+	params := msg.params()
+	if method == "echo" {
+		if msg.hasID() {
+			s.writeMessage(message{"id": msg["id"], "result": msg["params"], "error": json.RawMessage("null")})
+		}
+		return
+	}
+	var c *pooledConn
+	s.mutex.Lock()
+	switch method {
+	case "update", "update2", "update3", "monitor_canceled":
+		if len(params) > 0 {
+			cookie := canonicalJSON(params[0])
+			c = s.monitors[cookie]
+			if method == "monitor_canceled" {
+				delete(s.monitors, cookie)
+			}
+		}
+	case "locked", "stolen":
+		if id, ok := lockParam(params); ok {
+			c = s.locks[id]
+		}
+	}
+	s.mutex.Unlock()
+	if c != nil {
+		c.deliver(msg)
+		return
+	}
+	if msg.hasID() {
+		// the server awaits a reply, e.g. to an update of a monitor
+		// canceled in the meantime
+		errMsg, _ := json.Marshal("no client for " + method)
+		s.writeMessage(message{"id": msg["id"], "result": json.RawMessage("null"), "error": errMsg})
+	}
+}
+
+func lockParam(params []json.RawMessage) (string, bool) {
+	if len(params) == 0 {
+		return "", false
+	}
+	var id string
+	if err := json.Unmarshal(params[0], &id); err != nil {
+		return "", false
+	}
+	return id, true
+}
+
+// canonicalJSON returns a JSON value in a canonical form, so that the monitor
+// cookies the server sends back match the ones of the clients
+func canonicalJSON(raw json.RawMessage) string {
+	var v interface{}
+	if err := json.Unmarshal(raw, &v); err != nil {
+		return string(raw)
+	}
+	b, err := json.Marshal(v)
+	if err != nil {
+		return string(raw)
+	}
+	return string(b)
+}
+
+// maxQueuedMessages is the number of messages routed to a client of a shared
+// connection that it may be late to read, see pooledConn
+const maxQueuedMessages = 4096
+
+// pooledConn is the pool end of the connection of a client of a shared
+// connection. The messages routed to the client are queued, so that a client
+// that is slow to read them does not hold up the other clients. A client
+// that falls more than maxQueuedMessages behind is disconnected, like the
+// server does with the clients whose backlog grows too large.
+type pooledConn struct {
+	shared *sharedConn
+	conn   net.Conn
+	mutex  sync.Mutex
+	queue  []message
+	ready  chan struct{}
+	done   chan struct{}
+}
+
+// read sends the messages of the client until it closes its connection
+func (c *pooledConn) read() {
+	decoder := json.NewDecoder(c.conn)
+	for {
+		var msg message
+		if err := decoder.Decode(&msg); err != nil {
+			break
+		}
+		c.shared.send(c, msg)
+	}
+	close(c.done)
+	c.conn.Close()
+	c.shared.remove(c)
+}
+
+func (c *pooledConn) deliver(msg message) {
+	c.mutex.Lock()
+	if len(c.queue) >= maxQueuedMessages {
+		c.queue = nil
+		c.mutex.Unlock()
+		c.conn.Close()
+		return
+	}
+	c.queue = append(c.queue, msg)
+	c.mutex.Unlock()
+	select {
+	case c.ready <- struct{}{}:
+	default:
+	}
+}
+
+// write writes the queued messages to the client until it closes its
+// connection
+func (c *pooledConn) write() {
+	for {
+		select {
+		case <-c.done:
+			return
+		case <-c.ready:
+		}
+		c.mutex.Lock()
+		queue := c.queue
+		c.queue = nil
+		c.mutex.Unlock()
+		for _, msg := range queue {
+			b, err := json.Marshal(msg)
+			if err != nil {
+				continue
+			}
+			if _, err := c.conn.Write(b); err != nil {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,234 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/cenkalti/rpc2"
+	"github.com/ovn-org/libovsdb/model"
+	"github.com/ovn-org/libovsdb/ovsdb"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClientPool(t *testing.T) {
+	var defSchema ovsdb.DatabaseSchema
+	err := json.Unmarshal([]byte(schema), &defSchema)
+	require.NoError(t, err)
+	server, sock := newOVSDBServer(t, defDB, defSchema)
+	var connections int32
+	server.OnConnect(func(*rpc2.Client) {
+		atomic.AddInt32(&connections, 1)
+	})
+
+	pool, err := NewClientPool(WithEndpoint(fmt.Sprintf("unix:%s", sock)))
+	require.NoError(t, err)
+	bridgeDB, err := model.NewClientDBModel("Open_vSwitch", map[string]model.Model{"Bridge": &Bridge{}})
+	require.NoError(t, err)
+	ovsClient, err := pool.Client(defDB)
+	require.NoError(t, err)
+	bridgeClient, err := pool.Client(bridgeDB)
+	require.NoError(t, err)
+	for _, c := range []Client{ovsClient, bridgeClient} {
+		require.NoError(t, c.Connect(context.Background()))
+	}
+	t.Cleanup(ovsClient.Close)
+	t.Cleanup(bridgeClient.Close)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&connections))
+
+	// each client gets the updates of its own monitors
+	_, err = ovsClient.Monitor(context.Background(), ovsClient.NewMonitor(WithTable(&OpenvSwitch{})))
+	require.NoError(t, err)
+	_, err = bridgeClient.MonitorAll(context.Background())
+	require.NoError(t, err)
+	br := &Bridge{UUID: "br0", Name: "br0"}
+	ops, err := bridgeClient.Create(br)
+	require.NoError(t, err)
+	bridgeOps, err := ovsClient.Create(&OpenvSwitch{UUID: "ovs", Bridges: []string{"br0"}})
+	require.NoError(t, err)
+	results, err := ovsClient.Transact(context.Background(), append(ops, bridgeOps...)...)
+	require.NoError(t, err)
+	_, err = ovsdb.CheckOperationResults(results, append(ops, bridgeOps...))
+	require.NoError(t, err)
+	brUUID, ovsUUID := results[0].UUID.GoUUID, results[1].UUID.GoUUID
+	require.Eventually(t, func() bool {
+		return bridgeClient.Cache().Table("Bridge").Row(brUUID) != nil &&
+			ovsClient.Cache().Table("Open_vSwitch").Row(ovsUUID) != nil
+	}, 2*time.Second, 10*time.Millisecond)
+	assert.Equal(t, 0, bridgeClient.Cache().Table("Open_vSwitch").Len())
+	assert.Equal(t, 0, ovsClient.Cache().Table("Bridge").Len())
+
+	// the other client keeps the connection once one disconnected
+	ovsClient.Close()
+	require.Eventually(t, func() bool {
+		return !ovsClient.Connected()
+	}, 2*time.Second, 10*time.Millisecond)
+	b := &Bridge{UUID: brUUID, ExternalIDs: map[string]string{"foo": "bar"}}
+	ops, err = bridgeClient.Where(b).Update(b, &b.ExternalIDs)
+	require.NoError(t, err)
+	_, err = bridgeClient.Transact(context.Background(), ops...)
+	require.NoError(t, err)
+	require.Eventually(t, func() bool {
+		m := bridgeClient.Cache().Table("Bridge").Row(brUUID)
+		return m != nil && m.(*Bridge).ExternalIDs["foo"] == "bar"
+	}, 2*time.Second, 10*time.Millisecond)
+	pool.mutex.Lock()
+	assert.Len(t, pool.conns, 1)
+	pool.mutex.Unlock()
+
+	// the shared connection is closed once all the clients disconnected
+	bridgeClient.Close()
+	require.Eventually(t, func() bool {
+		pool.mutex.Lock()
+		defer pool.mutex.Unlock()
+		return len(pool.conns) == 0
+	}, 2*time.Second, 10*time.Millisecond)
+
+	// and opened again by the next one
+	require.NoError(t, bridgeClient.Connect(context.Background()))
+	_, err = bridgeClient.MonitorAll(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 1, bridgeClient.Cache().Table("Bridge").Len())
+	assert.Equal(t, int32(2), atomic.LoadInt32(&connections))
+}
+
+func TestClientPoolDialOnce(t *testing.T) {
+	var defSchema ovsdb.DatabaseSchema
+	err := json.Unmarshal([]byte(schema), &defSchema)
+	require.NoError(t, err)
+	server, sock := newOVSDBServer(t, defDB, defSchema)
+	var connections int32
+	server.OnConnect(func(*rpc2.Client) {
+		atomic.AddInt32(&connections, 1)
+	})
+
+	var pool *ClientPool
+	var dials int32
+	dial := func(ctx context.Context, network, address string) (net.Conn, error) {
+		// the pool is not locked while the connection is opened
+		if !pool.mutex.TryLock() {
+			return nil, fmt.Errorf("pool locked while dialing")
+		}
+		pool.mutex.Unlock()
+		atomic.AddInt32(&dials, 1)
+		time.Sleep(50 * time.Millisecond)
+		var dialer net.Dialer
+		return dialer.DialContext(ctx, network, address)
+	}
+	pool, err = NewClientPool(WithEndpoint(fmt.Sprintf("unix:%s", sock)), WithDialContext(dial))
+	require.NoError(t, err)
+
+	clients := make([]Client, 5)
+	for i := range clients {
+		clients[i], err = pool.Client(defDB)
+		require.NoError(t, err)
+		t.Cleanup(clients[i].Close)
+	}
+	var wg sync.WaitGroup
+	errs := make([]error, len(clients))
+	for i, c := range clients {
+		wg.Add(1)
+		go func(i int, c Client) {
+			defer wg.Done()
+			errs[i] = c.Connect(context.Background())
+		}(i, c)
+	}
+	wg.Wait()
+	for _, err := range errs {
+		require.NoError(t, err)
+	}
+	assert.Equal(t, int32(1), atomic.LoadInt32(&dials))
+	assert.Equal(t, int32(1), atomic.LoadInt32(&connections))
+}
+
+func TestClientPoolQueueLimit(t *testing.T) {
+	pool, err := NewClientPool()
+	require.NoError(t, err)
+	serverEnd, poolEnd := net.Pipe()
+	t.Cleanup(func() { serverEnd.Close() })
+	shared := newSharedConn(pool, "unix:test", poolEnd)
+	clientEnd, err := shared.add()
+	require.NoError(t, err)
+	shared.mutex.Lock()
+	var c *pooledConn
+	for client := range shared.clients {
+		c = client
+	}
+	shared.mutex.Unlock()
+
+	// the client does not read the messages routed to it
+	msg := message{"method": json.RawMessage(`"update"`), "params": json.RawMessage(`[null, {}]`), "id": json.RawMessage("null")}
+	for i := 0; i <= maxQueuedMessages+1; i++ {
+		c.deliver(msg)
+	}
+	select {
+	case <-c.done:
+	case <-time.After(2 * time.Second):
+		t.Fatal("the client that fell behind was not disconnected")
+	}
+	_, err = io.Copy(io.Discard, clientEnd)
+	assert.NoError(t, err)
+	shared.mutex.Lock()
+	assert.Empty(t, shared.clients)
+	shared.mutex.Unlock()
+}
+
+func TestClientPoolSize(t *testing.T) {
+	var defSchema ovsdb.DatabaseSchema
+	err := json.Unmarshal([]byte(schema), &defSchema)
+	require.NoError(t, err)
+	server, sock := newOVSDBServer(t, defDB, defSchema)
+	var connections int32
+	server.OnConnect(func(*rpc2.Client) {
+		atomic.AddInt32(&connections, 1)
+	})
+
+	_, err = NewClientPool(WithPoolSize(0))
+	assert.Error(t, err)
+	pool, err := NewClientPool(WithEndpoint(fmt.Sprintf("unix:%s", sock)), WithPoolSize(2))
+	require.NoError(t, err)
+	clients := make([]Client, 3)
+	for i := range clients {
+		clients[i], err = pool.Client(defDB)
+		require.NoError(t, err)
+		t.Cleanup(clients[i].Close)
+	}
+
+	// each client has a connection of its own, up to the size of the pool
+	for _, c := range clients[:2] {
+		require.NoError(t, c.Connect(context.Background()))
+	}
+	assert.Equal(t, int32(2), atomic.LoadInt32(&connections))
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	assert.Error(t, clients[2].Connect(ctx))
+
+	// a client waits for another one to disconnect
+	connected := make(chan error)
+	go func() {
+		connected <- clients[2].Connect(context.Background())
+	}()
+	select {
+	case err := <-connected:
+		t.Fatalf("connected beyond the size of the pool: %v", err)
+	case <-time.After(100 * time.Millisecond):
+	}
+	clients[0].Close()
+	select {
+	case err := <-connected:
+		require.NoError(t, err)
+	case <-time.After(2 * time.Second):
+		t.Fatal("the client did not connect once another one disconnected")
+	}
+	assert.Equal(t, int32(3), atomic.LoadInt32(&connections))
+	pool.mutex.Lock()
+	assert.Empty(t, pool.conns)
+	pool.mutex.Unlock()
+}
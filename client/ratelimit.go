@@ -0,0 +1,73 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// rateLimiter is a token bucket that holds up to burst tokens and is refilled
+// with rate tokens per second. Each transaction takes a token.
+type rateLimiter struct {
+	mutex  sync.Mutex
+	rate   float64
+	burst  float64
+	tokens float64
+	last   time.Time
+}
+
+func newRateLimiter(rps, burst int) *rateLimiter {
+	return &rateLimiter{
+		rate:   float64(rps),
+		burst:  float64(burst),
+		tokens: float64(burst),
+	}
+}
+
+// reserve takes a token and returns how long to wait before it is available.
+// The tokens go negative when they are reserved in advance.
+func (r *rateLimiter) reserve() time.Duration {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	now := time.Now()
+	if !r.last.IsZero() {
+		r.tokens += now.Sub(r.last).Seconds() * r.rate
+		if r.tokens > r.burst {
+			r.tokens = r.burst
+		}
+	}
+	r.last = now
+	r.tokens--
+	if r.tokens >= 0 {
+		return 0
+	}
+	return time.Duration(-r.tokens / r.rate * float64(time.Second))
+}
+
+// cancel gives back a token whose reservation was not used
+func (r *rateLimiter) cancel() {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.tokens++
+	if r.tokens > r.burst {
+		r.tokens = r.burst
+	}
+}
+
+// wait blocks until a token is available, or until the context is done
+func (r *rateLimiter) wait(ctx context.Context) error {
+	delay := r.reserve()
+	if delay == 0 {
+		return nil
+	}
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		r.cancel()
+		return fmt.Errorf("%w: while awaiting the rate limit", ctx.Err())
+	case <-timer.C:
+		return nil
+	}
+}
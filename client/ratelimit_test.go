@@ -0,0 +1,65 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/ovn-org/libovsdb/ovsdb"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithRateLimit(t *testing.T) {
+	_, err := newOVSDBClient(defDB, WithRateLimit(0, 1))
+	assert.Error(t, err)
+	_, err = newOVSDBClient(defDB, WithRateLimit(1, 0))
+	assert.Error(t, err)
+
+	var defSchema ovsdb.DatabaseSchema
+	err = json.Unmarshal([]byte(schema), &defSchema)
+	require.NoError(t, err)
+	_, sock := newOVSDBServer(t, defDB, defSchema)
+	connect := func(t *testing.T, rps, burst int) *ovsdbClient {
+		ovs, err := newOVSDBClient(defDB, WithEndpoint(fmt.Sprintf("unix:%s", sock)), WithRateLimit(rps, burst))
+		require.NoError(t, err)
+		err = ovs.Connect(context.Background())
+		require.NoError(t, err)
+		t.Cleanup(ovs.Close)
+		return ovs
+	}
+	op := ovsdb.Operation{Op: ovsdb.OperationSelect, Table: "Bridge"}
+
+	t.Run("throttled transactions", func(t *testing.T) {
+		ovs := connect(t, 20, 2)
+		start := time.Now()
+		// the first 2 transactions are a burst, the next 4 take 50ms each
+		for i := 0; i < 6; i++ {
+			_, err := ovs.Transact(context.Background(), op)
+			require.NoError(t, err)
+		}
+		assert.GreaterOrEqual(t, time.Since(start), 190*time.Millisecond)
+	})
+
+	t.Run("canceled transaction", func(t *testing.T) {
+		ovs := connect(t, 1, 1)
+		_, err := ovs.Transact(context.Background(), op)
+		require.NoError(t, err)
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+		start := time.Now()
+		_, err = ovs.Transact(ctx, op)
+		assert.True(t, errors.Is(err, context.DeadlineExceeded), "unexpected error %v", err)
+		assert.Less(t, time.Since(start), 500*time.Millisecond)
+
+		// the token of the canceled transaction is given back
+		limiter := ovs.options.rateLimiter
+		limiter.mutex.Lock()
+		tokens := limiter.tokens
+		limiter.mutex.Unlock()
+		assert.Greater(t, tokens, -0.5)
+	})
+}
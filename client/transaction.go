@@ -0,0 +1,204 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/cenkalti/backoff/v4"
+	"github.com/ovn-org/libovsdb/ovsdb"
+)
+
+// ErrTransactionCommitted is returned when operations are added to a
+// Transaction, or it is committed, after it was committed
+var ErrTransactionCommitted = errors.New("transaction already committed")
+
+// retryBackOff returns the backoff between the attempts of TransactWithRetry
+var retryBackOff = func() backoff.BackOff {
+	b := backoff.NewExponentialBackOff()
+	b.InitialInterval = 100 * time.Millisecond
+	b.MaxInterval = 5 * time.Second
+	// the number of attempts and the context bound the retries
+	b.MaxElapsedTime = 0
+	return b
+}
+
+// TransactWithRetry performs the provided operations like Transact, and checks
+// their results with ovsdb.CheckOperationResults. When the transaction fails
+// with an error that ovsdb.IsRetryable classifies as transient, like a
+// *ovsdb.NotLeader, it is sent again after an exponential backoff, up to
+// maxAttempts times in total. Other errors, like a *ovsdb.ConstraintViolation,
+// are returned immediately, as are the errors of the rpc itself, since the
+// server may have committed the transaction before the client got its reply.
+// It returns the results and the error of the last attempt.
+func (o *ovsdbClient) TransactWithRetry(ctx context.Context, maxAttempts int, operation ...ovsdb.Operation) ([]ovsdb.OperationResult, error) {
+	if maxAttempts < 1 {
+		return nil, fmt.Errorf("invalid number of attempts %d", maxAttempts)
+	}
+	b := backoff.WithContext(retryBackOff(), ctx)
+	for attempt := 1; ; attempt++ {
+		results, err := o.Transact(ctx, operation...)
+		if err != nil {
+			return nil, err
+		}
+		_, err = ovsdb.CheckOperationResults(results, operation)
+		if err == nil || !ovsdb.IsRetryable(err) || attempt == maxAttempts {
+			return results, err
+		}
+		next := b.NextBackOff()
+		if next == backoff.Stop {
+			return results, err
+		}
+		o.logger.V(3).Info("retrying transaction", "attempt", attempt, "error", err.Error(), "backoff", next)
+		select {
+		case <-ctx.Done():
+			return results, err
+		case <-time.After(next):
+		}
+	}
+}
+
+// Transaction accumulates the operations returned by several calls to the
+// API, like Create or Where(...).Update, and commits them in a single transact
+// rpc, in the order they were added. The operations of different calls can
+// reference the rows inserted by each other with their named-uuids, e.g.:
+//
+//	tx := ovs.NewTransaction()
+//	br := &Bridge{UUID: "br0", Name: "br0"}
+//	tx.Add(ovs.Create(br))
+//	o := &OpenvSwitch{UUID: ovsUUID}
+//	tx.Add(ovs.Where(o).Mutate(o, model.Mutation{
+//		Field:   &o.Bridges,
+//		Mutator: ovsdb.MutateOperationInsert,
+//		Value:   []string{br.UUID},
+//	}))
+//	results, err := tx.Commit(ctx)
+//
+// A Transaction is not safe for concurrent use.
+type Transaction struct {
+	client    Client
+	ops       []ovsdb.Operation
+	pending   []*PendingOps
+	err       error
+	results   []ovsdb.OperationResult
+	committed bool
+}
+
+// PendingOps are the operations added to a Transaction by a call to Add
+type PendingOps struct {
+	tx    *Transaction
+	start int
+	end   int
+}
+
+// NewTransaction returns an empty Transaction committed by the client
+func (o *ovsdbClient) NewTransaction() *Transaction {
+	return &Transaction{client: o}
+}
+
+// Add appends operations to the transaction. It takes the results of an API
+// call, so that they can be added directly, e.g. tx.Add(ovs.Create(m)). If
+// the call failed, its error is returned by Commit, which then sends nothing
+// to the server.
+func (t *Transaction) Add(ops []ovsdb.Operation, err error) *PendingOps {
+	p := &PendingOps{tx: t, start: len(t.ops)}
+	switch {
+	case t.committed:
+		t.setErr(ErrTransactionCommitted)
+	case err != nil:
+		t.setErr(fmt.Errorf("operation %d: %w", len(t.pending), err))
+	default:
+		t.ops = append(t.ops, ops...)
+	}
+	p.end = len(t.ops)
+	t.pending = append(t.pending, p)
+	return p
+}
+
+// Assert prepends an assert operation to the transaction, so that it fails
+// unless the client owns the lock with the given id, see Assert. The other
+// operations are not executed if it fails.
+func (t *Transaction) Assert(id string) *PendingOps {
+	p := &PendingOps{tx: t}
+	switch {
+	case t.committed:
+		t.setErr(ErrTransactionCommitted)
+	case id == "":
+		t.setErr(fmt.Errorf("operation %d: empty lock id", len(t.pending)))
+	default:
+		t.ops = append([]ovsdb.Operation{Assert(id)}, t.ops...)
+		for _, pending := range t.pending {
+			pending.start++
+			pending.end++
+		}
+		p.end = 1
+	}
+	t.pending = append(t.pending, p)
+	return p
+}
+
+func (t *Transaction) setErr(err error) {
+	if t.err == nil {
+		t.err = err
+	}
+}
+
+// Operations returns the operations added to the transaction so far
+func (t *Transaction) Operations() []ovsdb.Operation {
+	return t.ops
+}
+
+// Commit sends all the operations of the transaction to the server in a
+// single transact rpc, unless one of the calls given to Add failed. It returns
+// the results of the operations and the error returned by
+// ovsdb.CheckOperationResults, if any. The results of the operations added by
+// each call are then available from its PendingOps. A transaction can only be
+// committed once.
+func (t *Transaction) Commit(ctx context.Context) ([]ovsdb.OperationResult, error) {
+	if t.committed {
+		return nil, ErrTransactionCommitted
+	}
+	if t.err != nil {
+		return nil, t.err
+	}
+	t.committed = true
+	if len(t.ops) == 0 {
+		return nil, nil
+	}
+	results, err := t.client.Transact(ctx, t.ops...)
+	if err != nil {
+		return nil, err
+	}
+	t.results = results
+	_, err = ovsdb.CheckOperationResults(results, t.ops)
+	return results, err
+}
+
+// NamedUUIDs returns the UUIDs the server assigned to the rows inserted by
+// the committed transaction, keyed by their named-uuid
+func (t *Transaction) NamedUUIDs() (map[string]string, error) {
+	if !t.committed {
+		return nil, fmt.Errorf("transaction not committed")
+	}
+	return ovsdb.NamedUUIDs(t.ops, t.results)
+}
+
+// Operations returns the operations that were added
+func (p *PendingOps) Operations() []ovsdb.Operation {
+	return p.tx.ops[p.start:p.end]
+}
+
+// Results returns the results of the operations once the transaction was
+// committed, or nil. The results of operations the server did not execute,
+// because a previous one failed, are missing.
+func (p *PendingOps) Results() []ovsdb.OperationResult {
+	if p.start >= len(p.tx.results) {
+		return nil
+	}
+	end := p.end
+	if end > len(p.tx.results) {
+		end = len(p.tx.results)
+	}
+	return p.tx.results[p.start:end]
+}
@@ -0,0 +1,253 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/cenkalti/backoff/v4"
+	"github.com/cenkalti/rpc2"
+	"github.com/ovn-org/libovsdb/ovsdb"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTransaction(t *testing.T) {
+	var defSchema ovsdb.DatabaseSchema
+	err := json.Unmarshal([]byte(schema), &defSchema)
+	require.NoError(t, err)
+	_, sock := newOVSDBServer(t, defDB, defSchema)
+
+	metrics := newRecordingMetrics()
+	ovs, err := newOVSDBClient(defDB, WithEndpoint(fmt.Sprintf("unix:%s", sock)), WithMetrics(metrics))
+	require.NoError(t, err)
+	err = ovs.Connect(context.Background())
+	require.NoError(t, err)
+	t.Cleanup(ovs.Close)
+	_, err = ovs.MonitorAll(context.Background())
+	require.NoError(t, err)
+
+	// the Open_vSwitch row references the bridge inserted by a previous call
+	tx := ovs.NewTransaction()
+	br := &Bridge{UUID: "br0", Name: "br0"}
+	createBridge := tx.Add(ovs.Create(br))
+	createOvs := tx.Add(ovs.Create(&OpenvSwitch{UUID: "ovs", Bridges: []string{br.UUID}}))
+	comment := tx.Add([]ovsdb.Operation{Comment("three operations")}, nil)
+	require.Len(t, tx.Operations(), 3)
+	assert.Equal(t, ovsdb.OperationInsert, createBridge.Operations()[0].Op)
+	assert.Equal(t, "Bridge", createBridge.Operations()[0].Table)
+	assert.Equal(t, "Open_vSwitch", createOvs.Operations()[0].Table)
+	assert.Equal(t, ovsdb.OperationComment, comment.Operations()[0].Op)
+	assert.Nil(t, createBridge.Results())
+
+	metrics.mutex.Lock()
+	transacts := metrics.rpcs["transact"]
+	metrics.mutex.Unlock()
+	results, err := tx.Commit(context.Background())
+	require.NoError(t, err)
+	require.Len(t, results, 3)
+	metrics.mutex.Lock()
+	assert.Equal(t, transacts+1, metrics.rpcs["transact"])
+	metrics.mutex.Unlock()
+
+	require.Len(t, createBridge.Results(), 1)
+	require.Len(t, createOvs.Results(), 1)
+	assert.Len(t, comment.Results(), 1)
+	uuids, err := tx.NamedUUIDs()
+	require.NoError(t, err)
+	assert.Equal(t, createBridge.Results()[0].UUID.GoUUID, uuids["br0"])
+	assert.Equal(t, createOvs.Results()[0].UUID.GoUUID, uuids["ovs"])
+	require.Eventually(t, func() bool {
+		m := ovs.Cache().Table("Open_vSwitch").Row(uuids["ovs"])
+		if m == nil {
+			return false
+		}
+		return reflect.DeepEqual([]string{uuids["br0"]}, m.(*OpenvSwitch).Bridges)
+	}, 2*time.Second, 10*time.Millisecond)
+
+	_, err = tx.Commit(context.Background())
+	assert.True(t, errors.Is(err, ErrTransactionCommitted), "unexpected error %v", err)
+	tx.Add(ovs.Create(&Bridge{Name: "br1"}))
+	_, err = tx.Commit(context.Background())
+	assert.True(t, errors.Is(err, ErrTransactionCommitted), "unexpected error %v", err)
+}
+
+func TestTransactionAddError(t *testing.T) {
+	var defSchema ovsdb.DatabaseSchema
+	err := json.Unmarshal([]byte(schema), &defSchema)
+	require.NoError(t, err)
+	_, sock := newOVSDBServer(t, defDB, defSchema)
+
+	metrics := newRecordingMetrics()
+	ovs, err := newOVSDBClient(defDB, WithEndpoint(fmt.Sprintf("unix:%s", sock)), WithMetrics(metrics))
+	require.NoError(t, err)
+	err = ovs.Connect(context.Background())
+	require.NoError(t, err)
+	t.Cleanup(ovs.Close)
+
+	// nothing is sent if one of the calls failed
+	tx := ovs.NewTransaction()
+	tx.Add(ovs.Create(&Bridge{Name: "br0"}))
+	tx.Add(ovs.Create(&Bridge{UUID: "not a uuid", Name: "br1"}))
+	metrics.mutex.Lock()
+	transacts := metrics.rpcs["transact"]
+	metrics.mutex.Unlock()
+	_, err = tx.Commit(context.Background())
+	assert.EqualError(t, err, "operation 1: \"not a uuid\" is neither a valid uuid nor a valid named-uuid")
+	metrics.mutex.Lock()
+	assert.Equal(t, transacts, metrics.rpcs["transact"])
+	metrics.mutex.Unlock()
+
+	// failed operations are reported like by CheckOperationResults
+	tx = ovs.NewTransaction()
+	first := tx.Add(ovs.Create(&Bridge{UUID: "br0", Name: "br0"}))
+	timeout := 0
+	second := tx.Add([]ovsdb.Operation{{
+		Op:      ovsdb.OperationWait,
+		Table:   "Bridge",
+		Timeout: &timeout,
+		Where:   []ovsdb.Condition{},
+		Columns: []string{"name"},
+		Until:   string(ovsdb.WaitConditionEqual),
+		Rows:    []ovsdb.Row{{"name": "br1"}},
+	}}, nil)
+	results, err := tx.Commit(context.Background())
+	var abortErr *ovsdb.AbortError
+	require.True(t, errors.As(err, &abortErr), "unexpected error %v", err)
+	assert.Equal(t, 1, abortErr.Index)
+	assert.Len(t, results, 2)
+	assert.Len(t, first.Results(), 1)
+	require.Len(t, second.Results(), 1)
+	assert.NotEmpty(t, second.Results()[0].Error)
+}
+
+func TestTransactionClientValidation(t *testing.T) {
+	var defSchema ovsdb.DatabaseSchema
+	err := json.Unmarshal([]byte(schema), &defSchema)
+	require.NoError(t, err)
+	_, sock := newOVSDBServer(t, defDB, defSchema)
+
+	metrics := newRecordingMetrics()
+	ovs, err := newOVSDBClient(defDB, WithEndpoint(fmt.Sprintf("unix:%s", sock)), WithMetrics(metrics),
+		WithClientValidation())
+	require.NoError(t, err)
+	err = ovs.Connect(context.Background())
+	require.NoError(t, err)
+	t.Cleanup(ovs.Close)
+
+	// invalid rows are rejected before anything is sent
+	failMode := "unknown"
+	tx := ovs.NewTransaction()
+	tx.Add(ovs.Create(&Bridge{Name: "br0", FailMode: &failMode}))
+	metrics.mutex.Lock()
+	transacts := metrics.rpcs["transact"]
+	metrics.mutex.Unlock()
+	_, err = tx.Commit(context.Background())
+	var rowErr *ovsdb.ErrInvalidRow
+	require.True(t, errors.As(err, &rowErr), "unexpected error %v", err)
+	assert.Equal(t, "Bridge", rowErr.Table)
+	assert.Equal(t, "fail_mode", rowErr.Column)
+	metrics.mutex.Lock()
+	assert.Equal(t, transacts, metrics.rpcs["transact"])
+	metrics.mutex.Unlock()
+
+	failMode = BridgeFailModeSecure
+	tx = ovs.NewTransaction()
+	tx.Add(ovs.Create(&Bridge{Name: "br0", FailMode: &failMode, Protocols: []string{BridgeProtocolsOpenflow13}}))
+	_, err = tx.Commit(context.Background())
+	require.NoError(t, err)
+}
+
+// newTransactServer returns the socket of a server that replies to the n-th
+// transact rpc with the n-th given result, appended to the results of the
+// operations as the commit error if it is not empty, and the number of
+// transact rpcs it got
+func newTransactServer(t *testing.T, commitErrors ...ovsdb.OperationResult) (string, func() int) {
+	var mutex sync.Mutex
+	var transacts int
+
+	srv := newStubServer(t)
+	srv.Handle("transact", func(_ *rpc2.Client, args []json.RawMessage, reply *[]ovsdb.OperationResult) error {
+		mutex.Lock()
+		defer mutex.Unlock()
+		results := make([]ovsdb.OperationResult, len(args)-1)
+		for i := range results {
+			results[i] = ovsdb.OperationResult{UUID: ovsdb.UUID{GoUUID: "2f77b348-9768-4866-b761-89d5177ecda0"}}
+		}
+		if transacts < len(commitErrors) && commitErrors[transacts].Error != "" {
+			results = append(results, commitErrors[transacts])
+		}
+		transacts++
+		*reply = results
+		return nil
+	})
+	return srv.Serve(), func() int {
+		mutex.Lock()
+		defer mutex.Unlock()
+		return transacts
+	}
+}
+
+func TestTransactWithRetry(t *testing.T) {
+	defaultBackOff := retryBackOff
+	retryBackOff = func() backoff.BackOff { return backoff.NewConstantBackOff(10 * time.Millisecond) }
+	t.Cleanup(func() { retryBackOff = defaultBackOff })
+
+	notLeader := ovsdb.OperationResult{Error: "not leader", Details: "leadership changed"}
+	constraint := ovsdb.OperationResult{Error: "constraint violation", Details: "duplicate name"}
+	tests := []struct {
+		name         string
+		commitErrors []ovsdb.OperationResult
+		maxAttempts  int
+		transacts    int
+		err          interface{}
+	}{
+		{
+			"not leader is retried",
+			[]ovsdb.OperationResult{notLeader, notLeader},
+			5,
+			3,
+			nil,
+		},
+		{
+			"attempts are limited",
+			[]ovsdb.OperationResult{notLeader, notLeader, notLeader},
+			2,
+			2,
+			&ovsdb.NotLeader{},
+		},
+		{
+			"constraint violation is not retried",
+			[]ovsdb.OperationResult{constraint, notLeader},
+			5,
+			1,
+			&ovsdb.ConstraintViolation{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sock, transacts := newTransactServer(t, tt.commitErrors...)
+			ovs, err := newOVSDBClient(defDB, WithEndpoint(fmt.Sprintf("unix:%s", sock)))
+			require.NoError(t, err)
+			require.NoError(t, ovs.Connect(context.Background()))
+			t.Cleanup(ovs.Close)
+
+			ops, err := ovs.Create(&Bridge{Name: "br0"})
+			require.NoError(t, err)
+			results, err := ovs.TransactWithRetry(context.Background(), tt.maxAttempts, ops...)
+			assert.Equal(t, tt.transacts, transacts())
+			if tt.err == nil {
+				require.NoError(t, err)
+				assert.Len(t, results, 1)
+				return
+			}
+			require.Error(t, err)
+			assert.IsType(t, tt.err, err)
+		})
+	}
+}
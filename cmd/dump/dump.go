@@ -0,0 +1,49 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"time"
+
+	"github.com/ovn-org/libovsdb/client"
+)
+
+func usage() {
+	fmt.Fprintf(os.Stderr, "Dump the rows of a table as JSON:\n")
+	fmt.Fprintf(os.Stderr, "\tdump [flags] -table TABLE\n")
+	fmt.Fprintf(os.Stderr, "Flag:\n")
+	flag.PrintDefaults()
+}
+
+var endpoint = flag.String("endpoint", "unix:/var/run/openvswitch/db.sock", "OVSDB endpoint to connect to")
+var db = flag.String("db", "Open_vSwitch", "Database the table belongs to")
+var table = flag.String("table", "", "Table to dump")
+var timeout = flag.Duration("timeout", 10*time.Second, "Timeout to connect and get the rows")
+
+func main() {
+	log.SetFlags(0)
+	flag.Usage = usage
+	flag.Parse()
+
+	if *table == "" || len(flag.Args()) != 0 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), *timeout)
+	defer cancel()
+	rows, err := client.MonitorTable(ctx, *db, *table, client.WithEndpoint(*endpoint))
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	out, err := json.MarshalIndent(rows, "", "  ")
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Println(string(out))
+}
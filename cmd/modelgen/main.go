@@ -8,6 +8,7 @@ import (
 	"log"
 	"os"
 	"path/filepath"
+	"strings"
 
 	"github.com/ovn-org/libovsdb/modelgen"
 	"github.com/ovn-org/libovsdb/ovsdb"
@@ -16,17 +17,65 @@ import (
 func usage() {
 	fmt.Fprintf(os.Stderr, "Usage of modelgen:\n")
 	fmt.Fprintf(os.Stderr, "\tmodelgen [flags] OVS_SCHEMA\n")
+	fmt.Fprintf(os.Stderr, "\tmodelgen [flags] -package-prefix PREFIX OVS_SCHEMA...\n")
 	fmt.Fprintf(os.Stderr, "Flags:\n")
 	flag.PrintDefaults()
 }
 
 var (
-	outDirP  = flag.String("o", ".", "Directory where the generated files shall be stored")
-	pkgNameP = flag.String("p", "ovsmodel", "Package name")
-	dryRun   = flag.Bool("d", false, "Dry run")
-	extended = flag.Bool("extended", false, "Generates additional code like deep-copy methods, etc.")
+	outDirP   = flag.String("o", ".", "Directory where the generated files shall be stored")
+	pkgNameP  = flag.String("p", "ovsmodel", "Package name")
+	pkgPrefix = flag.String("package-prefix", "", "Generates the models of each schema into a package of its own, named after the database with this prefix, in a subdirectory of the output directory")
+	dryRun    = flag.Bool("d", false, "Dry run")
+	extended  = flag.Bool("extended", false, "Generates additional code like deep-copy methods, etc.")
+	comments  = flag.Bool("comments", false, "Documents each field with its column type and properties")
+	defaults  = flag.Bool("defaults", false, "Generates a SetDefaults method that initializes nil set and map fields")
+	validate  = flag.Bool("validation", false, "Generates a Validate method that checks the number of elements of set and map fields")
+	stringer  = flag.Bool("stringer", false, "Generates a String method that renders models with sorted sets and maps")
+	tableList = flag.Bool("table-constants", false, "Generates AllTables, the list of the table name constants")
+	accessors = flag.Bool("accessors", false, "Generates a Client type with typed Get and List methods for each table")
+	indexes   = flag.Bool("indexes", false, "Generates a TableIndexes method that returns the indexes declared by the schema of each table")
+	embed     = flag.Bool("embed-schema", false, "Embeds the schema file as it is, instead of serialized again, in the generated Schema function")
+	jsonTags  = flag.Bool("json-tags", false, "Adds a json tag with the column name next to the ovsdb tag of each field")
+	fieldAcc  = flag.Bool("field-accessors", false, "Generates GetField and SetField methods the mapper uses instead of reflection")
+	ctors     = flag.Bool("constructors", false, "Generates a New function for each table that takes the values of its required columns")
+	refAcc    = flag.Bool("reference-accessors", false, "Generates methods that resolve the rows referenced by each reference column from the cache, implies -accessors")
+	modelIfc  = flag.Bool("model-interface", false, "Generates GetUUID and SetUUID methods so that all models implement model.UUIDModel")
+	logFields = flag.Bool("log-fields", false, "Generates a Fields method that returns the value of each column by column name, for structured logging")
+	emptyDet  = flag.Bool("empty-detection", false, "Generates an IsEmpty method so that the empty optional columns are left out of inserted rows and the other ones are always sent")
+	single    = flag.String("single-file", "", "Writes all the generated code into this file of the output directory")
+	importP   = flag.String("import-path", modelgen.DefaultImportPath, "Import path of the libovsdb module used by the generated code")
+	overrides = flag.String("type-overrides", "", "JSON file mapping columns of tables to the Go types used for their fields")
+	initials  = flag.String("initialisms", "", "Comma-separated words spelled in upper case in the generated field and enum names, besides the common initialisms")
+	headerP   = flag.String("header", "", "Template file of the header comment of the generated files, executed with the schema")
 )
 
+// typeOverride is the Go type of a column in the file of -type-overrides, e.g.
+//
+//	{"Logical_Router": {"ip": {"type": "net.IP", "import": "net"}}}
+type typeOverride struct {
+	Type   string `json:"type"`
+	Import string `json:"import"`
+}
+
+func typeOverrideOptions(filename string) ([]modelgen.Option, error) {
+	b, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	var tables map[string]map[string]typeOverride
+	if err := json.Unmarshal(b, &tables); err != nil {
+		return nil, fmt.Errorf("invalid type overrides file %s: %w", filename, err)
+	}
+	var opts []modelgen.Option
+	for table, columns := range tables {
+		for column, override := range columns {
+			opts = append(opts, modelgen.WithColumnTypeOverride(table, column, override.Type, override.Import))
+		}
+	}
+	return opts, nil
+}
+
 func main() {
 	log.SetFlags(0)
 	log.SetPrefix("modelgen: ")
@@ -41,50 +90,133 @@ func main() {
 		log.Fatal(err)
 	}
 
-	if err := os.MkdirAll(outDir, 0755); err != nil {
-		log.Fatal(err)
-	}
-
-	if len(flag.Args()) != 1 {
+	// several schemas are always generated into a package per database
+	perDatabase := *pkgPrefix != "" || len(flag.Args()) > 1
+	if len(flag.Args()) == 0 || (len(flag.Args()) > 1 && !perDatabase) {
 		flag.Usage()
 		os.Exit(2)
 	}
 
-	schemaFile, err := os.Open(flag.Args()[0])
+	genOpts := []modelgen.Option{}
+	if *dryRun {
+		genOpts = append(genOpts, modelgen.WithDryRun())
+	}
+	if *refAcc {
+		*accessors = true
+	}
+	if *initials != "" {
+		genOpts = append(genOpts, modelgen.WithInitialisms(strings.Split(*initials, ",")...))
+	}
+	if *overrides != "" {
+		opts, err := typeOverrideOptions(*overrides)
+		if err != nil {
+			log.Fatal(err)
+		}
+		genOpts = append(genOpts, opts...)
+	}
+
+	var header string
+	if *headerP != "" {
+		b, err := ioutil.ReadFile(*headerP)
+		if err != nil {
+			log.Fatal(err)
+		}
+		header = string(b)
+	}
+
+	packages := map[string]string{}
+	for _, filename := range flag.Args() {
+		schemaBytes, dbSchema, err := readSchema(filename)
+		if err != nil {
+			log.Fatal(err)
+		}
+		dir := outDir
+		if perDatabase {
+			pkgName = modelgen.PackageName(*pkgPrefix, dbSchema.Name)
+			if other, ok := packages[pkgName]; ok {
+				log.Fatalf("%s and %s are both generated into package %s", other, filename, pkgName)
+			}
+			packages[pkgName] = filename
+			dir = filepath.Join(outDir, pkgName)
+		}
+		opts := genOpts
+		if header != "" {
+			opts = append(genOpts[:len(genOpts):len(genOpts)], modelgen.WithHeaderTemplate(header, dbSchema))
+		}
+		if err := generate(dir, pkgName, schemaBytes, dbSchema, opts); err != nil {
+			log.Fatal(err)
+		}
+	}
+}
+
+func readSchema(filename string) ([]byte, ovsdb.DatabaseSchema, error) {
+	var dbSchema ovsdb.DatabaseSchema
+	schemaFile, err := os.Open(filename)
 	if err != nil {
-		log.Fatal(err)
+		return nil, dbSchema, err
 	}
 	defer schemaFile.Close()
 
 	schemaBytes, err := ioutil.ReadAll(schemaFile)
 	if err != nil {
-		log.Fatal(err)
+		return nil, dbSchema, err
 	}
 
-	var dbSchema ovsdb.DatabaseSchema
 	if err := json.Unmarshal(schemaBytes, &dbSchema); err != nil {
-		log.Fatal(err)
+		return nil, dbSchema, fmt.Errorf("invalid schema file %s: %w", filename, err)
 	}
+	return schemaBytes, dbSchema, nil
+}
 
-	genOpts := []modelgen.Option{}
-	if *dryRun {
-		genOpts = append(genOpts, modelgen.WithDryRun())
+// generate writes the models of a database into the package of outDir
+func generate(outDir, pkgName string, schemaBytes []byte, dbSchema ovsdb.DatabaseSchema, genOpts []modelgen.Option) error {
+	if err := os.MkdirAll(outDir, 0755); err != nil {
+		return err
+	}
+	if *single != "" {
+		genOpts = append(genOpts[:len(genOpts):len(genOpts)], modelgen.WithSingleFile(filepath.Join(outDir, *single)))
 	}
 	gen, err := modelgen.NewGenerator(genOpts...)
 	if err != nil {
-		log.Fatal(err)
+		return err
 	}
 	for name, table := range dbSchema.Tables {
 		tmpl := modelgen.NewTableTemplate()
 		args := modelgen.GetTableTemplateData(pkgName, name, &table)
 		args.WithExtendedGen(*extended)
+		args.WithFieldComments(*comments)
+		args.WithDefaults(*defaults)
+		args.WithValidation(*validate)
+		args.WithStringer(*stringer)
+		args.WithAccessors(*accessors)
+		args.WithTableIndexes(*indexes)
+		args.WithJSONTags(*jsonTags)
+		args.WithFieldAccessors(*fieldAcc)
+		args.WithConstructors(*ctors)
+		args.WithReferenceAccessors(*refAcc)
+		args.WithModelInterface(*modelIfc)
+		args.WithLogFields(*logFields)
+		args.WithEmptyDetection(*emptyDet)
+		args.WithImportPath(*importP)
 		if err := gen.Generate(filepath.Join(outDir, modelgen.FileName(name)), tmpl, args); err != nil {
-			log.Fatal(err)
+			return err
 		}
 	}
 	dbTemplate := modelgen.NewDBTemplate()
 	dbArgs := modelgen.GetDBTemplateData(pkgName, dbSchema)
+	dbArgs.WithAccessors(*accessors)
+	dbArgs.WithTableConstants(*tableList)
+	dbArgs.WithImportPath(*importP)
+	if *embed {
+		if err := dbArgs.WithEmbeddedSchema(schemaBytes); err != nil {
+			return err
+		}
+	}
 	if err := gen.Generate(filepath.Join(outDir, "model.go"), dbTemplate, dbArgs); err != nil {
-		log.Fatal(err)
+		return err
+	}
+	if f, ok := gen.(modelgen.Flusher); ok {
+		return f.Flush()
 	}
+	return nil
 }
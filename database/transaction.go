@@ -19,6 +19,10 @@ type Transaction struct {
 	Model       model.DatabaseModel
 	DbName      string
 	Database    Database
+	// LockOwned returns whether the client that sent the transaction owns
+	// the lock with the given id, which assert operations check. They are
+	// not supported if it is nil.
+	LockOwned func(id string) bool
 }
 
 func NewTransaction(model model.DatabaseModel, dbName string, database Database, logger *logr.Logger) Transaction {
@@ -666,14 +670,22 @@ func (t *Transaction) Abort(table string) ovsdb.OperationResult {
 	return ovsdb.OperationResult{Error: e.Error()}
 }
 
+// Comment has no effect on the database, ovsdb-server only logs the comment
 func (t *Transaction) Comment(table string, comment string) ovsdb.OperationResult {
-	e := ovsdb.NotSupported{}
-	return ovsdb.OperationResult{Error: e.Error()}
+	return ovsdb.OperationResult{}
 }
 
+// Assert fails with a "not owner" error unless the client owns the lock
 func (t *Transaction) Assert(table, lock string) ovsdb.OperationResult {
-	e := ovsdb.NotSupported{}
-	return ovsdb.OperationResult{Error: e.Error()}
+	if t.LockOwned == nil {
+		e := ovsdb.NotSupported{}
+		return ovsdb.OperationResult{Error: e.Error()}
+	}
+	if !t.LockOwned(lock) {
+		e := ovsdb.NotOwner{}
+		return ovsdb.OperationResult{Error: e.Error(), Details: fmt.Sprintf("lock %s is not owned by the client", lock)}
+	}
+	return ovsdb.OperationResult{}
 }
 
 func diff(column *ovsdb.ColumnSchema, a interface{}, b interface{}) interface{} {
@@ -0,0 +1,61 @@
+package mapper
+
+import (
+	"fmt"
+	"reflect"
+	"sync"
+)
+
+// Codec converts the value of a model field whose type is not the native type
+// of its column, from and to the native type. It allows models to use richer
+// types, like a net.IP for a column of strings.
+type Codec interface {
+	// Encode returns the native value of the column for a field value
+	Encode(value interface{}) (interface{}, error)
+	// Decode returns the field value for a native value of the column
+	Decode(native interface{}) (interface{}, error)
+}
+
+var (
+	codecsMutex sync.RWMutex
+	codecs      = map[reflect.Type]Codec{}
+)
+
+// RegisterCodec registers the Codec of the fields of type t. Fields of that
+// type can then be tagged with any column, as long as the codec converts them
+// to the native type of the column. Codecs must be registered before the
+// models that use them are validated, e.g. by model.NewClientDBModel.
+func RegisterCodec(t reflect.Type, codec Codec) {
+	codecsMutex.Lock()
+	defer codecsMutex.Unlock()
+	if codec == nil {
+		delete(codecs, t)
+		return
+	}
+	codecs[t] = codec
+}
+
+func codecFor(t reflect.Type) Codec {
+	codecsMutex.RLock()
+	defer codecsMutex.RUnlock()
+	return codecs[t]
+}
+
+// encodeValue returns the native value of a value of the field type of a
+// column with a Codec. Other values are returned as they are.
+func (i *Info) encodeValue(column string, value interface{}) (interface{}, error) {
+	codec, ok := i.Metadata.Codecs[column]
+	if !ok || value == nil {
+		return value, nil
+	}
+	fieldName := i.Metadata.Fields[column]
+	field, _ := reflect.TypeOf(i.Obj).Elem().FieldByName(fieldName)
+	if reflect.TypeOf(value) != field.Type {
+		return value, nil
+	}
+	native, err := codec.Encode(value)
+	if err != nil {
+		return nil, fmt.Errorf("column %s: failed to encode %v (%s): %w", column, value, field.Type, err)
+	}
+	return native, nil
+}
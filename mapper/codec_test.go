@@ -0,0 +1,78 @@
+package mapper
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"reflect"
+	"testing"
+
+	"github.com/ovn-org/libovsdb/ovsdb"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type ipCodec struct{}
+
+func (ipCodec) Encode(value interface{}) (interface{}, error) {
+	ip := value.(net.IP)
+	if ip == nil {
+		return "", nil
+	}
+	return ip.String(), nil
+}
+
+func (ipCodec) Decode(native interface{}) (interface{}, error) {
+	s := native.(string)
+	if s == "" {
+		return net.IP(nil), nil
+	}
+	ip := net.ParseIP(s)
+	if ip == nil {
+		return nil, fmt.Errorf("invalid IP address %q", s)
+	}
+	return ip, nil
+}
+
+func TestMapperCodec(t *testing.T) {
+	var table ovsdb.TableSchema
+	require.NoError(t, json.Unmarshal(sampleTable, &table))
+	type obj struct {
+		IP   net.IP `ovsdb:"aString"`
+		Oint int    `ovsdb:"aInteger"`
+	}
+
+	_, err := NewInfo("Test", &table, &obj{})
+	require.Error(t, err, "fields without a codec must have the native type")
+
+	RegisterCodec(reflect.TypeOf(net.IP{}), ipCodec{})
+	defer RegisterCodec(reflect.TypeOf(net.IP{}), nil)
+
+	o := &obj{}
+	info, err := NewInfo("Test", &table, o)
+	require.NoError(t, err)
+	assert.Contains(t, info.Metadata.Codecs, "aString")
+	assert.NotContains(t, info.Metadata.Codecs, "aInteger")
+
+	require.NoError(t, info.SetField("aString", "192.0.2.1"))
+	assert.Equal(t, net.ParseIP("192.0.2.1"), o.IP)
+	native, err := info.FieldByColumn("aString")
+	require.NoError(t, err)
+	assert.Equal(t, "192.0.2.1", native)
+	assert.Error(t, info.SetField("aString", "not an IP"))
+
+	mapper := Mapper{}
+	row, err := mapper.NewRow(info)
+	require.NoError(t, err)
+	assert.Equal(t, "192.0.2.1", row["aString"])
+
+	other := &obj{}
+	otherInfo, err := NewInfo("Test", &table, other)
+	require.NoError(t, err)
+	require.NoError(t, mapper.GetRowData(&row, otherInfo))
+	assert.Equal(t, o.IP, other.IP)
+
+	cond, err := mapper.NewCondition(info, &o.IP, ovsdb.ConditionEqual, net.ParseIP("198.51.100.7"))
+	require.NoError(t, err)
+	assert.Equal(t, ovsdb.NewCondition("aString", ovsdb.ConditionEqual, "198.51.100.7"), *cond)
+}
@@ -37,6 +37,28 @@ type Metadata struct {
 	Fields      map[string]string  // Map of ColumnName -> FieldName
 	TableSchema *ovsdb.TableSchema // TableSchema associated
 	TableName   string             // Table name
+	Codecs      map[string]Codec   // Map of ColumnName -> Codec of the fields that are not of the native type
+}
+
+// FieldAccessor is implemented by models that access the fields of their
+// columns without reflection, like the models generated with the field
+// accessors of modelgen. The mapper prefers it to reflection.
+type FieldAccessor interface {
+	// GetField returns the value of the field of a column, or nil if the
+	// column has no field
+	GetField(column string) interface{}
+	// SetField sets the field of a column to a value of the type of the field
+	SetField(column string, value interface{}) error
+}
+
+// EmptyDetector is implemented by models that tell which of their columns are
+// empty, like the models generated with the empty detection of modelgen. The
+// mapper leaves the empty columns out of insert rows (see NewInsertRow), so
+// that they take their default value, and sends the other ones even if they
+// hold a zero value.
+type EmptyDetector interface {
+	// IsEmpty returns whether the field of a column holds no value
+	IsEmpty(column string) bool
 }
 
 // FieldByColumn returns the field value that corresponds to a column
@@ -45,7 +67,20 @@ func (i *Info) FieldByColumn(column string) (interface{}, error) {
 	if !ok {
 		return nil, NewErrColumnNotFound(column, i.Metadata.TableName)
 	}
-	return reflect.ValueOf(i.Obj).Elem().FieldByName(fieldName).Interface(), nil
+	var value interface{}
+	if accessor, ok := i.Obj.(FieldAccessor); ok {
+		value = accessor.GetField(column)
+	} else {
+		value = reflect.ValueOf(i.Obj).Elem().FieldByName(fieldName).Interface()
+	}
+	if codec, ok := i.Metadata.Codecs[column]; ok {
+		native, err := codec.Encode(value)
+		if err != nil {
+			return nil, fmt.Errorf("column %s: failed to encode field %s: %w", column, fieldName, err)
+		}
+		return native, nil
+	}
+	return value, nil
 }
 
 // FieldByColumn returns the field value that corresponds to a column
@@ -60,8 +95,24 @@ func (i *Info) SetField(column string, value interface{}) error {
 	if !ok {
 		return fmt.Errorf("SetField: column %s not found in orm info", column)
 	}
-	fieldValue := reflect.ValueOf(i.Obj).Elem().FieldByName(fieldName)
+	accessor, hasAccessor := i.Obj.(FieldAccessor)
+	if codec, ok := i.Metadata.Codecs[column]; ok {
+		decoded, err := codec.Decode(value)
+		if err != nil {
+			return fmt.Errorf("column %s: failed to decode native value %v into field %s: %w", column, value, fieldName, err)
+		}
+		if decoded == nil {
+			fieldValue := reflect.ValueOf(i.Obj).Elem().FieldByName(fieldName)
+			fieldValue.Set(reflect.Zero(fieldValue.Type()))
+			return nil
+		}
+		value = decoded
+	}
+	if hasAccessor {
+		return accessor.SetField(column, value)
+	}
 
+	fieldValue := reflect.ValueOf(i.Obj).Elem().FieldByName(fieldName)
 	if !fieldValue.Type().AssignableTo(reflect.TypeOf(value)) {
 		return fmt.Errorf("column %s: native value %v (%s) is not assignable to field %s (%s)",
 			column, value, reflect.TypeOf(value), fieldName, fieldValue.Type())
@@ -79,7 +130,9 @@ func (i *Info) ColumnByPtr(fieldPtr interface{}) (string, error) {
 	offset := fieldPtrVal.Pointer() - reflect.ValueOf(i.Obj).Pointer()
 	objType := reflect.TypeOf(i.Obj).Elem()
 	for j := 0; j < objType.NumField(); j++ {
-		if objType.Field(j).Offset == offset {
+		// a pointer to the struct itself has the same offset as its first
+		// field, so the field type needs to match as well
+		if objType.Field(j).Offset == offset && objType.Field(j).Type == fieldPtrVal.Type().Elem() {
 			column := objType.Field(j).Tag.Get("ovsdb")
 			if _, ok := i.Metadata.Fields[column]; !ok {
 				return "", fmt.Errorf("field does not have orm column information")
@@ -125,17 +178,13 @@ OUTER:
 
 // NewInfo creates a MapperInfo structure around an object based on a given table schema
 func NewInfo(tableName string, table *ovsdb.TableSchema, obj interface{}) (*Info, error) {
-	objPtrVal := reflect.ValueOf(obj)
-	if objPtrVal.Type().Kind() != reflect.Ptr {
-		return nil, ovsdb.NewErrWrongType("NewMapperInfo", "pointer to a struct", obj)
+	objType, err := structType(obj)
+	if err != nil {
+		return nil, err
 	}
-	objVal := reflect.Indirect(objPtrVal)
-	if objVal.Kind() != reflect.Struct {
-		return nil, ovsdb.NewErrWrongType("NewMapperInfo", "pointer to a struct", obj)
-	}
-	objType := objVal.Type()
 
 	fields := make(map[string]string, objType.NumField())
+	var codecs map[string]Codec
 	for i := 0; i < objType.NumField(); i++ {
 		field := objType.Field(i)
 		colName := field.Tag.Get("ovsdb")
@@ -143,29 +192,16 @@ func NewInfo(tableName string, table *ovsdb.TableSchema, obj interface{}) (*Info
 			// Untagged fields are ignored
 			continue
 		}
-		column := table.Column(colName)
-		if column == nil {
-			return nil, &ErrMapper{
-				objType:   objType.String(),
-				field:     field.Name,
-				fieldType: field.Type.String(),
-				fieldTag:  colName,
-				reason:    "Column does not exist in schema",
-			}
+		if err := validateField(objType, field, colName, table); err != nil {
+			return nil, err
 		}
-
-		// Perform schema-based type checking
-		expType := ovsdb.NativeType(column)
-		if expType != field.Type {
-			return nil, &ErrMapper{
-				objType:   objType.String(),
-				field:     field.Name,
-				fieldType: field.Type.String(),
-				fieldTag:  colName,
-				reason:    fmt.Sprintf("Wrong type, column expects %s", expType),
+		fields[colName] = field.Name
+		if codec := codecFor(field.Type); codec != nil && field.Type != ovsdb.NativeType(table.Column(colName)) {
+			if codecs == nil {
+				codecs = make(map[string]Codec)
 			}
+			codecs[colName] = codec
 		}
-		fields[colName] = field.Name
 	}
 
 	return &Info{
@@ -174,6 +210,71 @@ func NewInfo(tableName string, table *ovsdb.TableSchema, obj interface{}) (*Info
 			Fields:      fields,
 			TableSchema: table,
 			TableName:   tableName,
+			Codecs:      codecs,
 		},
 	}, nil
 }
+
+// ValidateModel checks all the tagged fields of an object against a given table
+// schema. Unlike NewInfo, which fails on the first field that does not match
+// its column, it returns an error for every such field.
+func ValidateModel(table *ovsdb.TableSchema, obj interface{}) []error {
+	objType, err := structType(obj)
+	if err != nil {
+		return []error{err}
+	}
+	var errs []error
+	for i := 0; i < objType.NumField(); i++ {
+		field := objType.Field(i)
+		colName := field.Tag.Get("ovsdb")
+		if colName == "" {
+			continue
+		}
+		if err := validateField(objType, field, colName, table); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errs
+}
+
+// structType returns the type of the struct the object points to
+func structType(obj interface{}) (reflect.Type, error) {
+	objPtrVal := reflect.ValueOf(obj)
+	if objPtrVal.Type().Kind() != reflect.Ptr {
+		return nil, ovsdb.NewErrWrongType("NewMapperInfo", "pointer to a struct", obj)
+	}
+	objVal := reflect.Indirect(objPtrVal)
+	if objVal.Kind() != reflect.Struct {
+		return nil, ovsdb.NewErrWrongType("NewMapperInfo", "pointer to a struct", obj)
+	}
+	return objVal.Type(), nil
+}
+
+// validateField checks that the column a field is tagged with exists in the
+// table schema and that the field has the native type of that column, or a
+// type with a registered Codec
+func validateField(objType reflect.Type, field reflect.StructField, colName string, table *ovsdb.TableSchema) error {
+	column := table.Column(colName)
+	if column == nil {
+		return &ErrMapper{
+			objType:   objType.String(),
+			field:     field.Name,
+			fieldType: field.Type.String(),
+			fieldTag:  colName,
+			reason:    "Column does not exist in schema",
+		}
+	}
+
+	// Perform schema-based type checking
+	expType := ovsdb.NativeType(column)
+	if expType != field.Type && codecFor(field.Type) == nil {
+		return &ErrMapper{
+			objType:   objType.String(),
+			field:     field.Name,
+			fieldType: field.Type.String(),
+			fieldTag:  colName,
+			reason:    fmt.Sprintf("Wrong type, column expects %s", expType),
+		}
+	}
+	return nil
+}
@@ -51,6 +51,50 @@ func TestNewMapperInfo(t *testing.T) {
 			}{},
 			err: false,
 		},
+		{
+			name:  "all columns",
+			table: sampleTable,
+			obj: &struct {
+				foo  string            `ovsdb:"aString"`
+				bar  int               `ovsdb:"aInteger"`
+				baz  []string          `ovsdb:"aSet"`
+				quux map[string]string `ovsdb:"aMap"`
+			}{},
+			expectedCols: []string{"aString", "aInteger", "aSet", "aMap"},
+			err:          false,
+		},
+		{
+			name:  "scalar type mismatch",
+			table: sampleTable,
+			obj: &struct {
+				foo int `ovsdb:"aString"`
+			}{},
+			err: true,
+		},
+		{
+			name:  "set type mismatch",
+			table: sampleTable,
+			obj: &struct {
+				foo []int `ovsdb:"aSet"`
+			}{},
+			err: true,
+		},
+		{
+			name:  "map type mismatch",
+			table: sampleTable,
+			obj: &struct {
+				foo map[string]int `ovsdb:"aMap"`
+			}{},
+			err: true,
+		},
+		{
+			name:  "unknown column",
+			table: sampleTable,
+			obj: &struct {
+				foo string `ovsdb:"unknown"`
+			}{},
+			err: true,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(fmt.Sprintf("NewMapper_%s", tt.name), func(t *testing.T) {
@@ -60,10 +104,11 @@ func TestNewMapperInfo(t *testing.T) {
 
 			info, err := NewInfo("Test", &table, tt.obj)
 			if tt.err {
-				assert.NotNil(t, err)
-			} else {
-				assert.Nil(t, err)
+				var errMapper *ErrMapper
+				assert.ErrorAs(t, err, &errMapper)
+				return
 			}
+			assert.Nil(t, err)
 			for _, col := range tt.expectedCols {
 				assert.Truef(t, info.hasColumn(col), "Expected column should be present in Mapper Info")
 			}
@@ -73,6 +118,28 @@ func TestNewMapperInfo(t *testing.T) {
 	}
 }
 
+func TestValidateModel(t *testing.T) {
+	var table ovsdb.TableSchema
+	err := json.Unmarshal(sampleTable, &table)
+	assert.Nil(t, err)
+
+	errs := ValidateModel(&table, &struct {
+		foo string `ovsdb:"aString"`
+		bar int    `ovsdb:"aInteger"`
+	}{})
+	assert.Empty(t, errs)
+
+	errs = ValidateModel(&table, &struct {
+		foo int    `ovsdb:"aString"`
+		bar string `ovsdb:"unknown"`
+		baz []int  `ovsdb:"aSet"`
+	}{})
+	assert.Len(t, errs, 3)
+
+	errs = ValidateModel(&table, struct{}{})
+	assert.Len(t, errs, 1)
+}
+
 func TestMapperInfoSet(t *testing.T) {
 	type obj struct {
 		Ostring string            `ovsdb:"aString"`
@@ -167,6 +234,7 @@ func TestMapperInfoColByPtr(t *testing.T) {
 		omap    map[string]string `ovsdb:"aMap"`
 	}
 	obj1 := obj{}
+	obj2 := obj{}
 
 	type test struct {
 		name   string
@@ -216,6 +284,20 @@ func TestMapperInfoColByPtr(t *testing.T) {
 			field: &obj{},
 			err:   true,
 		},
+		{
+			name:  "struct",
+			table: sampleTable,
+			obj:   &obj1,
+			field: &obj1,
+			err:   true,
+		},
+		{
+			name:  "other instance",
+			table: sampleTable,
+			obj:   &obj1,
+			field: &obj2.oint,
+			err:   true,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(fmt.Sprintf("GetFieldByPtr_%s", tt.name), func(t *testing.T) {
@@ -369,3 +451,140 @@ func TestOrmGetIndex(t *testing.T) {
 		})
 	}
 }
+
+// reflectObj and accessorObj hold the columns of sampleTable, accessorObj
+// like the models generated with field accessors
+type reflectObj struct {
+	AString  string            `ovsdb:"aString"`
+	AInteger int               `ovsdb:"aInteger"`
+	ASet     []string          `ovsdb:"aSet"`
+	AMap     map[string]string `ovsdb:"aMap"`
+}
+
+type accessorObj struct {
+	AString  string            `ovsdb:"aString"`
+	AInteger int               `ovsdb:"aInteger"`
+	ASet     []string          `ovsdb:"aSet"`
+	AMap     map[string]string `ovsdb:"aMap"`
+	calls    int
+}
+
+func (a *accessorObj) GetField(column string) interface{} {
+	a.calls++
+	switch column {
+	case "aString":
+		return a.AString
+	case "aInteger":
+		return a.AInteger
+	case "aSet":
+		return a.ASet
+	case "aMap":
+		return a.AMap
+	}
+	return nil
+}
+
+func (a *accessorObj) SetField(column string, value interface{}) error {
+	a.calls++
+	var ok bool
+	switch column {
+	case "aString":
+		a.AString, ok = value.(string)
+	case "aInteger":
+		a.AInteger, ok = value.(int)
+	case "aSet":
+		a.ASet, ok = value.([]string)
+	case "aMap":
+		a.AMap, ok = value.(map[string]string)
+	default:
+		return fmt.Errorf("column %s not found", column)
+	}
+	if !ok {
+		return fmt.Errorf("column %s: value %v (%T) has the wrong type", column, value, value)
+	}
+	return nil
+}
+
+func TestInfoFieldAccessor(t *testing.T) {
+	var table ovsdb.TableSchema
+	err := json.Unmarshal(sampleTable, &table)
+	assert.NoError(t, err)
+
+	obj := &accessorObj{}
+	info, err := NewInfo("Test", &table, obj)
+	assert.NoError(t, err)
+	assert.NoError(t, info.SetField("aString", "foo"))
+	assert.NoError(t, info.SetField("aSet", []string{"a", "b"}))
+	assert.Equal(t, "foo", obj.AString)
+	assert.Equal(t, []string{"a", "b"}, obj.ASet)
+	value, err := info.FieldByColumn("aSet")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"a", "b"}, value)
+	assert.Equal(t, 3, obj.calls)
+
+	assert.EqualError(t, info.SetField("aInteger", "42"), "column aInteger: value 42 (string) has the wrong type")
+	_, err = info.FieldByColumn("notAColumn")
+	assert.Error(t, err)
+	assert.Equal(t, 4, obj.calls)
+
+	// rows are mapped the same way with and without the accessors
+	row := ovsdb.Row{
+		"aString":  "foo",
+		"aInteger": 42,
+		"aSet":     ovsdb.OvsSet{GoSet: []interface{}{"a", "b"}},
+		"aMap":     ovsdb.OvsMap{GoMap: map[interface{}]interface{}{"k": "v"}},
+	}
+	m := Mapper{}
+	withReflect := &reflectObj{}
+	reflectInfo, err := NewInfo("Test", &table, withReflect)
+	assert.NoError(t, err)
+	assert.NoError(t, m.GetRowData(&row, reflectInfo))
+	withAccessor := &accessorObj{}
+	accessorInfo, err := NewInfo("Test", &table, withAccessor)
+	assert.NoError(t, err)
+	assert.NoError(t, m.GetRowData(&row, accessorInfo))
+	assert.Equal(t, *withReflect, reflectObj{withAccessor.AString, withAccessor.AInteger, withAccessor.ASet, withAccessor.AMap})
+	reflectRow, err := m.NewRow(reflectInfo)
+	assert.NoError(t, err)
+	accessorRow, err := m.NewRow(accessorInfo)
+	assert.NoError(t, err)
+	assert.Equal(t, reflectRow, accessorRow)
+}
+
+func BenchmarkInfoFields(b *testing.B) {
+	var table ovsdb.TableSchema
+	if err := json.Unmarshal(sampleTable, &table); err != nil {
+		b.Fatal(err)
+	}
+	row := ovsdb.Row{
+		"aString":  "foo",
+		"aInteger": 42,
+		"aSet":     ovsdb.OvsSet{GoSet: []interface{}{"a", "b"}},
+		"aMap":     ovsdb.OvsMap{GoMap: map[interface{}]interface{}{"k": "v"}},
+	}
+	benchmarks := []struct {
+		name string
+		obj  func() interface{}
+	}{
+		{"reflect", func() interface{} { return &reflectObj{} }},
+		{"accessor", func() interface{} { return &accessorObj{} }},
+	}
+	m := Mapper{}
+	for _, bm := range benchmarks {
+		b.Run(bm.name, func(b *testing.B) {
+			info, err := NewInfo("Test", &table, bm.obj())
+			if err != nil {
+				b.Fatal(err)
+			}
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				if err := m.GetRowData(&row, info); err != nil {
+					b.Fatal(err)
+				}
+				if _, err := m.NewRow(info); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
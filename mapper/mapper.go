@@ -18,6 +18,10 @@ import (
 //  type MyObj struct {
 //  	Name string `ovsdb:"name"`
 //  }
+//
+// The elements of set columns are never reordered: GetRowData fills slice
+// fields in the order of the set received on the wire, and NewRow sends them in
+// the order of the slice, so that sets used as ordered lists keep their order.
 type Mapper struct {
 	Schema ovsdb.DatabaseSchema
 }
@@ -78,6 +82,23 @@ func (m Mapper) getData(ovsData ovsdb.Row, result *Info) error {
 			return err
 		}
 	}
+
+	// _uuid is not one of the columns of the table schema
+	if ovsElem, ok := ovsData["_uuid"]; ok && result.hasColumn("_uuid") {
+		var uuid string
+		switch v := ovsElem.(type) {
+		case ovsdb.UUID:
+			uuid = v.GoUUID
+		case string:
+			uuid = v
+		default:
+			return fmt.Errorf("table %s, column _uuid: %w",
+				result.Metadata.TableName, ovsdb.NewErrWrongType("GetRowData", "UUID or string", ovsElem))
+		}
+		if err := result.SetField("_uuid", uuid); err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
@@ -85,6 +106,18 @@ func (m Mapper) getData(ovsData ovsdb.Row, result *Info) error {
 // By default, default or null values are skipped. This behavior can be modified by specifying
 // a list of fields (pointers to fields in the struct) to be added to the row
 func (m Mapper) NewRow(data *Info, fields ...interface{}) (ovsdb.Row, error) {
+	return m.newRow(data, false, fields...)
+}
+
+// NewInsertRow transforms an orm struct to the libovsdb.Row of an insert
+// operation. Unlike NewRow, the models that are an EmptyDetector tell which
+// columns are left out of the row, so that the zero values of the other ones
+// are sent.
+func (m Mapper) NewInsertRow(data *Info) (ovsdb.Row, error) {
+	return m.newRow(data, true)
+}
+
+func (m Mapper) newRow(data *Info, insert bool, fields ...interface{}) (ovsdb.Row, error) {
 	columns := make(map[string]*ovsdb.ColumnSchema)
 	for k, v := range data.Metadata.TableSchema.Columns {
 		columns[k] = v
@@ -115,7 +148,7 @@ func (m Mapper) NewRow(data *Info, fields ...interface{}) (ovsdb.Row, error) {
 				continue
 			}
 		}
-		if len(fields) == 0 && ovsdb.IsDefaultValue(column, nativeElem) {
+		if len(fields) == 0 && isEmpty(data, insert, name, column, nativeElem) {
 			continue
 		}
 		ovsElem, err := ovsdb.NativeToOvs(column, nativeElem)
@@ -127,6 +160,18 @@ func (m Mapper) NewRow(data *Info, fields ...interface{}) (ovsdb.Row, error) {
 	return ovsRow, nil
 }
 
+// isEmpty returns whether the value of a column is left out of a new row. For
+// insert rows, the models that are an EmptyDetector tell it for the columns
+// whose field has the native type, otherwise the default values of the column
+// are empty.
+func isEmpty(data *Info, insert bool, name string, column *ovsdb.ColumnSchema, nativeElem interface{}) bool {
+	_, hasCodec := data.Metadata.Codecs[name]
+	if detector, ok := data.Obj.(EmptyDetector); ok && insert && name != "_uuid" && !hasCodec {
+		return detector.IsEmpty(name)
+	}
+	return ovsdb.IsDefaultValue(column, nativeElem)
+}
+
 // NewEqualityCondition returns a list of equality conditions that match a given object
 // A list of valid columns that shall be used as a index can be provided.
 // If none are provided, we will try to use object's field that matches the '_uuid' ovsdb tag
@@ -209,6 +254,10 @@ func (m Mapper) NewCondition(data *Info, field interface{}, function ovsdb.Condi
 	if columnSchema == nil {
 		return nil, fmt.Errorf("column %s not found", column)
 	}
+	value, err = data.encodeValue(column, value)
+	if err != nil {
+		return nil, err
+	}
 	if err := ovsdb.ValidateCondition(columnSchema, function, value); err != nil {
 		return nil, err
 	}
@@ -236,20 +285,36 @@ func (m Mapper) NewMutation(data *Info, column string, mutator ovsdb.Mutator, va
 	if columnSchema == nil {
 		return nil, fmt.Errorf("column %s not found", column)
 	}
+	value, err := data.encodeValue(column, value)
+	if err != nil {
+		return nil, err
+	}
 	if err := ovsdb.ValidateMutation(columnSchema, mutator, value); err != nil {
 		return nil, err
 	}
 
 	var ovsValue interface{}
-	var err error
+	// A set mutation value can also be a single element (rfc7047 5.1). Wrap it
+	// in a slice so it is converted like any other set.
+	if columnSchema.Type == ovsdb.TypeSet && (mutator == ovsdb.MutateOperationInsert || mutator == ovsdb.MutateOperationDelete) &&
+		reflect.TypeOf(value).Kind() != reflect.Slice {
+		value = reflect.Append(reflect.MakeSlice(reflect.SliceOf(reflect.TypeOf(value)), 0, 1), reflect.ValueOf(value)).Interface()
+	}
 	// Usually a mutation value is of the same type of the value being mutated
 	// except for delete mutation of maps where it can also be a list of same type of
 	// keys (rfc7047 5.1). Handle this special case here.
 	if mutator == "delete" && columnSchema.Type == ovsdb.TypeMap && reflect.TypeOf(value).Kind() != reflect.Map {
-		// It's OK to cast the value to a list of elements because validation has passed
-		ovsSet, err := ovsdb.NewOvsSet(value)
-		if err != nil {
-			return nil, err
+		// It's OK to cast the value to a list of keys because validation has
+		// passed. The keys are converted like the keys of the map, e.g. to
+		// UUIDs.
+		keys := reflect.ValueOf(value)
+		ovsSet := ovsdb.OvsSet{GoSet: make([]interface{}, 0, keys.Len())}
+		for i := 0; i < keys.Len(); i++ {
+			key, err := ovsdb.NativeToOvsAtomic(columnSchema.TypeObj.Key.Type, keys.Index(i).Interface())
+			if err != nil {
+				return nil, err
+			}
+			ovsSet.GoSet = append(ovsSet.GoSet, key)
 		}
 		ovsValue = ovsSet
 	} else {
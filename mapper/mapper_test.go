@@ -2,11 +2,13 @@ package mapper
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"testing"
 
 	"github.com/ovn-org/libovsdb/ovsdb"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 var (
@@ -238,6 +240,68 @@ func TestMapperGetData(t *testing.T) {
 	assert.Equal(t, expected, test)
 }
 
+func TestMapperGetDataUUID(t *testing.T) {
+	type ormTestType struct {
+		UUID    string `ovsdb:"_uuid"`
+		AString string `ovsdb:"aString"`
+	}
+	var schema ovsdb.DatabaseSchema
+	if err := json.Unmarshal(testSchema, &schema); err != nil {
+		t.Fatal(err)
+	}
+	mapper := NewMapper(schema)
+
+	tests := []struct {
+		name     string
+		row      string
+		expected ormTestType
+		err      bool
+	}{
+		{
+			name:     "uuid",
+			row:      fmt.Sprintf(`{"_uuid": ["uuid", "%s"], "aString": "foo"}`, aUUID0),
+			expected: ormTestType{UUID: aUUID0, AString: "foo"},
+		},
+		{
+			name:     "named-uuid",
+			row:      `{"_uuid": ["named-uuid", "row0"], "aString": "foo"}`,
+			expected: ormTestType{UUID: "row0", AString: "foo"},
+		},
+		{
+			name:     "bare uuid",
+			row:      fmt.Sprintf(`{"_uuid": "%s", "aString": "foo"}`, aUUID0),
+			expected: ormTestType{UUID: aUUID0, AString: "foo"},
+		},
+		{
+			name:     "no uuid",
+			row:      `{"aString": "foo"}`,
+			expected: ormTestType{AString: "foo"},
+		},
+		{
+			name: "wrong type",
+			row:  `{"_uuid": 42, "aString": "foo"}`,
+			err:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var row ovsdb.Row
+			err := json.Unmarshal([]byte(tt.row), &row)
+			assert.NoError(t, err)
+			test := ormTestType{}
+			info, err := NewInfo("TestTable", schema.Table("TestTable"), &test)
+			assert.NoError(t, err)
+			err = mapper.GetRowData(&row, info)
+			if tt.err {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, test)
+		})
+	}
+}
+
 func TestMapperNewRow(t *testing.T) {
 	var schema ovsdb.DatabaseSchema
 	if err := json.Unmarshal(testSchema, &schema); err != nil {
@@ -339,6 +403,23 @@ func TestMapperNewRow(t *testing.T) {
 			MyMap: aMap,
 		},
 		expectedRow: ovsdb.Row(map[string]interface{}{"aMap": testOvsMap(t, aMap)}),
+	}, {
+		name: "optional pointer",
+		objInput: &struct {
+			MySingleSet *string `ovsdb:"aSingleSet"`
+		}{
+			MySingleSet: &aString,
+		},
+		expectedRow: ovsdb.Row(map[string]interface{}{"aSingleSet": testOvsSet(t, []string{aString})}),
+	}, {
+		name: "nil optional pointer",
+		objInput: &struct {
+			AString     string  `ovsdb:"aString"`
+			MySingleSet *string `ovsdb:"aSingleSet"`
+		}{
+			AString: aString,
+		},
+		expectedRow: ovsdb.Row(map[string]interface{}{"aString": aString}),
 	},
 	}
 	for _, test := range tests {
@@ -1000,6 +1081,40 @@ func TestMapperMutation(t *testing.T) {
 			expected: ovsdb.NewMutation("set", ovsdb.MutateOperationDelete, testOvsSet(t, []string{"foo"})),
 			err:      false,
 		},
+		{
+			name:     "Add single element to set",
+			column:   "set",
+			obj:      testType{},
+			mutator:  ovsdb.MutateOperationInsert,
+			value:    "foo",
+			expected: ovsdb.NewMutation("set", ovsdb.MutateOperationInsert, testOvsSet(t, []string{"foo"})),
+			err:      false,
+		},
+		{
+			name:     "Delete single element from set",
+			column:   "set",
+			obj:      testType{},
+			mutator:  ovsdb.MutateOperationDelete,
+			value:    "foo",
+			expected: ovsdb.NewMutation("set", ovsdb.MutateOperationDelete, testOvsSet(t, []string{"foo"})),
+			err:      false,
+		},
+		{
+			name:    "Add single element of the wrong type to set",
+			column:  "set",
+			obj:     testType{},
+			mutator: ovsdb.MutateOperationInsert,
+			value:   42,
+			err:     true,
+		},
+		{
+			name:    "Increment set",
+			column:  "set",
+			obj:     testType{},
+			mutator: ovsdb.MutateOperationAdd,
+			value:   "foo",
+			err:     true,
+		},
 		{
 			name:     "Delete keys from map ",
 			column:   "map",
@@ -1060,3 +1175,242 @@ func testOvsMap(t *testing.T, set interface{}) ovsdb.OvsMap {
 	assert.Nil(t, err)
 	return oMap
 }
+
+func TestMapperGetDataIntegerString(t *testing.T) {
+	var table ovsdb.TableSchema
+	assert.NoError(t, json.Unmarshal(sampleTable, &table))
+	type obj struct {
+		AInt int `ovsdb:"aInteger"`
+	}
+	mapper := Mapper{}
+	for data, expected := range map[string]int{
+		`{"aInteger": 42}`:                    42,
+		`{"aInteger": "42"}`:                  42,
+		`{"aInteger": "9223372036854775807"}`: 9223372036854775807,
+	} {
+		var row ovsdb.Row
+		assert.NoError(t, json.Unmarshal([]byte(data), &row))
+		o := &obj{}
+		info, err := NewInfo("Test", &table, o)
+		assert.NoError(t, err)
+		assert.NoError(t, mapper.GetRowData(&row, info))
+		assert.Equal(t, expected, o.AInt, data)
+	}
+}
+
+func TestMapperSetOrder(t *testing.T) {
+	type obj struct {
+		ASet    []string `ovsdb:"aSet"`
+		AIntSet []int    `ovsdb:"aIntSet"`
+	}
+	var schema ovsdb.DatabaseSchema
+	require.NoError(t, json.Unmarshal(testSchema, &schema))
+	mapper := NewMapper(schema)
+
+	// the wire order is kept on decode
+	row := ovsdb.Row{
+		"aSet":    ovsdb.OvsSet{GoSet: []interface{}{"c", "a", "b"}},
+		"aIntSet": ovsdb.OvsSet{GoSet: []interface{}{3, 1, 2}},
+	}
+	o := &obj{}
+	info, err := NewInfo("TestTable", schema.Table("TestTable"), o)
+	require.NoError(t, err)
+	require.NoError(t, mapper.GetRowData(&row, info))
+	assert.Equal(t, []string{"c", "a", "b"}, o.ASet)
+	assert.Equal(t, []int{3, 1, 2}, o.AIntSet)
+
+	// and the slice order on encode
+	o.ASet = []string{"z", "x", "y"}
+	newRow, err := mapper.NewRow(info)
+	require.NoError(t, err)
+	assert.Equal(t, ovsdb.OvsSet{GoSet: []interface{}{"z", "x", "y"}}, newRow["aSet"])
+	assert.Equal(t, ovsdb.OvsSet{GoSet: []interface{}{3, 1, 2}}, newRow["aIntSet"])
+}
+
+func TestMapperGetDataTypedSets(t *testing.T) {
+	type obj struct {
+		ASet    []string `ovsdb:"aSet"`
+		AIntSet []int    `ovsdb:"aIntSet"`
+	}
+	var schema ovsdb.DatabaseSchema
+	require.NoError(t, json.Unmarshal(testSchema, &schema))
+	mapper := NewMapper(schema)
+
+	tests := []struct {
+		name     string
+		row      ovsdb.Row
+		expected obj
+	}{
+		{
+			"sets decoded from the wire",
+			ovsdb.Row{},
+			obj{ASet: []string{"a", "b"}, AIntSet: []int{1, 2}},
+		},
+		{
+			"elements of the sets",
+			ovsdb.Row{
+				"aSet":    []interface{}{"a", "b"},
+				"aIntSet": []interface{}{1, 2.0},
+			},
+			obj{ASet: []string{"a", "b"}, AIntSet: []int{1, 2}},
+		},
+		{
+			"single elements",
+			ovsdb.Row{
+				"aSet":    "a",
+				"aIntSet": 1,
+			},
+			obj{ASet: []string{"a"}, AIntSet: []int{1}},
+		},
+	}
+	require.NoError(t, json.Unmarshal([]byte(`{"aSet": ["set", ["a", "b"]], "aIntSet": ["set", [1, 2]]}`), &tests[0].row))
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			o := &obj{}
+			info, err := NewInfo("TestTable", schema.Table("TestTable"), o)
+			require.NoError(t, err)
+			require.NoError(t, mapper.GetRowData(&tt.row, info))
+			assert.Equal(t, tt.expected, *o)
+		})
+	}
+
+	for column, set := range map[string]interface{}{
+		"aSet":    ovsdb.OvsSet{GoSet: []interface{}{"a", 1}},
+		"aIntSet": []interface{}{1, "b"},
+	} {
+		o := &obj{}
+		info, err := NewInfo("TestTable", schema.Table("TestTable"), o)
+		require.NoError(t, err)
+		err = mapper.GetRowData(&ovsdb.Row{column: set}, info)
+		require.Error(t, err, column)
+		assert.Contains(t, err.Error(), "set element 1", column)
+	}
+	var wrongType *ovsdb.ErrWrongType
+	_, err := ovsdb.OvsToNativeSlice(ovsdb.TypeString, []interface{}{"a", 1})
+	assert.True(t, errors.As(err, &wrongType), "unexpected error %v", err)
+}
+
+func TestMapperUUIDMaps(t *testing.T) {
+	var schema ovsdb.DatabaseSchema
+	require.NoError(t, json.Unmarshal([]byte(`{
+		"name": "TestSchema",
+		"tables": {
+			"TestTable": {
+				"columns": {
+					"uuidValues": {"type": {"key": "string", "value": {"type": "uuid", "refTable": "TestTable"}, "min": 0, "max": "unlimited"}},
+					"uuidKeys": {"type": {"key": {"type": "uuid", "refTable": "TestTable"}, "value": "integer", "min": 0, "max": "unlimited"}}
+				}
+			}
+		}
+	}`), &schema))
+	type obj struct {
+		UUIDValues map[string]string `ovsdb:"uuidValues"`
+		UUIDKeys   map[string]int    `ovsdb:"uuidKeys"`
+	}
+	mapper := NewMapper(schema)
+	wire := `{
+		"uuidValues": ["map", [["a", ["uuid", "` + aUUID0 + `"]], ["b", ["uuid", "` + aUUID1 + `"]]]],
+		"uuidKeys": ["map", [[["uuid", "` + aUUID2 + `"], 1]]]
+	}`
+
+	var row ovsdb.Row
+	require.NoError(t, json.Unmarshal([]byte(wire), &row))
+	o := &obj{}
+	info, err := NewInfo("TestTable", schema.Table("TestTable"), o)
+	require.NoError(t, err)
+	require.NoError(t, mapper.GetRowData(&row, info))
+	assert.Equal(t, &obj{
+		UUIDValues: map[string]string{"a": aUUID0, "b": aUUID1},
+		UUIDKeys:   map[string]int{aUUID2: 1},
+	}, o)
+
+	newRow, err := mapper.NewRow(info)
+	require.NoError(t, err)
+	assert.Equal(t, ovsdb.OvsMap{GoMap: map[interface{}]interface{}{
+		"a": ovsdb.UUID{GoUUID: aUUID0},
+		"b": ovsdb.UUID{GoUUID: aUUID1},
+	}}, newRow["uuidValues"])
+	b, err := json.Marshal(newRow)
+	require.NoError(t, err)
+	assert.JSONEq(t, wire, string(b))
+
+	// the keys of a delete mutation are converted like the keys of the map
+	mutation, err := mapper.NewMutation(info, "uuidKeys", ovsdb.MutateOperationDelete, []string{aUUID2})
+	require.NoError(t, err)
+	assert.Equal(t, ovsdb.OvsSet{GoSet: []interface{}{ovsdb.UUID{GoUUID: aUUID2}}}, mutation.Value)
+	mutation, err = mapper.NewMutation(info, "uuidValues", ovsdb.MutateOperationDelete, []string{"a"})
+	require.NoError(t, err)
+	assert.Equal(t, ovsdb.OvsSet{GoSet: []interface{}{"a"}}, mutation.Value)
+}
+
+// emptyDetectorObj tells that its optional columns are empty when nil
+type emptyDetectorObj struct {
+	UUID  string            `ovsdb:"_uuid"`
+	Name  string            `ovsdb:"name"`
+	Count int               `ovsdb:"count"`
+	Tag   *int              `ovsdb:"tag"`
+	IDs   map[string]string `ovsdb:"ids"`
+}
+
+func (o *emptyDetectorObj) IsEmpty(column string) bool {
+	switch column {
+	case "tag":
+		return o.Tag == nil
+	case "ids":
+		return len(o.IDs) == 0
+	}
+	return false
+}
+
+func TestMapperNewRowEmptyDetector(t *testing.T) {
+	var schema ovsdb.DatabaseSchema
+	require.NoError(t, json.Unmarshal([]byte(`{
+		"name": "TestSchema",
+		"tables": {
+			"TestTable": {
+				"columns": {
+					"name": {"type": "string"},
+					"count": {"type": "integer"},
+					"tag": {"type": {"key": "integer", "min": 0, "max": 1}},
+					"ids": {"type": {"key": "string", "value": "string", "min": 0, "max": "unlimited"}}
+				}
+			}
+		}
+	}`), &schema))
+	mapper := NewMapper(schema)
+
+	// the zero values of the columns that are not empty are inserted
+	zero := 0
+	info, err := NewInfo("TestTable", schema.Table("TestTable"), &emptyDetectorObj{Tag: &zero})
+	require.NoError(t, err)
+	row, err := mapper.NewInsertRow(info)
+	require.NoError(t, err)
+	assert.Equal(t, ovsdb.Row{
+		"name":  "",
+		"count": 0,
+		"tag":   ovsdb.OvsSet{GoSet: []interface{}{0}},
+	}, row)
+
+	// the other rows leave out the default values whatever the model says
+	row, err = mapper.NewRow(info)
+	require.NoError(t, err)
+	assert.Equal(t, ovsdb.Row{
+		"tag": ovsdb.OvsSet{GoSet: []interface{}{0}},
+	}, row)
+
+	// _uuid is left out when it is not set, as for the other models
+	info, err = NewInfo("TestTable", schema.Table("TestTable"), &emptyDetectorObj{
+		UUID: aUUID0,
+		Name: "foo",
+		IDs:  map[string]string{"k": "v"},
+	})
+	require.NoError(t, err)
+	row, err = mapper.NewInsertRow(info)
+	require.NoError(t, err)
+	assert.Equal(t, ovsdb.Row{
+		"_uuid": ovsdb.UUID{GoUUID: aUUID0},
+		"name":  "foo",
+		"count": 0,
+		"ids":   ovsdb.OvsMap{GoMap: map[interface{}]interface{}{"k": "v"}},
+	}, row)
+}
@@ -1,13 +1,93 @@
 package model
 
 import (
+	"errors"
 	"fmt"
 	"reflect"
+	"sort"
+	"strings"
 
 	"github.com/ovn-org/libovsdb/mapper"
 	"github.com/ovn-org/libovsdb/ovsdb"
 )
 
+// ErrTableNotFound is an error returned when the ClientDBModel contains a model
+// for a table that does not exist in the schema
+type ErrTableNotFound struct {
+	table string
+}
+
+// Error implements the error interface
+func (e *ErrTableNotFound) Error() string {
+	return fmt.Sprintf("database model contains a model for table %s that does not exist in schema", e.table)
+}
+
+// Table returns the name of the table that was not found
+func (e *ErrTableNotFound) Table() string {
+	return e.table
+}
+
+// NewErrTableNotFound returns a new ErrTableNotFound
+func NewErrTableNotFound(table string) *ErrTableNotFound {
+	return &ErrTableNotFound{
+		table: table,
+	}
+}
+
+// ErrColumnWithoutField is reported by ClientDBModel.Validate for a column of
+// the schema that no field of the model of its table is tagged with
+type ErrColumnWithoutField struct {
+	table  string
+	column string
+}
+
+// Error implements the error interface
+func (e *ErrColumnWithoutField) Error() string {
+	return fmt.Sprintf("database model has no field for column %s of table %s", e.column, e.table)
+}
+
+// Table returns the name of the table of the column
+func (e *ErrColumnWithoutField) Table() string {
+	return e.table
+}
+
+// Column returns the name of the column without a field
+func (e *ErrColumnWithoutField) Column() string {
+	return e.column
+}
+
+// ErrModelValidation is returned by ClientDBModel.Validate with every mismatch
+// it found between the model and the schema
+type ErrModelValidation struct {
+	database string
+	errors   []error
+}
+
+// Error implements the error interface
+func (e *ErrModelValidation) Error() string {
+	var combined []string
+	for _, err := range e.errors {
+		combined = append(combined, err.Error())
+	}
+	return fmt.Sprintf("database model %s validation error (%d): %s",
+		e.database, len(e.errors), strings.Join(combined, ". "))
+}
+
+// Errors returns the individual validation errors
+func (e *ErrModelValidation) Errors() []error {
+	return e.errors
+}
+
+// As finds the first validation error that matches target
+func (e *ErrModelValidation) As(target interface{}) bool {
+	for _, err := range e.errors {
+		if errors.As(err, target) {
+			return true
+		}
+	}
+	return false
+}
+
 // ColumnKey addresses a column and optionally a key within a column
 type ColumnKey struct {
 	Column string
@@ -63,8 +143,55 @@ func (db *ClientDBModel) SetIndexes(indexes map[string][]ClientIndex) {
 	db.indexes = copyIndexes(indexes)
 }
 
-// Validate validates the DatabaseModel against the input schema
-// Returns all the errors detected
+// Validate checks the models of the ClientDBModel against a schema and returns
+// an *ErrModelValidation listing every mismatch, or nil if there is none. On
+// top of the checks done by NewDatabaseModel, which accepts a schema newer
+// than the models, it reports every column of a table that no field of its
+// model is tagged with, as an *ErrColumnWithoutField. This catches the models
+// that drifted from the schema, e.g. at startup.
+func (db ClientDBModel) Validate(schema *ovsdb.DatabaseSchema) error {
+	if schema == nil {
+		return fmt.Errorf("no schema to validate database model %s against", db.name)
+	}
+	errs := db.validate(*schema)
+
+	tables := make([]string, 0, len(db.types))
+	for tableName := range db.types {
+		tables = append(tables, tableName)
+	}
+	sort.Strings(tables)
+	for _, tableName := range tables {
+		tableSchema := schema.Table(tableName)
+		if tableSchema == nil {
+			// already reported as an ErrTableNotFound
+			continue
+		}
+		modelType := db.types[tableName].Elem()
+		fields := make(map[string]bool, modelType.NumField())
+		for i := 0; i < modelType.NumField(); i++ {
+			if column := modelType.Field(i).Tag.Get("ovsdb"); column != "" {
+				fields[column] = true
+			}
+		}
+		columns := make([]string, 0, len(tableSchema.Columns))
+		for column := range tableSchema.Columns {
+			columns = append(columns, column)
+		}
+		sort.Strings(columns)
+		for _, column := range columns {
+			if !fields[column] {
+				errs = append(errs, &ErrColumnWithoutField{table: tableName, column: column})
+			}
+		}
+	}
+	if len(errs) > 0 {
+		return &ErrModelValidation{database: db.name, errors: errs}
+	}
+	return nil
+}
+
+// validate validates the DatabaseModel against the input schema, accepting
+// the columns without a field. Returns all the errors detected
 func (db ClientDBModel) validate(schema ovsdb.DatabaseSchema) []error {
 	var errors []error
 	if db.name != schema.Name {
@@ -76,7 +203,7 @@ func (db ClientDBModel) validate(schema ovsdb.DatabaseSchema) []error {
 	for tableName := range db.types {
 		tableSchema := schema.Table(tableName)
 		if tableSchema == nil {
-			errors = append(errors, fmt.Errorf("database model contains a model for table %s that does not exist in schema", tableName))
+			errors = append(errors, NewErrTableNotFound(tableName))
 			continue
 		}
 		model, err := db.newModel(tableName)
@@ -84,6 +211,12 @@ func (db ClientDBModel) validate(schema ovsdb.DatabaseSchema) []error {
 			errors = append(errors, err)
 			continue
 		}
+		// report every field that does not match the schema. Columns without
+		// a field are fine, the schema may be newer than the model.
+		if errs := mapper.ValidateModel(tableSchema, model); len(errs) > 0 {
+			errors = append(errors, errs...)
+			continue
+		}
 		info, err := mapper.NewInfo(tableName, tableSchema, model)
 		if err != nil {
 			errors = append(errors, err)
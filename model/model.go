@@ -29,10 +29,40 @@ type CloneableModel interface {
 	CloneModelInto(Model)
 }
 
+// ColumnCloneableModel is implemented by models that can copy the fields of
+// some of their columns only, like the ones generated by modelgen with the
+// extended option. The cache clones the rows of the tables monitored for some
+// columns with it.
+type ColumnCloneableModel interface {
+	// CloneModelColumns returns a deep copy of the UUID and of the fields of
+	// the given columns, leaving the other fields to their zero value
+	CloneModelColumns(columns []string) Model
+}
+
 type ComparableModel interface {
 	EqualsModel(Model) bool
 }
 
+// IndexedModel is implemented by models that know the indexes declared by the
+// schema of their table, like the ones generated by modelgen with table
+// indexes. The cache also indexes the columns of those that are not indexes of
+// the schema it is created with, like it does for client indexes.
+type IndexedModel interface {
+	// TableIndexes returns the groups of columns whose values are unique
+	// to each row of the table
+	TableIndexes() [][]string
+}
+
+// UUIDModel is implemented by models that give access to their UUID, like the
+// ones generated by modelgen with the model interface, so that code handling
+// the models of any table does not need reflection to get or set it
+type UUIDModel interface {
+	// GetUUID returns the UUID of the row
+	GetUUID() string
+	// SetUUID sets the UUID of the row
+	SetUUID(uuid string)
+}
+
 // Clone creates a deep copy of a model
 func Clone(a Model) Model {
 	if cloner, ok := a.(CloneableModel); ok {
@@ -46,6 +76,15 @@ func Clone(a Model) Model {
 	return b
 }
 
+// CloneColumns creates a deep copy of the UUID and of the given columns of a
+// model, or of the whole model if it is not a ColumnCloneableModel
+func CloneColumns(a Model, columns []string) Model {
+	if cloner, ok := a.(ColumnCloneableModel); ok {
+		return cloner.CloneModelColumns(columns)
+	}
+	return Clone(a)
+}
+
 // CloneInto deep copies a model into another one
 func CloneInto(src, dst Model) {
 	if cloner, ok := src.(CloneableModel); ok {
@@ -6,8 +6,10 @@ import (
 	"reflect"
 	"testing"
 
+	"github.com/ovn-org/libovsdb/mapper"
 	"github.com/ovn-org/libovsdb/ovsdb"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 type modelA struct {
@@ -333,7 +335,105 @@ func TestValidate(t *testing.T) {
 			}
 		})
 	}
+}
 
+func TestValidateTableNotFound(t *testing.T) {
+	model, err := NewClientDBModel("TestDB", map[string]Model{
+		"TestTable": &modelA{},
+	})
+	require.NoError(t, err)
+	var schema ovsdb.DatabaseSchema
+	err = json.Unmarshal([]byte(`{"name": "TestDB", "tables": {}}`), &schema)
+	require.NoError(t, err)
+	errors := model.validate(schema)
+	require.Len(t, errors, 1)
+	var errTableNotFound *ErrTableNotFound
+	require.ErrorAs(t, errors[0], &errTableNotFound)
+	assert.Equal(t, "TestTable", errTableNotFound.Table())
+}
+
+func TestValidateAllFields(t *testing.T) {
+	model, err := NewClientDBModel("TestDB", map[string]Model{
+		"TestTable": &struct {
+			aUUID   string `ovsdb:"_uuid"`
+			aString int    `ovsdb:"aString"`
+			aInt    int    `ovsdb:"aInt"`
+			aExtra  string `ovsdb:"aExtra"`
+		}{},
+	})
+	require.NoError(t, err)
+	var schema ovsdb.DatabaseSchema
+	err = json.Unmarshal([]byte(`{
+		"name": "TestDB",
+		"tables": {
+			"TestTable": {
+				"columns": {
+					"aString": { "type": "string" },
+					"aInt": { "type": "integer" },
+					"aMissing": { "type": "integer" }
+				}
+			}
+		}
+	}`), &schema)
+	require.NoError(t, err)
+
+	// the field of the wrong type and the field without a column are
+	// reported, as is the column without a field
+	err = model.Validate(&schema)
+	require.Error(t, err)
+	var errValidation *ErrModelValidation
+	require.ErrorAs(t, err, &errValidation)
+	errs := errValidation.Errors()
+	require.Len(t, errs, 3)
+	for _, err := range errs[:2] {
+		var errMapper *mapper.ErrMapper
+		assert.ErrorAs(t, err, &errMapper)
+	}
+	assert.Contains(t, errs[0].Error(), "aString")
+	assert.Contains(t, errs[1].Error(), "aExtra")
+	var errColumn *ErrColumnWithoutField
+	require.ErrorAs(t, errs[2], &errColumn)
+	assert.Equal(t, "TestTable", errColumn.Table())
+	assert.Equal(t, "aMissing", errColumn.Column())
+	require.ErrorAs(t, err, &errColumn)
+
+	// NewDatabaseModel accepts the schema to be newer than the model
+	assert.Len(t, model.validate(schema), 2)
+}
+
+func TestValidateMatchingModel(t *testing.T) {
+	model, err := NewClientDBModel("TestDB", map[string]Model{
+		"TestTable": &struct {
+			aUUID   string `ovsdb:"_uuid"`
+			aString string `ovsdb:"aString"`
+		}{},
+	})
+	require.NoError(t, err)
+	var schema ovsdb.DatabaseSchema
+	err = json.Unmarshal([]byte(`{
+		"name": "TestDB",
+		"tables": {
+			"TestTable": {
+				"columns": {
+					"aString": { "type": "string" }
+				}
+			}
+		}
+	}`), &schema)
+	require.NoError(t, err)
+	assert.NoError(t, model.Validate(&schema))
+	assert.Error(t, model.Validate(nil))
+
+	// the tables missing from the schema are reported once
+	var emptySchema ovsdb.DatabaseSchema
+	err = json.Unmarshal([]byte(`{"name": "TestDB", "tables": {}}`), &emptySchema)
+	require.NoError(t, err)
+	err = model.Validate(&emptySchema)
+	var errValidation *ErrModelValidation
+	require.ErrorAs(t, err, &errValidation)
+	require.Len(t, errValidation.Errors(), 1)
+	var errTableNotFound *ErrTableNotFound
+	assert.ErrorAs(t, err, &errTableNotFound)
 }
 
 type modelC struct {
@@ -415,6 +515,34 @@ func TestCloneIntoViaCloneable(t *testing.T) {
 	assert.NotEqual(t, a, b)
 }
 
+type modelD struct {
+	modelB
+}
+
+func (a *modelD) CloneModelColumns(columns []string) Model {
+	b := &modelD{modelB: modelB{UID: a.UID}}
+	for _, column := range columns {
+		switch column {
+		case "bar":
+			b.Foo = a.Foo
+		case "baz":
+			b.Bar = a.Bar
+		}
+	}
+	return b
+}
+
+func TestCloneColumns(t *testing.T) {
+	// models that are not column cloneable are cloned as a whole
+	a := &modelB{UID: "foo", Foo: "bar", Bar: "baz"}
+	assert.Equal(t, a, CloneColumns(a, []string{"bar"}))
+
+	d := &modelD{modelB: modelB{UID: "foo", Foo: "bar", Bar: "baz"}}
+	assert.Equal(t, &modelD{modelB: modelB{UID: "foo", Foo: "bar"}}, CloneColumns(d, []string{"bar"}))
+	assert.Equal(t, d, CloneColumns(d, []string{"bar", "baz"}))
+	assert.Equal(t, &modelD{modelB: modelB{UID: "foo"}}, CloneColumns(d, nil))
+}
+
 func TestEqualViaDeepEqual(t *testing.T) {
 	a := &modelB{UID: "foo", Foo: "bar", Bar: "baz"}
 	b := &modelB{UID: "foo", Foo: "bar", Bar: "baz"}
@@ -1,13 +1,20 @@
 package modelgen
 
 import (
+	"bytes"
 	"encoding/json"
+	"fmt"
 	"sort"
+	"strings"
 	"text/template"
 
 	"github.com/ovn-org/libovsdb/ovsdb"
 )
 
+// DefaultImportPath is the import path of the libovsdb module the generated
+// code imports by default
+const DefaultImportPath = "github.com/ovn-org/libovsdb"
+
 // NewDBTemplate returns a new ClientDBModel template. It includes the following
 // other templates that can be overridden to customize the generated file:
 //
@@ -15,12 +22,19 @@ import (
 //   - `preDBDefinitions`: to include code after package definition
 //   - `postDBDefinitions`: to include code at the end
 //
+// If table constants are enabled (see DBTemplateData.WithTableConstants), it
+// also defines AllTables, the list of the <StructName>Table constants defined
+// by the table templates. If accessors are enabled (see
+// DBTemplateData.WithAccessors), it also defines a Client type that wraps client.Client and is extended with typed
+// accessors by the table templates.
+//
 // It is designed to be used with a map[string] interface and some defined keys
 // (see GetDBTemplateData)
 func NewDBTemplate() *template.Template {
 	return template.Must(template.New("").Funcs(
 		template.FuncMap{
-			"escape": escape,
+			"escape":              escape,
+			"checkTableConstants": checkTableConstants,
 		},
 	).Parse(`
 {{- define "header" }}
@@ -31,16 +45,76 @@ func NewDBTemplate() *template.Template {
  import (
 	"encoding/json"
 
-	"github.com/ovn-org/libovsdb/model"
-	"github.com/ovn-org/libovsdb/ovsdb"
+	"{{ index . "ImportPath" }}/model"
+	"{{ index . "ImportPath" }}/ovsdb"
 )
 {{- end }}
 {{ define "postDBDefinitions" }}{{ end }}
+{{- define "accessorsImports" }}
+{{- if index . "WithAccessors" }}
+import (
+	"errors"
+	"fmt"
+
+	"{{ index . "ImportPath" }}/client"
+)
+{{- end }}
+{{- end }}
+{{- define "accessors" }}
+{{- if index . "WithAccessors" }}
+// Client wraps a client.Client to provide typed accessors to the rows of each
+// table in its cache
+type Client struct {
+	client.Client
+}
+
+// NewClient returns a Client that wraps the given client.Client
+func NewClient(c client.Client) *Client {
+	return &Client{Client: c}
+}
+
+// ErrCacheMiss is returned by the accessors when a row is not in the cache,
+// e.g. because its table is not being monitored
+var ErrCacheMiss = errors.New("cache miss")
+
+func (c *Client) cachedRow(table, uuid string) (model.Model, error) {
+	tableCache := c.Cache()
+	if tableCache == nil || tableCache.Table(table) == nil {
+		return nil, fmt.Errorf("%w: table %s is not cached", ErrCacheMiss, table)
+	}
+	m := tableCache.Table(table).Row(uuid)
+	if m == nil {
+		return nil, fmt.Errorf("%w: row %s not found in table %s", ErrCacheMiss, uuid, table)
+	}
+	return m, nil
+}
+
+func (c *Client) cachedRows(table string) ([]model.Model, error) {
+	tableCache := c.Cache()
+	if tableCache == nil || tableCache.Table(table) == nil {
+		return nil, fmt.Errorf("%w: table %s is not cached", ErrCacheMiss, table)
+	}
+	return tableCache.List(table)
+}
+{{- end }}
+{{- end }}
+{{- define "tableConstants" }}
+{{- if index . "WithTableConstants" }}
+{{- checkTableConstants (index . "Tables") }}
+// AllTables are the names of every table of the database, sorted
+var AllTables = []string{
+	{{- range index . "Tables" }}
+	{{ .StructName }}Table,
+	{{- end }}
+}
+{{- end }}
+{{- end }}
 {{ template "header" . }}
 
 package {{ index . "PackageName" }}
 
-{{ template "preDBDefinitions" }}
+{{ template "preDBDefinitions" . }}
+{{ template "accessorsImports" . }}
 
 // FullDatabaseModel returns the DatabaseModel object to be used in libovsdb
 func FullDatabaseModel() (model.ClientDBModel, error) {
@@ -50,8 +124,11 @@ func FullDatabaseModel() (model.ClientDBModel, error) {
 	})
 }
 
+{{ template "tableConstants" . }}
+
 var schema = {{ index . "Schema" | escape }}
 
+// Schema returns the schema of the database the models were generated from
 func Schema() ovsdb.DatabaseSchema {
 	var s ovsdb.DatabaseSchema
 	err := json.Unmarshal([]byte(schema), &s)
@@ -61,6 +138,7 @@ func Schema() ovsdb.DatabaseSchema {
 	return s
 }
 
+{{ template "accessors" . }}
 {{ template "postDBDefinitions" . }}
 `))
 }
@@ -71,13 +149,56 @@ type TableInfo struct {
 	StructName string
 }
 
+// DBTemplateData represents the data used by the DB Template
+type DBTemplateData map[string]interface{}
+
+// WithAccessors configures whether the Template should generate the Client
+// type used by the typed cache accessors of each table. It has to match the
+// setting of the table templates (see TableTemplateData.WithAccessors).
+func (d DBTemplateData) WithAccessors(val bool) {
+	d["WithAccessors"] = val
+}
+
+// WithTableConstants configures whether the Template should generate
+// AllTables, the list of the table name constants
+func (d DBTemplateData) WithTableConstants(val bool) {
+	d["WithTableConstants"] = val
+}
+
+// WithEmbeddedSchema configures the Template to embed the given source of the
+// schema, returned by the generated Schema function, instead of the schema
+// given to GetDBTemplateData serialized again. The source keeps the fields and
+// the order of the original schema file. It is only indented.
+func (d DBTemplateData) WithEmbeddedSchema(source []byte) error {
+	var schema ovsdb.DatabaseSchema
+	if err := json.Unmarshal(source, &schema); err != nil {
+		return fmt.Errorf("invalid schema to embed: %w", err)
+	}
+	var buf bytes.Buffer
+	if err := json.Indent(&buf, source, "", "  "); err != nil {
+		return fmt.Errorf("invalid schema to embed: %w", err)
+	}
+	d["Schema"] = buf.String()
+	return nil
+}
+
+// WithImportPath configures the import path of the libovsdb module the
+// generated code imports, e.g. when using a fork of it
+func (d DBTemplateData) WithImportPath(path string) {
+	d["ImportPath"] = path
+}
+
 // GetDBTemplateData returns the map needed to execute the DBTemplate. It has
 // the following keys:
 //
 //   - `DatabaseName`: (string) the database name
 //   - `PackageName`: (string) the package name
 //   - `Tables`: []Table list of Tables that form the Model
-func GetDBTemplateData(pkg string, schema ovsdb.DatabaseSchema) map[string]interface{} {
+//   - `Schema`: (string) the JSON schema returned by the generated Schema
+//   - `WithAccessors`: (bool) whether to generate the Client type
+//   - `WithTableConstants`: (bool) whether to generate AllTables
+//   - `ImportPath`: (string) the import path of the libovsdb module
+func GetDBTemplateData(pkg string, schema ovsdb.DatabaseSchema) DBTemplateData {
 	data := map[string]interface{}{}
 	data["DatabaseName"] = schema.Name
 	data["PackageName"] = pkg
@@ -98,9 +219,47 @@ func GetDBTemplateData(pkg string, schema ovsdb.DatabaseSchema) map[string]inter
 		})
 	}
 	data["Tables"] = tables
+	data["WithAccessors"] = false
+	data["WithTableConstants"] = false
+	data["ImportPath"] = DefaultImportPath
 	return data
 }
 
+// PackageName returns the name of the package of the models of a database
+// when each database is generated into a package of its own. It is the given
+// prefix followed by the lowercase letters and digits of the database name,
+// e.g. ovnnorthbound for OVN_Northbound, so that the tables several databases
+// have in common, like SSL, do not collide.
+func PackageName(prefix, dbName string) string {
+	name := strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= '0' && r <= '9':
+			return r
+		case r >= 'A' && r <= 'Z':
+			return r - 'A' + 'a'
+		}
+		return -1
+	}, dbName)
+	return prefix + name
+}
+
 func escape(s string) string {
-	return "`" + s + "`"
+	return "`" + strings.ReplaceAll(s, "`", "` + \"`\" + `") + "`"
+}
+
+// checkTableConstants returns an error if the <StructName>Table constant of a
+// table has the same name as the struct of another table, e.g. for tables
+// named "Bridge" and "Bridge_Table"
+func checkTableConstants(tables []TableInfo) (string, error) {
+	structs := make(map[string]string, len(tables))
+	for _, table := range tables {
+		structs[table.StructName] = table.TableName
+	}
+	for _, table := range tables {
+		if other, ok := structs[table.StructName+"Table"]; ok {
+			return "", fmt.Errorf("constant %sTable of table %s collides with the struct of table %s",
+				table.StructName, table.TableName, other)
+		}
+	}
+	return "", nil
 }
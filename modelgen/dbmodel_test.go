@@ -1,7 +1,14 @@
 package modelgen
 
 import (
+	"bytes"
 	"encoding/json"
+	"go/ast"
+	"go/constant"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
 	"testing"
 	"text/template"
 
@@ -110,6 +117,7 @@ var schema = ` + "`" + `{
   }
 }` + "`" + `
 
+// Schema returns the schema of the database the models were generated from
 func Schema() ovsdb.DatabaseSchema {
 	var s ovsdb.DatabaseSchema
 	err := json.Unmarshal([]byte(schema), &s)
@@ -146,3 +154,466 @@ func Schema() ovsdb.DatabaseSchema {
 		})
 	}
 }
+
+func TestAccessorsTemplate(t *testing.T) {
+	rawSchema := []byte(`
+	{
+		"name": "AccessorDB",
+		"version": "0.0.0",
+		"tables": {
+			"Logical_Switch": {
+				"columns": {
+					"name": {
+						"type": "string"
+					}
+				}
+			},
+			"Logical_Router": {
+				"columns": {
+					"name": {
+						"type": "string"
+					}
+				}
+			}
+		}
+	}`)
+	var schema ovsdb.DatabaseSchema
+	err := json.Unmarshal(rawSchema, &schema)
+	require.NoError(t, err)
+
+	g, err := NewGenerator()
+	require.NoError(t, err)
+
+	fset := token.NewFileSet()
+	var files []*ast.File
+	dbData := GetDBTemplateData("test", schema)
+	dbData.WithAccessors(true)
+	src, err := g.Format(NewDBTemplate(), dbData)
+	require.NoError(t, err)
+	file, err := parser.ParseFile(fset, "model.go", src, 0)
+	require.NoError(t, err)
+	files = append(files, file)
+	for name, table := range schema.Tables {
+		table := table
+		data := GetTableTemplateData("test", name, &table)
+		data.WithAccessors(true)
+		src, err := g.Format(NewTableTemplate(), data)
+		require.NoError(t, err)
+		file, err := parser.ParseFile(fset, FileName(name), src, 0)
+		require.NoError(t, err)
+		files = append(files, file)
+	}
+
+	// the generated package has to compile
+	conf := types.Config{Importer: importer.ForCompiler(fset, "source", nil)}
+	pkg, err := conf.Check("test", fset, files, nil)
+	require.NoError(t, err)
+
+	client := pkg.Scope().Lookup("Client")
+	require.NotNil(t, client)
+	methods := types.NewMethodSet(types.NewPointer(client.Type()))
+	signature := func(name string) string {
+		sel := methods.Lookup(pkg, name)
+		require.NotNil(t, sel, "method %s not found", name)
+		return types.TypeString(sel.Type(), types.RelativeTo(pkg))
+	}
+	assert.Equal(t, "func(uuid string) (*LogicalSwitch, error)", signature("GetLogicalSwitch"))
+	assert.Equal(t, "func() ([]*LogicalSwitch, error)", signature("ListLogicalSwitch"))
+	assert.Equal(t, "func(uuid string) (*LogicalRouter, error)", signature("GetLogicalRouter"))
+	assert.Equal(t, "func() ([]*LogicalRouter, error)", signature("ListLogicalRouter"))
+	assert.NotNil(t, pkg.Scope().Lookup("ErrCacheMiss"))
+}
+
+func TestReferenceAccessorsTemplate(t *testing.T) {
+	rawSchema := []byte(`
+	{
+		"name": "ReferenceDB",
+		"version": "0.0.0",
+		"tables": {
+			"Logical_Switch": {
+				"columns": {
+					"name": {"type": "string"},
+					"ports": {"type": {"key": {"type": "uuid", "refTable": "Logical_Switch_Port"}, "min": 0, "max": "unlimited"}},
+					"pair": {"type": {"key": {"type": "uuid", "refTable": "Logical_Switch_Port"}, "min": 0, "max": 2}}
+				}
+			},
+			"Logical_Switch_Port": {
+				"columns": {
+					"name": {"type": "string"},
+					"parent": {"type": {"key": {"type": "uuid", "refTable": "Logical_Switch"}}},
+					"peer": {"type": {"key": {"type": "uuid", "refTable": "Logical_Switch_Port"}, "min": 0, "max": 1}},
+					"options": {"type": {"key": {"type": "uuid", "refTable": "Logical_Switch"}, "value": "string", "min": 0, "max": "unlimited"}},
+					"tag": {"type": {"key": {"type": "uuid"}, "min": 0, "max": 1}}
+				}
+			}
+		}
+	}`)
+	var schema ovsdb.DatabaseSchema
+	err := json.Unmarshal(rawSchema, &schema)
+	require.NoError(t, err)
+
+	g, err := NewGenerator()
+	require.NoError(t, err)
+
+	// the generated package has to compile, also along with the getters of
+	// the extended generation
+	check := func(extended bool) *types.Package {
+		fset := token.NewFileSet()
+		var files []*ast.File
+		dbData := GetDBTemplateData("test", schema)
+		dbData.WithAccessors(true)
+		src, err := g.Format(NewDBTemplate(), dbData)
+		require.NoError(t, err)
+		file, err := parser.ParseFile(fset, "model.go", src, 0)
+		require.NoError(t, err)
+		files = append(files, file)
+		for name, table := range schema.Tables {
+			table := table
+			data := GetTableTemplateData("test", name, &table)
+			data.WithAccessors(true)
+			data.WithReferenceAccessors(true)
+			data.WithExtendedGen(extended)
+			src, err := g.Format(NewTableTemplate(), data)
+			require.NoError(t, err)
+			file, err := parser.ParseFile(fset, FileName(name), src, 0)
+			require.NoError(t, err)
+			files = append(files, file)
+		}
+		conf := types.Config{Importer: importer.ForCompiler(fset, "source", nil)}
+		pkg, err := conf.Check("test", fset, files, nil)
+		require.NoError(t, err)
+		return pkg
+	}
+	pkg := check(false)
+
+	methods := func(typeName string) *types.MethodSet {
+		obj := pkg.Scope().Lookup(typeName)
+		require.NotNil(t, obj)
+		return types.NewMethodSet(types.NewPointer(obj.Type()))
+	}
+	signature := func(methods *types.MethodSet, name string) string {
+		sel := methods.Lookup(pkg, name)
+		if sel == nil {
+			return ""
+		}
+		return types.TypeString(sel.Type(), types.RelativeTo(pkg))
+	}
+	lsp := methods("LogicalSwitchPort")
+	assert.Equal(t, "func(c *Client) (*LogicalSwitch, error)", signature(lsp, "ResolveParent"))
+	assert.Equal(t, "func(c *Client) (*LogicalSwitchPort, error)", signature(lsp, "ResolvePeer"))
+	assert.Empty(t, signature(lsp, "ResolveOptions"))
+	assert.Empty(t, signature(lsp, "ResolveTag"))
+	assert.Empty(t, signature(lsp, "ResolveName"))
+	ls := methods("LogicalSwitch")
+	assert.Equal(t, "func(c *Client) ([]*LogicalSwitchPort, error)", signature(ls, "ResolvePorts"))
+	assert.Equal(t, "func(c *Client) ([]*LogicalSwitchPort, error)", signature(ls, "ResolvePair"))
+
+	pkg = check(true)
+	ls = methods("LogicalSwitch")
+	assert.Equal(t, "func() []string", signature(ls, "GetPorts"))
+	assert.Equal(t, "func(c *Client) ([]*LogicalSwitchPort, error)", signature(ls, "ResolvePorts"))
+
+	// they are only generated on demand
+	g, err = NewGenerator()
+	require.NoError(t, err)
+	table := schema.Tables["Logical_Switch_Port"]
+	data := GetTableTemplateData("test", "Logical_Switch_Port", &table)
+	data.WithAccessors(true)
+	src, err := g.Format(NewTableTemplate(), data)
+	require.NoError(t, err)
+	assert.NotContains(t, string(src), "ResolveParent")
+}
+
+func TestImportPath(t *testing.T) {
+	rawSchema := []byte(`
+	{
+		"name": "ImportDB",
+		"version": "0.0.0",
+		"tables": {
+			"Bridge": {
+				"columns": {
+					"name": {
+						"type": "string"
+					}
+				}
+			}
+		}
+	}`)
+	var schema ovsdb.DatabaseSchema
+	require.NoError(t, json.Unmarshal(rawSchema, &schema))
+	const importPath = "github.com/blacob/libovsdb/v2"
+
+	g, err := NewGenerator()
+	require.NoError(t, err)
+	imports := func(src []byte) []string {
+		file, err := parser.ParseFile(token.NewFileSet(), "", src, parser.ImportsOnly)
+		require.NoError(t, err)
+		var paths []string
+		for _, spec := range file.Imports {
+			paths = append(paths, spec.Path.Value)
+		}
+		return paths
+	}
+
+	dbData := GetDBTemplateData("test", schema)
+	assert.Equal(t, DefaultImportPath, dbData["ImportPath"])
+	dbData.WithAccessors(true)
+	dbData.WithImportPath(importPath)
+	src, err := g.Format(NewDBTemplate(), dbData)
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		`"encoding/json"`,
+		`"github.com/blacob/libovsdb/v2/model"`,
+		`"github.com/blacob/libovsdb/v2/ovsdb"`,
+		`"errors"`,
+		`"fmt"`,
+		`"github.com/blacob/libovsdb/v2/client"`,
+	}, imports(src))
+
+	table := schema.Tables["Bridge"]
+	tableData := GetTableTemplateData("test", "Bridge", &table)
+	assert.Equal(t, DefaultImportPath, tableData["ImportPath"])
+	tableData.WithExtendedGen(true)
+	tableData.WithImportPath(importPath)
+	src, err = g.Format(NewTableTemplate(), tableData)
+	require.NoError(t, err)
+	assert.Equal(t, []string{`"github.com/blacob/libovsdb/v2/model"`}, imports(src))
+}
+
+func TestEmbeddedSchema(t *testing.T) {
+	rawSchema := []byte(`
+	{
+		"name": "EmbedDB",
+		"version": "1.2.3",
+		"cksum": "12345 678",
+		"tables": {
+			"Bridge": {
+				"columns": {
+					"name": {
+						"type": "string"
+					},
+					"mode": {
+						"type": {"key": {"type": "string", "enum": ["set", ["` + "`quoted`" + `", "plain"]]}}
+					}
+				},
+				"isRoot": true,
+				"indexes": [["name"]]
+			}
+		}
+	}`)
+	var schema ovsdb.DatabaseSchema
+	require.NoError(t, json.Unmarshal(rawSchema, &schema))
+
+	data := GetDBTemplateData("test", schema)
+	assert.Error(t, data.WithEmbeddedSchema([]byte(`{"name": `)))
+	require.NoError(t, data.WithEmbeddedSchema(rawSchema))
+	g, err := NewGenerator()
+	require.NoError(t, err)
+	src, err := g.Format(NewDBTemplate(), data)
+	require.NoError(t, err)
+
+	// evaluate the schema embedded in the generated file
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "model.go", src, 0)
+	require.NoError(t, err)
+	table := schema.Tables["Bridge"]
+	tableSrc, err := g.Format(NewTableTemplate(), GetTableTemplateData("test", "Bridge", &table))
+	require.NoError(t, err)
+	tableFile, err := parser.ParseFile(fset, "bridge.go", tableSrc, 0)
+	require.NoError(t, err)
+	conf := types.Config{Importer: importer.ForCompiler(fset, "source", nil)}
+	info := &types.Info{Types: map[ast.Expr]types.TypeAndValue{}}
+	_, err = conf.Check("test", fset, []*ast.File{file, tableFile}, info)
+	require.NoError(t, err)
+	var embedded string
+	for _, decl := range file.Decls {
+		if gen, ok := decl.(*ast.GenDecl); ok && gen.Tok == token.VAR {
+			spec := gen.Specs[0].(*ast.ValueSpec)
+			if spec.Names[0].Name == "schema" {
+				embedded = constant.StringVal(info.Types[spec.Values[0]].Value)
+			}
+		}
+	}
+	require.NotEmpty(t, embedded)
+
+	var expected, actual bytes.Buffer
+	require.NoError(t, json.Compact(&expected, rawSchema))
+	require.NoError(t, json.Compact(&actual, []byte(embedded)))
+	assert.Equal(t, expected.String(), actual.String())
+	var roundTrip ovsdb.DatabaseSchema
+	require.NoError(t, json.Unmarshal([]byte(embedded), &roundTrip))
+	assert.Equal(t, schema, roundTrip)
+}
+
+func TestTableConstants(t *testing.T) {
+	rawSchema := []byte(`
+	{
+		"name": "ConstDB",
+		"version": "0.0.0",
+		"tables": {
+			"Logical_Switch": {
+				"columns": {
+					"name": {
+						"type": "string"
+					}
+				}
+			},
+			"ACL": {
+				"columns": {
+					"name": {
+						"type": "string"
+					}
+				}
+			}
+		}
+	}`)
+	var schema ovsdb.DatabaseSchema
+	require.NoError(t, json.Unmarshal(rawSchema, &schema))
+
+	g, err := NewGenerator()
+	require.NoError(t, err)
+	dbData := GetDBTemplateData("test", schema)
+	src, err := g.Format(NewDBTemplate(), dbData)
+	require.NoError(t, err)
+	assert.NotContains(t, string(src), "AllTables")
+
+	fset := token.NewFileSet()
+	dbData.WithTableConstants(true)
+	src, err = g.Format(NewDBTemplate(), dbData)
+	require.NoError(t, err)
+	file, err := parser.ParseFile(fset, "model.go", src, 0)
+	require.NoError(t, err)
+	files := []*ast.File{file}
+	for name, table := range schema.Tables {
+		table := table
+		src, err := g.Format(NewTableTemplate(), GetTableTemplateData("test", name, &table))
+		require.NoError(t, err)
+		file, err := parser.ParseFile(fset, FileName(name), src, 0)
+		require.NoError(t, err)
+		files = append(files, file)
+	}
+
+	conf := types.Config{Importer: importer.ForCompiler(fset, "source", nil)}
+	pkg, err := conf.Check("test", fset, files, nil)
+	require.NoError(t, err)
+	constant := func(name string) string {
+		c, ok := pkg.Scope().Lookup(name).(*types.Const)
+		require.True(t, ok, "constant %s not found", name)
+		return c.Val().String()
+	}
+	assert.Equal(t, `"ACL"`, constant("ACLTable"))
+	assert.Equal(t, `"Logical_Switch"`, constant("LogicalSwitchTable"))
+	assert.Contains(t, string(src), "var AllTables = []string{\n\tACLTable,\n\tLogicalSwitchTable,\n}")
+
+	// the constant of a table must not collide with the struct of another
+	schema.Tables["ACL_Table"] = schema.Tables["ACL"]
+	_, err = g.Format(NewDBTemplate(), GetDBTemplateData("test", schema))
+	require.NoError(t, err)
+	dbData = GetDBTemplateData("test", schema)
+	dbData.WithTableConstants(true)
+	_, err = g.Format(NewDBTemplate(), dbData)
+	assert.ErrorContains(t, err, "constant ACLTable of table ACL collides with the struct of table ACL_Table")
+}
+
+func TestPackageName(t *testing.T) {
+	assert.Equal(t, "ovnnorthbound", PackageName("", "OVN_Northbound"))
+	assert.Equal(t, "ovn_ovnsouthbound", PackageName("ovn_", "OVN_Southbound"))
+	assert.Equal(t, "openvswitch", PackageName("", "Open_vSwitch"))
+}
+
+// packageImporter imports the packages type-checked by a test, and the others
+// from source
+type packageImporter map[string]*types.Package
+
+func (p packageImporter) Import(path string) (*types.Package, error) {
+	if pkg, ok := p[path]; ok {
+		return pkg, nil
+	}
+	return importer.ForCompiler(token.NewFileSet(), "source", nil).Import(path)
+}
+
+func TestPackagePerDatabase(t *testing.T) {
+	schemas := map[string]string{
+		"OVN_Northbound": `{
+			"name": "OVN_Northbound",
+			"version": "1.0.0",
+			"tables": {
+				"Logical_Switch": {"columns": {"name": {"type": "string"}}, "isRoot": true},
+				"SSL": {"columns": {"private_key": {"type": "string"}}}
+			}
+		}`,
+		"OVN_Southbound": `{
+			"name": "OVN_Southbound",
+			"version": "1.0.0",
+			"tables": {
+				"Chassis": {"columns": {"name": {"type": "string"}}, "isRoot": true},
+				"SSL": {"columns": {"certificate": {"type": "string"}}}
+			}
+		}`,
+	}
+	g, err := NewGenerator()
+	require.NoError(t, err)
+	fset := token.NewFileSet()
+	// parse returns the files of the models of a database generated into pkg
+	parse := func(dbName, pkg string) []*ast.File {
+		var schema ovsdb.DatabaseSchema
+		require.NoError(t, json.Unmarshal([]byte(schemas[dbName]), &schema))
+		src, err := g.Format(NewDBTemplate(), GetDBTemplateData(pkg, schema))
+		require.NoError(t, err)
+		file, err := parser.ParseFile(fset, pkg+"/model.go", src, 0)
+		require.NoError(t, err)
+		files := []*ast.File{file}
+		for name, table := range schema.Tables {
+			table := table
+			src, err := g.Format(NewTableTemplate(), GetTableTemplateData(pkg, name, &table))
+			require.NoError(t, err)
+			file, err := parser.ParseFile(fset, pkg+"/"+FileName(name), src, 0)
+			require.NoError(t, err)
+			files = append(files, file)
+		}
+		return files
+	}
+
+	// generated into the same package, the databases collide
+	flat := append(parse("OVN_Northbound", "ovnmodel"), parse("OVN_Southbound", "ovnmodel")...)
+	conf := types.Config{Importer: packageImporter{}}
+	_, err = conf.Check("ovnmodel", fset, flat, nil)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "redeclared")
+
+	imports := packageImporter{}
+	for dbName := range schemas {
+		pkg := PackageName("", dbName)
+		conf := types.Config{Importer: imports}
+		checked, err := conf.Check(pkg, fset, parse(dbName, pkg), nil)
+		require.NoError(t, err)
+		imports[pkg] = checked
+	}
+	require.Contains(t, imports, "ovnnorthbound")
+	require.Contains(t, imports, "ovnsouthbound")
+
+	// code using both packages refers to each of their identifiers
+	src := `package main
+
+import (
+	"ovnnorthbound"
+	"ovnsouthbound"
+)
+
+var (
+	_ = ovnnorthbound.LogicalSwitch{}
+	_ = ovnnorthbound.SSL{PrivateKey: "key"}
+	_ = ovnsouthbound.Chassis{}
+	_ = ovnsouthbound.SSL{Certificate: "cert"}
+	_ = ovnnorthbound.SSLTable != ovnsouthbound.SSLTable
+	_, _ = ovnnorthbound.FullDatabaseModel()
+	_, _ = ovnsouthbound.FullDatabaseModel()
+	_ = ovnnorthbound.Schema().Name != ovnsouthbound.Schema().Name
+)
+`
+	file, err := parser.ParseFile(fset, "main.go", src, 0)
+	require.NoError(t, err)
+	conf = types.Config{Importer: imports}
+	_, err = conf.Check("main", fset, []*ast.File{file}, nil)
+	assert.NoError(t, err)
+}
@@ -0,0 +1,166 @@
+package modelgen
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/ovn-org/libovsdb/ovsdb"
+)
+
+// SchemaChangeKind is the kind of change found between two schemas
+type SchemaChangeKind string
+
+const (
+	// TableAdded is a table that only exists in the new schema
+	TableAdded SchemaChangeKind = "table added"
+	// TableRemoved is a table that only exists in the old schema
+	TableRemoved SchemaChangeKind = "table removed"
+	// ColumnAdded is a column that only exists in the new schema
+	ColumnAdded SchemaChangeKind = "column added"
+	// ColumnRemoved is a column that only exists in the old schema
+	ColumnRemoved SchemaChangeKind = "column removed"
+	// ColumnTypeChanged is a column whose type differs between the schemas
+	ColumnTypeChanged SchemaChangeKind = "column type changed"
+)
+
+// SchemaChange is a change found between two schemas
+type SchemaChange struct {
+	Kind   SchemaChangeKind
+	Table  string
+	Column string
+	// OldType and NewType are the types of the generated field of a column
+	// whose type changed
+	OldType string
+	NewType string
+	// Breaking is whether code using the models generated from the old schema
+	// may no longer compile with the models generated from the new one
+	Breaking bool
+}
+
+// String implements the Stringer interface
+func (c SchemaChange) String() string {
+	s := fmt.Sprintf("%s: %s", c.Kind, c.Table)
+	if c.Column != "" {
+		s += "." + c.Column
+	}
+	if c.Kind == ColumnTypeChanged && c.OldType != c.NewType {
+		s += fmt.Sprintf(" (%s -> %s)", c.OldType, c.NewType)
+	}
+	if c.Breaking {
+		s += " [breaking]"
+	}
+	return s
+}
+
+// DiffSchemas returns the tables and columns that were added, removed or
+// whose type changed from the old to the new schema, sorted by table and
+// column. Removing a table or a column is a breaking change for the generated
+// code, as well as a column type change that changes the type of the
+// generated field (e.g. a scalar becoming a set) or removes enum values.
+func DiffSchemas(old, new *ovsdb.DatabaseSchema) []SchemaChange {
+	var changes []SchemaChange
+	tables := map[string]bool{}
+	for table := range old.Tables {
+		tables[table] = true
+	}
+	for table := range new.Tables {
+		tables[table] = true
+	}
+	for _, table := range sortedNames(tables) {
+		oldTable, inOld := old.Tables[table]
+		newTable, inNew := new.Tables[table]
+		switch {
+		case !inNew:
+			changes = append(changes, SchemaChange{Kind: TableRemoved, Table: table, Breaking: true})
+		case !inOld:
+			changes = append(changes, SchemaChange{Kind: TableAdded, Table: table})
+		default:
+			changes = append(changes, diffTables(table, &oldTable, &newTable)...)
+		}
+	}
+	return changes
+}
+
+func diffTables(table string, old, new *ovsdb.TableSchema) []SchemaChange {
+	var changes []SchemaChange
+	columns := map[string]bool{}
+	for column := range old.Columns {
+		columns[column] = true
+	}
+	for column := range new.Columns {
+		columns[column] = true
+	}
+	for _, column := range sortedNames(columns) {
+		oldColumn, inOld := old.Columns[column]
+		newColumn, inNew := new.Columns[column]
+		switch {
+		case !inNew:
+			changes = append(changes, SchemaChange{Kind: ColumnRemoved, Table: table, Column: column, Breaking: true})
+		case !inOld:
+			changes = append(changes, SchemaChange{Kind: ColumnAdded, Table: table, Column: column})
+		default:
+			if change := diffColumns(table, column, oldColumn, newColumn); change != nil {
+				changes = append(changes, *change)
+			}
+		}
+	}
+	return changes
+}
+
+func diffColumns(table, column string, old, new *ovsdb.ColumnSchema) *SchemaChange {
+	if columnTypeString(old) == columnTypeString(new) {
+		return nil
+	}
+	oldType := FieldTypeWithEnums(table, column, old)
+	newType := FieldTypeWithEnums(table, column, new)
+	return &SchemaChange{
+		Kind:     ColumnTypeChanged,
+		Table:    table,
+		Column:   column,
+		OldType:  oldType,
+		NewType:  newType,
+		Breaking: oldType != newType || enumValuesRemoved(table, column, old, new),
+	}
+}
+
+// columnTypeString returns the type of a column as found in the schema
+func columnTypeString(column *ovsdb.ColumnSchema) string {
+	if column.TypeObj != nil {
+		if b, err := json.Marshal(column.TypeObj); err == nil {
+			return string(b)
+		}
+	}
+	return string(column.Type)
+}
+
+// enumValuesRemoved returns whether any of the enum values of the old column,
+// for which a constant is generated, is no longer a value of the new column
+func enumValuesRemoved(table, column string, old, new *ovsdb.ColumnSchema) bool {
+	oldEnum := FieldEnum(table, column, old)
+	if oldEnum == nil {
+		return false
+	}
+	newValues := map[interface{}]bool{}
+	if newEnum := FieldEnum(table, column, new); newEnum != nil {
+		for _, v := range newEnum.Sets {
+			newValues[v] = true
+		}
+	}
+	for _, v := range oldEnum.Sets {
+		if !newValues[v] {
+			return true
+		}
+	}
+	return false
+}
+
+// sortedNames returns the names of the set in order
+func sortedNames(set map[string]bool) []string {
+	names := make([]string, 0, len(set))
+	for name := range set {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,118 @@
+package modelgen
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/ovn-org/libovsdb/ovsdb"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiffSchemas(t *testing.T) {
+	oldSchema := []byte(`
+	{
+		"name": "TestDB",
+		"version": "1.0.0",
+		"tables": {
+			"Bridge": {
+				"columns": {
+					"name": {"type": "string"},
+					"datapath_type": {"type": "string"},
+					"protocol": {"type": {"key": {"type": "string", "enum": ["set", ["tcp", "udp"]]}, "min": 0, "max": 1}},
+					"mode": {"type": {"key": {"type": "string", "enum": ["set", ["active", "standby"]]}}},
+					"ports": {"type": {"key": {"type": "uuid"}, "min": 0, "max": 10}},
+					"stale": {"type": "integer"}
+				}
+			},
+			"Legacy": {
+				"columns": {
+					"name": {"type": "string"}
+				}
+			}
+		}
+	}`)
+	newSchema := []byte(`
+	{
+		"name": "TestDB",
+		"version": "2.0.0",
+		"tables": {
+			"Bridge": {
+				"columns": {
+					"name": {"type": "string"},
+					"datapath_type": {"type": {"key": {"type": "string"}, "min": 0, "max": "unlimited"}},
+					"protocol": {"type": {"key": {"type": "string", "enum": ["set", ["tcp", "udp", "sctp"]]}, "min": 0, "max": 1}},
+					"mode": {"type": {"key": {"type": "string", "enum": ["set", ["active"]]}}},
+					"ports": {"type": {"key": {"type": "uuid"}, "min": 0, "max": "unlimited"}},
+					"external_ids": {"type": {"key": {"type": "string"}, "value": {"type": "string"}, "min": 0, "max": "unlimited"}}
+				}
+			},
+			"Port": {
+				"columns": {
+					"name": {"type": "string"}
+				}
+			}
+		}
+	}`)
+	var oldDB, newDB ovsdb.DatabaseSchema
+	require.NoError(t, json.Unmarshal(oldSchema, &oldDB))
+	require.NoError(t, json.Unmarshal(newSchema, &newDB))
+
+	expected := []SchemaChange{
+		{
+			Kind:     ColumnTypeChanged,
+			Table:    "Bridge",
+			Column:   "datapath_type",
+			OldType:  "string",
+			NewType:  "[]string",
+			Breaking: true,
+		},
+		{
+			Kind:   ColumnAdded,
+			Table:  "Bridge",
+			Column: "external_ids",
+		},
+		{
+			Kind:     ColumnTypeChanged,
+			Table:    "Bridge",
+			Column:   "mode",
+			OldType:  "BridgeMode",
+			NewType:  "BridgeMode",
+			Breaking: true,
+		},
+		{
+			Kind:     ColumnTypeChanged,
+			Table:    "Bridge",
+			Column:   "ports",
+			OldType:  "[10]string",
+			NewType:  "[]string",
+			Breaking: true,
+		},
+		{
+			Kind:    ColumnTypeChanged,
+			Table:   "Bridge",
+			Column:  "protocol",
+			OldType: "*BridgeProtocol",
+			NewType: "*BridgeProtocol",
+		},
+		{
+			Kind:     ColumnRemoved,
+			Table:    "Bridge",
+			Column:   "stale",
+			Breaking: true,
+		},
+		{
+			Kind:     TableRemoved,
+			Table:    "Legacy",
+			Breaking: true,
+		},
+		{
+			Kind:  TableAdded,
+			Table: "Port",
+		},
+	}
+	assert.Equal(t, expected, DiffSchemas(&oldDB, &newDB))
+	assert.Empty(t, DiffSchemas(&newDB, &newDB))
+	assert.Equal(t, "column type changed: Bridge.datapath_type (string -> []string) [breaking]", expected[0].String())
+	assert.Equal(t, "table removed: Legacy [breaking]", expected[6].String())
+}
@@ -3,9 +3,15 @@ package modelgen
 import (
 	"bytes"
 	"fmt"
+	"go/ast"
 	"go/format"
+	"go/parser"
+	"go/token"
 	"io/ioutil"
 	"log"
+	"sort"
+	"strconv"
+	"strings"
 	"text/template"
 )
 
@@ -15,12 +21,64 @@ type Generator interface {
 	Format(*template.Template, interface{}) ([]byte, error)
 }
 
+// Flusher is implemented by the generators returned by NewGenerator. Flush
+// writes the code collected by Generate when the generator was created
+// WithSingleFile. It does nothing otherwise.
+type Flusher interface {
+	Flush() error
+}
+
 type generator struct {
-	dryRun bool
+	dryRun           bool
+	singleFile       string
+	rawOnFormatError bool
+	header           string
+	namer            *namer
+	typeOverrides    map[string]map[string]TypeOverride
+	sources          [][]byte
+}
+
+// FormatError is returned by a generator created WithRawOnFormatError when
+// the output of a template is not valid Go code
+type FormatError struct {
+	Err    error
+	Source []byte
+}
+
+func (e *FormatError) Error() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%v\n---- unformatted source ----\n", e.Err)
+	for i, line := range strings.Split(string(e.Source), "\n") {
+		fmt.Fprintf(&b, "%4d  %s\n", i+1, line)
+	}
+	return b.String()
+}
+
+func (e *FormatError) Unwrap() error {
+	return e.Err
 }
 
 // Format returns a formatted byte slice by executing the template with the given args
 func (g *generator) Format(tmpl *template.Template, args interface{}) ([]byte, error) {
+	if data, ok := args.(TableTemplateData); ok {
+		var err error
+		if args, err = g.tableData(data); err != nil {
+			return nil, err
+		}
+	}
+	if g.namer != nil {
+		var err error
+		if tmpl, err = tmpl.Clone(); err != nil {
+			return nil, err
+		}
+		tmpl.Funcs(g.namer.funcs())
+	}
+	if g.header != "" && tmpl.Lookup("header") != nil {
+		var err error
+		if tmpl, err = g.withHeader(tmpl); err != nil {
+			return nil, err
+		}
+	}
 	buffer := bytes.Buffer{}
 	err := tmpl.Execute(&buffer, args)
 	if err != nil {
@@ -29,18 +87,87 @@ func (g *generator) Format(tmpl *template.Template, args interface{}) ([]byte, e
 
 	src, err := format.Source(buffer.Bytes())
 	if err != nil {
+		if g.rawOnFormatError {
+			return buffer.Bytes(), &FormatError{Err: err, Source: buffer.Bytes()}
+		}
 		return nil, err
 	}
 	return src, nil
 }
 
+// withHeader returns a copy of a template whose header template is replaced
+// by the header given to WithHeaderTemplate
+func (g *generator) withHeader(tmpl *template.Template) (*template.Template, error) {
+	clone, err := tmpl.Clone()
+	if err != nil {
+		return nil, err
+	}
+	return clone.Parse(`{{ define "header" }}` + "\n" + `{{ ` + strconv.Quote(g.header) + ` }}{{ end }}`)
+}
+
+// tableData returns a copy of the data of a table with the options of the
+// generator that apply to tables, like the type overrides of its columns
+func (g *generator) tableData(data TableTemplateData) (TableTemplateData, error) {
+	table, _ := data["TableName"].(string)
+	overrides := g.typeOverrides[table]
+	if len(overrides) == 0 && g.namer == nil {
+		return data, nil
+	}
+	tableData := make(TableTemplateData, len(data))
+	for k, v := range data {
+		tableData[k] = v
+	}
+	if fields, ok := data["Fields"].([]Field); ok && g.namer != nil {
+		enums := []Enum{}
+		for _, field := range fields {
+			if enum := g.namer.fieldEnum(table, field.Column, field.Schema); enum != nil {
+				enums = append(enums, *enum)
+			}
+		}
+		tableData["Enums"] = enums
+	}
+	columns := make([]string, 0, len(overrides))
+	for column := range overrides {
+		columns = append(columns, column)
+	}
+	sort.Strings(columns)
+	for _, column := range columns {
+		override := overrides[column]
+		if err := tableData.WithTypeOverride(column, override.Type, override.ImportPath); err != nil {
+			return nil, err
+		}
+	}
+	return tableData, nil
+}
+
 // Generate generates the code and writes it to specified file path
 func (g *generator) Generate(filename string, tmpl *template.Template, args interface{}) error {
 	src, err := g.Format(tmpl, args)
 	if err != nil {
 		return err
 	}
+	if g.singleFile != "" {
+		g.sources = append(g.sources, src)
+		return nil
+	}
+	return g.write(filename, src)
+}
+
+// Flush merges the code collected by Generate into a single source file and
+// writes it to the file given to WithSingleFile
+func (g *generator) Flush() error {
+	if g.singleFile == "" || len(g.sources) == 0 {
+		return nil
+	}
+	src, err := mergeSources(g.sources)
+	if err != nil {
+		return err
+	}
+	g.sources = nil
+	return g.write(g.singleFile, src)
+}
 
+func (g *generator) write(filename string, src []byte) error {
 	if g.dryRun {
 		log.Printf("---- Content of file %s ----\n", filename)
 		log.Print(string(src))
@@ -60,7 +187,102 @@ func NewGenerator(opts ...Option) (Generator, error) {
 	if err != nil {
 		return nil, err
 	}
+	var initialismsNamer *namer
+	if len(options.initialisms) > 0 {
+		n := defaultNamer.withInitialisms(options.initialisms)
+		initialismsNamer = &n
+	}
 	return &generator{
-		dryRun: options.dryRun,
+		dryRun:           options.dryRun,
+		singleFile:       options.singleFile,
+		rawOnFormatError: options.rawOnFormatError,
+		header:           options.header,
+		namer:            initialismsNamer,
+		typeOverrides:    options.typeOverrides,
 	}, nil
 }
+
+// mergeSources merges several source files of the same package into one,
+// keeping the header comment of the first one and a single import block
+func mergeSources(sources [][]byte) ([]byte, error) {
+	fset := token.NewFileSet()
+	var header []byte
+	var pkgName string
+	imports := map[string]string{}
+	var bodies [][]byte
+	for i, src := range sources {
+		file, err := parser.ParseFile(fset, "", src, parser.ParseComments)
+		if err != nil {
+			return nil, err
+		}
+		if i == 0 {
+			header = src[:fset.Position(file.Package).Offset]
+			pkgName = file.Name.Name
+		} else if file.Name.Name != pkgName {
+			return nil, fmt.Errorf("cannot merge packages %s and %s into a single file", pkgName, file.Name.Name)
+		}
+		for _, spec := range file.Imports {
+			path, err := strconv.Unquote(spec.Path.Value)
+			if err != nil {
+				return nil, err
+			}
+			var name string
+			if spec.Name != nil {
+				name = spec.Name.Name
+			}
+			if other, ok := imports[path]; ok && other != name {
+				return nil, fmt.Errorf("package %s is imported both as %q and %q", path, other, name)
+			}
+			imports[path] = name
+		}
+		// the body starts after the last import declaration, which the
+		// parser requires to come first
+		end := file.Name.End()
+		for _, decl := range file.Decls {
+			genDecl, ok := decl.(*ast.GenDecl)
+			if !ok || genDecl.Tok != token.IMPORT {
+				break
+			}
+			end = genDecl.End()
+		}
+		bodies = append(bodies, src[fset.Position(end).Offset:])
+	}
+
+	var std, others []string
+	for path, name := range imports {
+		spec := strconv.Quote(path)
+		if name != "" {
+			spec = name + " " + spec
+		}
+		// standard library paths do not have a domain
+		if strings.Contains(strings.Split(path, "/")[0], ".") {
+			others = append(others, spec)
+		} else {
+			std = append(std, spec)
+		}
+	}
+	sort.Strings(std)
+	sort.Strings(others)
+
+	buffer := bytes.Buffer{}
+	buffer.Write(header)
+	fmt.Fprintf(&buffer, "package %s\n\n", pkgName)
+	if len(imports) > 0 {
+		buffer.WriteString("import (\n")
+		for _, spec := range std {
+			fmt.Fprintf(&buffer, "\t%s\n", spec)
+		}
+		if len(std) > 0 && len(others) > 0 {
+			buffer.WriteString("\n")
+		}
+		for _, spec := range others {
+			fmt.Fprintf(&buffer, "\t%s\n", spec)
+		}
+		buffer.WriteString(")\n")
+	}
+	for _, body := range bodies {
+		buffer.Write(body)
+		buffer.WriteString("\n")
+	}
+	return format.Source(buffer.Bytes())
+}
@@ -0,0 +1,190 @@
+package modelgen
+
+import (
+	"encoding/json"
+	"errors"
+	"go/ast"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"text/template"
+
+	"github.com/ovn-org/libovsdb/ovsdb"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGeneratorSingleFile(t *testing.T) {
+	rawSchema := []byte(`
+	{
+		"name": "SingleDB",
+		"version": "0.0.0",
+		"tables": {
+			"Bridge": {
+				"columns": {
+					"name": {"type": "string"},
+					"fail_mode": {"type": {"key": {"type": "string", "enum": ["set", ["standalone", "secure"]]}, "min": 0, "max": 1}},
+					"ports": {"type": {"key": {"type": "uuid", "refTable": "Port"}, "min": 0, "max": "unlimited"}}
+				}
+			},
+			"Port": {
+				"columns": {
+					"name": {"type": "string"},
+					"external_ids": {"type": {"key": {"type": "string"}, "value": {"type": "string"}, "min": 0, "max": "unlimited"}}
+				}
+			}
+		}
+	}`)
+	var schema ovsdb.DatabaseSchema
+	require.NoError(t, json.Unmarshal(rawSchema, &schema))
+
+	dir, err := ioutil.TempDir("", "modelgen")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	filename := filepath.Join(dir, "generated.go")
+
+	g, err := NewGenerator(WithSingleFile(filename))
+	require.NoError(t, err)
+	for name, table := range schema.Tables {
+		table := table
+		data := GetTableTemplateData("single", name, &table)
+		data.WithExtendedGen(true)
+		data.WithAccessors(true)
+		require.NoError(t, g.Generate(filepath.Join(dir, FileName(name)), NewTableTemplate(), data))
+	}
+	dbData := GetDBTemplateData("single", schema)
+	dbData.WithAccessors(true)
+	require.NoError(t, g.Generate(filepath.Join(dir, "model.go"), NewDBTemplate(), dbData))
+
+	// nothing is written until the generator is flushed
+	files, err := ioutil.ReadDir(dir)
+	require.NoError(t, err)
+	assert.Empty(t, files)
+	require.Implements(t, (*Flusher)(nil), g)
+	require.NoError(t, g.(Flusher).Flush())
+	files, err = ioutil.ReadDir(dir)
+	require.NoError(t, err)
+	require.Len(t, files, 1)
+	assert.Equal(t, "generated.go", files[0].Name())
+
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, filename, nil, parser.ParseComments)
+	require.NoError(t, err)
+	assert.Equal(t, "single", file.Name.Name)
+	require.NotEmpty(t, file.Comments)
+	assert.Equal(t, "// Code generated by \"libovsdb.modelgen\"", file.Comments[0].List[0].Text)
+	var importDecls int
+	for _, decl := range file.Decls {
+		if genDecl, ok := decl.(*ast.GenDecl); ok && genDecl.Tok == token.IMPORT {
+			importDecls++
+		}
+	}
+	assert.Equal(t, 1, importDecls)
+	paths := map[string]int{}
+	for _, spec := range file.Imports {
+		paths[spec.Path.Value]++
+	}
+	for path, count := range paths {
+		assert.Equal(t, 1, count, "%s imported more than once", path)
+	}
+
+	// the generated file has to compile on its own
+	conf := types.Config{Importer: importer.ForCompiler(fset, "source", nil)}
+	pkg, err := conf.Check("single", fset, []*ast.File{file}, nil)
+	require.NoError(t, err)
+	for _, name := range []string{"Bridge", "Port", "BridgeFailModeSecure", "FullDatabaseModel", "Client"} {
+		assert.NotNil(t, pkg.Scope().Lookup(name), "%s not found", name)
+	}
+}
+
+func TestGeneratorRawOnFormatError(t *testing.T) {
+	tmpl := template.Must(template.New("broken").Parse("package {{ .Package }}\n\nfunc {{ .Name }}( {\n}\n"))
+	data := map[string]string{"Package": "broken", "Name": "Foo"}
+	raw := "package broken\n\nfunc Foo( {\n}\n"
+
+	g, err := NewGenerator()
+	require.NoError(t, err)
+	src, err := g.Format(tmpl, data)
+	require.Error(t, err)
+	assert.Nil(t, src)
+	var formatErr *FormatError
+	assert.False(t, errors.As(err, &formatErr))
+
+	g, err = NewGenerator(WithRawOnFormatError())
+	require.NoError(t, err)
+	src, err = g.Format(tmpl, data)
+	require.Error(t, err)
+	assert.Equal(t, raw, string(src))
+	require.True(t, errors.As(err, &formatErr))
+	assert.Equal(t, raw, string(formatErr.Source))
+	assert.Contains(t, err.Error(), "---- unformatted source ----")
+	assert.Contains(t, err.Error(), "   3  func Foo( {")
+}
+
+func TestGeneratorHeaderTemplate(t *testing.T) {
+	rawSchema := []byte(`
+	{
+		"name": "HeaderDB",
+		"version": "1.2.3",
+		"tables": {
+			"Bridge": {
+				"columns": {
+					"name": {"type": "string"}
+				}
+			}
+		}
+	}`)
+	var schema ovsdb.DatabaseSchema
+	require.NoError(t, json.Unmarshal(rawSchema, &schema))
+	table := schema.Tables["Bridge"]
+
+	tests := []struct {
+		name   string
+		header string
+		want   []string
+	}{
+		{
+			"custom",
+			"// Code generated by \"libovsdb.modelgen\" from {{ .Name }} {{ .Version }}. DO NOT EDIT.\n",
+			[]string{"// Code generated by \"libovsdb.modelgen\" from HeaderDB 1.2.3. DO NOT EDIT."},
+		},
+		{
+			"without generated code line",
+			"// Schema {{ .Name }} version {{ .Version }}",
+			[]string{"// Code generated by \"libovsdb.modelgen\". DO NOT EDIT.", "// Schema HeaderDB version 1.2.3"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g, err := NewGenerator(WithHeaderTemplate(tt.header, schema))
+			require.NoError(t, err)
+			for _, args := range []struct {
+				tmpl *template.Template
+				data interface{}
+			}{
+				{NewTableTemplate(), GetTableTemplateData("header", "Bridge", &table)},
+				{NewDBTemplate(), GetDBTemplateData("header", schema)},
+			} {
+				src, err := g.Format(args.tmpl, args.data)
+				require.NoError(t, err)
+				file, err := parser.ParseFile(token.NewFileSet(), "", src, parser.ParseComments)
+				require.NoError(t, err)
+				require.NotEmpty(t, file.Comments)
+				var lines []string
+				for _, comment := range file.Comments[0].List {
+					lines = append(lines, comment.Text)
+				}
+				assert.Equal(t, tt.want, lines)
+				assert.True(t, generatedCode.Match(src), "file not recognized as generated")
+			}
+		})
+	}
+
+	_, err := NewGenerator(WithHeaderTemplate("// {{ .Missing }}", schema))
+	assert.Error(t, err)
+}
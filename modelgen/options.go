@@ -1,7 +1,22 @@
 package modelgen
 
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"strings"
+	"text/template"
+
+	"github.com/ovn-org/libovsdb/ovsdb"
+)
+
 type options struct {
-	dryRun bool
+	dryRun           bool
+	singleFile       string
+	rawOnFormatError bool
+	header           string
+	initialisms      []string
+	typeOverrides    map[string]map[string]TypeOverride
 }
 
 type Option func(o *options) error
@@ -22,3 +37,99 @@ func WithDryRun() Option {
 		return nil
 	}
 }
+
+// WithSingleFile tells the generator to write all the generated code into the
+// given file, with a single header and import block, instead of one file per
+// call to Generate. The file is written when the generator is flushed, see
+// Flusher.
+func WithSingleFile(filename string) Option {
+	return func(o *options) error {
+		if filename == "" {
+			return fmt.Errorf("single file name must not be empty")
+		}
+		o.singleFile = filename
+		return nil
+	}
+}
+
+// WithRawOnFormatError tells the generator to return the unformatted source
+// when the template output cannot be formatted. The source is also included
+// in the returned FormatError, which helps to debug templates.
+func WithRawOnFormatError() Option {
+	return func(o *options) error {
+		o.rawOnFormatError = true
+		return nil
+	}
+}
+
+// initialism matches the words that can be spelled in upper case in names
+var initialism = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9]*$`)
+
+// WithInitialisms tells the generator to spell the given words in upper case,
+// like the common initialisms such as IP or VLAN, when they appear in the
+// names of the fields, enums and enum values generated for the columns, e.g.
+// WithInitialisms("LB") names the field of column lb_group LBGroup rather than
+// LbGroup. The names of the tables are not affected.
+func WithInitialisms(words ...string) Option {
+	return func(o *options) error {
+		for _, word := range words {
+			if !initialism.MatchString(word) {
+				return fmt.Errorf("invalid initialism %q", word)
+			}
+		}
+		o.initialisms = append(o.initialisms, words...)
+		return nil
+	}
+}
+
+// WithColumnTypeOverride tells the generator to use the given Go type for the
+// field of a column of a table instead of its native type, importing the
+// package of the type if importPath is not empty. It applies to the
+// TableTemplateData of the table passed to Generate or Format (see
+// TableTemplateData.WithTypeOverride).
+func WithColumnTypeOverride(table, column, goType, importPath string) Option {
+	return func(o *options) error {
+		if table == "" || column == "" || goType == "" {
+			return fmt.Errorf("type override of column %q of table %q must have a table, a column and a type", column, table)
+		}
+		if o.typeOverrides == nil {
+			o.typeOverrides = make(map[string]map[string]TypeOverride)
+		}
+		if o.typeOverrides[table] == nil {
+			o.typeOverrides[table] = make(map[string]TypeOverride)
+		}
+		o.typeOverrides[table][column] = TypeOverride{Type: goType, ImportPath: importPath}
+		return nil
+	}
+}
+
+// generatedCode matches the comment that marks generated files, see
+// https://golang.org/s/generatedcode
+var generatedCode = regexp.MustCompile(`(?m)^// Code generated .* DO NOT EDIT\.$`)
+
+// WithHeaderTemplate tells the generator to replace the header comment of the
+// generated files by the given template, executed with the schema the models
+// are generated from, e.g.:
+//
+//	// Code generated by "libovsdb.modelgen" from {{ .Name }} {{ .Version }}. DO NOT EDIT.
+//
+// The header must only contain comments. If it has no line that marks the file
+// as generated, as recognized by the go tooling, one is added at its top.
+func WithHeaderTemplate(text string, schema ovsdb.DatabaseSchema) Option {
+	return func(o *options) error {
+		tmpl, err := template.New("header").Parse(text)
+		if err != nil {
+			return fmt.Errorf("invalid header template: %w", err)
+		}
+		buffer := bytes.Buffer{}
+		if err := tmpl.Execute(&buffer, schema); err != nil {
+			return fmt.Errorf("invalid header template: %w", err)
+		}
+		header := strings.TrimSpace(buffer.String())
+		if !generatedCode.MatchString(header) {
+			header = "// Code generated by \"libovsdb.modelgen\". DO NOT EDIT.\n" + header
+		}
+		o.header = header
+		return nil
+	}
+}
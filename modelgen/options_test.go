@@ -72,3 +72,26 @@ func Test_newOptions(t *testing.T) {
 		})
 	}
 }
+
+func TestWithSingleFile(t *testing.T) {
+	opts := &options{}
+	if err := WithSingleFile("generated.go")(opts); err != nil {
+		t.Fatalf("WithSingleFile() error = %v", err)
+	}
+	if opts.singleFile != "generated.go" {
+		t.Errorf("WithSingleFile() = %v, want %v", opts.singleFile, "generated.go")
+	}
+	if err := WithSingleFile("")(&options{}); err == nil {
+		t.Error("WithSingleFile() with an empty file name should fail")
+	}
+}
+
+func TestWithRawOnFormatError(t *testing.T) {
+	opts := &options{}
+	if err := WithRawOnFormatError()(opts); err != nil {
+		t.Fatalf("WithRawOnFormatError() error = %v", err)
+	}
+	if !opts.rawOnFormatError {
+		t.Error("WithRawOnFormatError() did not set rawOnFormatError")
+	}
+}
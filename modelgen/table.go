@@ -1,10 +1,20 @@
 package modelgen
 
 import (
+	"encoding/json"
 	"fmt"
+	"go/ast"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"reflect"
 	"sort"
+	"strconv"
 	"strings"
+	"sync"
 	"text/template"
+	"unicode"
 
 	"golang.org/x/text/cases"
 	"golang.org/x/text/language"
@@ -19,7 +29,7 @@ var extendedGenTemplate = `
 {{- define "equalExtraFields" }}{{ end }}
 {{- define "extendedGenImports" }}
 {{- if index . "WithExtendedGen" }}
-import "github.com/ovn-org/libovsdb/model"
+import "{{ index . "ImportPath" }}/model"
 {{- end }}
 {{- end }}
 {{- define "extendedGen" }}
@@ -34,20 +44,22 @@ import "github.com/ovn-org/libovsdb/model"
 {{- else }}
 {{- $type = FieldType $tableName $field.Column $field.Schema }}
 {{- end }}
+{{- if $field.Override }}{{ $type = $field.Override.Type }}{{ end }}
 
 func (a *{{ $structName }}) Get{{ $fieldName }}() {{ $type }} {
 	return a.{{ $fieldName }}
 }
 
-{{ if or (eq (index $type 0) '*') (eq (slice $type 0 2) "[]") (eq (slice $type 0 3) "map") }}
+{{ $copy := CopyKind $type $field.Override }}
+{{- if $copy }}
 func copy{{ $structName }}{{ $fieldName }}(a {{ $type }}) {{ $type }} {
 	if a == nil {
 		return nil
 	}
-	{{- if eq (index $type 0) '*' }}
+	{{- if eq $copy "pointer" }}
 	b := *a
 	return &b
-	{{- else if eq (slice $type 0 2) "[]" }}
+	{{- else if eq $copy "slice" }}
 	b := make({{ $type }}, len(a))
 	copy(b, a)
 	return b
@@ -59,7 +71,8 @@ func copy{{ $structName }}{{ $fieldName }}(a {{ $type }}) {{ $type }} {
 	return b
 	{{- end }}
 }
-
+{{ end }}
+{{ if and (not $field.Override) (or (eq (index $type 0) '*') (eq (slice $type 0 2) "[]") (eq (slice $type 0 3) "map")) }}
 func equal{{ $structName }}{{ $fieldName }}(a, b {{ $type }}) bool {
 	if (a == nil) != (b == nil) {
 		return false
@@ -73,10 +86,26 @@ func equal{{ $structName }}{{ $fieldName }}(a, b {{ $type }}) bool {
 	if len(a) != len(b) {
 		return false
 	}
+	ordered := true
 	for i, v := range a {
 		if b[i] != v {
+			ordered = false
+			break
+		}
+	}
+	if ordered {
+		return true
+	}
+	// sets are unordered, compare the number of occurrences of each element
+	counts := make(map[{{ slice $type 2 }}]int, len(a))
+	for _, v := range a {
+		counts[v]++
+	}
+	for _, v := range b {
+		if counts[v] == 0 {
 			return false
 		}
+		counts[v]--
 	}
 	return true
 	{{- else }}
@@ -105,7 +134,8 @@ func (a *{{ $structName }}) DeepCopyInto(b *{{ $structName }}) {
 	{{- else }}
 	{{- $type = FieldType $tableName $field.Column $field.Schema }}
 	{{- end }}
-	{{- if or (eq (index $type 0) '*') (eq (slice $type 0 2) "[]") (eq (slice $type 0 3) "map") }}
+	{{- if $field.Override }}{{ $type = $field.Override.Type }}{{ end }}
+	{{- if CopyKind $type $field.Override }}
 	b.{{ $fieldName }} = copy{{ $structName }}{{ $fieldName }}(a.{{ $fieldName }})
 	{{- end }}
 	{{- end }}
@@ -127,6 +157,33 @@ func (a *{{ $structName }}) CloneModel() model.Model {
 	return a.DeepCopy()
 }
 
+func (a *{{ $structName }}) CloneModelColumns(columns []string) model.Model {
+	b := &{{ $structName }}{UUID: a.UUID}
+	for _, column := range columns {
+		switch column {
+		{{- range $field := index . "Fields" }}
+		{{- if ne $field.Column "_uuid" }}
+		{{- $fieldName := FieldName $field.Column }}
+		{{- $type := "" }}
+		{{- if index $ "WithEnumTypes" }}
+		{{- $type = FieldTypeWithEnums $tableName $field.Column $field.Schema }}
+		{{- else }}
+		{{- $type = FieldType $tableName $field.Column $field.Schema }}
+		{{- end }}
+		{{- if $field.Override }}{{ $type = $field.Override.Type }}{{ end }}
+		case {{ printf "%q" $field.Column }}:
+			{{- if CopyKind $type $field.Override }}
+			b.{{ $fieldName }} = copy{{ $structName }}{{ $fieldName }}(a.{{ $fieldName }})
+			{{- else }}
+			b.{{ $fieldName }} = a.{{ $fieldName }}
+			{{- end }}
+		{{- end }}
+		{{- end }}
+		}
+	}
+	return b
+}
+
 func (a *{{ $structName }}) Equals(b *{{ $structName }}) bool {
 	{{- range $i, $field := index . "Fields" }}
 	{{- $fieldName := FieldName $field.Column }}
@@ -136,9 +193,12 @@ func (a *{{ $structName }}) Equals(b *{{ $structName }}) bool {
 	{{- else }}
 	{{- $type = FieldType $tableName $field.Column $field.Schema }}
 	{{- end }}
+	{{- if $field.Override }}{{ $type = $field.Override.Type }}{{ end }}
 	{{- if $i }}&&
 	{{ else }}return {{ end }}
-	{{- if or (eq (index $type 0) '*') (eq (slice $type 0 2) "[]") (eq (slice $type 0 3) "map") -}}
+	{{- if $field.Override -}}
+	reflect.DeepEqual(a.{{ $fieldName }}, b.{{ $fieldName }})
+	{{- else if or (eq (index $type 0) '*') (eq (slice $type 0 2) "[]") (eq (slice $type 0 3) "map") -}}
 	equal{{ $structName }}{{ $fieldName }}(a.{{ $fieldName }}, b.{{ $fieldName }})
 	{{- else -}}
 	a.{{ $fieldName }} == b.{{ $fieldName }}
@@ -153,11 +213,469 @@ func (a *{{ $structName }}) EqualsModel(b model.Model) bool {
 }
 
 var _ model.CloneableModel = &{{ $structName }}{}
+var _ model.ColumnCloneableModel = &{{ $structName }}{}
 var _ model.ComparableModel = &{{ $structName }}{}
 {{- end }}
 {{- end }}
 `
 
+// defaultsTemplate includes a method to initialize the fields of a model to
+// the default values of their columns
+var defaultsTemplate = `
+{{- define "defaults" }}
+{{- if index . "WithDefaults" }}
+{{- $tableName := index . "TableName" }}
+{{- $structName := index . "StructName" }}
+
+// SetDefaults initializes the set and map fields that are nil to empty sets
+// and maps, the default value of their columns, so that they can be mutated.
+// Other fields already hold the default value of their column.
+func (a *{{ $structName }}) SetDefaults() {
+	{{- range $field := index . "Fields" }}
+	{{- $fieldName := FieldName $field.Column }}
+	{{- $type := "" }}
+	{{- if index $ "WithEnumTypes" }}
+	{{- $type = FieldTypeWithEnums $tableName $field.Column $field.Schema }}
+	{{- else }}
+	{{- $type = FieldType $tableName $field.Column $field.Schema }}
+	{{- end }}
+	{{- if $field.Override }}{{ $type = $field.Override.Type }}{{ end }}
+	{{- if and (not $field.Override) (or (eq (slice $type 0 2) "[]") (eq (slice $type 0 3) "map")) }}
+	if a.{{ $fieldName }} == nil {
+		a.{{ $fieldName }} = {{ $type }}{}
+	}
+	{{- end }}
+	{{- end }}
+}
+{{- end }}
+{{- end }}
+`
+
+// validationTemplate includes a method to check the number of elements of the
+// set and map fields against the bounds of their columns
+var validationTemplate = `
+{{- define "validation" }}
+{{- if index . "WithValidation" }}
+{{- $tableName := index . "TableName" }}
+{{- $structName := index . "StructName" }}
+
+// Validate checks that the number of elements of each set and map field is
+// within the bounds of its column
+func (a *{{ $structName }}) Validate() error {
+	{{- range $field := index . "Fields" }}
+	{{- $fieldName := FieldName $field.Column }}
+	{{- if not $field.Override }}
+	{{- with FieldBounds $field.Schema }}
+	{{- if gt .Min 0 }}
+	if len(a.{{ $fieldName }}) < {{ .Min }} {
+		return fmt.Errorf("column {{ $field.Column }} of table {{ $tableName }} has %d elements, fewer than the minimum of {{ .Min }}", len(a.{{ $fieldName }}))
+	}
+	{{- end }}
+	{{- if ne .Max -1 }}
+	if len(a.{{ $fieldName }}) > {{ .Max }} {
+		return fmt.Errorf("column {{ $field.Column }} of table {{ $tableName }} has %d elements, more than the maximum of {{ .Max }}", len(a.{{ $fieldName }}))
+	}
+	{{- end }}
+	{{- end }}
+	{{- end }}
+	{{- end }}
+	return nil
+}
+{{- end }}
+{{- end }}
+`
+
+// stringerTemplate includes a String method that renders a model in a readable
+// and stable form
+var stringerTemplate = `
+{{- define "stringer" }}
+{{- if index . "WithStringer" }}
+{{- $tableName := index . "TableName" }}
+{{- $structName := index . "StructName" }}
+{{- $sets := false }}
+{{- range $field := index . "Fields" }}
+{{- if and (not $field.Override) (eq (slice (FieldType $tableName $field.Column $field.Schema) 0 1) "[") }}{{ $sets = true }}{{ end }}
+{{- end }}
+
+// String returns the {{ $structName }} as {{ $tableName }}{column=value, ...}. The
+// elements of sets are sorted, and only the first {{ MaxSetElements }} of longer sets
+// are shown. Maps are sorted by key.
+func (a {{ $structName }}) String() string {
+	{{- if $sets }}
+	formatSet := func(elems []string) string {
+		sort.Strings(elems)
+		if len(elems) > {{ MaxSetElements }} {
+			return fmt.Sprintf("[%s ... (%d more)]", strings.Join(elems[:{{ MaxSetElements }}], " "), len(elems)-{{ MaxSetElements }})
+		}
+		return "[" + strings.Join(elems, " ") + "]"
+	}
+	{{- end }}
+	fields := make([]string, 0, {{ len (index . "Fields") }})
+	{{- range $field := index . "Fields" }}
+	{{- $fieldName := FieldName $field.Column }}
+	{{- if $field.Override }}
+	fields = append(fields, fmt.Sprintf("{{ $field.Column }}=%v", a.{{ $fieldName }}))
+	{{- else if FieldOptional $field.Schema }}
+	if a.{{ $fieldName }} != nil {
+		fields = append(fields, fmt.Sprintf("{{ $field.Column }}=%v", *a.{{ $fieldName }}))
+	} else {
+		fields = append(fields, "{{ $field.Column }}=[]")
+	}
+	{{- else if eq (slice (FieldType $tableName $field.Column $field.Schema) 0 1) "[" }}
+	{
+		elems := make([]string, 0, len(a.{{ $fieldName }}))
+		for _, elem := range a.{{ $fieldName }} {
+			elems = append(elems, fmt.Sprint(elem))
+		}
+		fields = append(fields, "{{ $field.Column }}="+formatSet(elems))
+	}
+	{{- else }}
+	fields = append(fields, fmt.Sprintf("{{ $field.Column }}=%v", a.{{ $fieldName }}))
+	{{- end }}
+	{{- end }}
+	return "{{ $tableName }}{" + strings.Join(fields, ", ") + "}"
+}
+{{- end }}
+{{- end }}
+`
+
+// methodImportsTemplate imports the packages used by the optional validation
+// and stringer methods
+var methodImportsTemplate = `
+{{- define "methodImports" }}
+{{- $tableName := index . "TableName" }}
+{{- $fmt := or (index . "WithStringer") (index . "WithFieldAccessors") }}
+{{- $sets := false }}
+{{- range $field := index . "Fields" }}
+{{- if and (index $ "WithValidation") (not $field.Override) (FieldBounds $field.Schema) }}{{ $fmt = true }}{{ end }}
+{{- if and (index $ "WithStringer") (not $field.Override) (eq (slice (FieldType $tableName $field.Column $field.Schema) 0 1) "[") }}{{ $sets = true }}{{ end }}
+{{- end }}
+{{- if $fmt }}
+import "fmt"
+{{- end }}
+{{- if $sets }}
+import "sort"
+{{- end }}
+{{- if index . "WithStringer" }}
+import "strings"
+{{- end }}
+{{- end }}
+`
+
+// overrideImportsTemplate imports the packages of the types that override the
+// native type of some fields, and the reflect package used to compare them
+var overrideImportsTemplate = `
+{{- define "overrideImports" }}
+{{- range index . "OverrideImports" }}
+import "{{ . }}"
+{{- end }}
+{{- $reflect := false }}
+{{- range $field := index . "Fields" }}
+{{- if and (index $ "WithExtendedGen") $field.Override }}{{ $reflect = true }}{{ end }}
+{{- end }}
+{{- if $reflect }}
+import "reflect"
+{{- end }}
+{{- end }}
+`
+
+// tableIndexesTemplate includes a method that returns the indexes declared by
+// the schema of the table, see model.IndexedModel
+var tableIndexesTemplate = `
+{{- define "tableIndexes" }}
+{{- if index . "WithTableIndexes" }}
+{{- $structName := index . "StructName" }}
+
+// TableIndexes returns the indexes of the {{ index . "TableName" }} table, the groups of
+// columns whose values are unique to each row
+func (a *{{ $structName }}) TableIndexes() [][]string {
+	{{- with index . "Indexes" }}
+	return [][]string{
+		{{- range . }}
+		{ {{- range $i, $column := . }}{{ if $i }}, {{ end }}{{ printf "%q" $column }}{{ end }}},
+		{{- end }}
+	}
+	{{- else }}
+	return nil
+	{{- end }}
+}
+{{- end }}
+{{- end }}
+`
+
+// modelInterfaceTemplate includes the methods that give access to the UUID of
+// the row, see model.UUIDModel. The extended generation already has GetUUID.
+var modelInterfaceTemplate = `
+{{- define "modelInterface" }}
+{{- if index . "WithModelInterface" }}
+{{- $structName := index . "StructName" }}
+{{- if not (index . "WithExtendedGen") }}
+
+// GetUUID returns the UUID of the row
+func (a *{{ $structName }}) GetUUID() string {
+	return a.UUID
+}
+{{- end }}
+
+// SetUUID sets the UUID of the row
+func (a *{{ $structName }}) SetUUID(uuid string) {
+	a.UUID = uuid
+}
+{{- end }}
+{{- end }}
+`
+
+// logFieldsTemplate includes a method that returns the value of each column by
+// column name, for structured logging
+var logFieldsTemplate = `
+{{- define "logFields" }}
+{{- if index . "WithLogFields" }}
+{{- $tableName := index . "TableName" }}
+{{- $structName := index . "StructName" }}
+
+// Fields returns the value of each column by column name, e.g. to be logged by
+// a structured logger. Optional columns without value are nil. The sets and
+// maps are the ones of the {{ $structName }}, they must not be modified.
+func (a *{{ $structName }}) Fields() map[string]interface{} {
+	fields := map[string]interface{}{
+		{{- range $field := index . "Fields" }}
+		{{- if or $field.Override (ne (slice (FieldType $tableName $field.Column $field.Schema) 0 1) "*") }}
+		{{ printf "%q" $field.Column }}: a.{{ FieldName $field.Column }},
+		{{- else }}
+		{{ printf "%q" $field.Column }}: nil,
+		{{- end }}
+		{{- end }}
+	}
+	{{- range $field := index . "Fields" }}
+	{{- if and (not $field.Override) (eq (slice (FieldType $tableName $field.Column $field.Schema) 0 1) "*") }}
+	if a.{{ FieldName $field.Column }} != nil {
+		fields[{{ printf "%q" $field.Column }}] = *a.{{ FieldName $field.Column }}
+	}
+	{{- end }}
+	{{- end }}
+	return fields
+}
+{{- end }}
+{{- end }}
+`
+
+// emptyDetectionTemplate includes a method that tells the mapper which columns
+// are left out of inserted rows (see mapper.EmptyDetector)
+var emptyDetectionTemplate = `
+{{- define "emptyDetection" }}
+{{- if index . "WithEmptyDetection" }}
+{{- $structName := index . "StructName" }}
+
+// IsEmpty returns whether the field of a column holds no value: the sets and
+// maps that may be empty and have no element, and the optional values that
+// are nil. The other columns are never empty.
+func (a *{{ $structName }}) IsEmpty(column string) bool {
+	switch column {
+	{{- range $field := index . "Fields" }}
+	{{- if and (not $field.Override) (FieldEmptiable $field.Schema) }}
+	case {{ printf "%q" $field.Column }}:
+		{{- if FieldOptional $field.Schema }}
+		return a.{{ FieldName $field.Column }} == nil
+		{{- else }}
+		return len(a.{{ FieldName $field.Column }}) == 0
+		{{- end }}
+	{{- end }}
+	{{- end }}
+	}
+	return false
+}
+{{- end }}
+{{- end }}
+`
+
+// fieldAccessorsTemplate includes the GetField and SetField methods the mapper
+// uses instead of reflection to access the fields of the columns (see
+// mapper.FieldAccessor)
+var fieldAccessorsTemplate = `
+{{- define "fieldAccessors" }}
+{{- if index . "WithFieldAccessors" }}
+{{- $tableName := index . "TableName" }}
+{{- $structName := index . "StructName" }}
+
+// GetField returns the value of the field of a column, or nil if the column
+// has no field
+func (a *{{ $structName }}) GetField(column string) interface{} {
+	switch column {
+	{{- range $field := index . "Fields" }}
+	case {{ printf "%q" $field.Column }}:
+		return a.{{ FieldName $field.Column }}
+	{{- end }}
+	}
+	return nil
+}
+
+// SetField sets the field of a column to a value of the type of the field
+func (a *{{ $structName }}) SetField(column string, value interface{}) error {
+	switch column {
+	{{- range $field := index . "Fields" }}
+	{{- $type := "" }}
+	{{- if $field.Override }}
+	{{- $type = $field.Override.Type }}
+	{{- else if index $ "WithEnumTypes" }}
+	{{- $type = FieldTypeWithEnums $tableName $field.Column $field.Schema }}
+	{{- else }}
+	{{- $type = FieldType $tableName $field.Column $field.Schema }}
+	{{- end }}
+	case {{ printf "%q" $field.Column }}:
+		v, ok := value.({{ $type }})
+		if !ok {
+			return fmt.Errorf("column %s: value %v (%T) is not of type %s", column, value, value, {{ printf "%q" $type }})
+		}
+		a.{{ FieldName $field.Column }} = v
+	{{- end }}
+	default:
+		return fmt.Errorf("column %s not found in table %s", column, {{ $structName }}Table)
+	}
+	return nil
+}
+{{- end }}
+{{- end }}
+`
+
+// constructorsTemplate includes a function that returns a model with the
+// values of the required columns given as arguments, see RequiredField
+var constructorsTemplate = `
+{{- define "constructors" }}
+{{- if index . "WithConstructors" }}
+{{- $tableName := index . "TableName" }}
+{{- $structName := index . "StructName" }}
+{{- $indexes := index . "Indexes" }}
+{{- $first := true }}
+
+// New{{ $structName }} returns a {{ $structName }} with the given values of the required
+// columns of the {{ $tableName }} table, and empty sets and maps
+func New{{ $structName }}(
+	{{- range $field := index . "Fields" }}
+	{{- if RequiredField $field $indexes }}
+	{{- $type := "" }}
+	{{- if $field.Override }}
+	{{- $type = $field.Override.Type }}
+	{{- else if index $ "WithEnumTypes" }}
+	{{- $type = FieldTypeWithEnums $tableName $field.Column $field.Schema }}
+	{{- else }}
+	{{- $type = FieldType $tableName $field.Column $field.Schema }}
+	{{- end }}
+	{{- if not $first }}, {{ end }}{{ $first = false }}{{ ArgName $field.Column }} {{ $type }}
+	{{- end }}
+	{{- end -}}
+) *{{ $structName }} {
+	return &{{ $structName }}{
+		{{- range $field := index . "Fields" }}
+		{{- $type := "" }}
+		{{- if index $ "WithEnumTypes" }}
+		{{- $type = FieldTypeWithEnums $tableName $field.Column $field.Schema }}
+		{{- else }}
+		{{- $type = FieldType $tableName $field.Column $field.Schema }}
+		{{- end }}
+		{{- if RequiredField $field $indexes }}
+		{{ FieldName $field.Column }}: {{ ArgName $field.Column }},
+		{{- else if and (not $field.Override) (or (eq (slice $type 0 2) "[]") (eq (slice $type 0 3) "map")) }}
+		{{ FieldName $field.Column }}: {{ $type }}{},
+		{{- end }}
+		{{- end }}
+	}
+}
+{{- end }}
+{{- end }}
+`
+
+// accessorsTemplate includes typed accessors to the rows of the table in the
+// cache, defined as methods of the Client type generated by the DB template
+var accessorsTemplate = `
+{{- define "accessors" }}
+{{- if index . "WithAccessors" }}
+{{- $structName := index . "StructName" }}
+
+// Get{{ $structName }} returns a copy of the {{ $structName }} with the given UUID
+// from the cache, or ErrCacheMiss if it is not there
+func (c *Client) Get{{ $structName }}(uuid string) (*{{ $structName }}, error) {
+	m, err := c.cachedRow({{ $structName }}Table, uuid)
+	if err != nil {
+		return nil, err
+	}
+	return m.(*{{ $structName }}), nil
+}
+
+// List{{ $structName }} returns a copy of every {{ $structName }} in the cache,
+// sorted by UUID
+func (c *Client) List{{ $structName }}() ([]*{{ $structName }}, error) {
+	models, err := c.cachedRows({{ $structName }}Table)
+	if err != nil {
+		return nil, err
+	}
+	result := make([]*{{ $structName }}, 0, len(models))
+	for _, m := range models {
+		result = append(result, m.(*{{ $structName }}))
+	}
+	return result, nil
+}
+{{- end }}
+{{- end }}
+`
+
+// referenceAccessorsTemplate includes methods that resolve the rows referenced
+// by the reference columns of the table from the cache. They take the Client
+// type of the accessors, so the accessors of the referenced tables have to be
+// enabled too.
+var referenceAccessorsTemplate = `
+{{- define "referenceAccessors" }}
+{{- if index . "WithReferenceAccessors" }}
+{{- $tableName := index . "TableName" }}
+{{- $structName := index . "StructName" }}
+{{- range $field := index . "Fields" }}
+{{- $refTable := RefTable $field.Schema }}
+{{- if and $refTable (not $field.Override) }}
+{{- $refStruct := StructName $refTable }}
+{{- $fieldName := FieldName $field.Column }}
+{{- $type := FieldType $tableName $field.Column $field.Schema }}
+{{- if eq (slice $type 0 1) "[" }}
+
+// Resolve{{ $fieldName }} returns a copy of each {{ $refStruct }} referenced by the
+// {{ $field.Column }} column from the cache, or ErrCacheMiss if one of them is not there
+func (a *{{ $structName }}) Resolve{{ $fieldName }}(c *Client) ([]*{{ $refStruct }}, error) {
+	result := make([]*{{ $refStruct }}, 0, len(a.{{ $fieldName }}))
+	for _, uuid := range a.{{ $fieldName }} {
+		if uuid == "" {
+			continue
+		}
+		m, err := c.Get{{ $refStruct }}(uuid)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, m)
+	}
+	return result, nil
+}
+{{- else if eq (slice $type 0 1) "*" }}
+
+// Resolve{{ $fieldName }} returns a copy of the {{ $refStruct }} referenced by the
+// {{ $field.Column }} column from the cache, nil if the column is empty, or
+// ErrCacheMiss if it is not there
+func (a *{{ $structName }}) Resolve{{ $fieldName }}(c *Client) (*{{ $refStruct }}, error) {
+	if a.{{ $fieldName }} == nil {
+		return nil, nil
+	}
+	return c.Get{{ $refStruct }}(*a.{{ $fieldName }})
+}
+{{- else }}
+
+// Resolve{{ $fieldName }} returns a copy of the {{ $refStruct }} referenced by the
+// {{ $field.Column }} column from the cache, or ErrCacheMiss if it is not there
+func (a *{{ $structName }}) Resolve{{ $fieldName }}(c *Client) (*{{ $refStruct }}, error) {
+	return c.Get{{ $refStruct }}(a.{{ $fieldName }})
+}
+{{- end }}
+{{- end }}
+{{- end }}
+{{- end }}
+{{- end }}
+`
+
 // NewTableTemplate returns a new table template. It includes the following
 // other templates that can be overridden to customize the generated file:
 //
@@ -165,6 +683,8 @@ var _ model.ComparableModel = &{{ $structName }}{}
 //   - `preStructDefinitions`: deprecated in favor of `extraImports`
 //   - `extraImports`: include additional imports
 //   - `structComment`: override the comment generated for the table
+//   - `fieldComment`: override the comment generated for each field when
+//     field comments are enabled
 //   - `extraFields`: add extra fields to the table
 //   - `extraTags`: add tags to the extra fields
 //   - `deepCopyExtraFields`: copy extra fields when copying a table
@@ -180,7 +700,18 @@ var _ model.ComparableModel = &{{ $structName }}{}
 //   - `FieldName`: prints the name of a field based on its column
 //   - `FieldType`: prints the field type based on its column and schema
 //   - `FieldTypeWithEnums`: same as FieldType but with enum type expansion
+//   - `FieldOptional`: whether the column is an optional scalar
+//   - `FieldBounds`: the bounds of the number of elements of a field, if any
+//   - `MaxSetElements`: the number of elements of a set shown by String
+//   - `EnumValueName`: prints the name suffix of an enum value constant
+//   - `FieldComment`: prints the documentation of a field based on its column
+//   - `CopyKind`: how the extended generation copies a field of a given type
+//   - `RequiredField`: whether a field is required, given the table indexes
+//   - `ArgName`: prints the name of a function argument based on its column
+//   - `RefTable`: prints the table referenced by a column, if any
+//   - `StructName`: prints the name of the struct of a table
 //   - `OvsdbTag`: prints the ovsdb tag
+//   - `JSONTag`: prints the json tag
 func NewTableTemplate() *template.Template {
 	return template.Must(template.New("").Funcs(
 		template.FuncMap{
@@ -188,9 +719,21 @@ func NewTableTemplate() *template.Template {
 			"FieldName":          FieldName,
 			"FieldType":          FieldType,
 			"FieldTypeWithEnums": FieldTypeWithEnums,
+			"FieldOptional":      FieldOptional,
+			"FieldEmptiable":     FieldEmptiable,
+			"FieldBounds":        FieldBounds,
+			"CopyKind":           copyKind,
+			"MaxSetElements":     maxSetElements,
+			"EnumValueName":      EnumValueName,
+			"FieldComment":       FieldComment,
+			"RequiredField":      RequiredField,
+			"ArgName":            argName,
+			"RefTable":           RefTable,
+			"StructName":         StructName,
 			"OvsdbTag":           Tag,
+			"JSONTag":            JSONTag,
 		},
-	).Parse(extendedGenTemplate + `
+	).Parse(extendedGenTemplate + defaultsTemplate + validationTemplate + stringerTemplate + methodImportsTemplate + overrideImportsTemplate + tableIndexesTemplate + modelInterfaceTemplate + logFieldsTemplate + emptyDetectionTemplate + fieldAccessorsTemplate + constructorsTemplate + accessorsTemplate + referenceAccessorsTemplate + `
 {{- define "header" }}
 // Code generated by "libovsdb.modelgen"
 // DO NOT EDIT.
@@ -203,6 +746,8 @@ func NewTableTemplate() *template.Template {
 {{- define "showTableName" }}
 const {{ index . "StructName" }}Table = "{{ index . "TableName" }}"
 {{- end }}
+{{- define "fieldComment" }}	// {{ FieldComment .Column .Schema }}
+{{ end }}
 {{ define "extraTags" }}{{ end }}
 {{ define "extraFields" }}{{ end }}
 {{ define "extraDefinitions" }}{{ end }}
@@ -221,7 +766,7 @@ var (
 {{ range  index . "Enums" }}
 {{- $e := . }}
 {{- range .Sets }}
-{{ $e.Alias }}{{ FieldName . }} {{ $e.Alias }} = {{ PrintVal . $e.Type }}
+{{ $e.Alias }}{{ EnumValueName . }} {{ $e.Alias }} = {{ PrintVal . $e.Type }}
 {{- end }}
 {{- end }}
 )
@@ -230,6 +775,8 @@ var (
 {{- end }}
 package {{ index . "PackageName" }}
 {{ template "extendedGenImports" . }}
+{{ template "methodImports" . }}
+{{ template "overrideImports" . }}
 {{ template "extraImports" . }}
 {{ template "preStructDefinitions" . }}
 {{ template "showTableName" . }}
@@ -238,10 +785,12 @@ package {{ index . "PackageName" }}
 type {{ index . "StructName" }} struct {
 {{- $tableName := index . "TableName" }}
 {{ if index . "WithEnumTypes" }}
-{{ range $field := index . "Fields" }}	{{ FieldName $field.Column }}  {{ FieldTypeWithEnums $tableName $field.Column $field.Schema }} ` + "`" + `{{ OvsdbTag $field.Column }}{{ template "extraTags" . }}` + "`" + `
+{{- range $field := index . "Fields" }}
+{{- if index $ "WithFieldComments" }}{{ template "fieldComment" $field }}{{ end }}	{{ FieldName $field.Column }}  {{ with $field.Override }}{{ .Type }}{{ else }}{{ FieldTypeWithEnums $tableName $field.Column $field.Schema }}{{ end }} ` + "`" + `{{ OvsdbTag $field.Column }}{{ if index $ "WithJSONTags" }} {{ JSONTag $field.Column }}{{ end }}{{ template "extraTags" . }}` + "`" + `
 {{ end }}
 {{ else }}
-{{ range  $field := index . "Fields" }}	{{ FieldName $field.Column }}  {{ FieldType $tableName $field.Column $field.Schema }} ` + "`" + `{{ OvsdbTag $field.Column }}{{ template "extraTags" . }}` + "`" + `
+{{- range  $field := index . "Fields" }}
+{{- if index $ "WithFieldComments" }}{{ template "fieldComment" $field }}{{ end }}	{{ FieldName $field.Column }}  {{ with $field.Override }}{{ .Type }}{{ else }}{{ FieldType $tableName $field.Column $field.Schema }}{{ end }} ` + "`" + `{{ OvsdbTag $field.Column }}{{ if index $ "WithJSONTags" }} {{ JSONTag $field.Column }}{{ end }}{{ template "extraTags" . }}` + "`" + `
 {{ end }}
 {{ end }}
 {{ template "extraFields" . }}
@@ -249,11 +798,23 @@ type {{ index . "StructName" }} struct {
 {{ template "postStructDefinitions" . }}
 {{ template "extraDefinitions" . }}
 {{ template "extendedGen" . }}
+{{ template "defaults" . }}
+{{ template "validation" . }}
+{{ template "stringer" . }}
+{{ template "tableIndexes" . }}
+{{ template "modelInterface" . }}
+{{ template "logFields" . }}
+{{ template "emptyDetection" . }}
+{{ template "fieldAccessors" . }}
+{{ template "constructors" . }}
+{{ template "accessors" . }}
+{{ template "referenceAccessors" . }}
 `))
 }
 
 // Enum represents the enum schema type
 type Enum struct {
+	// Type is the native type of the enum values
 	Type  string
 	Alias string
 	Sets  []interface{}
@@ -263,6 +824,22 @@ type Enum struct {
 type Field struct {
 	Column string
 	Schema *ovsdb.ColumnSchema
+	// Override is the type of the field when it is not the native type of
+	// the column, see TableTemplateData.WithTypeOverride
+	Override *TypeOverride
+}
+
+// TypeOverride is a Go type used for a field instead of the native type of its
+// column, like net.IP for a column of IP addresses
+type TypeOverride struct {
+	// Type is the qualified Go type, e.g. net.IP
+	Type string
+	// ImportPath is the path of the package of the type, e.g. net. It is
+	// empty for predeclared types.
+	ImportPath string
+	// kind is reflect.Ptr, reflect.Slice or reflect.Map for the types the
+	// extended generation has to copy, see overrideKind
+	kind reflect.Kind
 }
 
 // TableTemplateData represents the data used by the Table Template
@@ -281,6 +858,226 @@ func (t TableTemplateData) WithExtendedGen(val bool) {
 	t["WithExtendedGen"] = val
 }
 
+// WithDefaults configures whether the Template should generate a SetDefaults
+// method that initializes nil set and map fields to empty ones
+func (t TableTemplateData) WithDefaults(val bool) {
+	t["WithDefaults"] = val
+}
+
+// WithValidation configures whether the Template should generate a Validate
+// method that checks the number of elements of set and map fields
+func (t TableTemplateData) WithValidation(val bool) {
+	t["WithValidation"] = val
+}
+
+// WithStringer configures whether the Template should generate a String
+// method that renders the model readably, with sorted sets and maps
+func (t TableTemplateData) WithStringer(val bool) {
+	t["WithStringer"] = val
+}
+
+// WithAccessors configures whether the Template should generate typed Get and
+// List accessors to the rows of the table in the cache. They are methods of the
+// Client type generated by the DB template, so it has to be enabled there too
+// (see DBTemplateData.WithAccessors).
+func (t TableTemplateData) WithAccessors(val bool) {
+	t["WithAccessors"] = val
+}
+
+// WithReferenceAccessors configures whether the Template should generate a
+// Resolve method for each column that references the rows of another table,
+// which resolves them from the cache, e.g. ResolvePorts for the ports column.
+// Sets of references return a slice of models. The methods take the Client
+// type of the accessors, which have to be enabled for the referenced tables too
+// (see WithAccessors).
+func (t TableTemplateData) WithReferenceAccessors(val bool) {
+	t["WithReferenceAccessors"] = val
+}
+
+// WithTableIndexes configures whether the Template should generate a
+// TableIndexes method that returns the indexes declared by the schema of the
+// table, which the cache uses to index the rows (see model.IndexedModel)
+func (t TableTemplateData) WithTableIndexes(val bool) {
+	t["WithTableIndexes"] = val
+}
+
+// WithModelInterface configures whether the Template should generate the
+// GetUUID and SetUUID methods, so that the models of all the tables implement
+// model.UUIDModel. With the extended generation, GetUUID is its getter.
+func (t TableTemplateData) WithModelInterface(val bool) {
+	t["WithModelInterface"] = val
+}
+
+// WithLogFields configures whether the Template should generate a Fields
+// method that returns the value of each column by column name, to be logged
+// by a structured logger without reflection
+func (t TableTemplateData) WithLogFields(val bool) {
+	t["WithLogFields"] = val
+}
+
+// WithEmptyDetection configures whether the Template should generate an
+// IsEmpty method that tells which columns hold no value, see FieldEmptiable.
+// The mapper leaves them out of inserted rows and sends the other columns even
+// if they hold a zero value (see mapper.EmptyDetector).
+func (t TableTemplateData) WithEmptyDetection(val bool) {
+	t["WithEmptyDetection"] = val
+}
+
+// WithFieldAccessors configures whether the Template should generate the
+// GetField and SetField methods the mapper uses to access the fields of the
+// columns without reflection (see mapper.FieldAccessor)
+func (t TableTemplateData) WithFieldAccessors(val bool) {
+	t["WithFieldAccessors"] = val
+}
+
+// WithConstructors configures whether the Template should generate a New
+// function that takes the values of the required columns of the table, see
+// RequiredField, and initializes the other set and map fields to empty ones
+func (t TableTemplateData) WithConstructors(val bool) {
+	t["WithConstructors"] = val
+}
+
+// WithJSONTags configures whether the Template should add a json tag with the
+// name of the column next to the ovsdb tag of each field
+func (t TableTemplateData) WithJSONTags(val bool) {
+	t["WithJSONTags"] = val
+}
+
+// WithFieldComments configures whether the Template should document each
+// field with the column name, its OVSDB type and its properties
+func (t TableTemplateData) WithFieldComments(val bool) {
+	t["WithFieldComments"] = val
+}
+
+// WithImportPath configures the import path of the libovsdb module the
+// generated code imports, e.g. when using a fork of it
+func (t TableTemplateData) WithImportPath(path string) {
+	t["ImportPath"] = path
+}
+
+// WithTypeOverride configures the Template to use the given Go type for the
+// field of a column instead of its native type. The generated file imports the
+// package of the type, if any. The type must have a mapper.Codec registered,
+// see mapper.RegisterCodec, which converts it from and to the native type.
+// The extended generation copies overridden fields according to the
+// underlying type of the Go type, which is looked up in the package of the
+// type, and compares them with reflect.DeepEqual. Types declared in the package
+// of the models cannot be looked up and are copied by assignment. Overridden
+// fields are left out of the defaults and validation.
+func (t TableTemplateData) WithTypeOverride(column, goType, importPath string) error {
+	kind, err := overrideKind(goType, importPath)
+	if err != nil {
+		return fmt.Errorf("cannot override the type of column %s of table %s: %w", column, t["TableName"], err)
+	}
+	fields, _ := t["Fields"].([]Field)
+	found := false
+	overridden := make([]Field, len(fields))
+	imports := []string{}
+	for i, field := range fields {
+		if field.Column == column {
+			field.Override = &TypeOverride{Type: goType, ImportPath: importPath, kind: kind}
+			found = true
+		}
+		if field.Override != nil && field.Override.ImportPath != "" {
+			imports = append(imports, field.Override.ImportPath)
+		}
+		overridden[i] = field
+	}
+	if !found {
+		return fmt.Errorf("cannot override the type of column %s of table %s: column not found", column, t["TableName"])
+	}
+	sort.Strings(imports)
+	unique := imports[:0]
+	for i, path := range imports {
+		if i == 0 || path != imports[i-1] {
+			unique = append(unique, path)
+		}
+	}
+	t["Fields"] = overridden
+	t["OverrideImports"] = unique
+	return nil
+}
+
+// overrideImporter imports the packages of the types of the overridden fields
+// from their source, see overrideKind
+var overrideImporter struct {
+	sync.Mutex
+	types.ImporterFrom
+}
+
+// overrideKind returns the kind of the underlying type of a Go type when the
+// extended generation has to copy its values rather than assign them:
+// reflect.Ptr, reflect.Slice or reflect.Map. It returns reflect.Invalid for the
+// other types, and for the types declared in the package of the models.
+func overrideKind(goType, importPath string) (reflect.Kind, error) {
+	expr, err := parser.ParseExpr(goType)
+	if err != nil {
+		return reflect.Invalid, fmt.Errorf("invalid type %s: %w", goType, err)
+	}
+	switch e := expr.(type) {
+	case *ast.StarExpr:
+		return reflect.Ptr, nil
+	case *ast.ArrayType:
+		if e.Len == nil {
+			return reflect.Slice, nil
+		}
+	case *ast.MapType:
+		return reflect.Map, nil
+	case *ast.SelectorExpr:
+		if importPath == "" {
+			return reflect.Invalid, fmt.Errorf("type %s has no import path", goType)
+		}
+		overrideImporter.Lock()
+		defer overrideImporter.Unlock()
+		if overrideImporter.ImporterFrom == nil {
+			overrideImporter.ImporterFrom = importer.ForCompiler(token.NewFileSet(), "source", nil).(types.ImporterFrom)
+		}
+		pkg, err := overrideImporter.ImportFrom(importPath, ".", 0)
+		if err != nil {
+			return reflect.Invalid, fmt.Errorf("cannot import the package of type %s: %w", goType, err)
+		}
+		obj, ok := pkg.Scope().Lookup(e.Sel.Name).(*types.TypeName)
+		if !ok {
+			return reflect.Invalid, fmt.Errorf("package %s has no type %s", importPath, e.Sel.Name)
+		}
+		switch obj.Type().Underlying().(type) {
+		case *types.Pointer:
+			return reflect.Ptr, nil
+		case *types.Slice:
+			return reflect.Slice, nil
+		case *types.Map:
+			return reflect.Map, nil
+		}
+	}
+	return reflect.Invalid, nil
+}
+
+// copyKind returns how the extended generation copies a field of the given Go
+// type: "pointer", "slice" or "map", or an empty string when the field is
+// copied by assignment
+func copyKind(goType string, override *TypeOverride) string {
+	kind := reflect.Invalid
+	switch {
+	case override != nil:
+		kind = override.kind
+	case strings.HasPrefix(goType, "*"):
+		kind = reflect.Ptr
+	case strings.HasPrefix(goType, "[]"):
+		kind = reflect.Slice
+	case strings.HasPrefix(goType, "map"):
+		kind = reflect.Map
+	}
+	switch kind {
+	case reflect.Ptr:
+		return "pointer"
+	case reflect.Slice:
+		return "slice"
+	case reflect.Map:
+		return "map"
+	}
+	return ""
+}
+
 // GetTableTemplateData returns the TableTemplateData map. It has the following
 // keys:
 //
@@ -288,6 +1085,7 @@ func (t TableTemplateData) WithExtendedGen(val bool) {
 //   - `TPackageName`: (string) the package name
 //   - `TStructName`: (string) the struct name
 //   - `TFields`: []Field a list of Fields that the struct has
+//   - `Indexes`: [][]string the indexes declared by the schema of the table
 func GetTableTemplateData(pkg, name string, table *ovsdb.TableSchema) TableTemplateData {
 	data := map[string]interface{}{}
 	data["TableName"] = name
@@ -318,12 +1116,72 @@ func GetTableTemplateData(pkg, name string, table *ovsdb.TableSchema) TableTempl
 	data["Enums"] = Enums
 	data["WithEnumTypes"] = true
 	data["WithExtendedGen"] = false
+	data["WithFieldComments"] = false
+	data["WithJSONTags"] = false
+	data["WithDefaults"] = false
+	data["WithValidation"] = false
+	data["WithStringer"] = false
+	data["WithAccessors"] = false
+	data["WithReferenceAccessors"] = false
+	data["WithTableIndexes"] = false
+	data["WithFieldAccessors"] = false
+	data["WithModelInterface"] = false
+	data["WithLogFields"] = false
+	data["WithEmptyDetection"] = false
+	data["WithConstructors"] = false
+	data["Indexes"] = table.Indexes
+	data["ImportPath"] = DefaultImportPath
+	data["OverrideImports"] = []string{}
 	return data
 }
 
 // FieldName returns the name of a column field
 func FieldName(column string) string {
-	return camelCase(strings.Trim(column, "_"))
+	return defaultNamer.fieldName(column)
+}
+
+func (n namer) fieldName(column string) string {
+	return n.camelCase(strings.Trim(column, "_"))
+}
+
+// argName returns the name of a function argument that takes the value of a
+// column: its field name with the leading initialism, if any, in lower case,
+// and a Value suffix if that is a keyword or a predeclared identifier
+func argName(column string) string {
+	return defaultNamer.argName(column)
+}
+
+func (n namer) argName(column string) string {
+	runes := []rune(n.fieldName(column))
+	for i := range runes {
+		if i > 0 && (!unicode.IsUpper(runes[i]) || (i+1 < len(runes) && !unicode.IsUpper(runes[i+1]))) {
+			break
+		}
+		runes[i] = unicode.ToLower(runes[i])
+	}
+	name := string(runes)
+	if token.IsKeyword(name) || types.Universe.Lookup(name) != nil {
+		name += "Value"
+	}
+	return name
+}
+
+// RequiredField returns whether the value of a field has to be given to the
+// constructor of a model: the columns of the indexes of the table, which are
+// unique to each row, and the set and map columns that cannot be empty
+func RequiredField(field Field, indexes [][]string) bool {
+	for _, index := range indexes {
+		for _, column := range index {
+			if column == field.Column {
+				return true
+			}
+		}
+	}
+	if field.Override != nil {
+		return false
+	}
+	bounds := FieldBounds(field.Schema)
+	return bounds != nil && bounds.Min > 0
 }
 
 // StructName returns the name of the table struct
@@ -331,11 +1189,11 @@ func StructName(tableName string) string {
 	return cases.Title(language.Und, cases.NoLower).String(strings.ReplaceAll(tableName, "_", ""))
 }
 
-func fieldType(tableName, columnName string, column *ovsdb.ColumnSchema, enumTypes bool) string {
+func (n namer) fieldType(tableName, columnName string, column *ovsdb.ColumnSchema, enumTypes bool) string {
 	switch column.Type {
 	case ovsdb.TypeEnum:
 		if enumTypes {
-			return enumName(tableName, columnName)
+			return n.enumName(tableName, columnName)
 		}
 		return AtomicType(column.TypeObj.Key.Type)
 	case ovsdb.TypeMap:
@@ -343,29 +1201,29 @@ func fieldType(tableName, columnName string, column *ovsdb.ColumnSchema, enumTyp
 			AtomicType(column.TypeObj.Value.Type))
 	case ovsdb.TypeSet:
 		// optional with max 1 element
-		if column.TypeObj.Min() == 0 && column.TypeObj.Max() == 1 {
+		if FieldOptional(column) {
 			if enumTypes && FieldEnum(tableName, columnName, column) != nil {
-				return fmt.Sprintf("*%s", enumName(tableName, columnName))
+				return fmt.Sprintf("*%s", n.enumName(tableName, columnName))
 			}
 			return fmt.Sprintf("*%s", AtomicType(column.TypeObj.Key.Type))
 		}
 		// required, max 1 element
 		if column.TypeObj.Min() == 1 && column.TypeObj.Max() == 1 {
 			if enumTypes && FieldEnum(tableName, columnName, column) != nil {
-				return enumName(tableName, columnName)
+				return n.enumName(tableName, columnName)
 			}
 			return AtomicType(column.TypeObj.Key.Type)
 		}
 		// use array for columns with max > 1
 		if column.TypeObj.Max() > 1 {
 			if enumTypes && FieldEnum(tableName, columnName, column) != nil {
-				return fmt.Sprintf("[%d]%s", column.TypeObj.Max(), enumName(tableName, columnName))
+				return fmt.Sprintf("[%d]%s", column.TypeObj.Max(), n.enumName(tableName, columnName))
 			}
 			return fmt.Sprintf("[%d]%s", column.TypeObj.Max(), AtomicType(column.TypeObj.Key.Type))
 		}
 		// use a slice
 		if enumTypes && FieldEnum(tableName, columnName, column) != nil {
-			return fmt.Sprintf("[]%s", enumName(tableName, columnName))
+			return fmt.Sprintf("[]%s", n.enumName(tableName, columnName))
 		}
 		return fmt.Sprintf("[]%s", AtomicType(column.TypeObj.Key.Type))
 	default:
@@ -373,34 +1231,171 @@ func fieldType(tableName, columnName string, column *ovsdb.ColumnSchema, enumTyp
 	}
 }
 
-// EnumName returns the name of the enum field
-func enumName(tableName, columnName string) string {
-	return cases.Title(language.Und, cases.NoLower).String(StructName(tableName)) + camelCase(columnName)
+// RefTable returns the table referenced by the elements of a set or atomic
+// column of UUIDs, or an empty string if the column holds no references
+func RefTable(column *ovsdb.ColumnSchema) string {
+	if column.Type == ovsdb.TypeMap || column.TypeObj == nil || column.TypeObj.Key == nil ||
+		column.TypeObj.Key.Type != ovsdb.TypeUUID {
+		return ""
+	}
+	refTable, _ := column.TypeObj.Key.RefTable()
+	return refTable
+}
+
+// FieldOptional returns whether the column holds an optional scalar, that is, a
+// set with at most one element that may be empty. Such columns are generated as
+// pointers so that a nil value can be told apart from the zero value
+func FieldOptional(column *ovsdb.ColumnSchema) bool {
+	return column.Type == ovsdb.TypeSet && column.TypeObj != nil &&
+		column.TypeObj.Min() == 0 && column.TypeObj.Max() == 1
+}
+
+// FieldEmptiable returns whether the column may hold no value, that is, a set
+// or map that may be empty and is not generated as an array, which always
+// holds its maximum number of elements
+func FieldEmptiable(column *ovsdb.ColumnSchema) bool {
+	if column.TypeObj == nil || column.TypeObj.Min() > 0 {
+		return false
+	}
+	switch column.Type {
+	case ovsdb.TypeSet:
+		return FieldOptional(column) || ovsdb.NativeType(column).Kind() == reflect.Slice
+	case ovsdb.TypeMap:
+		return true
+	}
+	return false
+}
+
+// MaxStringSetElements is the number of elements of a set that the generated
+// String method shows, the remaining ones are only counted
+const MaxStringSetElements = 10
+
+func maxSetElements() int {
+	return MaxStringSetElements
+}
+
+// Bounds are the minimum and maximum number of elements of a field. A maximum
+// of ovsdb.Unlimited means there is none
+type Bounds struct {
+	Min int
+	Max int
+}
+
+// FieldBounds returns the bounds of the number of elements of a set or map
+// field, or nil if any number of elements the field can hold is valid. Sets
+// with a limited maximum are generated as arrays, which always hold that many
+// elements, so only the minimum of slices is checked
+func FieldBounds(column *ovsdb.ColumnSchema) *Bounds {
+	if column.TypeObj == nil {
+		return nil
+	}
+	min, max := column.TypeObj.Min(), column.TypeObj.Max()
+	switch column.Type {
+	case ovsdb.TypeSet:
+		if ovsdb.NativeType(column).Kind() != reflect.Slice || min == 0 {
+			return nil
+		}
+		return &Bounds{Min: min, Max: ovsdb.Unlimited}
+	case ovsdb.TypeMap:
+		if min == 0 && max == ovsdb.Unlimited {
+			return nil
+		}
+		return &Bounds{Min: min, Max: max}
+	}
+	return nil
+}
+
+// FieldComment returns the documentation of a column field: the column name,
+// its OVSDB type as found in the schema and whether it is optional, ephemeral
+// or immutable
+func FieldComment(column string, schema *ovsdb.ColumnSchema) string {
+	return defaultNamer.fieldComment(column, schema)
+}
+
+func (n namer) fieldComment(column string, schema *ovsdb.ColumnSchema) string {
+	typeStr := string(schema.Type)
+	if schema.TypeObj != nil {
+		if b, err := json.Marshal(schema.TypeObj); err == nil {
+			typeStr = string(b)
+		}
+	}
+	comment := fmt.Sprintf("%s is the %q column of type %s", n.fieldName(column), column, typeStr)
+
+	var notes []string
+	if schema.TypeObj != nil && schema.TypeObj.Min() == 0 {
+		notes = append(notes, "optional")
+	}
+	if schema.Ephemeral() {
+		notes = append(notes, "ephemeral")
+	}
+	if !schema.Mutable() {
+		notes = append(notes, "immutable")
+	}
+	if len(notes) > 0 {
+		comment += fmt.Sprintf(" (%s)", strings.Join(notes, ", "))
+	}
+	return comment
+}
+
+// enumName returns the name of the enum field
+func (n namer) enumName(tableName, columnName string) string {
+	return cases.Title(language.Und, cases.NoLower).String(StructName(tableName)) + n.camelCase(columnName)
 }
 
 // FieldType returns the string representation of a column type without enum types expansion
 func FieldType(tableName, columnName string, column *ovsdb.ColumnSchema) string {
-	return fieldType(tableName, columnName, column, false)
+	return defaultNamer.fieldType(tableName, columnName, column, false)
 }
 
 // FieldTypeWithEnums returns the string representation of a column type where Enums
 // are expanded into their own types
 func FieldTypeWithEnums(tableName, columnName string, column *ovsdb.ColumnSchema) string {
-	return fieldType(tableName, columnName, column, true)
+	return defaultNamer.fieldType(tableName, columnName, column, true)
 }
 
 // FieldEnum returns the Enum if the column is an enum type
 func FieldEnum(tableName, columnName string, column *ovsdb.ColumnSchema) *Enum {
+	return defaultNamer.fieldEnum(tableName, columnName, column)
+}
+
+func (n namer) fieldEnum(tableName, columnName string, column *ovsdb.ColumnSchema) *Enum {
 	if column.TypeObj == nil || column.TypeObj.Key.Enum == nil {
 		return nil
 	}
 	return &Enum{
-		Type:  column.TypeObj.Key.Type,
-		Alias: enumName(tableName, columnName),
+		Type:  AtomicType(column.TypeObj.Key.Type),
+		Alias: n.enumName(tableName, columnName),
 		Sets:  column.TypeObj.Key.Enum,
 	}
 }
 
+// EnumValueName returns the name used for the constant of an enum value, which
+// is prefixed by the enum type name. Characters that are not valid in Go
+// identifiers act as word separators, and negative numbers are prefixed by
+// Minus so that they do not share the name of their absolute value
+func EnumValueName(value interface{}) string {
+	return defaultNamer.enumValueName(value)
+}
+
+func (n namer) enumValueName(value interface{}) string {
+	var name string
+	switch v := value.(type) {
+	case string:
+		name = v
+	case float64:
+		name = strconv.FormatFloat(v, 'f', -1, 64)
+		if v < 0 {
+			name = "minus_" + strconv.FormatFloat(-v, 'f', -1, 64)
+		}
+	default:
+		name = fmt.Sprintf("%v", v)
+	}
+	parts := strings.FieldsFunc(name, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	return n.camelCase(strings.Join(parts, "_"))
+}
+
 // AtomicType returns the string type of an AtomicType
 func AtomicType(atype string) string {
 	switch atype {
@@ -423,6 +1418,13 @@ func Tag(column string) string {
 	return fmt.Sprintf("ovsdb:\"%s\"", column)
 }
 
+// JSONTag returns the json tag of a column. It is named after the column
+// without leading and trailing underscores, so _uuid becomes uuid, and empty
+// values are omitted.
+func JSONTag(column string) string {
+	return fmt.Sprintf("json:\"%s,omitempty\"", strings.Trim(column, "_"))
+}
+
 // FileName returns the filename of a table
 func FileName(table string) string {
 	return fmt.Sprintf("%s.go", strings.ToLower(table))
@@ -459,9 +1461,53 @@ var initialisms = map[string]bool{
 	"SNAT":  true,
 	"ICMP":  true,
 	"SLB":   true,
+	"NAT":   true,
+	"URL":   true,
+	"HTTP":  true,
+}
+
+// namer names the fields, enums and enum values generated for the columns,
+// spelling the initialisms it knows in upper case
+type namer struct {
+	initialisms map[string]bool
+}
+
+// defaultNamer knows the common initialisms
+var defaultNamer = namer{initialisms: initialisms}
+
+// withInitialisms returns a namer that also knows the given initialisms
+func (n namer) withInitialisms(words []string) namer {
+	known := make(map[string]bool, len(n.initialisms)+len(words))
+	for word := range n.initialisms {
+		known[word] = true
+	}
+	for _, word := range words {
+		known[strings.ToUpper(word)] = true
+	}
+	return namer{initialisms: known}
+}
+
+// funcs returns the functions of the table template that name identifiers
+func (n namer) funcs() template.FuncMap {
+	return template.FuncMap{
+		"FieldName": n.fieldName,
+		"FieldType": func(tableName, columnName string, column *ovsdb.ColumnSchema) string {
+			return n.fieldType(tableName, columnName, column, false)
+		},
+		"FieldTypeWithEnums": func(tableName, columnName string, column *ovsdb.ColumnSchema) string {
+			return n.fieldType(tableName, columnName, column, true)
+		},
+		"EnumValueName": n.enumValueName,
+		"FieldComment":  n.fieldComment,
+		"ArgName":       n.argName,
+	}
 }
 
 func camelCase(field string) string {
+	return defaultNamer.camelCase(field)
+}
+
+func (n namer) camelCase(field string) string {
 	s := strings.ToLower(field)
 	parts := strings.FieldsFunc(s, func(r rune) bool {
 		return r == '_' || r == '-'
@@ -469,23 +1515,23 @@ func camelCase(field string) string {
 	if len(parts) > 1 {
 		s = ""
 		for _, p := range parts {
-			s += cases.Title(language.Und, cases.NoLower).String(expandInitilaisms(p))
+			s += cases.Title(language.Und, cases.NoLower).String(n.expandInitialisms(p))
 		}
 	} else {
-		s = cases.Title(language.Und, cases.NoLower).String(expandInitilaisms(s))
+		s = cases.Title(language.Und, cases.NoLower).String(n.expandInitialisms(s))
 	}
 	return s
 }
 
-func expandInitilaisms(s string) string {
+func (n namer) expandInitialisms(s string) string {
 	// check initialisms
-	if u := strings.ToUpper(s); initialisms[u] {
+	if u := strings.ToUpper(s); n.initialisms[u] {
 		return strings.ToUpper(s)
 	}
 	// check for plurals too
 	if strings.HasSuffix(s, "s") {
 		sub := s[:len(s)-1]
-		if u := strings.ToUpper(sub); initialisms[u] {
+		if u := strings.ToUpper(sub); n.initialisms[u] {
 			return strings.ToUpper(sub) + "s"
 		}
 	}
@@ -495,6 +1541,10 @@ func expandInitilaisms(s string) string {
 func printVal(v interface{}, t string) string {
 	switch t {
 	case "int":
+		// numbers are decoded from the schema as float64
+		if f, ok := v.(float64); ok {
+			return fmt.Sprintf(`%d`, int(f))
+		}
 		return fmt.Sprintf(`%d`, v)
 	case "float64":
 		return fmt.Sprintf(`%f`, v)
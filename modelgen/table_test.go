@@ -3,6 +3,14 @@ package modelgen
 import (
 	"encoding/json"
 	"fmt"
+	"go/ast"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"os"
+	"os/exec"
+	"path/filepath"
 	"reflect"
 	"testing"
 	"text/template"
@@ -251,6 +259,25 @@ func (a *AtomicTable) CloneModel() model.Model {
 	return a.DeepCopy()
 }
 
+func (a *AtomicTable) CloneModelColumns(columns []string) model.Model {
+	b := &AtomicTable{UUID: a.UUID}
+	for _, column := range columns {
+		switch column {
+		case "event_type":
+			b.EventType = a.EventType
+		case "float":
+			b.Float = a.Float
+		case "int":
+			b.Int = a.Int
+		case "protocol":
+			b.Protocol = copyAtomicTableProtocol(a.Protocol)
+		case "str":
+			b.Str = a.Str
+		}
+	}
+	return b
+}
+
 func (a *AtomicTable) Equals(b *AtomicTable) bool {
 	return a.UUID == b.UUID &&
 		a.EventType == b.EventType &&
@@ -266,6 +293,7 @@ func (a *AtomicTable) EqualsModel(b model.Model) bool {
 }
 
 var _ model.CloneableModel = &AtomicTable{}
+var _ model.ColumnCloneableModel = &AtomicTable{}
 var _ model.ComparableModel = &AtomicTable{}
 `,
 		},
@@ -439,6 +467,25 @@ func (a *AtomicTable) CloneModel() model.Model {
 	return a.DeepCopy()
 }
 
+func (a *AtomicTable) CloneModelColumns(columns []string) model.Model {
+	b := &AtomicTable{UUID: a.UUID}
+	for _, column := range columns {
+		switch column {
+		case "event_type":
+			b.EventType = a.EventType
+		case "float":
+			b.Float = a.Float
+		case "int":
+			b.Int = a.Int
+		case "protocol":
+			b.Protocol = copyAtomicTableProtocol(a.Protocol)
+		case "str":
+			b.Str = a.Str
+		}
+	}
+	return b
+}
+
 func (a *AtomicTable) Equals(b *AtomicTable) bool {
 	return a.UUID == b.UUID &&
 		a.EventType == b.EventType &&
@@ -455,6 +502,7 @@ func (a *AtomicTable) EqualsModel(b model.Model) bool {
 }
 
 var _ model.CloneableModel = &AtomicTable{}
+var _ model.ColumnCloneableModel = &AtomicTable{}
 var _ model.ComparableModel = &AtomicTable{}
 `,
 		},
@@ -545,6 +593,25 @@ func (a *AtomicTable) CloneModel() model.Model {
 	return a.DeepCopy()
 }
 
+func (a *AtomicTable) CloneModelColumns(columns []string) model.Model {
+	b := &AtomicTable{UUID: a.UUID}
+	for _, column := range columns {
+		switch column {
+		case "event_type":
+			b.EventType = a.EventType
+		case "float":
+			b.Float = a.Float
+		case "int":
+			b.Int = a.Int
+		case "protocol":
+			b.Protocol = copyAtomicTableProtocol(a.Protocol)
+		case "str":
+			b.Str = a.Str
+		}
+	}
+	return b
+}
+
 func (a *AtomicTable) Equals(b *AtomicTable) bool {
 	return a.UUID == b.UUID &&
 		a.EventType == b.EventType &&
@@ -560,6 +627,7 @@ func (a *AtomicTable) EqualsModel(b model.Model) bool {
 }
 
 var _ model.CloneableModel = &AtomicTable{}
+var _ model.ColumnCloneableModel = &AtomicTable{}
 var _ model.ComparableModel = &AtomicTable{}
 `,
 		},
@@ -665,6 +733,251 @@ WRONG FORMAT
 	}
 }
 
+func TestNewTableTemplateEnums(t *testing.T) {
+	rawSchema := []byte(`
+	{
+		"name": "EnumDB",
+		"version": "0.0.0",
+		"tables": {
+			"First_Table": {
+				"columns": {
+					"mode": {"type": {"key": {"type": "string",
+											  "enum": ["set", ["active-backup", "balance.tcp"]]}}},
+					"level": {"type": {"key": {"type": "integer",
+											   "enum": ["set", [1, 2]]}}}
+				}
+			},
+			"Second_Table": {
+				"columns": {
+					"mode": {"type": {"key": {"type": "string",
+											  "enum": ["set", ["active-backup"]]}}}
+				}
+			}
+		}
+	}`)
+	var schema ovsdb.DatabaseSchema
+	err := json.Unmarshal(rawSchema, &schema)
+	require.NoError(t, err)
+
+	expected := map[string]string{
+		"First_Table": `// Code generated by "libovsdb.modelgen"
+// DO NOT EDIT.
+
+package test
+
+const FirstTableTable = "First_Table"
+
+type (
+	FirstTableLevel = int
+	FirstTableMode  = string
+)
+
+var (
+	FirstTableLevel1           FirstTableLevel = 1
+	FirstTableLevel2           FirstTableLevel = 2
+	FirstTableModeActiveBackup FirstTableMode  = "active-backup"
+	FirstTableModeBalanceTCP   FirstTableMode  = "balance.tcp"
+)
+
+// FirstTable defines an object in First_Table table
+type FirstTable struct {
+	UUID  string          ` + "`" + `ovsdb:"_uuid"` + "`" + `
+	Level FirstTableLevel ` + "`" + `ovsdb:"level"` + "`" + `
+	Mode  FirstTableMode  ` + "`" + `ovsdb:"mode"` + "`" + `
+}
+`,
+		"Second_Table": `// Code generated by "libovsdb.modelgen"
+// DO NOT EDIT.
+
+package test
+
+const SecondTableTable = "Second_Table"
+
+type (
+	SecondTableMode = string
+)
+
+var (
+	SecondTableModeActiveBackup SecondTableMode = "active-backup"
+)
+
+// SecondTable defines an object in Second_Table table
+type SecondTable struct {
+	UUID string          ` + "`" + `ovsdb:"_uuid"` + "`" + `
+	Mode SecondTableMode ` + "`" + `ovsdb:"mode"` + "`" + `
+}
+`,
+	}
+
+	for tableName, want := range expected {
+		t.Run(tableName, func(t *testing.T) {
+			table := schema.Tables[tableName]
+			data := GetTableTemplateData("test", tableName, &table)
+			g, err := NewGenerator()
+			require.NoError(t, err)
+			b, err := g.Format(NewTableTemplate(), data)
+			require.NoError(t, err)
+			assert.Equal(t, want, string(b))
+		})
+	}
+}
+
+func TestNewTableTemplateDeepCopy(t *testing.T) {
+	rawSchema := []byte(`
+	{
+		"columns": {
+			"name": {"type": "string"},
+			"ports": {"type": {"key": {"type": "uuid"}, "min": 0, "max": "unlimited"}},
+			"vlans": {"type": {"key": {"type": "integer"}, "min": 0, "max": 2}},
+			"external_ids": {"type": {"key": "string", "value": "string", "min": 0, "max": "unlimited"}}
+		}
+	}`)
+	var table ovsdb.TableSchema
+	err := json.Unmarshal(rawSchema, &table)
+	require.NoError(t, err)
+
+	data := GetTableTemplateData("test", "Bridge", &table)
+	data.WithExtendedGen(true)
+	g, err := NewGenerator()
+	require.NoError(t, err)
+	b, err := g.Format(NewTableTemplate(), data)
+	require.NoError(t, err)
+
+	assert.Contains(t, string(b), `func copyBridgePorts(a []string) []string {
+	if a == nil {
+		return nil
+	}
+	b := make([]string, len(a))
+	copy(b, a)
+	return b
+}`)
+	assert.Contains(t, string(b), `func copyBridgeExternalIDs(a map[string]string) map[string]string {
+	if a == nil {
+		return nil
+	}
+	b := make(map[string]string, len(a))
+	for k, v := range a {
+		b[k] = v
+	}
+	return b
+}`)
+	assert.Contains(t, string(b), `func equalBridgePorts(a, b []string) bool {
+	if (a == nil) != (b == nil) {
+		return false
+	}
+	if len(a) != len(b) {
+		return false
+	}
+	ordered := true
+	for i, v := range a {
+		if b[i] != v {
+			ordered = false
+			break
+		}
+	}
+	if ordered {
+		return true
+	}
+	// sets are unordered, compare the number of occurrences of each element
+	counts := make(map[string]int, len(a))
+	for _, v := range a {
+		counts[v]++
+	}
+	for _, v := range b {
+		if counts[v] == 0 {
+			return false
+		}
+		counts[v]--
+	}
+	return true
+}`)
+	// arrays are copied by value and need no helper
+	assert.NotContains(t, string(b), "copyBridgeVlans")
+	assert.Contains(t, string(b), `func (a *Bridge) DeepCopyInto(b *Bridge) {
+	*b = *a
+	b.ExternalIDs = copyBridgeExternalIDs(a.ExternalIDs)
+	b.Ports = copyBridgePorts(a.Ports)
+}`)
+}
+
+func TestNewTableTemplateFieldComments(t *testing.T) {
+	rawSchema := []byte(`
+	{
+		"columns": {
+			"name": {"type": "string", "mutable": false},
+			"status": {"type": {"key": "string", "value": "string", "min": 0, "max": "unlimited"}, "ephemeral": true},
+			"protocol": {"type": {"key": {"type": "string", "enum": ["set", ["tcp", "udp"]]}, "min": 0, "max": 1}}
+		}
+	}`)
+	var table ovsdb.TableSchema
+	err := json.Unmarshal(rawSchema, &table)
+	require.NoError(t, err)
+
+	data := GetTableTemplateData("test", "Bridge", &table)
+	data.WithFieldComments(true)
+	g, err := NewGenerator()
+	require.NoError(t, err)
+	b, err := g.Format(NewTableTemplate(), data)
+	require.NoError(t, err)
+	assert.Equal(t, `// Code generated by "libovsdb.modelgen"
+// DO NOT EDIT.
+
+package test
+
+const BridgeTable = "Bridge"
+
+type (
+	BridgeProtocol = string
+)
+
+var (
+	BridgeProtocolTCP BridgeProtocol = "tcp"
+	BridgeProtocolUDP BridgeProtocol = "udp"
+)
+
+// Bridge defines an object in Bridge table
+type Bridge struct {
+	// UUID is the "_uuid" column of type uuid
+	UUID string `+"`"+`ovsdb:"_uuid"`+"`"+`
+	// Name is the "name" column of type "string" (immutable)
+	Name string `+"`"+`ovsdb:"name"`+"`"+`
+	// Protocol is the "protocol" column of type {"key":{"type":"string","enum":["set",["tcp","udp"]]},"min":0,"max":1} (optional)
+	Protocol *BridgeProtocol `+"`"+`ovsdb:"protocol"`+"`"+`
+	// Status is the "status" column of type {"key":{"type":"string"},"value":{"type":"string"},"min":0,"max":"unlimited"} (optional, ephemeral)
+	Status map[string]string `+"`"+`ovsdb:"status"`+"`"+`
+}
+`, string(b))
+}
+
+func TestFieldComment(t *testing.T) {
+	var column ovsdb.ColumnSchema
+	err := json.Unmarshal([]byte(`{"type": "integer", "ephemeral": true}`), &column)
+	require.NoError(t, err)
+	assert.Equal(t, `Statistics is the "statistics" column of type "integer" (ephemeral)`, FieldComment("statistics", &column))
+}
+
+func TestEnumValueName(t *testing.T) {
+	cases := []struct {
+		in       interface{}
+		expected string
+	}{
+		{"secure", "Secure"},
+		{"active-backup", "ActiveBackup"},
+		{"dnat_and_snat", "DNATAndSNAT"},
+		{"balance.tcp", "BalanceTCP"},
+		{float64(42), "42"},
+		{float64(1), "1"},
+		{float64(-1), "Minus1"},
+		{float64(-42), "Minus42"},
+		{true, "True"},
+	}
+	for _, tt := range cases {
+		if s := EnumValueName(tt.in); s != tt.expected {
+			t.Fatalf("got %s, wanted %s", s, tt.expected)
+		}
+	}
+}
+
 func TestFieldName(t *testing.T) {
 	cases := []struct {
 		in       string
@@ -729,6 +1042,47 @@ func TestFieldType(t *testing.T) {
 	}
 }
 
+func TestFieldOptional(t *testing.T) {
+	rawSchema := []byte(`{
+		"columns": {
+			"required_str": {"type": "string"},
+			"required_set": {"type": {"key": {"type": "string"}, "min": 1, "max": 1}},
+			"optional_str": {"type": {"key": {"type": "string"}, "min": 0, "max": 1}},
+			"optional_int": {"type": {"key": {"type": "integer"}, "min": 0, "max": 1}},
+			"unlimited_set": {"type": {"key": {"type": "string"}, "min": 0, "max": "unlimited"}},
+			"optional_map": {"type": {"key": {"type": "string"}, "value": {"type": "string"}, "min": 0, "max": 1}},
+			"uuid_values": {"type": {"key": {"type": "string"}, "value": {"type": "uuid", "refTable": "t2"}, "min": 0, "max": "unlimited"}},
+			"uuid_keys": {"type": {"key": {"type": "uuid", "refTable": "t2"}, "value": {"type": "integer"}, "min": 0, "max": "unlimited"}}
+		}
+	}`)
+	var table ovsdb.TableSchema
+	err := json.Unmarshal(rawSchema, &table)
+	require.NoError(t, err)
+
+	tests := []struct {
+		column    string
+		optional  bool
+		fieldType string
+	}{
+		{"required_str", false, "string"},
+		{"required_set", false, "string"},
+		{"optional_str", true, "*string"},
+		{"optional_int", true, "*int"},
+		{"unlimited_set", false, "[]string"},
+		{"optional_map", false, "map[string]string"},
+		{"uuid_values", false, "map[string]string"},
+		{"uuid_keys", false, "map[string]int"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.column, func(t *testing.T) {
+			column := table.Column(tt.column)
+			require.NotNil(t, column)
+			assert.Equal(t, tt.optional, FieldOptional(column))
+			assert.Equal(t, tt.fieldType, FieldType("t1", tt.column, column))
+		})
+	}
+}
+
 func TestAtomicType(t *testing.T) {
 	tests := []struct {
 		name string
@@ -757,6 +1111,15 @@ func TestTag(t *testing.T) {
 	}
 }
 
+func TestJSONTag(t *testing.T) {
+	if s := JSONTag("foo_bar"); s != "json:\"foo_bar,omitempty\"" {
+		t.Fatalf("got %s, wanted json:\"foo_bar,omitempty\"", s)
+	}
+	if s := JSONTag("_uuid"); s != "json:\"uuid,omitempty\"" {
+		t.Fatalf("got %s, wanted json:\"uuid,omitempty\"", s)
+	}
+}
+
 func TestFileName(t *testing.T) {
 	if s := FileName("foo"); s != "foo.go" {
 		t.Fatalf("got %s, wanted foo.go", s)
@@ -777,6 +1140,11 @@ func TestCamelCase(t *testing.T) {
 		{"dns_records", "DNSRecords"},
 		{"logical_ip", "LogicalIP"},
 		{"ip", "IP"},
+		{"tcp_flags", "TCPFlags"},
+		{"mac_address", "MACAddress"},
+		{"vlan_id", "VLANID"},
+		{"nat_addresses", "NATAddresses"},
+		{"http_url", "HTTPURL"},
 	}
 	for _, tt := range cases {
 		if s := camelCase(tt.in); s != tt.expected {
@@ -833,6 +1201,779 @@ func (t {{ index . "StructName" }}) {{ index . "FuncName"}}() string {
 	}
 }
 
+func TestNewTableTemplateDefaults(t *testing.T) {
+	rawSchema := []byte(`
+	{
+		"columns": {
+			"name": {"type": "string"},
+			"enabled": {"type": "boolean"},
+			"ports": {"type": {"key": {"type": "uuid"}, "min": 0, "max": "unlimited"}},
+			"protocols": {"type": {"key": {"type": "string", "enum": ["set", ["tcp", "udp"]]}, "min": 0, "max": "unlimited"}},
+			"vlans": {"type": {"key": {"type": "integer"}, "min": 0, "max": 2}},
+			"external_ids": {"type": {"key": "string", "value": "string", "min": 0, "max": "unlimited"}}
+		}
+	}`)
+	var table ovsdb.TableSchema
+	err := json.Unmarshal(rawSchema, &table)
+	require.NoError(t, err)
+
+	g, err := NewGenerator()
+	require.NoError(t, err)
+	data := GetTableTemplateData("test", "Bridge", &table)
+	b, err := g.Format(NewTableTemplate(), data)
+	require.NoError(t, err)
+	assert.NotContains(t, string(b), "SetDefaults")
+
+	data.WithDefaults(true)
+	b, err = g.Format(NewTableTemplate(), data)
+	require.NoError(t, err)
+	// maps and slices are initialized to empty ones, while arrays and
+	// scalars already hold their default value
+	assert.Contains(t, string(b), `func (a *Bridge) SetDefaults() {
+	if a.ExternalIDs == nil {
+		a.ExternalIDs = map[string]string{}
+	}
+	if a.Ports == nil {
+		a.Ports = []string{}
+	}
+	if a.Protocols == nil {
+		a.Protocols = []BridgeProtocols{}
+	}
+}`)
+
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "bridge.go", b, 0)
+	require.NoError(t, err)
+	_, err = (&types.Config{}).Check("test", fset, []*ast.File{file}, nil)
+	require.NoError(t, err)
+}
+
+// runGenerated builds the generated files, keyed by file name, along with
+// the given main.go into a program and returns what it prints. The program
+// is built in a directory of this package, so that it can import the
+// packages of this module.
+func runGenerated(t *testing.T, files map[string][]byte, main string) string {
+	t.Helper()
+	goBin, err := exec.LookPath("go")
+	require.NoError(t, err, "running the generated code requires the go toolchain")
+	dir, err := os.MkdirTemp(".", "_generated")
+	require.NoError(t, err)
+	t.Cleanup(func() { os.RemoveAll(dir) })
+	for name, src := range files {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), src, 0644))
+	}
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "main.go"), []byte(main), 0644))
+	out, err := exec.Command(goBin, "run", "./"+dir).CombinedOutput()
+	require.NoError(t, err, string(out))
+	return string(out)
+}
+
+func TestNewTableTemplateValidation(t *testing.T) {
+	rawSchema := []byte(`
+	{
+		"columns": {
+			"name": {"type": "string"},
+			"ports": {"type": {"key": {"type": "uuid"}, "min": 1, "max": "unlimited"}},
+			"trunks": {"type": {"key": {"type": "integer"}, "min": 0, "max": "unlimited"}},
+			"vlans": {"type": {"key": {"type": "integer"}, "min": 1, "max": 4}},
+			"options": {"type": {"key": "string", "value": "string", "min": 1, "max": 2}}
+		}
+	}`)
+	var table ovsdb.TableSchema
+	err := json.Unmarshal(rawSchema, &table)
+	require.NoError(t, err)
+
+	g, err := NewGenerator()
+	require.NoError(t, err)
+	data := GetTableTemplateData("main", "Bridge", &table)
+	b, err := g.Format(NewTableTemplate(), data)
+	require.NoError(t, err)
+	assert.NotContains(t, string(b), "Validate")
+
+	data.WithValidation(true)
+	b, err = g.Format(NewTableTemplate(), data)
+	require.NoError(t, err)
+	// arrays always hold as many elements as their maximum, and unbounded
+	// sets accept any number of elements
+	assert.Contains(t, string(b), `func (a *Bridge) Validate() error {
+	if len(a.Options) < 1 {
+		return fmt.Errorf("column options of table Bridge has %d elements, fewer than the minimum of 1", len(a.Options))
+	}
+	if len(a.Options) > 2 {
+		return fmt.Errorf("column options of table Bridge has %d elements, more than the maximum of 2", len(a.Options))
+	}
+	if len(a.Ports) < 1 {
+		return fmt.Errorf("column ports of table Bridge has %d elements, fewer than the minimum of 1", len(a.Ports))
+	}
+	return nil
+}`)
+
+	// without bounded columns, fmt must not be imported
+	var unbounded ovsdb.TableSchema
+	err = json.Unmarshal([]byte(`{"columns": {"name": {"type": "string"}}}`), &unbounded)
+	require.NoError(t, err)
+	unboundedData := GetTableTemplateData("main", "Bridge", &unbounded)
+	unboundedData.WithValidation(true)
+	unboundedSrc, err := g.Format(NewTableTemplate(), unboundedData)
+	require.NoError(t, err)
+	assert.NotContains(t, string(unboundedSrc), `import "fmt"`)
+	assert.Contains(t, string(unboundedSrc), "func (a *Bridge) Validate() error {\n\treturn nil\n}")
+
+	out := runGenerated(t, map[string][]byte{"bridge.go": b}, `package main
+
+import "fmt"
+
+func main() {
+	fmt.Println((&Bridge{Options: map[string]string{"a": "b"}}).Validate())
+	fmt.Println((&Bridge{Ports: []string{"p"}, Options: map[string]string{"a": "b"}}).Validate())
+	fmt.Println((&Bridge{Ports: []string{"p"}, Options: map[string]string{"a": "b", "c": "d", "e": "f"}}).Validate())
+}
+`)
+	assert.Equal(t, `column ports of table Bridge has 0 elements, fewer than the minimum of 1
+<nil>
+column options of table Bridge has 3 elements, more than the maximum of 2
+`, out)
+}
+
+func TestNewTableTemplateStringer(t *testing.T) {
+	rawSchema := []byte(`
+	{
+		"columns": {
+			"name": {"type": "string"},
+			"datapath_id": {"type": {"key": "string", "min": 0, "max": 1}},
+			"fail_mode": {"type": {"key": "string", "min": 0, "max": 1}},
+			"ports": {"type": {"key": {"type": "uuid"}, "min": 1, "max": "unlimited"}},
+			"flood_vlans": {"type": {"key": {"type": "integer"}, "min": 0, "max": "unlimited"}},
+			"external_ids": {"type": {"key": "string", "value": "string", "min": 0, "max": "unlimited"}}
+		}
+	}`)
+	var table ovsdb.TableSchema
+	err := json.Unmarshal(rawSchema, &table)
+	require.NoError(t, err)
+
+	g, err := NewGenerator()
+	require.NoError(t, err)
+	data := GetTableTemplateData("main", "Bridge", &table)
+	b, err := g.Format(NewTableTemplate(), data)
+	require.NoError(t, err)
+	assert.NotContains(t, string(b), "String()")
+
+	// the validation and stringer methods share their imports
+	data.WithStringer(true)
+	data.WithValidation(true)
+	b, err = g.Format(NewTableTemplate(), data)
+	require.NoError(t, err)
+	assert.Contains(t, string(b), "func (a Bridge) String() string {")
+
+	out := runGenerated(t, map[string][]byte{"bridge.go": b}, `package main
+
+import "fmt"
+
+func main() {
+	mode := "secure"
+	br := Bridge{
+		UUID:        "b5b3d2f2-0f3a-4b52-8b5b-54c3bd0e0f7a",
+		Name:        "br0",
+		FailMode:    &mode,
+		Ports:       []string{"p3", "p1", "p2"},
+		ExternalIDs: map[string]string{"z": "1", "a": "2", "m": "3"},
+	}
+	fmt.Println(br)
+	fmt.Println(&br)
+	for i := 12; i > 0; i-- {
+		br.FloodVLANs = append(br.FloodVLANs, i)
+	}
+	fmt.Println(br)
+}
+`)
+	expected := "Bridge{_uuid=b5b3d2f2-0f3a-4b52-8b5b-54c3bd0e0f7a, datapath_id=[], external_ids=map[a:2 m:3 z:1], fail_mode=secure, flood_vlans=[], name=br0, ports=[p1 p2 p3]}\n"
+	assert.Equal(t, expected+expected+
+		"Bridge{_uuid=b5b3d2f2-0f3a-4b52-8b5b-54c3bd0e0f7a, datapath_id=[], external_ids=map[a:2 m:3 z:1], fail_mode=secure, flood_vlans=[1 10 11 12 2 3 4 5 6 7 ... (2 more)], name=br0, ports=[p1 p2 p3]}\n",
+		out)
+}
+
+func TestColumnTypeOverride(t *testing.T) {
+	rawSchema := []byte(`
+	{
+		"columns": {
+			"name": {
+				"type": "string"
+			},
+			"ip": {
+				"type": "string"
+			},
+			"netmask": {
+				"type": "string"
+			},
+			"ports": {
+				"type": {"key": "integer", "min": 0, "max": 4}
+			}
+		}
+	}`)
+	var table ovsdb.TableSchema
+	require.NoError(t, json.Unmarshal(rawSchema, &table))
+
+	g, err := NewGenerator(
+		WithColumnTypeOverride("Host", "ip", "net.IP", "net"),
+		WithColumnTypeOverride("Host", "netmask", "net.IPMask", "net"),
+		WithColumnTypeOverride("Host", "ports", "PortSet", ""),
+	)
+	require.NoError(t, err)
+	data := GetTableTemplateData("test", "Host", &table)
+	data.WithExtendedGen(true)
+	data.WithDefaults(true)
+	data.WithValidation(true)
+	data.WithStringer(true)
+	src, err := g.Format(NewTableTemplate(), data)
+	require.NoError(t, err)
+	// the data passed to the generator is left as it is
+	assert.Empty(t, data["OverrideImports"])
+
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "host.go", src, 0)
+	require.NoError(t, err)
+	var imports []string
+	for _, spec := range file.Imports {
+		imports = append(imports, spec.Path.Value)
+	}
+	assert.ElementsMatch(t, []string{`"github.com/ovn-org/libovsdb/model"`, `"fmt"`, `"strings"`, `"net"`, `"reflect"`}, imports)
+
+	// the generated file has to compile, with a user defined PortSet type
+	portSet, err := parser.ParseFile(fset, "portset.go", "package test\n\ntype PortSet map[int]bool\n", 0)
+	require.NoError(t, err)
+	conf := types.Config{Importer: importer.ForCompiler(fset, "source", nil)}
+	pkg, err := conf.Check("test", fset, []*ast.File{file, portSet}, nil)
+	require.NoError(t, err, string(src))
+	host := pkg.Scope().Lookup("Host").Type().Underlying().(*types.Struct)
+	fields := map[string]string{}
+	for i := 0; i < host.NumFields(); i++ {
+		fields[host.Field(i).Name()] = types.TypeString(host.Field(i).Type(), types.RelativeTo(pkg))
+	}
+	assert.Equal(t, "net.IP", fields["IP"])
+	assert.Equal(t, "net.IPMask", fields["Netmask"])
+	assert.Equal(t, "PortSet", fields["Ports"])
+	assert.Equal(t, "string", fields["Name"])
+
+	g, err = NewGenerator(WithColumnTypeOverride("Host", "mac", "net.HardwareAddr", "net"))
+	require.NoError(t, err)
+	_, err = g.Format(NewTableTemplate(), GetTableTemplateData("test", "Host", &table))
+	assert.ErrorContains(t, err, "column mac of table Host: column not found")
+
+	_, err = NewGenerator(WithColumnTypeOverride("Host", "ip", "", "net"))
+	assert.Error(t, err)
+}
+
+func TestColumnTypeOverrideDeepCopy(t *testing.T) {
+	rawSchema := []byte(`
+	{
+		"columns": {
+			"name": {"type": "string"},
+			"address": {"type": "string"},
+			"config": {"type": "string"}
+		}
+	}`)
+	var table ovsdb.TableSchema
+	require.NoError(t, json.Unmarshal(rawSchema, &table))
+
+	g, err := NewGenerator(
+		WithColumnTypeOverride("Host", "address", "net.IP", "net"),
+		WithColumnTypeOverride("Host", "config", "json.RawMessage", "encoding/json"),
+	)
+	require.NoError(t, err)
+	data := GetTableTemplateData("main", "Host", &table)
+	data.WithExtendedGen(true)
+	b, err := g.Format(NewTableTemplate(), data)
+	require.NoError(t, err)
+	assert.Contains(t, string(b), "func copyHostAddress(a net.IP) net.IP {")
+	assert.Contains(t, string(b), "func copyHostConfig(a json.RawMessage) json.RawMessage {")
+	assert.NotContains(t, string(b), "func equalHostAddress")
+
+	out := runGenerated(t, map[string][]byte{"host.go": b}, `package main
+
+import (
+	"fmt"
+	"net"
+)
+
+func main() {
+	host := &Host{UUID: "a", Name: "h", Address: net.ParseIP("192.0.2.1").To4(), Config: []byte("{}")}
+	c := host.DeepCopy()
+	c.Address[3] = 2
+	c.Config[0] = '['
+	fmt.Println(host.Address, string(host.Config), c.Address, host.Equals(c))
+	cols := host.CloneModelColumns([]string{"address"}).(*Host)
+	cols.Address[3] = 3
+	fmt.Println(host.Address, cols.Address, cols.Config == nil)
+}
+`)
+	assert.Equal(t, "192.0.2.1 {} 192.0.2.2 false\n192.0.2.1 192.0.2.3 true\n", out)
+
+	err = GetTableTemplateData("main", "Host", &table).WithTypeOverride("address", "net.Address", "net")
+	assert.ErrorContains(t, err, "package net has no type Address")
+}
+
+func TestTableIndexes(t *testing.T) {
+	rawSchema := []byte(`
+	{
+		"columns": {
+			"name": {
+				"type": "string"
+			},
+			"tenant": {
+				"type": "string"
+			},
+			"vlan": {
+				"type": "integer"
+			}
+		},
+		"indexes": [["name"], ["tenant", "vlan"]]
+	}`)
+	var table ovsdb.TableSchema
+	require.NoError(t, json.Unmarshal(rawSchema, &table))
+	g, err := NewGenerator()
+	require.NoError(t, err)
+
+	data := GetTableTemplateData("test", "Network", &table)
+	src, err := g.Format(NewTableTemplate(), data)
+	require.NoError(t, err)
+	assert.NotContains(t, string(src), "TableIndexes")
+
+	data.WithTableIndexes(true)
+	src, err = g.Format(NewTableTemplate(), data)
+	require.NoError(t, err)
+	assert.Contains(t, string(src), `
+// TableIndexes returns the indexes of the Network table, the groups of
+// columns whose values are unique to each row
+func (a *Network) TableIndexes() [][]string {
+	return [][]string{
+		{"name"},
+		{"tenant", "vlan"},
+	}
+}
+`)
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "network.go", src, 0)
+	require.NoError(t, err)
+	conf := types.Config{Importer: importer.ForCompiler(fset, "source", nil)}
+	_, err = conf.Check("test", fset, []*ast.File{file}, nil)
+	require.NoError(t, err)
+
+	table.Indexes = nil
+	data = GetTableTemplateData("test", "Network", &table)
+	data.WithTableIndexes(true)
+	src, err = g.Format(NewTableTemplate(), data)
+	require.NoError(t, err)
+	assert.Contains(t, string(src), "func (a *Network) TableIndexes() [][]string {\n\treturn nil\n}")
+}
+
+func TestJSONTags(t *testing.T) {
+	rawSchema := []byte(`
+	{
+		"columns": {
+			"name": {
+				"type": "string"
+			},
+			"external_ids": {
+				"type": {"key": "string", "value": "string", "min": 0, "max": "unlimited"}
+			}
+		}
+	}`)
+	var table ovsdb.TableSchema
+	require.NoError(t, json.Unmarshal(rawSchema, &table))
+	g, err := NewGenerator()
+	require.NoError(t, err)
+
+	data := GetTableTemplateData("test", "Network", &table)
+	src, err := g.Format(NewTableTemplate(), data)
+	require.NoError(t, err)
+	assert.NotContains(t, string(src), "json:")
+
+	data.WithJSONTags(true)
+	src, err = g.Format(NewTableTemplate(), data)
+	require.NoError(t, err)
+	assert.Contains(t, string(src), "`ovsdb:\"_uuid\" json:\"uuid,omitempty\"`")
+	assert.Contains(t, string(src), "`ovsdb:\"external_ids\" json:\"external_ids,omitempty\"`")
+	assert.Contains(t, string(src), "`ovsdb:\"name\" json:\"name,omitempty\"`")
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "network.go", src, 0)
+	require.NoError(t, err)
+	conf := types.Config{Importer: importer.ForCompiler(fset, "source", nil)}
+	_, err = conf.Check("test", fset, []*ast.File{file}, nil)
+	require.NoError(t, err)
+}
+
+func TestFieldAccessors(t *testing.T) {
+	rawSchema := []byte(`
+	{
+		"columns": {
+			"name": {"type": "string"},
+			"ofport": {"type": "integer"},
+			"weight": {"type": "real"},
+			"enabled": {"type": "boolean"},
+			"parent": {"type": {"key": {"type": "uuid"}, "min": 1, "max": 1}},
+			"mode": {"type": {"key": {"type": "string", "enum": ["set", ["active", "passive"]]}}},
+			"fail_mode": {"type": {"key": {"type": "string", "enum": ["set", ["secure", "standalone"]]}, "min": 0, "max": 1}},
+			"mac": {"type": {"key": "string", "min": 0, "max": 1}},
+			"ports": {"type": {"key": {"type": "uuid"}, "min": 0, "max": "unlimited"}},
+			"trunks": {"type": {"key": {"type": "integer"}, "min": 0, "max": 4096}},
+			"external_ids": {"type": {"key": "string", "value": "string", "min": 0, "max": "unlimited"}},
+			"flow_tables": {"type": {"key": "integer", "value": "string", "min": 0, "max": "unlimited"}},
+			"address": {"type": "string"}
+		}
+	}`)
+	var table ovsdb.TableSchema
+	err := json.Unmarshal(rawSchema, &table)
+	require.NoError(t, err)
+
+	g, err := NewGenerator()
+	require.NoError(t, err)
+	data := GetTableTemplateData("main", "Bridge", &table)
+	require.NoError(t, data.WithTypeOverride("address", "net.IP", "net"))
+	b, err := g.Format(NewTableTemplate(), data)
+	require.NoError(t, err)
+	assert.NotContains(t, string(b), "GetField")
+
+	data.WithFieldAccessors(true)
+	b, err = g.Format(NewTableTemplate(), data)
+	require.NoError(t, err)
+	assert.Contains(t, string(b), "func (a *Bridge) GetField(column string) interface{} {")
+	assert.Contains(t, string(b), "func (a *Bridge) SetField(column string, value interface{}) error {")
+	assert.Contains(t, string(b), `import "fmt"`)
+
+	out := runGenerated(t, map[string][]byte{"bridge.go": b}, `package main
+
+import (
+	"fmt"
+	"net"
+	"reflect"
+)
+
+func main() {
+	mac := "00:00:00:00:00:01"
+	br := &Bridge{
+		UUID:        "b5b3d2f2-0f3a-4b52-8b5b-54c3bd0e0f7a",
+		Name:        "br0",
+		Ofport:      1,
+		Weight:      0.5,
+		Enabled:     true,
+		Parent:      "4a7b1bbd-2c0e-4a52-9f3e-1d5c36e8b6a1",
+		Mode:        BridgeModePassive,
+		FailMode:    &BridgeFailModeSecure,
+		MAC:         &mac,
+		Ports:       []string{"p1", "p2"},
+		ExternalIDs: map[string]string{"a": "b"},
+		FlowTables:  map[int]string{1: "t1"},
+		Address:     net.ParseIP("192.0.2.1"),
+	}
+	br.Trunks[0] = 42
+	columns := []string{"_uuid", "name", "ofport", "weight", "enabled", "parent", "mode", "fail_mode",
+		"mac", "ports", "trunks", "external_ids", "flow_tables", "address"}
+	other := &Bridge{}
+	for _, column := range columns {
+		if err := other.SetField(column, br.GetField(column)); err != nil {
+			fmt.Println(err)
+		}
+	}
+	fmt.Println(reflect.DeepEqual(br, other))
+	fmt.Println(other.SetField("ofport", "1"))
+	fmt.Println(other.SetField("unknown", 1))
+	fmt.Println(other.GetField("unknown"))
+}
+`)
+	assert.Equal(t, `true
+column ofport: value 1 (string) is not of type int
+column unknown not found in table Bridge
+<nil>
+`, out)
+}
+
+func TestConstructors(t *testing.T) {
+	rawSchema := []byte(`
+	{
+		"columns": {
+			"name": {"type": "string"},
+			"type": {"type": "string"},
+			"ip_address": {"type": "string"},
+			"ofport": {"type": "integer"},
+			"ports": {"type": {"key": {"type": "uuid"}, "min": 1, "max": "unlimited"}},
+			"tags": {"type": {"key": {"type": "string"}, "min": 0, "max": "unlimited"}},
+			"external_ids": {"type": {"key": "string", "value": "string", "min": 0, "max": "unlimited"}},
+			"options": {"type": {"key": "string", "value": "integer", "min": 0, "max": "unlimited"}}
+		},
+		"indexes": [["name"], ["type", "ip_address"]]
+	}`)
+	var table ovsdb.TableSchema
+	err := json.Unmarshal(rawSchema, &table)
+	require.NoError(t, err)
+
+	g, err := NewGenerator()
+	require.NoError(t, err)
+	data := GetTableTemplateData("main", "Bridge", &table)
+	b, err := g.Format(NewTableTemplate(), data)
+	require.NoError(t, err)
+	assert.NotContains(t, string(b), "func NewBridge")
+
+	data.WithConstructors(true)
+	b, err = g.Format(NewTableTemplate(), data)
+	require.NoError(t, err)
+	assert.Contains(t, string(b), "func NewBridge(ipAddress string, name string, ports []string, typeValue string) *Bridge {")
+
+	out := runGenerated(t, map[string][]byte{"bridge.go": b}, `package main
+
+import "fmt"
+
+func main() {
+	br := NewBridge("192.0.2.1", "br0", []string{"p1"}, "internal")
+	fmt.Println(br.IPAddress, br.Name, br.Ports, br.Type, br.Ofport)
+	fmt.Println(br.ExternalIDs != nil, br.Options != nil, br.Tags != nil)
+	br.ExternalIDs["a"] = "b"
+	br.Options["c"] = 1
+	fmt.Println(len(br.ExternalIDs), len(br.Options), len(br.Tags))
+}
+`)
+	assert.Equal(t, `192.0.2.1 br0 [p1] internal 0
+true true true
+1 1 0
+`, out)
+}
+
+func TestModelInterface(t *testing.T) {
+	rawSchema := []byte(`
+	{
+		"columns": {
+			"name": {"type": "string"}
+		}
+	}`)
+	var table ovsdb.TableSchema
+	err := json.Unmarshal(rawSchema, &table)
+	require.NoError(t, err)
+
+	g, err := NewGenerator()
+	require.NoError(t, err)
+	data := GetTableTemplateData("main", "Bridge", &table)
+	b, err := g.Format(NewTableTemplate(), data)
+	require.NoError(t, err)
+	assert.NotContains(t, string(b), "GetUUID")
+
+	data.WithModelInterface(true)
+	b, err = g.Format(NewTableTemplate(), data)
+	require.NoError(t, err)
+	assert.Contains(t, string(b), "func (a *Bridge) GetUUID() string {")
+	assert.Contains(t, string(b), "func (a *Bridge) SetUUID(uuid string) {")
+
+	out := runGenerated(t, map[string][]byte{"bridge.go": b}, `package main
+
+import (
+	"fmt"
+
+	"github.com/ovn-org/libovsdb/model"
+)
+
+func main() {
+	var m model.UUIDModel = &Bridge{UUID: "a", Name: "br0"}
+	fmt.Println(m.GetUUID())
+	m.SetUUID("b")
+	fmt.Println(m.GetUUID(), m.(*Bridge).UUID, m.(*Bridge).Name)
+}
+`)
+	assert.Equal(t, "a\nb b br0\n", out)
+}
+
+func TestModelInterfaceExtendedGen(t *testing.T) {
+	rawSchema := []byte(`
+	{
+		"columns": {
+			"name": {"type": "string"}
+		}
+	}`)
+	var table ovsdb.TableSchema
+	err := json.Unmarshal(rawSchema, &table)
+	require.NoError(t, err)
+
+	// the extended generation already has a GetUUID getter
+	g, err := NewGenerator()
+	require.NoError(t, err)
+	data := GetTableTemplateData("test", "Bridge", &table)
+	data.WithExtendedGen(true)
+	data.WithModelInterface(true)
+	b, err := g.Format(NewTableTemplate(), data)
+	require.NoError(t, err)
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "bridge.go", b, 0)
+	require.NoError(t, err)
+	conf := types.Config{Importer: importer.ForCompiler(fset, "source", nil)}
+	pkg, err := conf.Check("test", fset, []*ast.File{file}, nil)
+	require.NoError(t, err)
+
+	methods := types.NewMethodSet(types.NewPointer(pkg.Scope().Lookup("Bridge").Type()))
+	for _, name := range []string{"GetUUID", "SetUUID"} {
+		assert.NotNil(t, methods.Lookup(pkg, name), "method %s not found", name)
+	}
+}
+
+func TestLogFields(t *testing.T) {
+	rawSchema := []byte(`
+	{
+		"columns": {
+			"name": {"type": "string"},
+			"tag": {"type": {"key": "integer", "min": 0, "max": 1}},
+			"peer": {"type": {"key": "string", "min": 0, "max": 1}},
+			"trunks": {"type": {"key": "integer", "min": 0, "max": "unlimited"}},
+			"external_ids": {"type": {"key": "string", "value": "string", "min": 0, "max": "unlimited"}},
+			"mode": {"type": {"key": {"type": "string", "enum": ["set", ["active", "passive"]]}}}
+		}
+	}`)
+	var table ovsdb.TableSchema
+	err := json.Unmarshal(rawSchema, &table)
+	require.NoError(t, err)
+
+	g, err := NewGenerator()
+	require.NoError(t, err)
+	data := GetTableTemplateData("main", "Port", &table)
+	b, err := g.Format(NewTableTemplate(), data)
+	require.NoError(t, err)
+	assert.NotContains(t, string(b), "Fields()")
+
+	data.WithLogFields(true)
+	b, err = g.Format(NewTableTemplate(), data)
+	require.NoError(t, err)
+	assert.Contains(t, string(b), "func (a *Port) Fields() map[string]interface{} {")
+
+	out := runGenerated(t, map[string][]byte{"port.go": b}, `package main
+
+import (
+	"fmt"
+	"sort"
+)
+
+func main() {
+	tag := 10
+	port := &Port{
+		UUID:        "a",
+		Name:        "p0",
+		Tag:         &tag,
+		Trunks:      []int{1, 2},
+		ExternalIDs: map[string]string{"k": "v"},
+		Mode:        PortModeActive,
+	}
+	fields := port.Fields()
+	columns := make([]string, 0, len(fields))
+	for column := range fields {
+		columns = append(columns, column)
+	}
+	sort.Strings(columns)
+	for _, column := range columns {
+		fmt.Printf("%s=%#v\n", column, fields[column])
+	}
+}
+`)
+	assert.Equal(t, `_uuid="a"
+external_ids=map[string]string{"k":"v"}
+mode="active"
+name="p0"
+peer=<nil>
+tag=10
+trunks=[]int{1, 2}
+`, out)
+}
+
+func TestEmptyDetection(t *testing.T) {
+	rawSchema := []byte(`
+	{
+		"columns": {
+			"name": {"type": "string"},
+			"tag": {"type": {"key": "integer", "min": 0, "max": 1}},
+			"trunks": {"type": {"key": "integer", "min": 0, "max": "unlimited"}},
+			"ports": {"type": {"key": "string", "min": 1, "max": "unlimited"}},
+			"pair": {"type": {"key": "string", "min": 2, "max": 2}},
+			"external_ids": {"type": {"key": "string", "value": "string", "min": 0, "max": "unlimited"}}
+		}
+	}`)
+	var table ovsdb.TableSchema
+	err := json.Unmarshal(rawSchema, &table)
+	require.NoError(t, err)
+
+	g, err := NewGenerator()
+	require.NoError(t, err)
+	data := GetTableTemplateData("main", "Port", &table)
+	b, err := g.Format(NewTableTemplate(), data)
+	require.NoError(t, err)
+	assert.NotContains(t, string(b), "IsEmpty(")
+
+	data.WithEmptyDetection(true)
+	b, err = g.Format(NewTableTemplate(), data)
+	require.NoError(t, err)
+	assert.Contains(t, string(b), `func (a *Port) IsEmpty(column string) bool {
+	switch column {
+	case "external_ids":
+		return len(a.ExternalIDs) == 0
+	case "tag":
+		return a.Tag == nil
+	case "trunks":
+		return len(a.Trunks) == 0
+	}
+	return false
+}`)
+
+	assert.True(t, FieldEmptiable(table.Column("tag")))
+	assert.True(t, FieldEmptiable(table.Column("external_ids")))
+	assert.False(t, FieldEmptiable(table.Column("name")))
+	assert.False(t, FieldEmptiable(table.Column("ports")))
+	assert.False(t, FieldEmptiable(table.Column("pair")))
+}
+
+func TestArgName(t *testing.T) {
+	for column, name := range map[string]string{
+		"name":         "name",
+		"_uuid":        "uuid",
+		"ip_address":   "ipAddress",
+		"external_ids": "externalIDs",
+		"type":         "typeValue",
+		"string":       "stringValue",
+	} {
+		assert.Equal(t, name, argName(column), column)
+	}
+}
+
+func TestInitialisms(t *testing.T) {
+	rawSchema := []byte(`
+	{
+		"columns": {
+			"lb_group": {"type": "string"},
+			"vlan_id": {"type": "integer"},
+			"lb_mode": {"type": {"key": {"type": "string", "enum": ["set", ["lb_hash", "dp_hash"]]}}}
+		}
+	}`)
+	var table ovsdb.TableSchema
+	err := json.Unmarshal(rawSchema, &table)
+	require.NoError(t, err)
+	data := GetTableTemplateData("main", "Load_Balancer", &table)
+
+	g, err := NewGenerator()
+	require.NoError(t, err)
+	b, err := g.Format(NewTableTemplate(), data)
+	require.NoError(t, err)
+	assert.Contains(t, string(b), "LbGroup")
+	assert.Contains(t, string(b), "LoadBalancerLbModeLbHash")
+	assert.Contains(t, string(b), "VLANID")
+
+	g, err = NewGenerator(WithInitialisms("lb", "DP"))
+	require.NoError(t, err)
+	b, err = g.Format(NewTableTemplate(), data)
+	require.NoError(t, err)
+	assert.NotContains(t, string(b), "Lb")
+	assert.Contains(t, string(b), "LBGroup string")
+	assert.Contains(t, string(b), "LBMode  LoadBalancerLBMode")
+	assert.Contains(t, string(b), "LoadBalancerLBModeLBHash LoadBalancerLBMode = \"lb_hash\"")
+	assert.Contains(t, string(b), "LoadBalancerLBModeDPHash LoadBalancerLBMode = \"dp_hash\"")
+	assert.Contains(t, string(b), "VLANID")
+	// the data of the table is left as it is
+	assert.Equal(t, "LoadBalancerLbMode", data["Enums"].([]Enum)[0].Alias)
+
+	_, err = NewGenerator(WithInitialisms("L-B"))
+	assert.Error(t, err)
+}
+
 func TestExtendedGenCloneableModel(t *testing.T) {
 	a := &vswitchd.Bridge{}
 	func(a interface{}) {
@@ -841,6 +1982,38 @@ func TestExtendedGenCloneableModel(t *testing.T) {
 	}(a)
 }
 
+func TestExtendedGenDeepCopy(t *testing.T) {
+	a := buildTestBridge()
+	b := a.DeepCopy()
+	assert.Equal(t, a, b)
+
+	b.ExternalIDs["foo"] = "bar"
+	b.Ports[0] = "baz"
+	*b.FailMode = vswitchd.BridgeFailModeStandalone
+	b.FloodVLANs[0] = 42
+	assert.NotContains(t, a.ExternalIDs, "foo")
+	assert.NotEqual(t, "baz", a.Ports[0])
+	assert.Equal(t, vswitchd.BridgeFailModeSecure, *a.FailMode)
+	assert.Equal(t, 0, a.FloodVLANs[0])
+}
+
+func TestExtendedGenEquals(t *testing.T) {
+	a := buildTestBridge()
+	b := a.DeepCopy()
+	assert.True(t, a.Equals(b))
+
+	// sets are compared regardless of their order
+	b.Ports[0], b.Ports[1] = b.Ports[1], b.Ports[0]
+	assert.True(t, a.Equals(b))
+
+	b.Ports[0] = b.Ports[1]
+	assert.False(t, a.Equals(b))
+
+	b = a.DeepCopy()
+	b.ExternalIDs["foo"] = "bar"
+	assert.False(t, a.Equals(b))
+}
+
 func TestExtendedGenComparableModel(t *testing.T) {
 	a := &vswitchd.Bridge{}
 	func(a interface{}) {
@@ -3,6 +3,7 @@ package ovsdb
 import (
 	"fmt"
 	"reflect"
+	"strconv"
 )
 
 var (
@@ -99,6 +100,15 @@ func OvsToNativeAtomic(basicType string, ovsElem interface{}) (interface{}, erro
 		return ovsElem, nil
 	case TypeInteger:
 		naType := NativeTypeFromAtomic(basicType)
+		// Some servers encode the integers that a double cannot hold
+		// exactly as strings
+		if s, ok := ovsElem.(string); ok {
+			i, err := strconv.ParseInt(s, 10, strconv.IntSize)
+			if err != nil {
+				return nil, NewErrWrongType("OvsToNativeAtomic", "integer or string holding an integer", ovsElem)
+			}
+			return int(i), nil
+		}
 		// Default decoding of numbers is float64, convert them to int
 		if !reflect.TypeOf(ovsElem).ConvertibleTo(naType) {
 			return nil, NewErrWrongType("OvsToNativeAtomic", fmt.Sprintf("Convertible to %s", naType), ovsElem)
@@ -115,32 +125,37 @@ func OvsToNativeAtomic(basicType string, ovsElem interface{}) (interface{}, erro
 	}
 }
 
+// OvsToNativeSlice returns a slice of the native type of baseType holding the
+// elements of a set. The set can be an OvsSet, the []interface{} of its
+// elements, or a single element. An error is returned if one of the elements
+// does not have the base type.
 func OvsToNativeSlice(baseType string, ovsElem interface{}) (interface{}, error) {
 	naType := NativeTypeFromAtomic(baseType)
-	var nativeSet reflect.Value
-	switch ovsSet := ovsElem.(type) {
-	case OvsSet:
-		nativeSet = reflect.MakeSlice(reflect.SliceOf(naType), 0, len(ovsSet.GoSet))
-		for _, v := range ovsSet.GoSet {
-			nv, err := OvsToNativeAtomic(baseType, v)
-			if err != nil {
-				return nil, err
-			}
-			nativeSet = reflect.Append(nativeSet, reflect.ValueOf(nv))
-		}
-
-	default:
-		nativeSet = reflect.MakeSlice(reflect.SliceOf(naType), 0, 1)
-		nv, err := OvsToNativeAtomic(baseType, ovsElem)
+	elems := setElements(ovsElem)
+	nativeSet := reflect.MakeSlice(reflect.SliceOf(naType), 0, len(elems))
+	for i, v := range elems {
+		nv, err := OvsToNativeAtomic(baseType, v)
 		if err != nil {
-			return nil, err
+			return nil, fmt.Errorf("set element %d: %w", i, err)
 		}
-
 		nativeSet = reflect.Append(nativeSet, reflect.ValueOf(nv))
 	}
 	return nativeSet.Interface(), nil
 }
 
+// setElements returns the elements of a set, which is either an OvsSet, the
+// []interface{} of its elements or a single element
+func setElements(ovsElem interface{}) []interface{} {
+	switch ovsSet := ovsElem.(type) {
+	case OvsSet:
+		return ovsSet.GoSet
+	case []interface{}:
+		return ovsSet
+	default:
+		return []interface{}{ovsElem}
+	}
+}
+
 // OvsToNative transforms an ovs type to native one based on the column type information
 func OvsToNative(column *ColumnSchema, ovsElem interface{}) (interface{}, error) {
 	switch column.Type {
@@ -154,47 +169,32 @@ func OvsToNative(column *ColumnSchema, ovsElem interface{}) (interface{}, error)
 		// We need to convert it to the real type os slice
 		switch naType.Kind() {
 		case reflect.Ptr:
-			switch ovsSet := ovsElem.(type) {
-			case OvsSet:
-				if len(ovsSet.GoSet) > 1 {
-					return nil, fmt.Errorf("expected a slice of len =< 1, but got a slice with %d elements", len(ovsSet.GoSet))
-				}
-				if len(ovsSet.GoSet) == 0 {
-					return reflect.Zero(naType).Interface(), nil
-				}
-				native, err := OvsToNativeAtomic(column.TypeObj.Key.Type, ovsSet.GoSet[0])
-				if err != nil {
-					return nil, err
-				}
-				pv := reflect.New(naType.Elem())
-				pv.Elem().Set(reflect.ValueOf(native))
-				return pv.Interface(), nil
-			default:
-				native, err := OvsToNativeAtomic(column.TypeObj.Key.Type, ovsElem)
-				if err != nil {
-					return nil, err
-				}
-				pv := reflect.New(naType.Elem())
-				pv.Elem().Set(reflect.ValueOf(native))
-				return pv.Interface(), nil
+			elems := setElements(ovsElem)
+			if len(elems) > 1 {
+				return nil, fmt.Errorf("expected a slice of len =< 1, but got a slice with %d elements", len(elems))
+			}
+			if len(elems) == 0 {
+				return reflect.Zero(naType).Interface(), nil
+			}
+			native, err := OvsToNativeAtomic(column.TypeObj.Key.Type, elems[0])
+			if err != nil {
+				return nil, err
 			}
+			pv := reflect.New(naType.Elem())
+			pv.Elem().Set(reflect.ValueOf(native))
+			return pv.Interface(), nil
 		case reflect.Array:
+			elems := setElements(ovsElem)
+			if len(elems) > column.TypeObj.Max() {
+				return nil, fmt.Errorf("expected a slice of len =< %d, but got a slice with %d elements", column.TypeObj.Max(), len(elems))
+			}
 			array := reflect.New(reflect.ArrayOf(column.TypeObj.Max(), naType.Elem())).Elem()
-			switch ovsSet := ovsElem.(type) {
-			case OvsSet:
-				for i, v := range ovsSet.GoSet {
-					nv, err := OvsToNativeAtomic(column.TypeObj.Key.Type, v)
-					if err != nil {
-						return nil, err
-					}
-					array.Index(i).Set(reflect.ValueOf(nv))
-				}
-			default:
-				nv, err := OvsToNativeAtomic(column.TypeObj.Key.Type, ovsElem)
+			for i, v := range elems {
+				nv, err := OvsToNativeAtomic(column.TypeObj.Key.Type, v)
 				if err != nil {
-					return nil, err
+					return nil, fmt.Errorf("set element %d: %w", i, err)
 				}
-				array.Index(0).Set(reflect.ValueOf(nv))
+				array.Index(i).Set(reflect.ValueOf(nv))
 			}
 			return array.Interface(), nil
 		case reflect.Slice:
@@ -405,7 +405,12 @@ func ValidateCondition(column *ColumnSchema, function ConditionFunction, nativeV
 			NativeType(column).String(), nativeValue)
 	}
 
-	switch column.Type {
+	columnType := column.Type
+	if columnType == TypeEnum {
+		// enums support the same functions as the type of their values
+		columnType = ExtendedType(column.TypeObj.Key.Type)
+	}
+	switch columnType {
 	case TypeSet, TypeMap, TypeBoolean, TypeString, TypeUUID:
 		switch function {
 		case ConditionEqual, ConditionNotEqual, ConditionIncludes, ConditionExcludes:
@@ -414,8 +419,13 @@ func ValidateCondition(column *ColumnSchema, function ConditionFunction, nativeV
 			return fmt.Errorf("wrong condition function %s for type: %s", function, column.Type)
 		}
 	case TypeInteger, TypeReal:
-		// All functions are valid
-		return nil
+		switch function {
+		case ConditionEqual, ConditionNotEqual, ConditionIncludes, ConditionExcludes,
+			ConditionGreaterThan, ConditionGreaterThanOrEqual, ConditionLessThan, ConditionLessThanOrEqual:
+			return nil
+		default:
+			return fmt.Errorf("wrong condition function %s for type: %s", function, column.Type)
+		}
 	default:
 		panic("Unsupported Type")
 	}
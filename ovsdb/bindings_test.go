@@ -1148,6 +1148,46 @@ func TestConditionValidation(t *testing.T) {
 			value:     map[string]int{"foo": 42},
 			valid:     false,
 		},
+		{
+			name:      "numeric unknown function",
+			column:    []byte(`{"type":"integer"}`),
+			functions: []ConditionFunction{"foo"},
+			value:     1000,
+			valid:     false,
+		},
+		{
+			name: "string enum",
+			column: []byte(`{
+				   "type": {
+				     "key": {"type": "string", "enum": ["set", ["foo", "bar"]]}
+				   }
+				 }`),
+			functions: []ConditionFunction{ConditionEqual, ConditionIncludes, ConditionNotEqual, ConditionExcludes},
+			value:     "foo",
+			valid:     true,
+		},
+		{
+			name: "string enum wrong function",
+			column: []byte(`{
+				   "type": {
+				     "key": {"type": "string", "enum": ["set", ["foo", "bar"]]}
+				   }
+				 }`),
+			functions: []ConditionFunction{ConditionGreaterThanOrEqual, ConditionGreaterThan, ConditionLessThan, ConditionLessThanOrEqual},
+			value:     "foo",
+			valid:     false,
+		},
+		{
+			name: "integer enum",
+			column: []byte(`{
+				   "type": {
+				     "key": {"type": "integer", "enum": ["set", [1, 2]]}
+				   }
+				 }`),
+			functions: []ConditionFunction{ConditionGreaterThanOrEqual, ConditionGreaterThan, ConditionLessThan, ConditionLessThanOrEqual, ConditionEqual, ConditionIncludes, ConditionNotEqual, ConditionExcludes},
+			value:     1,
+			valid:     true,
+		},
 	}
 	for _, test := range tests {
 		t.Run(fmt.Sprintf("ConditionValidation: %s", test.name), func(t *testing.T) {
@@ -1166,3 +1206,26 @@ func TestConditionValidation(t *testing.T) {
 		})
 	}
 }
+
+func TestOvsToNativeIntegerString(t *testing.T) {
+	var column ColumnSchema
+	require.NoError(t, json.Unmarshal([]byte(`{"type":"integer"}`), &column))
+	var row Row
+	require.NoError(t, json.Unmarshal([]byte(`{"number": 42, "string": "42", "large": "9223372036854775807", "invalid": "foo"}`), &row))
+
+	for name, expected := range map[string]int{"number": 42, "string": 42, "large": 9223372036854775807} {
+		native, err := OvsToNative(&column, row[name])
+		require.NoError(t, err)
+		assert.Equal(t, expected, native, name)
+	}
+	_, err := OvsToNative(&column, row["invalid"])
+	assert.Error(t, err)
+
+	var set ColumnSchema
+	require.NoError(t, json.Unmarshal([]byte(`{"type":{"key":"integer","min":0,"max":"unlimited"}}`), &set))
+	var ovsSet OvsSet
+	require.NoError(t, json.Unmarshal([]byte(`["set", ["9223372036854775807", "-9223372036854775808"]]`), &ovsSet))
+	native, err := OvsToNative(&set, ovsSet)
+	require.NoError(t, err)
+	assert.Equal(t, []int{9223372036854775807, -9223372036854775808}, native)
+}
@@ -175,6 +175,178 @@ func (c ConditionFunction) Evaluate(a interface{}, b interface{}) (bool, error)
 	return false, fmt.Errorf("unreachable condition")
 }
 
+// EvaluateCondition evaluates a condition against a row in OVS notation, like
+// the rows of the updates of the server, as described in RFC7047: 5.1.
+// Columns holding a set may hold a single element instead of an OvsSet. The
+// relational functions only apply to integers and reals, the other functions
+// compare sets as sets and maps as maps. An error is returned if the column is
+// not in the row or if the value of the condition does not have the type of
+// the column.
+func EvaluateCondition(cond Condition, row Row) (bool, error) {
+	value, ok := row[cond.Column]
+	if !ok {
+		return false, fmt.Errorf("column %s not found in row", cond.Column)
+	}
+	_, isMap := value.(OvsMap)
+	_, isCondMap := cond.Value.(OvsMap)
+	if isMap != isCondMap {
+		return false, fmt.Errorf("cannot compare %v (%T) of column %s with %v (%T)", value, value, cond.Column, cond.Value, cond.Value)
+	}
+	if isMap {
+		return evaluateMapCondition(cond.Function, value.(OvsMap), cond.Value.(OvsMap))
+	}
+	elems := conditionElements(value)
+	condElems := conditionElements(cond.Value)
+	switch cond.Function {
+	case ConditionEqual, ConditionNotEqual:
+		equal, err := setsEqual(elems, condElems)
+		if err != nil {
+			return false, err
+		}
+		return equal == (cond.Function == ConditionEqual), nil
+	case ConditionIncludes:
+		return setIncludes(elems, condElems)
+	case ConditionExcludes:
+		for _, e := range condElems {
+			found, err := setContains(elems, e)
+			if err != nil || found {
+				return false, err
+			}
+		}
+		return true, nil
+	case ConditionLessThan, ConditionLessThanOrEqual, ConditionGreaterThan, ConditionGreaterThanOrEqual:
+		if len(elems) > 1 || len(condElems) != 1 {
+			return false, fmt.Errorf("condition %s is only supported on integers and reals", cond.Function)
+		}
+		y, ok := toFloat(condElems[0])
+		if !ok {
+			return false, fmt.Errorf("condition %s is only supported on integers and reals, got %v (%T)", cond.Function, condElems[0], condElems[0])
+		}
+		if len(elems) == 0 {
+			// an optional column without value
+			return false, nil
+		}
+		x, ok := toFloat(elems[0])
+		if !ok {
+			return false, fmt.Errorf("condition %s is only supported on integers and reals, column %s holds %v (%T)", cond.Function, cond.Column, elems[0], elems[0])
+		}
+		switch cond.Function {
+		case ConditionLessThan:
+			return x < y, nil
+		case ConditionLessThanOrEqual:
+			return x <= y, nil
+		case ConditionGreaterThan:
+			return x > y, nil
+		default:
+			return x >= y, nil
+		}
+	default:
+		return false, fmt.Errorf("unsupported condition function %s", cond.Function)
+	}
+}
+
+// evaluateMapCondition evaluates a condition on a map column
+func evaluateMapCondition(function ConditionFunction, value, condValue OvsMap) (bool, error) {
+	switch function {
+	case ConditionEqual, ConditionNotEqual:
+		equal := len(value.GoMap) == len(condValue.GoMap)
+		if equal {
+			included, err := mapIncludes(value, condValue)
+			if err != nil {
+				return false, err
+			}
+			equal = included
+		}
+		return equal == (function == ConditionEqual), nil
+	case ConditionIncludes:
+		return mapIncludes(value, condValue)
+	case ConditionExcludes:
+		for k, v := range condValue.GoMap {
+			found, err := mapContainsPair(value, k, v)
+			if err != nil || found {
+				return false, err
+			}
+		}
+		return true, nil
+	default:
+		return false, fmt.Errorf("condition %s is not supported on maps", function)
+	}
+}
+
+// conditionElements returns the elements of a set, or the atom itself
+func conditionElements(v interface{}) []interface{} {
+	if set, ok := v.(OvsSet); ok {
+		return set.GoSet
+	}
+	return []interface{}{v}
+}
+
+// atomsEqual compares two atoms. Integers and reals are compared by value, as
+// the integers decoded from JSON are float64. An error is returned if the
+// atoms are not of the same type.
+func atomsEqual(a, b interface{}) (bool, error) {
+	af, aok := toFloat(a)
+	bf, bok := toFloat(b)
+	if aok && bok {
+		return af == bf, nil
+	}
+	if ta, tb := atomicType(a), atomicType(b); ta == "" || ta != tb {
+		return false, NewErrWrongType("EvaluateCondition", fmt.Sprintf("%T", a), b)
+	}
+	return a == b, nil
+}
+
+func setContains(set []interface{}, elem interface{}) (bool, error) {
+	for _, e := range set {
+		equal, err := atomsEqual(e, elem)
+		if err != nil || equal {
+			return equal, err
+		}
+	}
+	return false, nil
+}
+
+func setIncludes(set, elems []interface{}) (bool, error) {
+	for _, e := range elems {
+		found, err := setContains(set, e)
+		if err != nil || !found {
+			return false, err
+		}
+	}
+	return true, nil
+}
+
+func setsEqual(a, b []interface{}) (bool, error) {
+	included, err := setIncludes(a, b)
+	if err != nil || !included {
+		return false, err
+	}
+	return setIncludes(b, a)
+}
+
+func mapContainsPair(m OvsMap, key, value interface{}) (bool, error) {
+	for k, v := range m.GoMap {
+		equal, err := atomsEqual(k, key)
+		if err != nil {
+			return false, err
+		}
+		if equal {
+			return atomsEqual(v, value)
+		}
+	}
+	return false, nil
+}
+
+func mapIncludes(m, pairs OvsMap) (bool, error) {
+	for k, v := range pairs.GoMap {
+		found, err := mapContainsPair(m, k, v)
+		if err != nil || !found {
+			return false, err
+		}
+	}
+	return true, nil
+}
+
 func sliceContains(x, y reflect.Value) bool {
 	for i := 0; i < y.Len(); i++ {
 		found := false
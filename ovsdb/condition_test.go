@@ -671,3 +671,85 @@ func TestMapContains(t *testing.T) {
 		})
 	}
 }
+
+func TestEvaluateCondition(t *testing.T) {
+	row := Row{
+		"int":      1.0,
+		"real":     1.5,
+		"string":   "foo",
+		"uuid":     UUID{GoUUID: "abc"},
+		"optional": OvsSet{GoSet: []interface{}{}},
+		"set":      OvsSet{GoSet: []interface{}{"a", "b"}},
+		"single":   "a",
+		"map":      OvsMap{GoMap: map[interface{}]interface{}{"a": "1", "b": "2"}},
+	}
+	set := func(elems ...interface{}) OvsSet {
+		return OvsSet{GoSet: elems}
+	}
+	tests := []struct {
+		column   string
+		function ConditionFunction
+		value    interface{}
+		expected bool
+		err      bool
+	}{
+		// scalars
+		{"int", ConditionEqual, 1, true, false},
+		{"int", ConditionNotEqual, 1, false, false},
+		{"int", ConditionLessThan, 2, true, false},
+		{"int", ConditionLessThanOrEqual, 1, true, false},
+		{"int", ConditionGreaterThan, 1, false, false},
+		{"int", ConditionGreaterThanOrEqual, 0.5, true, false},
+		{"int", ConditionIncludes, 1, true, false},
+		{"int", ConditionExcludes, 1, false, false},
+		{"real", ConditionGreaterThan, 1, true, false},
+		{"string", ConditionEqual, "foo", true, false},
+		{"string", ConditionNotEqual, "bar", true, false},
+		{"string", ConditionIncludes, "bar", false, false},
+		{"string", ConditionExcludes, "bar", true, false},
+		{"uuid", ConditionEqual, UUID{GoUUID: "abc"}, true, false},
+		{"uuid", ConditionEqual, UUID{GoUUID: "def"}, false, false},
+		{"optional", ConditionLessThan, 1, false, false},
+		// sets
+		{"set", ConditionEqual, set("b", "a"), true, false},
+		{"set", ConditionEqual, set("a"), false, false},
+		{"set", ConditionNotEqual, set("a"), true, false},
+		{"set", ConditionIncludes, set("a"), true, false},
+		{"set", ConditionIncludes, set("a", "c"), false, false},
+		{"set", ConditionIncludes, set(), true, false},
+		{"set", ConditionExcludes, set("c", "d"), true, false},
+		{"set", ConditionExcludes, set("a", "c"), false, false},
+		{"single", ConditionEqual, set("a"), true, false},
+		{"single", ConditionIncludes, set("a", "b"), false, false},
+		{"optional", ConditionEqual, set(), true, false},
+		{"optional", ConditionExcludes, set("a"), true, false},
+		// maps
+		{"map", ConditionEqual, OvsMap{GoMap: map[interface{}]interface{}{"b": "2", "a": "1"}}, true, false},
+		{"map", ConditionEqual, OvsMap{GoMap: map[interface{}]interface{}{"a": "1"}}, false, false},
+		{"map", ConditionNotEqual, OvsMap{GoMap: map[interface{}]interface{}{"a": "2", "b": "2"}}, true, false},
+		{"map", ConditionIncludes, OvsMap{GoMap: map[interface{}]interface{}{"a": "1"}}, true, false},
+		{"map", ConditionIncludes, OvsMap{GoMap: map[interface{}]interface{}{"a": "2"}}, false, false},
+		{"map", ConditionExcludes, OvsMap{GoMap: map[interface{}]interface{}{"a": "2", "c": "3"}}, true, false},
+		{"map", ConditionExcludes, OvsMap{GoMap: map[interface{}]interface{}{"a": "1"}}, false, false},
+		// errors
+		{"missing", ConditionEqual, 1, false, true},
+		{"int", ConditionEqual, "1", false, true},
+		{"set", ConditionIncludes, set(1), false, true},
+		{"string", ConditionLessThan, "foo", false, true},
+		{"set", ConditionGreaterThan, 1, false, true},
+		{"map", ConditionLessThan, OvsMap{GoMap: map[interface{}]interface{}{}}, false, true},
+		{"map", ConditionIncludes, set("a"), false, true},
+		{"map", ConditionIncludes, OvsMap{GoMap: map[interface{}]interface{}{1: "1"}}, false, true},
+		{"int", "like", 1, false, true},
+	}
+	for _, tt := range tests {
+		cond := NewCondition(tt.column, tt.function, tt.value)
+		got, err := EvaluateCondition(cond, row)
+		if tt.err {
+			assert.Error(t, err, cond.String())
+			continue
+		}
+		assert.NoError(t, err, cond.String())
+		assert.Equal(t, tt.expected, got, cond.String())
+	}
+}
@@ -177,3 +177,171 @@ func TestSet(t *testing.T) {
 		})
 	}
 }
+
+func TestSetUnmarshalInvalid(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+	}{
+		{
+			"empty array",
+			`[]`,
+		},
+		{
+			"set without elements",
+			`["set"]`,
+		},
+		{
+			"set with non array elements",
+			`["set",1]`,
+		},
+		{
+			"set with extra elements",
+			`["set",[],[]]`,
+		},
+		{
+			"uuid with non string value",
+			`["uuid",1]`,
+		},
+		{
+			"not a set",
+			`["map",[]]`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var res OvsSet
+			err := json.Unmarshal([]byte(tt.input), &res)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestMapRoundTrip(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    interface{}
+		expected string
+	}{
+		{
+			"string to string",
+			map[string]string{`k1`: `v1`, `k0`: `v0`, `k2`: `v2`},
+			`["map",[["k0","v0"],["k1","v1"],["k2","v2"]]]`,
+		},
+		{
+			"integer to uuid",
+			map[int]UUID{10: validUUID1, 2: validUUID0},
+			fmt.Sprintf(`["map",[[2,["uuid","%v"]],[10,["uuid","%v"]]]]`, validUUIDStr0, validUUIDStr1),
+		},
+		{
+			"integer to string",
+			map[int]string{10: "ten", 2: "two", -1: "minus one", 100: "hundred"},
+			`["map",[[-1,"minus one"],[2,"two"],[10,"ten"],[100,"hundred"]]]`,
+		},
+		{
+			"string keys are sorted lexically",
+			map[string]string{"10": "ten", "2": "two", "b": "b", "A": "A"},
+			`["map",[["10","ten"],["2","two"],["A","A"],["b","b"]]]`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, err := NewOvsMap(tt.input)
+			assert.Nil(t, err)
+			jsonStr, err := json.Marshal(m)
+			assert.Nil(t, err)
+			// keys are sorted so the output is stable
+			assert.Equal(t, tt.expected, string(jsonStr))
+
+			var res OvsMap
+			err = json.Unmarshal(jsonStr, &res)
+			assert.Nil(t, err)
+			assert.Len(t, res.GoMap, len(m.GoMap))
+			for k, v := range m.GoMap {
+				// integers are decoded as float64
+				if i, ok := k.(int); ok {
+					assert.Equal(t, v, res.GoMap[float64(i)])
+				} else {
+					assert.Equal(t, v, res.GoMap[k])
+				}
+			}
+		})
+	}
+}
+
+func TestNewOvsMapInvalid(t *testing.T) {
+	tests := []struct {
+		name  string
+		input interface{}
+	}{
+		{
+			"not a map",
+			[]string{`aa`},
+		},
+		{
+			"non atomic key",
+			map[[1]string]string{{`aa`}: `bb`},
+		},
+		{
+			"non atomic value",
+			map[string][]string{`aa`: {`bb`}},
+		},
+		{
+			"nil value",
+			map[string]interface{}{`aa`: nil},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewOvsMap(tt.input)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestMapUnmarshalInvalid(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+	}{
+		{
+			"invalid json",
+			`["map",`,
+		},
+		{
+			"empty array",
+			`[]`,
+		},
+		{
+			"map without pairs",
+			`["map"]`,
+		},
+		{
+			"not a map",
+			`["set",[]]`,
+		},
+		{
+			"pairs not an array",
+			`["map",1]`,
+		},
+		{
+			"pair not an array",
+			`["map",["aa"]]`,
+		},
+		{
+			"pair with one element",
+			`["map",[["aa"]]]`,
+		},
+		{
+			"nested map",
+			`["map",[["aa",["map",[]]]]]`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var res OvsMap
+			err := json.Unmarshal([]byte(tt.input), &res)
+			assert.Error(t, err)
+		})
+	}
+}
@@ -1,6 +1,10 @@
 package ovsdb
 
-import "fmt"
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
 
 const (
 	referentialIntegrityViolation = "referential integrity violation"
@@ -14,8 +18,17 @@ const (
 	notSupported                  = "not supported"
 	aborted                       = "aborted"
 	notOwner                      = "not owner"
+	notLeader                     = "not leader"
 )
 
+// ErrorFromResult returns the specific OVSDB error type of the error code of an
+// OperationResult, e.g. a *ConstraintViolation, or a generic *Error for codes
+// that are not defined by RFC 7047. It returns nil if the result has no error.
+// The details of the error are kept and returned by its Details method.
+func ErrorFromResult(r OperationResult) OperationError {
+	return errorFromResult(nil, r)
+}
+
 // errorFromResult returns an specific OVSDB error type from
 // an OperationResult
 func errorFromResult(op *Operation, r OperationResult) OperationError {
@@ -45,6 +58,8 @@ func errorFromResult(op *Operation, r OperationResult) OperationError {
 		return &Aborted{r.Details, op}
 	case notOwner:
 		return &NotOwner{r.Details, op}
+	case notLeader:
+		return &NotLeader{r.Details, op}
 	default:
 		return &Error{r.Error, r.Details, op}
 	}
@@ -55,16 +70,15 @@ func errorFromResult(op *Operation, r OperationResult) OperationError {
 // If the operation failed, due to a error committing the transaction it will
 // return nil, error.
 // Finally, in the case where one or more of the operations in the transaction
-// failed, we return []OperationErrors, error
+// failed, we return []OperationErrors, error. The error names the index,
+// error code and details of every operation that failed.
 // Within []OperationErrors, the OperationErrors.Index() corresponds to the same index in
 // the original Operations struct. You may also perform type assertions against
 // the error so the caller can decide how best to handle it
 func CheckOperationResults(result []OperationResult, ops []Operation) ([]OperationError, error) {
-	// this shouldn't happen, but we'll cover the case to be certain
-	if len(result) < len(ops) {
-		return nil, fmt.Errorf("ovsdb transaction error. %d operations submitted but only %d results received", len(ops), len(result))
-	}
 	var errs []OperationError
+	var details []string
+	failed := -1
 	for i, op := range result {
 		// RFC 7047: if all of the operations succeed, but the results cannot
 		// be committed, then "result" will have one more element than "params",
@@ -73,15 +87,77 @@ func CheckOperationResults(result []OperationResult, ops []Operation) ([]Operati
 			return errs, errorFromResult(nil, op)
 		}
 		if err := errorFromResult(&ops[i], op); err != nil {
+			if failed < 0 {
+				failed = i
+			}
 			errs = append(errs, err)
+			details = append(details, fmt.Sprintf("operation %d (%s on %s): %s", i, ops[i].Op, ops[i].Table, err.Error()))
+		}
+	}
+	// the server stops executing operations after the first one that fails,
+	// in which case the results of the remaining operations may be missing
+	if len(result) < len(ops) {
+		if len(errs) > 0 {
+			return errs, &AbortError{failed, errs[0], fmt.Sprintf("%d ovsdb operations failed, %d operations were not executed: %s",
+				len(errs), len(ops)-len(result), strings.Join(details, ". "))}
 		}
+		return nil, fmt.Errorf("ovsdb transaction error. %d operations submitted but only %d results received", len(ops), len(result))
 	}
 	if len(errs) > 0 {
-		return errs, fmt.Errorf("%d ovsdb operations failed", len(errs))
+		return errs, &AbortError{failed, errs[0], fmt.Sprintf("%d ovsdb operations failed: %s", len(errs), strings.Join(details, ". "))}
 	}
 	return nil, nil
 }
 
+// ErrAborted matches, with errors.Is, the error returned by
+// CheckOperationResults when an operation of the transaction failed and the
+// server aborted the transaction. Use IsRetryable to decide whether to send
+// it again. Transact does not check the results of the operations, so it
+// never returns this error; CheckOperationResults does, and so do the
+// client's TransactWithRetry, Transaction.Commit, Select and DryRun, which
+// call it
+var ErrAborted = errors.New("ovsdb transaction aborted")
+
+// IsRetryable returns whether an error returned by CheckOperationResults is
+// transient, so that the same transaction may succeed if it is sent again:
+// the server lost the leadership of the cluster (*NotLeader) or failed to
+// write the database (*IOError). Other errors, like a *ConstraintViolation,
+// fail the same way every time. A wait operation that timed out (*TimedOut)
+// is not retryable either, as the rows it waits for have to be read again to
+// build a new wait.
+func IsRetryable(err error) bool {
+	var notLeaderErr *NotLeader
+	var ioErr *IOError
+	return errors.As(err, &notLeaderErr) || errors.As(err, &ioErr)
+}
+
+// AbortError is returned by CheckOperationResults when the server aborted a
+// transaction because one of its operations failed. It unwraps to the
+// OperationError of the first failed operation, so the caller may also use
+// errors.As to check the reason, e.g. a *TimedOut wait operation
+type AbortError struct {
+	// Index of the first failed operation in the transaction
+	Index int
+	// Reason is the error of the first failed operation
+	Reason OperationError
+	msg    string
+}
+
+// Error implements the error interface
+func (e *AbortError) Error() string {
+	return e.msg
+}
+
+// Is makes errors.Is(err, ErrAborted) hold for an AbortError
+func (e *AbortError) Is(target error) bool {
+	return target == ErrAborted
+}
+
+// Unwrap returns the error of the first failed operation
+func (e *AbortError) Unwrap() error {
+	return e.Reason
+}
+
 // OperationError represents an error that occurred as part of an
 // OVSDB Operation
 type OperationError interface {
@@ -110,6 +186,11 @@ func (e *ReferentialIntegrityViolation) Operation() *Operation {
 	return e.operation
 }
 
+// Details returns the details of the error sent by the server, if any
+func (e *ReferentialIntegrityViolation) Details() string {
+	return e.details
+}
+
 // ConstraintViolation is described in RFC 7047: 4.1.3
 type ConstraintViolation struct {
 	details   string
@@ -130,6 +211,11 @@ func (e *ConstraintViolation) Operation() *Operation {
 	return e.operation
 }
 
+// Details returns the details of the error sent by the server, if any
+func (e *ConstraintViolation) Details() string {
+	return e.details
+}
+
 // ResourcesExhausted is described in RFC 7047: 4.1.3
 type ResourcesExhausted struct {
 	details   string
@@ -150,6 +236,11 @@ func (e *ResourcesExhausted) Operation() *Operation {
 	return e.operation
 }
 
+// Details returns the details of the error sent by the server, if any
+func (e *ResourcesExhausted) Details() string {
+	return e.details
+}
+
 // IOError is described in RFC7047: 4.1.3
 type IOError struct {
 	details   string
@@ -170,6 +261,11 @@ func (e *IOError) Operation() *Operation {
 	return e.operation
 }
 
+// Details returns the details of the error sent by the server, if any
+func (e *IOError) Details() string {
+	return e.details
+}
+
 // DuplicateUUIDName is described in RFC7047 5.2.1
 type DuplicateUUIDName struct {
 	details   string
@@ -190,6 +286,11 @@ func (e *DuplicateUUIDName) Operation() *Operation {
 	return e.operation
 }
 
+// Details returns the details of the error sent by the server, if any
+func (e *DuplicateUUIDName) Details() string {
+	return e.details
+}
+
 // DomainError is described in RFC 7047: 5.2.4
 type DomainError struct {
 	details   string
@@ -210,6 +311,11 @@ func (e *DomainError) Operation() *Operation {
 	return e.operation
 }
 
+// Details returns the details of the error sent by the server, if any
+func (e *DomainError) Details() string {
+	return e.details
+}
+
 // RangeError is described in RFC 7047: 5.2.4
 type RangeError struct {
 	details   string
@@ -230,6 +336,11 @@ func (e *RangeError) Operation() *Operation {
 	return e.operation
 }
 
+// Details returns the details of the error sent by the server, if any
+func (e *RangeError) Details() string {
+	return e.details
+}
+
 // TimedOut is described in RFC 7047: 5.2.6
 type TimedOut struct {
 	details   string
@@ -250,6 +361,11 @@ func (e *TimedOut) Operation() *Operation {
 	return e.operation
 }
 
+// Details returns the details of the error sent by the server, if any
+func (e *TimedOut) Details() string {
+	return e.details
+}
+
 // NotSupported is described in RFC 7047: 5.2.7
 type NotSupported struct {
 	details   string
@@ -270,6 +386,11 @@ func (e *NotSupported) Operation() *Operation {
 	return e.operation
 }
 
+// Details returns the details of the error sent by the server, if any
+func (e *NotSupported) Details() string {
+	return e.details
+}
+
 // Aborted is described in RFC 7047: 5.2.8
 type Aborted struct {
 	details   string
@@ -290,6 +411,11 @@ func (e *Aborted) Operation() *Operation {
 	return e.operation
 }
 
+// Details returns the details of the error sent by the server, if any
+func (e *Aborted) Details() string {
+	return e.details
+}
+
 // NotOwner is described in RFC 7047: 5.2.9
 type NotOwner struct {
 	details   string
@@ -310,6 +436,37 @@ func (e *NotOwner) Operation() *Operation {
 	return e.operation
 }
 
+// Details returns the details of the error sent by the server, if any
+func (e *NotOwner) Details() string {
+	return e.details
+}
+
+// NotLeader is not defined by RFC 7047. A clustered ovsdb-server returns it
+// when it lost the leadership of the cluster while committing a transaction
+type NotLeader struct {
+	details   string
+	operation *Operation
+}
+
+// Error implements the error interface
+func (e *NotLeader) Error() string {
+	msg := notLeader
+	if e.details != "" {
+		msg += ": " + e.details
+	}
+	return msg
+}
+
+// Operation implements the OperationError interface
+func (e *NotLeader) Operation() *Operation {
+	return e.operation
+}
+
+// Details returns the details of the error sent by the server, if any
+func (e *NotLeader) Details() string {
+	return e.details
+}
+
 // Error is a generic OVSDB Error type that implements the
 // OperationError and error interfaces
 type Error struct {
@@ -327,7 +484,17 @@ func (e *Error) Error() string {
 	return msg
 }
 
+// Name returns the error code sent by the server
+func (e *Error) Name() string {
+	return e.name
+}
+
 // Operation implements the OperationError interface
 func (e *Error) Operation() *Operation {
 	return e.operation
 }
+
+// Details returns the details of the error sent by the server, if any
+func (e *Error) Details() string {
+	return e.details
+}
@@ -1,6 +1,7 @@
 package ovsdb
 
 import (
+	"errors"
 	"reflect"
 	"testing"
 
@@ -72,6 +73,11 @@ func TestErrorFromResult(t *testing.T) {
 			args{nil, OperationResult{Error: notOwner}},
 			&NotOwner{},
 		},
+		{
+			notLeader,
+			args{nil, OperationResult{Error: notLeader}},
+			&NotLeader{},
+		},
 		{
 			"generic error",
 			args{nil, OperationResult{Error: "foo"}},
@@ -91,6 +97,38 @@ func TestErrorFromResult(t *testing.T) {
 	}
 }
 
+func TestErrorFromResultErrorsAs(t *testing.T) {
+	var err error = ErrorFromResult(OperationResult{Error: constraintViolation, Details: "Transaction causes multiple rows in \"Bridge\" table to have identical values (\"br0\") for index on column \"name\"."})
+	var constraint *ConstraintViolation
+	assert.True(t, errors.As(err, &constraint))
+	assert.Equal(t, "Transaction causes multiple rows in \"Bridge\" table to have identical values (\"br0\") for index on column \"name\".", constraint.Details())
+
+	err = ErrorFromResult(OperationResult{Error: referentialIntegrityViolation, Details: "cannot delete Bridge row"})
+	var referential *ReferentialIntegrityViolation
+	assert.True(t, errors.As(err, &referential))
+	assert.Equal(t, "cannot delete Bridge row", referential.Details())
+	assert.False(t, errors.As(err, &constraint))
+
+	err = ErrorFromResult(OperationResult{Error: duplicateUUIDName, Details: "myuuid"})
+	var duplicate *DuplicateUUIDName
+	assert.True(t, errors.As(err, &duplicate))
+	assert.Equal(t, "duplicate uuid name: myuuid", err.Error())
+
+	err = ErrorFromResult(OperationResult{Error: resourcesExhausted})
+	var exhausted *ResourcesExhausted
+	assert.True(t, errors.As(err, &exhausted))
+	assert.Equal(t, "", exhausted.Details())
+
+	err = ErrorFromResult(OperationResult{Error: "unknown code", Details: "something went wrong"})
+	var generic *Error
+	assert.True(t, errors.As(err, &generic))
+	assert.Equal(t, "unknown code", generic.Name())
+	assert.Equal(t, "something went wrong", generic.Details())
+	assert.Equal(t, "unknown code: something went wrong", err.Error())
+
+	assert.Nil(t, ErrorFromResult(OperationResult{}))
+}
+
 func TestCheckOperationResults(t *testing.T) {
 	type args struct {
 		result []OperationResult
@@ -120,6 +158,18 @@ func TestCheckOperationResults(t *testing.T) {
 			[]OperationError{&ConstraintViolation{details: "foo", operation: &Operation{Op: "insert"}}, &ConstraintViolation{details: "bar", operation: &Operation{Op: "mutate"}}},
 			true,
 		},
+		{
+			"constraint violation with short results",
+			args{[]OperationResult{{}, {Error: constraintViolation, Details: "foo"}}, []Operation{{Op: "insert"}, {Op: "insert"}, {Op: "mutate"}}},
+			[]OperationError{&ConstraintViolation{details: "foo", operation: &Operation{Op: "insert"}}},
+			true,
+		},
+		{
+			"short results without error",
+			args{[]OperationResult{{}}, []Operation{{Op: "insert"}, {Op: "mutate"}}},
+			nil,
+			true,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -134,3 +184,58 @@ func TestCheckOperationResults(t *testing.T) {
 		})
 	}
 }
+
+func TestCheckOperationResultsMessage(t *testing.T) {
+	ops := []Operation{
+		{Op: OperationInsert, Table: "Bridge"},
+		{Op: OperationMutate, Table: "Open_vSwitch"},
+		{Op: OperationInsert, Table: "Port"},
+	}
+	results := []OperationResult{{}, {Error: constraintViolation, Details: "duplicate name"}}
+	_, err := CheckOperationResults(results, ops)
+	if err == nil {
+		t.Fatal("CheckOperationResults() expected an error")
+	}
+	expected := "1 ovsdb operations failed, 1 operations were not executed: operation 1 (mutate on Open_vSwitch): constraint violation: duplicate name"
+	if err.Error() != expected {
+		t.Errorf("CheckOperationResults() error = %q, want %q", err.Error(), expected)
+	}
+}
+
+func TestCheckOperationResultsAborted(t *testing.T) {
+	ops := []Operation{
+		{Op: OperationWait, Table: "Bridge"},
+		{Op: OperationInsert, Table: "Bridge"},
+	}
+	_, err := CheckOperationResults([]OperationResult{{Error: timedOut, Details: "wait timed out"}}, ops)
+	assert.ErrorIs(t, err, ErrAborted)
+	var abortErr *AbortError
+	if assert.ErrorAs(t, err, &abortErr) {
+		assert.Equal(t, 0, abortErr.Index)
+		assert.IsType(t, &TimedOut{}, abortErr.Reason)
+	}
+	var timedOutErr *TimedOut
+	assert.ErrorAs(t, err, &timedOutErr)
+
+	// neither a successful transaction nor a missing result abort it
+	_, err = CheckOperationResults([]OperationResult{{}, {}}, ops)
+	assert.NoError(t, err)
+	_, err = CheckOperationResults([]OperationResult{{}}, ops)
+	assert.Error(t, err)
+	assert.NotErrorIs(t, err, ErrAborted)
+}
+
+func TestIsRetryable(t *testing.T) {
+	ops := []Operation{{Op: OperationInsert, Table: "Bridge"}}
+	_, err := CheckOperationResults([]OperationResult{{}, {Error: notLeader}}, ops)
+	assert.True(t, IsRetryable(err))
+	_, err = CheckOperationResults([]OperationResult{{Error: ioError}}, ops)
+	assert.True(t, IsRetryable(err))
+	// the same wait would time out again
+	_, err = CheckOperationResults([]OperationResult{{Error: timedOut}}, ops)
+	assert.False(t, IsRetryable(err))
+	_, err = CheckOperationResults([]OperationResult{{Error: constraintViolation}}, ops)
+	assert.False(t, IsRetryable(err))
+	assert.False(t, IsRetryable(errors.New("not leader")))
+	assert.False(t, IsRetryable(nil))
+}
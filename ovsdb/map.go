@@ -4,6 +4,8 @@ import (
 	"encoding/json"
 	"fmt"
 	"reflect"
+	"sort"
+	"strings"
 )
 
 // OvsMap is the JSON map structure used for OVSDB
@@ -18,14 +20,22 @@ type OvsMap struct {
 }
 
 // MarshalJSON marshalls an OVSDB style Map to a byte array
+// The pairs are sorted by key with CompareAtomic so that the output is stable
 func (o OvsMap) MarshalJSON() ([]byte, error) {
 	if len(o.GoMap) > 0 {
+		keys := make([]interface{}, 0, len(o.GoMap))
+		for key := range o.GoMap {
+			keys = append(keys, key)
+		}
+		sort.Slice(keys, func(i, j int) bool {
+			return atomicLess(keys[i], keys[j])
+		})
 		var ovsMap, innerMap []interface{}
 		ovsMap = append(ovsMap, "map")
-		for key, val := range o.GoMap {
+		for _, key := range keys {
 			var mapSeg []interface{}
 			mapSeg = append(mapSeg, key)
-			mapSeg = append(mapSeg, val)
+			mapSeg = append(mapSeg, o.GoMap[key])
 			innerMap = append(innerMap, mapSeg)
 		}
 		ovsMap = append(ovsMap, innerMap)
@@ -35,45 +45,55 @@ func (o OvsMap) MarshalJSON() ([]byte, error) {
 }
 
 // UnmarshalJSON unmarshals an OVSDB style Map from a byte array
-func (o *OvsMap) UnmarshalJSON(b []byte) (err error) {
+func (o *OvsMap) UnmarshalJSON(b []byte) error {
 	var oMap []interface{}
 	o.GoMap = make(map[interface{}]interface{})
-	if err := json.Unmarshal(b, &oMap); err == nil && len(oMap) > 1 {
-		innerSlice := oMap[1].([]interface{})
-		for _, val := range innerSlice {
-			f := val.([]interface{})
-			var k interface{}
-			switch f[0].(type) {
-			case []interface{}:
-				vSet := f[0].([]interface{})
-				if len(vSet) != 2 || vSet[0] == "map" {
-					return &json.UnmarshalTypeError{Value: reflect.ValueOf(oMap).String(), Type: reflect.TypeOf(*o)}
-				}
-				goSlice, err := ovsSliceToGoNotation(vSet)
-				if err != nil {
-					return err
-				}
-				k = goSlice
-			default:
-				k = f[0]
-			}
-			switch f[1].(type) {
-			case []interface{}:
-				vSet := f[1].([]interface{})
-				if len(vSet) != 2 || vSet[0] == "map" {
-					return &json.UnmarshalTypeError{Value: reflect.ValueOf(oMap).String(), Type: reflect.TypeOf(*o)}
-				}
-				goSlice, err := ovsSliceToGoNotation(vSet)
-				if err != nil {
-					return err
-				}
-				o.GoMap[k] = goSlice
-			default:
-				o.GoMap[k] = f[1]
-			}
+	if err := json.Unmarshal(b, &oMap); err != nil {
+		return err
+	}
+	typeErr := &json.UnmarshalTypeError{Value: reflect.ValueOf(oMap).String(), Type: reflect.TypeOf(*o)}
+	if len(oMap) != 2 || oMap[0] != "map" {
+		return typeErr
+	}
+	innerSlice, ok := oMap[1].([]interface{})
+	if !ok {
+		return typeErr
+	}
+	for _, val := range innerSlice {
+		f, ok := val.([]interface{})
+		if !ok || len(f) != 2 {
+			return typeErr
+		}
+		k, err := ovsMapAtomToGoNotation(f[0])
+		if err != nil {
+			return err
 		}
+		if k == nil {
+			return typeErr
+		}
+		v, err := ovsMapAtomToGoNotation(f[1])
+		if err != nil {
+			return err
+		}
+		if v == nil {
+			return typeErr
+		}
+		o.GoMap[k] = v
+	}
+	return nil
+}
+
+// ovsMapAtomToGoNotation converts a key or value of an OVSDB map to its Go
+// notation. It returns nil if the atom is not valid in a map.
+func ovsMapAtomToGoNotation(atom interface{}) (interface{}, error) {
+	vSet, ok := atom.([]interface{})
+	if !ok {
+		return atom, nil
+	}
+	if len(vSet) != 2 || vSet[0] == "map" {
+		return nil, nil
 	}
-	return err
+	return ovsSliceToGoNotation(vSet)
 }
 
 // NewOvsMap will return an OVSDB style map from a provided Golang Map
@@ -84,9 +104,141 @@ func NewOvsMap(goMap interface{}) (OvsMap, error) {
 	}
 
 	genMap := make(map[interface{}]interface{})
+	var keyType, valueType string
 	keys := v.MapKeys()
+	sort.Slice(keys, func(i, j int) bool {
+		return atomicLess(keys[i].Interface(), keys[j].Interface())
+	})
 	for _, key := range keys {
-		genMap[key.Interface()] = v.MapIndex(key).Interface()
+		k := key.Interface()
+		val := v.MapIndex(key).Interface()
+		if err := checkAtomicType("ovsmap key", k, &keyType); err != nil {
+			return OvsMap{}, err
+		}
+		if err := checkAtomicType("ovsmap value", val, &valueType); err != nil {
+			return OvsMap{}, err
+		}
+		genMap[k] = val
 	}
 	return OvsMap{genMap}, nil
 }
+
+// atomicType returns the OVSDB atomic type of a value, or an empty string if
+// it cannot be used as an element of an OVSDB set or map
+func atomicType(v interface{}) string {
+	if v == nil {
+		return ""
+	}
+	switch reflect.TypeOf(v).Kind() {
+	case reflect.String:
+		return TypeString
+	case reflect.Bool:
+		return TypeBoolean
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return TypeInteger
+	case reflect.Float32, reflect.Float64:
+		return TypeReal
+	case reflect.Struct:
+		if reflect.TypeOf(v) == reflect.TypeOf(UUID{}) {
+			return TypeUUID
+		}
+	}
+	return ""
+}
+
+// checkAtomicType checks that an element of a set or map is of an atomic type
+// and of the same type as the previous ones, whose type is stored in
+// elemType. It is set to the type of the first element.
+func checkAtomicType(what string, v interface{}, elemType *string) error {
+	t := atomicType(v)
+	if t == "" {
+		return fmt.Errorf("%s %v (%T) is not an atomic type", what, v, v)
+	}
+	if *elemType == "" {
+		*elemType = t
+	} else if t != *elemType {
+		return fmt.Errorf("%s %v (%T) is of type %s but the other elements are of type %s", what, v, v, t, *elemType)
+	}
+	return nil
+}
+
+// CompareAtomic compares two values of OVSDB atomic types, and returns -1, 0
+// or 1 if a is lower than, equal to or greater than b. Integers and reals are
+// compared by value, strings and UUIDs lexically, and false is lower than
+// true. Values of different atomic types are ordered by their type name, and
+// values that are not atomic by their string representation, so that the
+// order is always total.
+func CompareAtomic(a, b interface{}) int {
+	at, bt := atomicType(a), atomicType(b)
+	aNumber := at == TypeInteger || at == TypeReal
+	bNumber := bt == TypeInteger || bt == TypeReal
+	switch {
+	case at == TypeInteger && bt == TypeInteger:
+		return compareIntegers(reflect.ValueOf(a), reflect.ValueOf(b))
+	case aNumber && bNumber:
+		af, _ := toFloat(a)
+		bf, _ := toFloat(b)
+		return compareOrdered(af < bf, af > bf)
+	case at != bt:
+		if at == "" || bt == "" {
+			break
+		}
+		return strings.Compare(at, bt)
+	case at == TypeString:
+		return strings.Compare(reflect.ValueOf(a).String(), reflect.ValueOf(b).String())
+	case at == TypeBoolean:
+		ab, bb := reflect.ValueOf(a).Bool(), reflect.ValueOf(b).Bool()
+		return compareOrdered(!ab && bb, ab && !bb)
+	case at == TypeUUID:
+		return strings.Compare(a.(UUID).GoUUID, b.(UUID).GoUUID)
+	}
+	return strings.Compare(fmt.Sprint(a), fmt.Sprint(b))
+}
+
+// compareIntegers compares two signed or unsigned integers without converting
+// them to floats, which would round the large ones
+func compareIntegers(a, b reflect.Value) int {
+	aSigned := a.Kind() >= reflect.Int && a.Kind() <= reflect.Int64
+	bSigned := b.Kind() >= reflect.Int && b.Kind() <= reflect.Int64
+	switch {
+	case aSigned && bSigned:
+		return compareOrdered(a.Int() < b.Int(), a.Int() > b.Int())
+	case aSigned:
+		if a.Int() < 0 {
+			return -1
+		}
+		return compareOrdered(uint64(a.Int()) < b.Uint(), uint64(a.Int()) > b.Uint())
+	case bSigned:
+		return -compareIntegers(b, a)
+	}
+	return compareOrdered(a.Uint() < b.Uint(), a.Uint() > b.Uint())
+}
+
+func compareOrdered(less, greater bool) int {
+	if less {
+		return -1
+	}
+	if greater {
+		return 1
+	}
+	return 0
+}
+
+// atomicLess orders two map keys, see CompareAtomic
+func atomicLess(a, b interface{}) bool {
+	return CompareAtomic(a, b) < 0
+}
+
+func toFloat(v interface{}) (float64, bool) {
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(rv.Int()), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(rv.Uint()), true
+	case reflect.Float32, reflect.Float64:
+		return rv.Float(), true
+	}
+	return 0, false
+}
@@ -60,3 +60,65 @@ func BenchmarkMapUnmarshalJSON5(b *testing.B) {
 func BenchmarkMapUnmarshalJSON8(b *testing.B) {
 	benchmarkMapUnmarshalJSON([]byte(`[ "map", [["foo","bar"],["baz", "quuz"],["foofoo", "foobar"],["foobaz", "fooquuz"], ["barfoo", "barbar"],["barbaz", "barquux"],["bazfoo", "bazbar"], ["bazbaz", "bazquux"]]]`), b)
 }
+
+func TestNewOvsMapElementTypes(t *testing.T) {
+	tests := []struct {
+		name string
+		m    interface{}
+		err  string
+	}{
+		{"strings", map[string]string{"foo": "bar"}, ""},
+		{"integers to uuids", map[int]UUID{1: {GoUUID: "foo"}, 2: {GoUUID: "bar"}}, ""},
+		{"unsupported value", map[string]interface{}{"foo": map[string]string{"bar": "baz"}}, "ovsmap value map[bar:baz] (map[string]string) is not an atomic type"},
+		{"mixed keys", map[interface{}]string{1: "foo", "bar": "baz"}, "ovsmap key bar (string) is of type string but the other elements are of type integer"},
+		{"mixed values", map[string]interface{}{"a": true, "b": "true"}, "ovsmap value true (string) is of type string but the other elements are of type boolean"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewOvsMap(tt.m)
+			if tt.err == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || err.Error() != tt.err {
+				t.Fatalf("got error %v, wanted %s", err, tt.err)
+			}
+		})
+	}
+}
+
+func TestCompareAtomic(t *testing.T) {
+	tests := []struct {
+		name     string
+		a        interface{}
+		b        interface{}
+		expected int
+	}{
+		{"integers", 2, 10, -1},
+		{"equal integers", 10, 10, 0},
+		{"negative integers", -10, 2, -1},
+		{"large integers", int64(1<<53 + 1), int64(1 << 53), 1},
+		{"signed and unsigned", -1, uint(1), -1},
+		{"unsigned and signed", uint64(1 << 63), int64(1<<63 - 1), 1},
+		{"integer and real", 2, 1.5, 1},
+		{"equal integer and real", 2, 2.0, 0},
+		{"reals", 0.5, 1.5, -1},
+		{"strings", "10", "2", -1},
+		{"equal strings", "a", "a", 0},
+		{"booleans", true, false, 1},
+		{"uuids", validUUID0, validUUID1, -1},
+		{"different types", "a", true, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := CompareAtomic(tt.a, tt.b); got != tt.expected {
+				t.Fatalf("CompareAtomic(%v, %v) = %d, wanted %d", tt.a, tt.b, got, tt.expected)
+			}
+			if got := CompareAtomic(tt.b, tt.a); got != -tt.expected {
+				t.Fatalf("CompareAtomic(%v, %v) = %d, wanted %d", tt.b, tt.a, got, -tt.expected)
+			}
+		})
+	}
+}
@@ -2,6 +2,7 @@ package ovsdb
 
 import (
 	"encoding/json"
+	"fmt"
 )
 
 const (
@@ -46,7 +47,10 @@ type Operation struct {
 
 // MarshalJSON marshalls 'Operation' to a byte array
 // For 'select' operations, we don't omit the 'Where' field
-// to allow selecting all rows of a table
+// to allow selecting all rows of a table, and for 'wait' operations
+// we don't omit an empty, non-nil 'Rows' field to allow waiting for
+// no row to match. 'comment' operations only have the comment, and 'assert'
+// operations only have the lock.
 func (o Operation) MarshalJSON() ([]byte, error) {
 	type OpAlias Operation
 	switch o.Op {
@@ -62,13 +66,40 @@ func (o Operation) MarshalJSON() ([]byte, error) {
 			Where:   where,
 			OpAlias: (OpAlias)(o),
 		})
-	default:
+	case "comment":
+		// a comment operation has no table
 		return json.Marshal(&struct {
-			OpAlias
+			Op      string  `json:"op"`
+			Comment *string `json:"comment"`
 		}{
-			OpAlias: (OpAlias)(o),
+			Op:      o.Op,
+			Comment: o.Comment,
 		})
+	case "assert":
+		// an assert operation has no table
+		return json.Marshal(&struct {
+			Op   string  `json:"op"`
+			Lock *string `json:"lock"`
+		}{
+			Op:   o.Op,
+			Lock: o.Lock,
+		})
+	case "wait":
+		if o.Rows != nil {
+			return json.Marshal(&struct {
+				Rows []Row `json:"rows"`
+				OpAlias
+			}{
+				Rows:    o.Rows,
+				OpAlias: (OpAlias)(o),
+			})
+		}
 	}
+	return json.Marshal(&struct {
+		OpAlias
+	}{
+		OpAlias: (OpAlias)(o),
+	})
 }
 
 // MonitorRequests represents a group of monitor requests according to RFC7047
@@ -86,6 +117,14 @@ type MonitorRequest struct {
 	Select  *MonitorSelect `json:"select,omitempty"`
 }
 
+// MonitorCondChangeRequest represents a request to change the conditions of a
+// table in an existing monitor, as described in ovsdb-server(7). An empty list
+// of conditions matches every row.
+type MonitorCondChangeRequest struct {
+	Columns []string    `json:"columns,omitempty"`
+	Where   []Condition `json:"where"`
+}
+
 // TransactResponse represents the response to a Transact Operation
 type TransactResponse struct {
 	Result []OperationResult `json:"result"`
@@ -101,6 +140,26 @@ type OperationResult struct {
 	Rows    []Row  `json:"rows,omitempty"`
 }
 
+// NamedUUIDs returns the UUIDs the server assigned to the rows inserted by
+// the given operations, keyed by the uuid-name they were inserted with.
+// The results must be the ones returned for those same operations.
+func NamedUUIDs(ops []Operation, results []OperationResult) (map[string]string, error) {
+	if len(results) < len(ops) {
+		return nil, fmt.Errorf("%d operations submitted but only %d results received", len(ops), len(results))
+	}
+	uuids := make(map[string]string)
+	for i, op := range ops {
+		if op.Op != OperationInsert || op.UUIDName == "" {
+			continue
+		}
+		if results[i].Error != "" || results[i].UUID.GoUUID == "" {
+			return nil, fmt.Errorf("no uuid was assigned to named-uuid %s", op.UUIDName)
+		}
+		uuids[op.UUIDName] = results[i].UUID.GoUUID
+	}
+	return uuids, nil
+}
+
 func ovsSliceToGoNotation(val interface{}) (interface{}, error) {
 	switch sl := val.(type) {
 	case []interface{}:
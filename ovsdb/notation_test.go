@@ -167,6 +167,79 @@ func TestNewNamedUUID(t *testing.T) {
 	}
 }
 
+func TestNamedUUIDs(t *testing.T) {
+	ops := []Operation{
+		{
+			Op:       OperationInsert,
+			Table:    "Logical_Switch",
+			Row:      Row{"name": "ls0"},
+			UUIDName: "ls0",
+		},
+		{
+			Op:       OperationInsert,
+			Table:    "Logical_Switch_Port",
+			Row:      Row{"name": "lsp0", "switch": UUID{GoUUID: "ls0"}},
+			UUIDName: "lsp0",
+		},
+		{
+			Op:    OperationInsert,
+			Table: "Logical_Router",
+			Row:   Row{"name": "lr0"},
+		},
+	}
+	b, err := json.Marshal(ops[1])
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"op":"insert","table":"Logical_Switch_Port","row":{"name":"lsp0","switch":["named-uuid","ls0"]},"uuid-name":"lsp0"}`, string(b))
+
+	results := []OperationResult{
+		{UUID: UUID{GoUUID: "2f77b348-9768-4866-b761-89d5177ecda0"}},
+		{UUID: UUID{GoUUID: "2f77b348-9768-4866-b761-89d5177ecda1"}},
+		{UUID: UUID{GoUUID: "2f77b348-9768-4866-b761-89d5177ecda2"}},
+	}
+	uuids, err := NamedUUIDs(ops, results)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{
+		"ls0":  "2f77b348-9768-4866-b761-89d5177ecda0",
+		"lsp0": "2f77b348-9768-4866-b761-89d5177ecda1",
+	}, uuids)
+
+	_, err = NamedUUIDs(ops, results[:2])
+	assert.Error(t, err)
+
+	results[0] = OperationResult{Error: "constraint violation"}
+	_, err = NamedUUIDs(ops, results)
+	assert.Error(t, err)
+}
+
+func TestWaitOperationSerialization(t *testing.T) {
+	timeout := 1000
+	op := Operation{
+		Op:      OperationWait,
+		Table:   "Logical_Switch",
+		Timeout: &timeout,
+		Where:   []Condition{NewCondition("name", ConditionEqual, "ls0")},
+		Columns: []string{"name"},
+		Until:   string(WaitConditionNotEqual),
+		Rows:    []Row{{"name": "ls0"}},
+	}
+	b, err := json.Marshal(op)
+	require.NoError(t, err)
+	expected := `{"op":"wait","table":"Logical_Switch","timeout":1000,"where":[["name","==","ls0"]],"columns":["name"],"until":"!=","rows":[{"name":"ls0"}]}`
+	assert.JSONEq(t, expected, string(b))
+
+	// a zero timeout must not be omitted, it makes the wait fail right away
+	timeout = 0
+	b, err = json.Marshal(op)
+	require.NoError(t, err)
+	assert.Contains(t, string(b), `"timeout":0`)
+
+	// neither must an empty list of rows, which waits for no row to match
+	op.Rows = []Row{}
+	b, err = json.Marshal(op)
+	require.NoError(t, err)
+	assert.Contains(t, string(b), `"rows":[]`)
+}
+
 func TestNewMutation(t *testing.T) {
 	mutation := NewMutation("column", "+=", 1)
 	mutationStr, _ := json.Marshal(mutation)
@@ -7,6 +7,8 @@ const (
 	ConditionalMonitorRPC = "monitor_cond"
 	// ConditionalMonitorSinceRPC is the monitor_cond_since RPC method
 	ConditionalMonitorSinceRPC = "monitor_cond_since"
+	// ConditionalMonitorChangeRPC is the monitor_cond_change RPC method
+	ConditionalMonitorChangeRPC = "monitor_cond_change"
 )
 
 // NewEchoArgs creates a new set of arguments for an echo RPC
@@ -48,6 +50,11 @@ func NewMonitorCondSinceArgs(database string, value interface{}, requests map[st
 	return []interface{}{database, value, requests, lastTransactionID}
 }
 
+// NewMonitorCondChangeArgs creates a new set of arguments for a monitor_cond_change RPC
+func NewMonitorCondChangeArgs(value interface{}, newValue interface{}, requests map[string]MonitorCondChangeRequest) []interface{} {
+	return []interface{}{value, newValue, requests}
+}
+
 // NewMonitorCancelArgs creates a new set of arguments for a monitor_cancel RPC
 func NewMonitorCancelArgs(value interface{}) []interface{} {
 	return []interface{}{value}
@@ -58,6 +65,11 @@ func NewLockArgs(id interface{}) []interface{} {
 	return []interface{}{id}
 }
 
+// LockResult is the result of a lock or steal RPC
+type LockResult struct {
+	Locked bool `json:"locked"`
+}
+
 // NotificationHandler is the interface that must be implemented to receive notifications
 type NotificationHandler interface {
 	// RFC 7047 section 4.1.6 Update Notification
@@ -78,6 +78,25 @@ func TestNewMonitorArgs(t *testing.T) {
 	}
 }
 
+func TestNewMonitorCondArgs(t *testing.T) {
+	database := "Open_vSwitch"
+	value := 1
+	r := MonitorRequest{
+		Columns: []string{"name"},
+		Where:   []Condition{NewCondition("name", ConditionEqual, "br-int")},
+		Select:  NewDefaultMonitorSelect(),
+	}
+	requests := make(map[string]MonitorRequest)
+	requests["Bridge"] = r
+
+	args := NewMonitorArgs(database, value, requests)
+	argString, _ := json.Marshal(args)
+	expected := `["Open_vSwitch",1,{"Bridge":{"columns":["name"],"where":[["name","==","br-int"]],"select":{"initial":true,"insert":true,"delete":true,"modify":true}}}]`
+	if string(argString) != expected {
+		t.Error("Expected: ", expected, " Got: ", string(argString))
+	}
+}
+
 func TestNewMonitorCancelArgs(t *testing.T) {
 	value := 1
 	args := NewMonitorCancelArgs(value)
@@ -88,6 +107,19 @@ func TestNewMonitorCancelArgs(t *testing.T) {
 	}
 }
 
+func TestNewMonitorCondChangeArgs(t *testing.T) {
+	requests := map[string]MonitorCondChangeRequest{
+		"Bridge": {Where: []Condition{NewCondition("name", ConditionEqual, "br0")}},
+		"Port":   {Where: []Condition{}},
+	}
+	args := NewMonitorCondChangeArgs(1, 2, requests)
+	argString, _ := json.Marshal(args)
+	expected := `[1,2,{"Bridge":{"where":[["name","==","br0"]]},"Port":{"where":[]}}]`
+	if string(argString) != expected {
+		t.Error("Expected: ", expected, " Got: ", string(argString))
+	}
+}
+
 func TestNewLockArgs(t *testing.T) {
 	id := "testId"
 	args := NewLockArgs(id)
@@ -30,6 +30,52 @@ func (schema DatabaseSchema) Table(tableName string) *TableSchema {
 	return nil
 }
 
+// IsGarbageCollected returns whether the server deletes the rows of a table
+// once no row references them with a strong reference, which is the case of
+// the tables that are not root tables, unless no table of the database is a
+// root table, in which case none of them is garbage collected (see ovsdb(5))
+func (schema DatabaseSchema) IsGarbageCollected(tableName string) bool {
+	table, ok := schema.Tables[tableName]
+	if !ok || table.IsRoot {
+		return false
+	}
+	for _, other := range schema.Tables {
+		if other.IsRoot {
+			return true
+		}
+	}
+	return false
+}
+
+// StrongReferences returns the columns of each table that reference the rows
+// of the given table with a strong reference, as keys or values
+func (schema DatabaseSchema) StrongReferences(tableName string) map[string][]string {
+	references := map[string][]string{}
+	for name, table := range schema.Tables {
+		for column, columnSchema := range table.Columns {
+			if columnSchema.TypeObj == nil {
+				continue
+			}
+			if isStrongReference(columnSchema.TypeObj.Key, tableName) || isStrongReference(columnSchema.TypeObj.Value, tableName) {
+				references[name] = append(references[name], column)
+			}
+		}
+	}
+	return references
+}
+
+func isStrongReference(b *BaseType, tableName string) bool {
+	if b == nil || b.Type != TypeUUID {
+		return false
+	}
+	refTable, err := b.RefTable()
+	if err != nil || refTable != tableName {
+		return false
+	}
+	refType, _ := b.RefType()
+	return refType == Strong
+}
+
 // Print will print the contents of the DatabaseSchema
 func (schema DatabaseSchema) Print(w io.Writer) {
 	fmt.Fprintf(w, "%s, (%s)\n", schema.Name, schema.Version)
@@ -64,7 +110,15 @@ func SchemaFromFile(f *os.File) (DatabaseSchema, error) {
 func (schema DatabaseSchema) ValidateOperations(operations ...Operation) bool {
 	for _, op := range operations {
 		switch op.Op {
-		case OperationAbort, OperationAssert, OperationComment, OperationCommit, OperationWait:
+		case OperationComment:
+			if op.Comment == nil || *op.Comment == "" {
+				return false
+			}
+		case OperationAssert:
+			if op.Lock == nil || *op.Lock == "" {
+				return false
+			}
+		case OperationAbort, OperationCommit, OperationWait:
 			continue
 		case OperationInsert, OperationSelect, OperationUpdate, OperationMutate, OperationDelete:
 			table, ok := schema.Tables[op.Table]
@@ -104,6 +158,7 @@ func (schema DatabaseSchema) ValidateOperations(operations ...Operation) bool {
 type TableSchema struct {
 	Columns map[string]*ColumnSchema `json:"columns"`
 	Indexes [][]string               `json:"indexes,omitempty"`
+	IsRoot  bool                     `json:"isRoot,omitempty"`
 }
 
 // Column returns the Column object for a specific column name
@@ -8,6 +8,7 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestSchema(t *testing.T) {
@@ -406,6 +407,44 @@ func TestTable(t *testing.T) {
 	})
 }
 
+func TestSchemaGarbageCollection(t *testing.T) {
+	schemaJ := []byte(`{"name": "TestSchema",
+		  "version": "0.0.0",
+		  "tables": {
+		    "root": {
+		      "columns": {
+		        "strong": {"type": {"key": {"type": "uuid", "refTable": "child"}, "min": 0, "max": "unlimited"}},
+		        "weak": {"type": {"key": {"type": "uuid", "refTable": "child", "refType": "weak"}, "min": 0, "max": "unlimited"}},
+		        "by_name": {"type": {"key": "string", "value": {"type": "uuid", "refTable": "child", "refType": "strong"}, "min": 0, "max": "unlimited"}}
+		      },
+		      "isRoot": true
+		    },
+		    "child": {
+		      "columns": {
+		        "name": {"type": "string"}
+		      }
+		    }
+		}
+	    }`)
+
+	var schema DatabaseSchema
+	require.NoError(t, json.Unmarshal(schemaJ, &schema))
+	assert.True(t, schema.Tables["root"].IsRoot)
+	assert.False(t, schema.IsGarbageCollected("root"))
+	assert.True(t, schema.IsGarbageCollected("child"))
+	assert.False(t, schema.IsGarbageCollected("unknown"))
+	references := schema.StrongReferences("child")
+	require.Contains(t, references, "root")
+	assert.ElementsMatch(t, []string{"strong", "by_name"}, references["root"])
+	assert.Empty(t, schema.StrongReferences("root"))
+
+	// no table is garbage collected if none of them is a root table
+	root := schema.Tables["root"]
+	root.IsRoot = false
+	schema.Tables["root"] = root
+	assert.False(t, schema.IsGarbageCollected("child"))
+}
+
 func TestBaseTypeMarshalUnmarshalJSON(t *testing.T) {
 	datapath := "Datapath"
 	zero := 0
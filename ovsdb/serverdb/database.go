@@ -161,6 +161,31 @@ func (a *Database) CloneModel() model.Model {
 	return a.DeepCopy()
 }
 
+func (a *Database) CloneModelColumns(columns []string) model.Model {
+	b := &Database{UUID: a.UUID}
+	for _, column := range columns {
+		switch column {
+		case "cid":
+			b.Cid = copyDatabaseCid(a.Cid)
+		case "connected":
+			b.Connected = a.Connected
+		case "index":
+			b.Index = copyDatabaseIndex(a.Index)
+		case "leader":
+			b.Leader = a.Leader
+		case "model":
+			b.Model = a.Model
+		case "name":
+			b.Name = a.Name
+		case "schema":
+			b.Schema = copyDatabaseSchema(a.Schema)
+		case "sid":
+			b.Sid = copyDatabaseSid(a.Sid)
+		}
+	}
+	return b
+}
+
 func (a *Database) Equals(b *Database) bool {
 	return a.UUID == b.UUID &&
 		equalDatabaseCid(a.Cid, b.Cid) &&
@@ -179,4 +204,5 @@ func (a *Database) EqualsModel(b model.Model) bool {
 }
 
 var _ model.CloneableModel = &Database{}
+var _ model.ColumnCloneableModel = &Database{}
 var _ model.ComparableModel = &Database{}
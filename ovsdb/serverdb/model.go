@@ -88,6 +88,7 @@ var schema = `{
   }
 }`
 
+// Schema returns the schema of the database the models were generated from
 func Schema() ovsdb.DatabaseSchema {
 	var s ovsdb.DatabaseSchema
 	err := json.Unmarshal([]byte(schema), &s)
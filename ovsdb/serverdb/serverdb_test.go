@@ -0,0 +1,81 @@
+package serverdb
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/ovn-org/libovsdb/model"
+	"github.com/ovn-org/libovsdb/ovsdb"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDatabaseModel(t *testing.T) {
+	dbModel, err := FullDatabaseModel()
+	require.NoError(t, err)
+	_, errs := model.NewDatabaseModel(Schema(), dbModel)
+	require.Empty(t, errs)
+}
+
+func TestDatabaseRow(t *testing.T) {
+	dbModel, err := FullDatabaseModel()
+	require.NoError(t, err)
+	dbm, errs := model.NewDatabaseModel(Schema(), dbModel)
+	require.Empty(t, errs)
+
+	cid := "6c8d4bf4-5b17-4cbb-a1b3-a4a1e0d1a8b7"
+	rawRow := []byte(`{
+		"_uuid": ["uuid", "0d4c3d7b-c28f-4f9b-8dc4-a8db3c4e9f3a"],
+		"name": "OVN_Northbound",
+		"model": "clustered",
+		"connected": true,
+		"leader": true,
+		"cid": ["uuid", "` + cid + `"],
+		"sid": ["set", []],
+		"index": ["set", [42]],
+		"schema": ["set", []]
+	}`)
+	var row ovsdb.Row
+	require.NoError(t, json.Unmarshal(rawRow, &row))
+
+	database := &Database{}
+	info, err := dbm.NewModelInfo(database)
+	require.NoError(t, err)
+	require.NoError(t, dbm.Mapper.GetRowData(&row, info))
+
+	index := 42
+	assert.Equal(t, &Database{
+		UUID:      "0d4c3d7b-c28f-4f9b-8dc4-a8db3c4e9f3a",
+		Name:      "OVN_Northbound",
+		Model:     DatabaseModelClustered,
+		Connected: true,
+		Leader:    true,
+		Cid:       &cid,
+		Index:     &index,
+	}, database)
+}
+
+func TestDatabaseCloneModelColumns(t *testing.T) {
+	cid := "6c8d4bf4-5b17-4cbb-a1b3-a4a1e0d1a8b7"
+	index := 42
+	database := &Database{
+		UUID:      "0d4c3d7b-c28f-4f9b-8dc4-a8db3c4e9f3a",
+		Name:      "OVN_Northbound",
+		Model:     DatabaseModelClustered,
+		Connected: true,
+		Leader:    true,
+		Cid:       &cid,
+		Index:     &index,
+	}
+
+	clone := model.CloneColumns(database, []string{"name", "leader", "index"}).(*Database)
+	assert.Equal(t, &Database{
+		UUID:   database.UUID,
+		Name:   "OVN_Northbound",
+		Leader: true,
+		Index:  &index,
+	}, clone)
+	// the fields are deep copied
+	*clone.Index = 1
+	assert.Equal(t, 42, *database.Index)
+}
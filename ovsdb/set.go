@@ -33,8 +33,13 @@ func NewOvsSet(obj interface{}) (OvsSet, error) {
 
 	switch v.Kind() {
 	case reflect.Slice, reflect.Array:
+		var elemType string
 		for i := 0; i < v.Len(); i++ {
-			ovsSet = append(ovsSet, v.Index(i).Interface())
+			elem := v.Index(i).Interface()
+			if err := checkAtomicType("ovsset element", elem, &elemType); err != nil {
+				return OvsSet{}, err
+			}
+			ovsSet = append(ovsSet, elem)
 		}
 	case reflect.String,
 		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
@@ -88,13 +93,20 @@ func (o *OvsSet) UnmarshalJSON(b []byte) (err error) {
 		oSet = inter.([]interface{})
 		// it's a single uuid object
 		if len(oSet) == 2 && (oSet[0] == "uuid" || oSet[0] == "named-uuid") {
-			return addToSet(o, UUID{GoUUID: oSet[1].(string)})
+			id, ok := oSet[1].(string)
+			if !ok {
+				return &json.UnmarshalTypeError{Value: reflect.ValueOf(inter).String(), Type: reflect.TypeOf(*o)}
+			}
+			return addToSet(o, UUID{GoUUID: id})
 		}
-		if oSet[0] != "set" {
+		if len(oSet) != 2 || oSet[0] != "set" {
 			// it is a slice, but is not a set
 			return &json.UnmarshalTypeError{Value: reflect.ValueOf(inter).String(), Type: reflect.TypeOf(*o)}
 		}
-		innerSet := oSet[1].([]interface{})
+		innerSet, ok := oSet[1].([]interface{})
+		if !ok {
+			return &json.UnmarshalTypeError{Value: reflect.ValueOf(inter).String(), Type: reflect.TypeOf(*o)}
+		}
 		for _, val := range innerSet {
 			err := addToSet(o, val)
 			if err != nil {
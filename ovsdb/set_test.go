@@ -169,3 +169,33 @@ func setify(i interface{}) []byte {
 	}
 	return []byte(fmt.Sprintf(`[ "set", [ "%s" ]]`, strings.Join(s, `","`)))
 }
+
+func TestNewOvsSetElementTypes(t *testing.T) {
+	tests := []struct {
+		name string
+		set  interface{}
+		err  string
+	}{
+		{"strings", []string{"foo", "bar"}, ""},
+		{"integers of several sizes", []interface{}{1, int64(2), uint8(3)}, ""},
+		{"uuids", []UUID{{GoUUID: testUUIDs[0]}, {GoUUID: testUUIDs[1]}}, ""},
+		{"mixed strings and integers", []interface{}{"foo", 1}, "ovsset element 1 (int) is of type integer but the other elements are of type string"},
+		{"mixed integers and reals", []interface{}{1, 2.5}, "ovsset element 2.5 (float64) is of type real but the other elements are of type integer"},
+		{"unsupported element", []interface{}{[]string{"foo"}}, "ovsset element [foo] ([]string) is not an atomic type"},
+		{"nil element", []interface{}{"foo", nil}, "ovsset element <nil> (<nil>) is not an atomic type"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewOvsSet(tt.set)
+			if tt.err == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || err.Error() != tt.err {
+				t.Fatalf("got error %v, wanted %s", err, tt.err)
+			}
+		})
+	}
+}
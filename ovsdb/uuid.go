@@ -7,6 +7,7 @@ import (
 )
 
 var validUUID = regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{12}$`)
+var validNamedUUID = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_]*$`)
 
 // UUID is a UUID according to RFC7047
 type UUID struct {
@@ -27,12 +28,25 @@ func (u UUID) MarshalJSON() ([]byte, error) {
 }
 
 // UnmarshalJSON will unmarshal a JSON encoded byte array to a OVSDB style UUID
-func (u *UUID) UnmarshalJSON(b []byte) (err error) {
+func (u *UUID) UnmarshalJSON(b []byte) error {
 	var ovsUUID []string
-	if err := json.Unmarshal(b, &ovsUUID); err == nil {
-		u.GoUUID = ovsUUID[1]
+	if err := json.Unmarshal(b, &ovsUUID); err != nil {
+		return err
 	}
-	return err
+	if len(ovsUUID) != 2 || (ovsUUID[0] != "uuid" && ovsUUID[0] != "named-uuid") {
+		return fmt.Errorf("invalid uuid notation %v", ovsUUID)
+	}
+	u.GoUUID = ovsUUID[1]
+	return nil
+}
+
+// Validate checks that the UUID is either a valid RFC4122 UUID or a valid
+// named-uuid identifier
+func (u UUID) Validate() error {
+	if u.validateUUID() == nil {
+		return nil
+	}
+	return u.validateNamedUUID()
 }
 
 func (u UUID) validateUUID() error {
@@ -47,6 +61,13 @@ func (u UUID) validateUUID() error {
 	return nil
 }
 
+func (u UUID) validateNamedUUID() error {
+	if !validNamedUUID.MatchString(u.GoUUID) {
+		return fmt.Errorf("%q is neither a valid uuid nor a valid named-uuid", u.GoUUID)
+	}
+	return nil
+}
+
 func isNamed(uuid string) bool {
 	return len(uuid) > 0 && !validUUID.MatchString(uuid)
 }
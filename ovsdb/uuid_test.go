@@ -1,6 +1,9 @@
 package ovsdb
 
-import "testing"
+import (
+	"encoding/json"
+	"testing"
+)
 
 func TestUUIDIsNamed(t *testing.T) {
 	tests := []struct {
@@ -32,3 +35,112 @@ func TestUUIDIsNamed(t *testing.T) {
 		})
 	}
 }
+
+func TestUUIDValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		uuid    string
+		wantErr bool
+	}{
+		{
+			"uuid",
+			aUUID0,
+			false,
+		},
+		{
+			"named-uuid",
+			"row_0",
+			false,
+		},
+		{
+			"named-uuid starting with underscore",
+			"_row",
+			false,
+		},
+		{
+			"empty",
+			"",
+			true,
+		},
+		{
+			"named-uuid starting with a digit",
+			"0row",
+			true,
+		},
+		{
+			"named-uuid with invalid characters",
+			"row-0",
+			true,
+		},
+		{
+			"uppercase uuid",
+			"2F77B348-9768-4866-B761-89D5177ECDA0",
+			true,
+		},
+		{
+			"truncated uuid",
+			aUUID0[:35],
+			true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := UUID{GoUUID: tt.uuid}.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("UUID.Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestUUIDUnmarshalJSON(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    string
+		wantErr bool
+	}{
+		{
+			"uuid",
+			`["uuid","` + aUUID0 + `"]`,
+			aUUID0,
+			false,
+		},
+		{
+			"named-uuid",
+			`["named-uuid","row0"]`,
+			"row0",
+			false,
+		},
+		{
+			"empty array",
+			`[]`,
+			"",
+			true,
+		},
+		{
+			"wrong tag",
+			`["set","row0"]`,
+			"",
+			true,
+		},
+		{
+			"not an array",
+			`"row0"`,
+			"",
+			true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var u UUID
+			err := json.Unmarshal([]byte(tt.input), &u)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("UUID.UnmarshalJSON() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if u.GoUUID != tt.want {
+				t.Errorf("UUID.UnmarshalJSON() = %v, want %v", u.GoUUID, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,185 @@
+package ovsdb
+
+import (
+	"fmt"
+	"sort"
+)
+
+// ErrInvalidRow is returned when a row does not comply with the schema of
+// its table
+type ErrInvalidRow struct {
+	Table  string
+	Column string
+	Reason string
+}
+
+func (e *ErrInvalidRow) Error() string {
+	return fmt.Sprintf("invalid row for table %s, column %s: %s", e.Table, e.Column, e.Reason)
+}
+
+// ValidateRow checks a complete row, as inserted in the given table, against
+// the schema: the required columns must be present, the sets and maps must
+// have a number of elements between the min and max of their column, the
+// values of enumerations must be allowed, and the UUIDs must be valid uuids or
+// named-uuids. A column is required when the server could not fill it with its
+// default value: it holds at least one element, and that element references
+// another row or belongs to an enumeration that excludes the default value.
+// Columns that are not part of the schema are ignored.
+func ValidateRow(schema *DatabaseSchema, table string, row Row) error {
+	return validateRow(schema, table, row, true)
+}
+
+// ValidateRowColumns checks the columns a row contains like ValidateRow, but
+// does not require any column, as for the row of an update
+func ValidateRowColumns(schema *DatabaseSchema, table string, row Row) error {
+	return validateRow(schema, table, row, false)
+}
+
+func validateRow(schema *DatabaseSchema, table string, row Row, complete bool) error {
+	tableSchema := schema.Table(table)
+	if tableSchema == nil {
+		return fmt.Errorf("table %s not found in schema %s", table, schema.Name)
+	}
+	// validate the columns in order so that the same error is always returned
+	names := make([]string, 0, len(tableSchema.Columns))
+	for name := range tableSchema.Columns {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		column := tableSchema.Columns[name]
+		value, ok := row[name]
+		if !ok {
+			if complete && isRequired(column) {
+				return &ErrInvalidRow{Table: table, Column: name, Reason: "required column is missing"}
+			}
+			continue
+		}
+		if err := validateColumn(column, value); err != nil {
+			return &ErrInvalidRow{Table: table, Column: name, Reason: err.Error()}
+		}
+	}
+	return nil
+}
+
+// isRequired returns whether the default value of a column is rejected by the
+// server
+func isRequired(column *ColumnSchema) bool {
+	if column.TypeObj.Min() < 1 {
+		return false
+	}
+	for _, base := range []*BaseType{column.TypeObj.Key, column.TypeObj.Value} {
+		if base == nil {
+			continue
+		}
+		if base.refTable != nil {
+			return true
+		}
+		if len(base.Enum) > 0 && !inEnum(base, defaultAtom(base.Type)) {
+			return true
+		}
+	}
+	return false
+}
+
+func validateColumn(column *ColumnSchema, value interface{}) error {
+	columnType := column.TypeObj
+	switch column.Type {
+	case TypeSet:
+		// RFC7047 says a <set> may be an <atom> with a single element
+		elems := []interface{}{value}
+		if set, ok := value.(OvsSet); ok {
+			elems = set.GoSet
+		}
+		if err := validateSize("set", len(elems), columnType); err != nil {
+			return err
+		}
+		for _, elem := range elems {
+			if err := validateAtom(columnType.Key, elem); err != nil {
+				return err
+			}
+		}
+	case TypeMap:
+		m, ok := value.(OvsMap)
+		if !ok {
+			return NewErrWrongType("map column", "OvsMap", value)
+		}
+		if err := validateSize("map", len(m.GoMap), columnType); err != nil {
+			return err
+		}
+		for k, v := range m.GoMap {
+			if err := validateAtom(columnType.Key, k); err != nil {
+				return err
+			}
+			if err := validateAtom(columnType.Value, v); err != nil {
+				return err
+			}
+		}
+	default:
+		return validateAtom(columnType.Key, value)
+	}
+	return nil
+}
+
+func validateSize(kind string, size int, columnType *ColumnType) error {
+	if size < columnType.Min() {
+		return fmt.Errorf("%s has %d elements, expected at least %d", kind, size, columnType.Min())
+	}
+	if columnType.Max() != Unlimited && size > columnType.Max() {
+		return fmt.Errorf("%s has %d elements, expected at most %d", kind, size, columnType.Max())
+	}
+	return nil
+}
+
+func validateAtom(base *BaseType, atom interface{}) error {
+	if base.Type == TypeUUID {
+		uuid, ok := atom.(UUID)
+		if !ok {
+			return NewErrWrongType("uuid atom", "UUID", atom)
+		}
+		if err := uuid.Validate(); err != nil {
+			return err
+		}
+	}
+	if len(base.Enum) > 0 && !inEnum(base, atom) {
+		return fmt.Errorf("value %v is not one of %v", atom, base.Enum)
+	}
+	return nil
+}
+
+// inEnum returns whether an atom is one of the values of the enumeration of a
+// base type. Numbers are compared by value as the enumeration is decoded from
+// JSON.
+func inEnum(base *BaseType, atom interface{}) bool {
+	for _, value := range base.Enum {
+		if base.Type == TypeInteger || base.Type == TypeReal {
+			a, aok := toFloat(atom)
+			v, vok := toFloat(value)
+			if aok && vok && a == v {
+				return true
+			}
+			continue
+		}
+		if value == atom {
+			return true
+		}
+	}
+	return false
+}
+
+// defaultAtom returns the default value of an atomic type, as per RFC7047
+func defaultAtom(atomicType string) interface{} {
+	switch atomicType {
+	case TypeInteger:
+		return 0
+	case TypeReal:
+		return 0.0
+	case TypeBoolean:
+		return false
+	case TypeString:
+		return ""
+	case TypeUUID:
+		return UUID{GoUUID: "00000000-0000-0000-0000-000000000000"}
+	}
+	return nil
+}
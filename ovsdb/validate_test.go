@@ -0,0 +1,130 @@
+package ovsdb
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var validateSchema = []byte(`{
+	"name": "Validate",
+	"version": "0.0.1",
+	"tables": {
+		"Parent": {
+			"columns": {
+				"name": {"type": "string"},
+				"child": {"type": {"key": {"type": "uuid", "refTable": "Child"}}},
+				"mode": {"type": {"key": {"type": "string", "enum": ["set", ["active", "standby"]]}}},
+				"level": {"type": {"key": {"type": "integer", "enum": ["set", [1, 2, 3]]}, "min": 0, "max": 1}},
+				"tags": {"type": {"key": "string", "min": 1, "max": 2}},
+				"options": {"type": {"key": "string", "value": {"type": "string", "enum": ["set", ["on", "off"]]}, "min": 0, "max": "unlimited"}}
+			}
+		},
+		"Child": {
+			"columns": {
+				"name": {"type": "string"}
+			}
+		}
+	}
+}`)
+
+func TestValidateRow(t *testing.T) {
+	var schema DatabaseSchema
+	require.NoError(t, json.Unmarshal(validateSchema, &schema))
+	valid := func() Row {
+		return Row{
+			"child": UUID{GoUUID: "child"},
+			"mode":  "active",
+			"level": OvsSet{GoSet: []interface{}{2}},
+			"tags":  OvsSet{GoSet: []interface{}{"a", "b"}},
+			"options": OvsMap{GoMap: map[interface{}]interface{}{
+				"foo": "on",
+			}},
+		}
+	}
+	tests := []struct {
+		name   string
+		modify func(Row)
+		column string
+	}{
+		{
+			"valid",
+			func(Row) {},
+			"",
+		},
+		{
+			"missing reference",
+			func(row Row) { delete(row, "child") },
+			"child",
+		},
+		{
+			"missing enum",
+			func(row Row) { delete(row, "mode") },
+			"mode",
+		},
+		{
+			"missing optional",
+			func(row Row) { delete(row, "level") },
+			"",
+		},
+		{
+			"enum out of range",
+			func(row Row) { row["mode"] = "passive" },
+			"mode",
+		},
+		{
+			"integer enum out of range",
+			func(row Row) { row["level"] = OvsSet{GoSet: []interface{}{4}} },
+			"level",
+		},
+		{
+			"single atom set",
+			func(row Row) { row["level"] = 3 },
+			"",
+		},
+		{
+			"map value enum out of range",
+			func(row Row) { row["options"] = OvsMap{GoMap: map[interface{}]interface{}{"foo": "maybe"}} },
+			"options",
+		},
+		{
+			"too many elements",
+			func(row Row) { row["tags"] = OvsSet{GoSet: []interface{}{"a", "b", "c"}} },
+			"tags",
+		},
+		{
+			"too few elements",
+			func(row Row) { row["tags"] = OvsSet{GoSet: []interface{}{}} },
+			"tags",
+		},
+		{
+			"invalid uuid",
+			func(row Row) { row["child"] = UUID{GoUUID: "not a uuid"} },
+			"child",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			row := valid()
+			tt.modify(row)
+			err := ValidateRow(&schema, "Parent", row)
+			if tt.column == "" {
+				assert.NoError(t, err)
+				return
+			}
+			var rowErr *ErrInvalidRow
+			require.True(t, errors.As(err, &rowErr), "unexpected error %v", err)
+			assert.Equal(t, "Parent", rowErr.Table)
+			assert.Equal(t, tt.column, rowErr.Column)
+		})
+	}
+
+	err := ValidateRow(&schema, "Parent", Row{"mode": "active"})
+	assert.EqualError(t, err, "invalid row for table Parent, column child: required column is missing")
+	assert.NoError(t, ValidateRowColumns(&schema, "Parent", Row{"mode": "active"}))
+	assert.Error(t, ValidateRowColumns(&schema, "Parent", Row{"mode": "passive"}))
+	assert.Error(t, ValidateRow(&schema, "Unknown", Row{}))
+}
@@ -0,0 +1,57 @@
+package ovsdb
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// SchemaVersion is the version of a database schema, which ovsdb(7) defines
+// as <major>.<minor>.<patch>
+type SchemaVersion struct {
+	Major int
+	Minor int
+	Patch int
+}
+
+// ParseSchemaVersion parses a schema version in the <major>.<minor>.<patch>
+// format, where each part is a non-negative integer
+func ParseSchemaVersion(version string) (SchemaVersion, error) {
+	parts := strings.Split(version, ".")
+	if len(parts) != 3 {
+		return SchemaVersion{}, fmt.Errorf("invalid schema version %q: expected <major>.<minor>.<patch>", version)
+	}
+	var numbers [3]int
+	for i, part := range parts {
+		n, err := strconv.ParseUint(part, 10, 31)
+		if err != nil {
+			return SchemaVersion{}, fmt.Errorf("invalid schema version %q: %q is not a valid number", version, part)
+		}
+		numbers[i] = int(n)
+	}
+	return SchemaVersion{Major: numbers[0], Minor: numbers[1], Patch: numbers[2]}, nil
+}
+
+// String returns the version in the <major>.<minor>.<patch> format
+func (v SchemaVersion) String() string {
+	return fmt.Sprintf("%d.%d.%d", v.Major, v.Minor, v.Patch)
+}
+
+// Compare returns -1, 0 or 1 if the version is lower than, equal to or greater
+// than the other version, respectively
+func (v SchemaVersion) Compare(other SchemaVersion) int {
+	for _, diff := range []int{v.Major - other.Major, v.Minor - other.Minor, v.Patch - other.Patch} {
+		if diff < 0 {
+			return -1
+		}
+		if diff > 0 {
+			return 1
+		}
+	}
+	return 0
+}
+
+// SchemaVersion returns the parsed version of the schema
+func (schema DatabaseSchema) SchemaVersion() (SchemaVersion, error) {
+	return ParseSchemaVersion(schema.Version)
+}
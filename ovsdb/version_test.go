@@ -0,0 +1,114 @@
+package ovsdb
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseSchemaVersion(t *testing.T) {
+	tests := []struct {
+		name    string
+		version string
+		want    SchemaVersion
+		wantErr bool
+	}{
+		{
+			"zero",
+			"0.0.0",
+			SchemaVersion{},
+			false,
+		},
+		{
+			"multiple digits",
+			"8.10.123",
+			SchemaVersion{Major: 8, Minor: 10, Patch: 123},
+			false,
+		},
+		{
+			"empty",
+			"",
+			SchemaVersion{},
+			true,
+		},
+		{
+			"missing patch",
+			"5.0",
+			SchemaVersion{},
+			true,
+		},
+		{
+			"too many parts",
+			"5.0.0.1",
+			SchemaVersion{},
+			true,
+		},
+		{
+			"negative",
+			"5.-1.0",
+			SchemaVersion{},
+			true,
+		},
+		{
+			"not a number",
+			"5.x.0",
+			SchemaVersion{},
+			true,
+		},
+		{
+			"pre-release suffix",
+			"5.0.0-rc1",
+			SchemaVersion{},
+			true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseSchemaVersion(tt.version)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.version, got.String())
+		})
+	}
+}
+
+func TestSchemaVersionCompare(t *testing.T) {
+	tests := []struct {
+		a    string
+		b    string
+		want int
+	}{
+		{"5.0.0", "5.0.0", 0},
+		{"5.0.0", "5.0.1", -1},
+		{"5.1.0", "5.0.9", 1},
+		{"4.10.0", "5.0.0", -1},
+		{"10.0.0", "9.9.9", 1},
+		{"5.10.0", "5.9.0", 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.a+" "+tt.b, func(t *testing.T) {
+			a, err := ParseSchemaVersion(tt.a)
+			require.NoError(t, err)
+			b, err := ParseSchemaVersion(tt.b)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, a.Compare(b))
+			assert.Equal(t, -tt.want, b.Compare(a))
+		})
+	}
+}
+
+func TestDatabaseSchemaVersion(t *testing.T) {
+	schema := DatabaseSchema{Name: "Open_vSwitch", Version: "8.3.0"}
+	version, err := schema.SchemaVersion()
+	require.NoError(t, err)
+	assert.Equal(t, SchemaVersion{Major: 8, Minor: 3}, version)
+
+	schema.Version = "8.3"
+	_, err = schema.SchemaVersion()
+	assert.Error(t, err)
+}
@@ -4,5 +4,19 @@ Package server provides an alpha-quality implementation of an OVSDB Server
 It is designed only to be used for testing the functionality of the client
 library such that assertions can be made on the cache that backs the
 client's monitor or the server
+
+The server keeps the rows of its databases in memory. Besides listening on a
+socket with Serve, it can serve an in-memory connection returned by Pipe, that
+a client dials with client.WithDialContext, so that tests do not need any
+external process:
+
+	srv, _ := server.NewOvsdbServer(database.NewInMemoryDatabase(clientDBModels), dbModel)
+	ovs, _ := client.NewOVSDBClient(clientDBModel, client.WithEndpoint("unix:/ovsdb.sock"),
+		client.WithDialContext(func(context.Context, string, string) (net.Conn, error) {
+			return srv.Pipe(), nil
+		}))
+
+Inject executes a transaction on the server as if a client had sent it, which
+notifies the monitors of the connected clients of its updates.
 */
 package server
@@ -33,6 +33,10 @@ type OvsdbServer struct {
 	monitorMutex sync.RWMutex
 	logger       logr.Logger
 	txnMutex     sync.Mutex
+	// locks contains the clients that own or wait for each lock, the
+	// owner first
+	locks      map[string][]*rpc2.Client
+	locksMutex sync.Mutex
 }
 
 // NewOvsdbServer returns a new OvsdbServer
@@ -47,6 +51,7 @@ func NewOvsdbServer(db database.Database, models ...model.DatabaseModel) (*Ovsdb
 		monitors:     make(map[*rpc2.Client]*connectionMonitors),
 		monitorMutex: sync.RWMutex{},
 		logger:       l,
+		locks:        make(map[string][]*rpc2.Client),
 	}
 	o.modelsMutex.Lock()
 	for _, model := range models {
@@ -67,9 +72,11 @@ func NewOvsdbServer(db database.Database, models ...model.DatabaseModel) (*Ovsdb
 	o.srv.Handle("monitor_cond", o.MonitorCond)
 	o.srv.Handle("monitor_cond_since", o.MonitorCondSince)
 	o.srv.Handle("monitor_cancel", o.MonitorCancel)
+	o.srv.Handle("lock", o.Lock)
 	o.srv.Handle("steal", o.Steal)
 	o.srv.Handle("unlock", o.Unlock)
 	o.srv.Handle("echo", o.Echo)
+	o.srv.OnDisconnect(o.releaseLocks)
 	return o, nil
 }
 
@@ -102,6 +109,21 @@ func (o *OvsdbServer) Serve(protocol string, path string) error {
 	}
 }
 
+// ServeConn serves a single connection, like one end of a net.Pipe, until it
+// is closed
+func (o *OvsdbServer) ServeConn(conn net.Conn) {
+	o.srv.ServeCodec(jsonrpc.NewJSONCodec(conn))
+}
+
+// Pipe returns one end of an in-memory connection whose other end the server
+// serves, which a client can use instead of a socket, e.g. by dialing it with
+// client.WithDialContext
+func (o *OvsdbServer) Pipe() net.Conn {
+	serverConn, clientConn := net.Pipe()
+	go o.ServeConn(serverConn)
+	return clientConn
+}
+
 func isClosed(ch <-chan struct{}) bool {
 	select {
 	case <-ch:
@@ -165,12 +187,6 @@ func (o *OvsdbServer) GetSchema(client *rpc2.Client, args []interface{}, reply *
 
 // Transact issues a new database transaction and returns the results
 func (o *OvsdbServer) Transact(client *rpc2.Client, args []json.RawMessage, reply *[]*ovsdb.OperationResult) error {
-	// While allowing other rpc handlers to run in parallel, this ovsdb server expects transactions
-	// to be serialized. The following mutex ensures that.
-	// Ref: https://github.com/cenkalti/rpc2/blob/c1acbc6ec984b7ae6830b6a36b62f008d5aefc4c/client.go#L187
-	o.txnMutex.Lock()
-	defer o.txnMutex.Unlock()
-
 	if len(args) < 2 {
 		return fmt.Errorf("not enough args")
 	}
@@ -180,52 +196,119 @@ func (o *OvsdbServer) Transact(client *rpc2.Client, args []json.RawMessage, repl
 		return fmt.Errorf("database %v is not a string", args[0])
 	}
 	var ops []ovsdb.Operation
-	namedUUID := make(map[string]ovsdb.UUID)
 	for i := 1; i < len(args); i++ {
 		var op ovsdb.Operation
 		err = json.Unmarshal(args[i], &op)
 		if err != nil {
 			return err
 		}
-		if op.UUIDName != "" {
-			newUUID := uuid.NewString()
-			namedUUID[op.UUIDName] = ovsdb.UUID{GoUUID: newUUID}
-			op.UUIDName = newUUID
-		}
-		for i, condition := range op.Where {
-			op.Where[i].Value = expandNamedUUID(condition.Value, namedUUID)
-		}
-		for i, mutation := range op.Mutations {
-			op.Mutations[i].Value = expandNamedUUID(mutation.Value, namedUUID)
-		}
-		for _, row := range op.Rows {
-			for k, v := range row {
-				row[k] = expandNamedUUID(v, namedUUID)
-			}
-		}
-		for k, v := range op.Row {
-			op.Row[k] = expandNamedUUID(v, namedUUID)
-		}
 		ops = append(ops, op)
 	}
-	response, updates := o.transact(db, ops)
-	*reply = response
+	*reply, err = o.commit(client, db, ops)
+	return err
+}
+
+// Inject executes a transaction on a database of the server as if a client had
+// sent it, so that the monitors of the clients are notified of its updates
+// like for any other transaction. That client owns no lock, so the assert
+// operations of the transaction fail. It returns the results of the operations,
+// which have to be checked with ovsdb.CheckOperationResults.
+func (o *OvsdbServer) Inject(db string, operations ...ovsdb.Operation) ([]ovsdb.OperationResult, error) {
+	if !o.db.Exists(db) {
+		return nil, fmt.Errorf("database %s does not exist", db)
+	}
+	ops := make([]ovsdb.Operation, len(operations))
+	copy(ops, operations)
+	response, err := o.commit(nil, db, ops)
+	results := make([]ovsdb.OperationResult, 0, len(response))
+	for _, result := range response {
+		if result != nil {
+			results = append(results, *result)
+		}
+	}
+	return results, err
+}
+
+// commit executes a transaction sent by a client, or injected if the client is
+// nil, and, if all of its operations succeed, sends its updates to the
+// monitors and commits them to the database
+func (o *OvsdbServer) commit(client *rpc2.Client, db string, ops []ovsdb.Operation) ([]*ovsdb.OperationResult, error) {
+	// While allowing other rpc handlers to run in parallel, this ovsdb server expects transactions
+	// to be serialized. The following mutex ensures that.
+	// Ref: https://github.com/cenkalti/rpc2/blob/c1acbc6ec984b7ae6830b6a36b62f008d5aefc4c/client.go#L187
+	o.txnMutex.Lock()
+	defer o.txnMutex.Unlock()
+
+	namedUUID := make(map[string]ovsdb.UUID)
+	for i := range ops {
+		ops[i] = expandOperation(ops[i], namedUUID)
+	}
+	response, updates := o.transact(client, db, ops)
 	for _, operResult := range response {
 		if operResult.Error != "" {
 			o.logger.Error(errors.New("failed to process operation"), "Skipping transaction DB commit due to error", "operations", ops, "results", response, "operation error", operResult.Error)
-			return nil
+			return response, nil
 		}
 	}
 	transactionID := uuid.New()
 	o.processMonitors(transactionID, updates)
-	return o.db.Commit(db, transactionID, updates)
+	return response, o.db.Commit(db, transactionID, updates)
 }
 
-func (o *OvsdbServer) transact(name string, operations []ovsdb.Operation) ([]*ovsdb.OperationResult, ovsdb.TableUpdates2) {
+// expandOperation returns a copy of an operation whose named-uuids are
+// replaced by the UUIDs of the rows inserted with them, generating the UUID of
+// the row it inserts if it is named
+func expandOperation(op ovsdb.Operation, namedUUID map[string]ovsdb.UUID) ovsdb.Operation {
+	if op.UUIDName != "" {
+		newUUID := uuid.NewString()
+		namedUUID[op.UUIDName] = ovsdb.UUID{GoUUID: newUUID}
+		op.UUIDName = newUUID
+	}
+	if op.Where != nil {
+		where := make([]ovsdb.Condition, len(op.Where))
+		for i, condition := range op.Where {
+			condition.Value = expandNamedUUID(condition.Value, namedUUID)
+			where[i] = condition
+		}
+		op.Where = where
+	}
+	if op.Mutations != nil {
+		mutations := make([]ovsdb.Mutation, len(op.Mutations))
+		for i, mutation := range op.Mutations {
+			mutation.Value = expandNamedUUID(mutation.Value, namedUUID)
+			mutations[i] = mutation
+		}
+		op.Mutations = mutations
+	}
+	if op.Rows != nil {
+		rows := make([]ovsdb.Row, len(op.Rows))
+		for i, row := range op.Rows {
+			rows[i] = expandRow(row, namedUUID)
+		}
+		op.Rows = rows
+	}
+	if op.Row != nil {
+		op.Row = expandRow(op.Row, namedUUID)
+	}
+	return op
+}
+
+func expandRow(row ovsdb.Row, namedUUID map[string]ovsdb.UUID) ovsdb.Row {
+	expanded := make(ovsdb.Row, len(row))
+	for k, v := range row {
+		expanded[k] = expandNamedUUID(v, namedUUID)
+	}
+	return expanded
+}
+
+func (o *OvsdbServer) transact(client *rpc2.Client, name string, operations []ovsdb.Operation) ([]*ovsdb.OperationResult, ovsdb.TableUpdates2) {
 	o.modelsMutex.Lock()
 	dbModel := o.models[name]
 	o.modelsMutex.Unlock()
 	transaction := database.NewTransaction(dbModel, name, o.db, &o.logger)
+	transaction.LockOwned = func(id string) bool {
+		return client != nil && o.lockOwner(id) == client
+	}
 	return transaction.Transact(operations)
 }
 
@@ -286,6 +369,9 @@ func (o *OvsdbServer) Monitor(client *rpc2.Client, args []json.RawMessage, reply
 
 	tableUpdates := make(ovsdb.TableUpdates)
 	for t, request := range request {
+		if request.Select != nil && !request.Select.Initial() {
+			continue
+		}
 		rows := transaction.Select(t, nil, request.Columns)
 		for i := range rows.Rows {
 			tu := make(ovsdb.TableUpdate)
@@ -333,6 +419,9 @@ func (o *OvsdbServer) MonitorCond(client *rpc2.Client, args []json.RawMessage, r
 
 	tableUpdates := make(ovsdb.TableUpdates2)
 	for t, request := range request {
+		if request.Select != nil && !request.Select.Initial() {
+			continue
+		}
 		rows := transaction.Select(t, nil, request.Columns)
 		for i := range rows.Rows {
 			tu := make(ovsdb.TableUpdate2)
@@ -378,6 +467,9 @@ func (o *OvsdbServer) MonitorCondSince(client *rpc2.Client, args []json.RawMessa
 
 	tableUpdates := make(ovsdb.TableUpdates2)
 	for t, request := range request {
+		if request.Select != nil && !request.Select.Initial() {
+			continue
+		}
 		rows := transaction.Select(t, nil, request.Columns)
 		for i := range rows.Rows {
 			tu := make(ovsdb.TableUpdate2)
@@ -391,24 +483,159 @@ func (o *OvsdbServer) MonitorCondSince(client *rpc2.Client, args []json.RawMessa
 	return nil
 }
 
-// MonitorCancel cancels a monitor on a given table
-func (o *OvsdbServer) MonitorCancel(client *rpc2.Client, args []interface{}, reply *[]interface{}) error {
-	return fmt.Errorf("not implemented")
+// MonitorCancel cancels a monitor of the client
+func (o *OvsdbServer) MonitorCancel(client *rpc2.Client, args []json.RawMessage, reply *struct{}) error {
+	if len(args) != 1 {
+		return fmt.Errorf("monitor_cancel requires exactly 1 arg")
+	}
+	value := string(args[0])
+	o.monitorMutex.Lock()
+	defer o.monitorMutex.Unlock()
+	clientMonitors, ok := o.monitors[client]
+	if !ok {
+		return fmt.Errorf("unknown monitor")
+	}
+	if _, ok := clientMonitors.monitors[value]; !ok {
+		return fmt.Errorf("unknown monitor")
+	}
+	delete(clientMonitors.monitors, value)
+	return nil
 }
 
-// Lock acquires a lock on a table for a the client
-func (o *OvsdbServer) Lock(client *rpc2.Client, args []interface{}, reply *[]interface{}) error {
-	return fmt.Errorf("not implemented")
+// Lock acquires a lock for the client, or queues the client until the clients
+// that own or wait for the lock release it, in which case the client gets a
+// locked notification
+func (o *OvsdbServer) Lock(client *rpc2.Client, args []interface{}, reply *ovsdb.LockResult) error {
+	id, err := lockID("lock", args)
+	if err != nil {
+		return err
+	}
+	o.locksMutex.Lock()
+	defer o.locksMutex.Unlock()
+	for _, c := range o.locks[id] {
+		if c == client {
+			return fmt.Errorf("lock %s already requested", id)
+		}
+	}
+	o.locks[id] = append(o.locks[id], client)
+	reply.Locked = o.locks[id][0] == client
+	return nil
 }
 
-// Steal steals a lock for a client
-func (o *OvsdbServer) Steal(client *rpc2.Client, args []interface{}, reply *[]interface{}) error {
-	return fmt.Errorf("not implemented")
+// Steal steals a lock for a client. The previous owner gets a stolen
+// notification, and waits for the lock again.
+func (o *OvsdbServer) Steal(client *rpc2.Client, args []interface{}, reply *ovsdb.LockResult) error {
+	id, err := lockID("steal", args)
+	if err != nil {
+		return err
+	}
+	o.locksMutex.Lock()
+	waiters := removeClient(o.locks[id], client)
+	var owner *rpc2.Client
+	if len(waiters) > 0 {
+		owner = waiters[0]
+	}
+	o.locks[id] = append([]*rpc2.Client{client}, waiters...)
+	o.locksMutex.Unlock()
+	if owner != nil {
+		o.notifyLock(owner, "stolen", id)
+	}
+	reply.Locked = true
+	return nil
 }
 
-// Unlock releases a lock for a client
-func (o *OvsdbServer) Unlock(client *rpc2.Client, args []interface{}, reply *[]interface{}) error {
-	return fmt.Errorf("not implemented")
+// Unlock releases a lock for a client, or stops waiting for it. The next
+// client waiting for the lock gets a locked notification.
+func (o *OvsdbServer) Unlock(client *rpc2.Client, args []interface{}, reply *struct{}) error {
+	id, err := lockID("unlock", args)
+	if err != nil {
+		return err
+	}
+	o.locksMutex.Lock()
+	waiters := o.locks[id]
+	if len(removeClient(waiters, client)) == len(waiters) {
+		o.locksMutex.Unlock()
+		return fmt.Errorf("lock %s not requested", id)
+	}
+	owner := o.unlock(client, id)
+	o.locksMutex.Unlock()
+	if owner != nil {
+		o.notifyLock(owner, "locked", id)
+	}
+	return nil
+}
+
+// lockOwner returns the client that owns a lock, if any
+func (o *OvsdbServer) lockOwner(id string) *rpc2.Client {
+	o.locksMutex.Lock()
+	defer o.locksMutex.Unlock()
+	if len(o.locks[id]) == 0 {
+		return nil
+	}
+	return o.locks[id][0]
+}
+
+// releaseLocks releases the locks of a client that disconnected
+func (o *OvsdbServer) releaseLocks(client *rpc2.Client) {
+	owners := map[string]*rpc2.Client{}
+	o.locksMutex.Lock()
+	for id := range o.locks {
+		if owner := o.unlock(client, id); owner != nil {
+			owners[id] = owner
+		}
+	}
+	o.locksMutex.Unlock()
+	for id, owner := range owners {
+		o.notifyLock(owner, "locked", id)
+	}
+}
+
+// unlock removes a client from the clients of a lock, and returns the new
+// owner of the lock if the client owned it. Caller must hold locksMutex.
+func (o *OvsdbServer) unlock(client *rpc2.Client, id string) *rpc2.Client {
+	waiters := o.locks[id]
+	if len(waiters) == 0 {
+		return nil
+	}
+	owned := waiters[0] == client
+	waiters = removeClient(waiters, client)
+	if len(waiters) == 0 {
+		delete(o.locks, id)
+		return nil
+	}
+	o.locks[id] = waiters
+	if !owned {
+		return nil
+	}
+	return waiters[0]
+}
+
+func (o *OvsdbServer) notifyLock(client *rpc2.Client, method, id string) {
+	if err := client.Notify(method, ovsdb.NewLockArgs(id)); err != nil {
+		o.logger.Error(err, "failed to send lock notification", "method", method, "lock", id)
+	}
+}
+
+func lockID(method string, args []interface{}) (string, error) {
+	if len(args) != 1 {
+		return "", fmt.Errorf("%s requires exactly 1 arg", method)
+	}
+	id, ok := args[0].(string)
+	if !ok {
+		return "", fmt.Errorf("%s requires a lock id, not %v", method, args[0])
+	}
+	return id, nil
+}
+
+// removeClient returns the clients without the given one
+func removeClient(clients []*rpc2.Client, client *rpc2.Client) []*rpc2.Client {
+	kept := make([]*rpc2.Client, 0, len(clients))
+	for _, c := range clients {
+		if c != client {
+			kept = append(kept, c)
+		}
+	}
+	return kept
 }
 
 // Echo tests the liveness of the connection
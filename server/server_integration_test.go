@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"math/rand"
+	"net"
 	"os"
 	"reflect"
 	"sync"
@@ -380,3 +381,66 @@ func TestUnsetOptional(t *testing.T) {
 	require.NoError(t, err)
 	require.Nil(t, br.DatapathID)
 }
+
+func TestClientServerPipe(t *testing.T) {
+	defDB, err := model.NewClientDBModel("Open_vSwitch", map[string]model.Model{
+		"Open_vSwitch": &OvsType{},
+		"Bridge":       &BridgeType{}})
+	require.NoError(t, err)
+	schema, err := GetSchema()
+	require.NoError(t, err)
+	dbModel, errs := model.NewDatabaseModel(schema, defDB)
+	require.Empty(t, errs)
+	server, err := NewOvsdbServer(database.NewInMemoryDatabase(map[string]model.ClientDBModel{"Open_vSwitch": defDB}), dbModel)
+	require.NoError(t, err)
+	defer server.Close()
+
+	ovs, err := client.NewOVSDBClient(defDB, client.WithEndpoint("unix:/pipe"),
+		client.WithDialContext(func(ctx context.Context, network, address string) (net.Conn, error) {
+			assert.Equal(t, "unix", network)
+			assert.Equal(t, "/pipe", address)
+			return server.Pipe(), nil
+		}))
+	require.NoError(t, err)
+	err = ovs.Connect(context.Background())
+	require.NoError(t, err)
+	defer ovs.Disconnect()
+	require.NoError(t, ovs.Echo(context.Background()))
+	_, err = ovs.MonitorAll(context.Background())
+	require.NoError(t, err)
+
+	// insert through the client, and get the row back from the monitor
+	ops, err := ovs.Create(&BridgeType{Name: "br0", ExternalIds: map[string]string{"foo": "bar"}})
+	require.NoError(t, err)
+	reply, err := ovs.Transact(context.Background(), ops...)
+	require.NoError(t, err)
+	_, err = ovsdb.CheckOperationResults(reply, ops)
+	require.NoError(t, err)
+	br0 := &BridgeType{UUID: reply[0].UUID.GoUUID}
+	require.Eventually(t, func() bool {
+		return ovs.Get(context.Background(), br0) == nil
+	}, 2*time.Second, 10*time.Millisecond)
+	assert.Equal(t, "br0", br0.Name)
+	assert.Equal(t, map[string]string{"foo": "bar"}, br0.ExternalIds)
+
+	// inject a transaction on the server side, the client is notified of it
+	injected := []ovsdb.Operation{{
+		Op:       ovsdb.OperationInsert,
+		Table:    "Bridge",
+		UUIDName: "br1",
+		Row:      ovsdb.Row{"name": "br1"},
+	}}
+	reply, err = server.Inject("Open_vSwitch", injected...)
+	require.NoError(t, err)
+	_, err = ovsdb.CheckOperationResults(reply, injected)
+	require.NoError(t, err)
+	assert.Equal(t, "br1", injected[0].UUIDName)
+	br1 := &BridgeType{UUID: reply[0].UUID.GoUUID}
+	require.Eventually(t, func() bool {
+		return ovs.Get(context.Background(), br1) == nil
+	}, 2*time.Second, 10*time.Millisecond)
+	assert.Equal(t, "br1", br1.Name)
+
+	_, err = server.Inject("Unknown", injected...)
+	assert.Error(t, err)
+}
@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"testing"
 
+	"github.com/cenkalti/rpc2"
 	"github.com/google/uuid"
 	"github.com/ovn-org/libovsdb/database"
 	"github.com/ovn-org/libovsdb/model"
@@ -153,3 +154,34 @@ func TestOvsdbServerMonitor(t *testing.T) {
 	}
 	assert.Equal(t, expected, reply)
 }
+
+func TestOvsdbServerLock(t *testing.T) {
+	defDB, err := model.NewClientDBModel("Open_vSwitch", map[string]model.Model{
+		"Open_vSwitch": &OvsType{},
+		"Bridge":       &BridgeType{}})
+	require.NoError(t, err)
+	schema, err := GetSchema()
+	require.NoError(t, err)
+	ovsDB := database.NewInMemoryDatabase(map[string]model.ClientDBModel{"Open_vSwitch": defDB})
+	dbModel, errs := model.NewDatabaseModel(schema, defDB)
+	require.Empty(t, errs)
+	o, err := NewOvsdbServer(ovsDB, dbModel)
+	require.NoError(t, err)
+
+	// notifications are only sent to the waiters that get the lock
+	owner, waiter := &rpc2.Client{}, &rpc2.Client{}
+	var result ovsdb.LockResult
+	require.NoError(t, o.Lock(owner, []interface{}{"lock"}, &result))
+	assert.True(t, result.Locked)
+	require.NoError(t, o.Lock(waiter, []interface{}{"lock"}, &result))
+	assert.False(t, result.Locked)
+	assert.Error(t, o.Lock(waiter, []interface{}{"lock"}, &result))
+	assert.Error(t, o.Lock(waiter, []interface{}{42}, &result))
+	assert.Equal(t, []*rpc2.Client{owner, waiter}, o.locks["lock"])
+
+	assert.Error(t, o.Unlock(&rpc2.Client{}, []interface{}{"lock"}, &struct{}{}))
+	require.NoError(t, o.Unlock(waiter, []interface{}{"lock"}, &struct{}{}))
+	assert.Equal(t, []*rpc2.Client{owner}, o.locks["lock"])
+	require.NoError(t, o.Unlock(owner, []interface{}{"lock"}, &struct{}{}))
+	assert.NotContains(t, o.locks, "lock")
+}